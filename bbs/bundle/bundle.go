@@ -0,0 +1,294 @@
+/*
+ * Copyright (c) 2015, Shinya Yagyu
+ * All rights reserved.
+ * Redistribution and use in source and binary forms, with or without
+ * modification, are permitted provided that the following conditions are met:
+ *
+ * 1. Redistributions of source code must retain the above copyright notice,
+ *    this list of conditions and the following disclaimer.
+ * 2. Redistributions in binary form must reproduce the above copyright notice,
+ *    this list of conditions and the following disclaimer in the documentation
+ *    and/or other materials provided with the distribution.
+ * 3. Neither the name of the copyright holder nor the names of its
+ *    contributors may be used to endorse or promote products derived from this
+ *    software without specific prior written permission.
+ *
+ * THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+ * AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+ * IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+ * ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+ * LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+ * CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+ * SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+ * INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+ * CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+ * ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+ * POSSIBILITY OF SUCH DAMAGE.
+ */
+package bundle
+
+import (
+	"bufio"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"bbs/cfg"
+	"bbs/db"
+	"bbs/myself"
+	"bbs/recentlist"
+	"bbs/record"
+	"bbs/tag"
+	"bbs/tag/user"
+	"bbs/thread"
+	"bbs/updateque"
+	"bbs/util"
+)
+
+//A bundle is a gzipped text file which has lines below.
+//thread lines are followed by record lines of the thread.
+//  thread<>datfile<>user tags separated by space
+//  record<>datfile<>1 if removed else 0<>Recstr
+//
+//Recstr has the legacy (md5) id and the attached file, same as /get/ response.
+const (
+	threadLine = "thread"
+	recordLine = "record"
+)
+
+//batchSize is # of lines imported in one transaction.
+const batchSize = 1000
+
+//Filter selects records to be exported.
+type Filter struct {
+	Threads []string //datfiles
+	Tag     string   //user tag of threads
+	Since   int64    //stamp of the oldest record
+}
+
+//datfiles returns threads selected by f.
+//if both Threads and Tag are empty, all threads are selected.
+func (f *Filter) datfiles(tx db.Tx) ([]string, error) {
+	if len(f.Threads) == 0 && f.Tag == "" {
+		if !tx.HasBucket("thread") {
+			return nil, nil
+		}
+		return db.KeyStrings(tx, "thread")
+	}
+	ds := append([]string{}, f.Threads...)
+	if f.Tag != "" {
+		ds = append(ds, user.ThreadsTX(tx, f.Tag)...)
+	}
+	return ds, nil
+}
+
+//Export writes records selected by f to w as a bundle, and returns # of records.
+func Export(my *myself.Myself, w io.Writer, f *Filter) (int, error) {
+	gw := gzip.NewWriter(w)
+	n := 0
+	err := my.DB.View(func(tx db.Tx) error {
+		ds, err := f.datfiles(tx)
+		if err != nil {
+			return err
+		}
+		for _, datfile := range ds {
+			tags := strings.Join(user.GetStringsTX(tx, datfile), " ")
+			if _, err := fmt.Fprintf(gw, "%s<>%s<>%s\n", threadLine, datfile, tags); err != nil {
+				return err
+			}
+			if !tx.HasBucket("record") {
+				continue
+			}
+			rs, err := record.GetFromDBs(tx, datfile)
+			if err != nil {
+				return err
+			}
+			for _, d := range rs {
+				if d.Stamp < f.Since {
+					continue
+				}
+				if err := writeRecord(tx, gw, d); err != nil {
+					return err
+				}
+				n++
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return n, err
+	}
+	return n, gw.Close()
+}
+
+//writeRecord writes a record line of d to w.
+func writeRecord(tx db.Tx, w io.Writer, d *record.DB) error {
+	body, err := d.BodyTX(tx)
+	if err != nil {
+		return err
+	}
+	id := d.ID
+	if d.LegacyID != "" {
+		id = d.LegacyID
+	}
+	removed := 0
+	if d.Deleted {
+		removed = 1
+	}
+	_, err = fmt.Fprintf(w, "%s<>%s<>%d<>%d<>%s<>%s\n", recordLine, d.Datfile, removed, d.Stamp, id, body)
+	return err
+}
+
+//Result is # of records imported from a bundle.
+type Result struct {
+	Threads  int
+	Records  int //newly added records
+	Exists   int //records which were already saved
+	Removed  int //records which were marked as removed
+	Spam     int //spam, too large or forged records, which were saved as removed
+	Rejected int //records whose id is wrong or which cannot be parsed
+	Queued   int //records which were queued to tell other nodes
+}
+
+//Import reads a bundle from r and saves records after checking them with Cache.CheckData.
+//if q is not nil, new records in update range are queued in q, and are told to other nodes
+//when the queue is retried by the running node.
+func Import(my *myself.Myself, r io.Reader, q *updateque.Queue) (*Result, error) {
+	gr, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, err
+	}
+	br := bufio.NewReader(gr)
+	res := &Result{}
+	var updated []*record.Record
+	for eof := false; !eof; {
+		var lines []string
+		for len(lines) < batchSize {
+			line, err := br.ReadString('\n')
+			if err == io.EOF {
+				eof = true
+				if line != "" {
+					lines = append(lines, line)
+				}
+				break
+			}
+			if err != nil {
+				return res, err
+			}
+			lines = append(lines, line)
+		}
+		err = my.DB.Update(func(tx db.Tx) error {
+			for _, line := range lines {
+				rec, err := importLine(my, tx, strings.TrimRight(line, "\r\n"), res)
+				if err != nil {
+					return err
+				}
+				if rec != nil && q != nil && recentlist.IsInUpdateRange(rec.Stamp) {
+					updated = append(updated, rec)
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return res, err
+		}
+	}
+	for _, rec := range updated {
+		q.Add(rec)
+	}
+	res.Queued = len(updated)
+	return res, nil
+}
+
+//importLine imports one line in a bundle and returns the record if it is newly added and alive.
+func importLine(my *myself.Myself, tx db.Tx, line string, res *Result) (*record.Record, error) {
+	buf := strings.SplitN(line, "<>", 4)
+	switch {
+	case line == "":
+		return nil, nil
+	case len(buf) >= 2 && !isDatfile(buf[1]):
+		res.Rejected++
+		return nil, nil
+	case len(buf) >= 2 && buf[0] == threadLine:
+		ca := thread.NewCache(my, buf[1])
+		ca.SubscribeTX(tx)
+		res.Threads++
+		if len(buf) < 3 {
+			return nil, nil
+		}
+		return nil, user.AddTX(tx, ca.Datfile, bundleTags(buf[2]))
+	case len(buf) == 4 && buf[0] == recordLine:
+		return importRecord(my, tx, buf[1], buf[2] == "1", buf[3], res)
+	}
+	return nil, errors.New("illegal bundle line: " + line)
+}
+
+//bundleTags returns valid tags without duplicates in tags separated by spaces.
+func bundleTags(tags string) []string {
+	var r []string
+	for _, t := range strings.Fields(tags) {
+		if tag.IsOK(t) && !util.HasString(r, t) {
+			r = append(r, t)
+		}
+	}
+	return r
+}
+
+//isDatfile returns true if datfile is a valid thread name.
+func isDatfile(datfile string) bool {
+	return strings.HasPrefix(datfile, "thread_") && util.FileDecode(datfile) != ""
+}
+
+//importRecord saves the record recstr in datfile through Cache.CheckData.
+func importRecord(my *myself.Myself, tx db.Tx, datfile string, removed bool, recstr string, res *Result) (*record.Record, error) {
+	rec := record.New(my, datfile, "", 0)
+	if err := rec.Parse(recstr); err != nil {
+		res.Rejected++
+		return nil, nil
+	}
+	if record.ResolveTX(tx, rec.Head) != nil {
+		res.Exists++
+		return nil, nil
+	}
+	ca := thread.NewCache(my, datfile)
+	ca.SubscribeTX(tx)
+	switch err := ca.CheckData(tx, recstr, -1, "", 0, 0); err {
+	case nil:
+	case cfg.ErrSpam, cfg.ErrSign:
+		res.Spam++
+		return nil, nil
+	case cfg.ErrGet:
+		res.Rejected++
+		return nil, nil
+	default:
+		return nil, err
+	}
+	res.Records++
+	if !removed {
+		return rec, nil
+	}
+	h := record.ResolveTX(tx, rec.Head)
+	if h == nil {
+		log.Println(rec.Idstr(), "is not saved")
+		return nil, nil
+	}
+	res.Removed++
+	return nil, h.RemoveTX(tx)
+}
+
+//ParseSince parses stamp or date in local time for Filter.Since.
+func ParseSince(s string) (int64, error) {
+	if s == "" {
+		return 0, nil
+	}
+	if stamp, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return stamp, nil
+	}
+	t, err := time.ParseInLocation("2006-01-02", s, time.Local)
+	return t.Unix(), err
+}
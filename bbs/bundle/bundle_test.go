@@ -0,0 +1,123 @@
+/*
+ * Copyright (c) 2015, Shinya Yagyu
+ * All rights reserved.
+ * Redistribution and use in source and binary forms, with or without
+ * modification, are permitted provided that the following conditions are met:
+ *
+ * 1. Redistributions of source code must retain the above copyright notice,
+ *    this list of conditions and the following disclaimer.
+ * 2. Redistributions in binary form must reproduce the above copyright notice,
+ *    this list of conditions and the following disclaimer in the documentation
+ *    and/or other materials provided with the distribution.
+ * 3. Neither the name of the copyright holder nor the names of its
+ *    contributors may be used to endorse or promote products derived from this
+ *    software without specific prior written permission.
+ *
+ * THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+ * AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+ * IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+ * ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+ * LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+ * CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+ * SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+ * INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+ * CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+ * ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+ * POSSIBILITY OF SUCH DAMAGE.
+ */
+
+package bundle
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"testing"
+	"time"
+
+	"bbs/myself"
+	"bbs/record"
+	"bbs/tag/user"
+	"bbs/thread"
+	"bbs/updateque"
+	"bbs/util"
+)
+
+func TestExportImport(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gou")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	src := myself.NewMemory(dir)
+	dst := myself.NewMemory(dir)
+
+	datfile := util.FileEncode("thread", "bundle")
+	thread.NewCache(src, datfile).Subscribe()
+	now := time.Now().Unix()
+	for i := 0; i < 3; i++ {
+		rec := record.New(src, datfile, "", 0)
+		rec.Build(now-int64(i), map[string]string{"body": "body" + strconv.Itoa(i)}, "")
+		rec.Sync()
+	}
+
+	var buf bytes.Buffer
+	n, err := Export(src, &buf, &Filter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 3 {
+		t.Fatal("exported", n, "records, expected 3")
+	}
+	q := updateque.New(dst)
+	res, err := Import(dst, bytes.NewReader(buf.Bytes()), q)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Threads != 1 || res.Records != 3 || res.Queued != 3 {
+		t.Fatalf("unexpected result %+v", res)
+	}
+	if its := q.Items(); len(its) != 3 {
+		t.Fatal(len(its), "items are queued, expected 3")
+	}
+	recs := thread.NewCache(dst, datfile).LoadRecords(record.Alive)
+	if len(recs) != 3 {
+		t.Fatal(len(recs), "records are imported, expected 3")
+	}
+
+	res, err = Import(dst, bytes.NewReader(buf.Bytes()), q)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Records != 0 || res.Exists != 3 || res.Queued != 0 {
+		t.Fatalf("unexpected result of reimport %+v", res)
+	}
+}
+
+func TestImportTags(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gou")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	my := myself.NewMemory(dir)
+
+	datfile := util.FileEncode("thread", "tags")
+	var buf bytes.Buffer
+	gw := gzip.NewWriter(&buf)
+	if _, err := gw.Write([]byte("thread<>" + datfile + "<>news a<b news &amp; c>\n")); err != nil {
+		t.Fatal(err)
+	}
+	if err := gw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Import(my, &buf, updateque.New(my)); err != nil {
+		t.Fatal(err)
+	}
+	tags := user.GetStrings(my.DB, datfile)
+	if len(tags) != 1 || tags[0] != "news" {
+		t.Fatal("unexpected tags", tags)
+	}
+}
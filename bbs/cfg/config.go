@@ -67,15 +67,12 @@ const (
 var (
 	ErrSpam = errors.New("this is spam")
 	ErrGet  = errors.New("cannot get data")
+	ErrSign = errors.New("sign is forged")
 )
 
-var (
-	defaultInitNode = []string{
-		"node.shingetsu.info:8000/server.cgi",
-	}
-	//InitNode is initiali nodes.
-	InitNode *util.ConfList
-)
+var defaultInitNode = []string{
+	"node.shingetsu.info:8000/server.cgi",
+}
 
 //cwd represents current working dir.
 //which should be the result of getFilesDir()  at android.
@@ -90,11 +87,12 @@ func SetAndroid(path string) {
 	cwd = path
 }
 
-//config params
-var (
+//Config is config params of one node.
+type Config struct {
 	Docroot     string
 	LogDir      string
 	RunDir      string
+	BackupDir   string
 	FileDir     string
 	TemplateDir string
 
@@ -106,10 +104,13 @@ var (
 	SaveRemoved          int64
 	DefaultPort          int //DefaultPort is listening port
 	MaxConnection        int
+	UpdateWorkers        int   //# of nodes told an update at once
+	UpdateDeadline       int64 //seconds until telling an update gives up
 	SpamList             string
 	InitnodeList         string
 	NodeAllowFile        string
 	NodeDenyFile         string
+	ModeratorFile        string
 	ReAdminStr           string
 	ReFriendStr          string
 	ReVisitorStr         string
@@ -123,18 +124,25 @@ var (
 	DefaultThumbnailSize string
 	Enable2ch            bool
 	ForceThumbnail       bool
+	LegacySign           bool
+	CacheHashMethod      string // asis(md5), md5, sha1, sha224, sha256, sha384, or sha512
 	EnableProf           bool
 	HeavyMoon            bool
 	EnableEmbed          bool
-)
+	BackupInterval       int64 //0 disables scheduled snapshots
+	BackupCount          int   //# of snapshots to be kept
+
+	//InitNode is initiali nodes.
+	InitNode *util.ConfList
+	//Moderators is pubkeys of trusted moderators.
+	Moderators *util.ConfList
+	//Spam is regexps of spam records.
+	Spam *util.RegexpList
+}
 
 //SuffixTXT is suffix of text files.
 var SuffixTXT = "txt"
 
-// asis, md5, sha1, sha224, sha256, sha384, or sha512
-//	cache_hash_method = "asis"
-//others are not implemented for gou for now.
-
 //Version is one of Gou. it shoud be overwritten when building on travis.
 var Version = "unstable"
 
@@ -182,8 +190,8 @@ func getPathValue(i *ini.File, section, key string, vdefault string) string {
 	return filepath.FromSlash(h)
 }
 
-//Parse makes  config vars from the ini files and returns it.
-func Parse() {
+//Parse makes config from the ini files and returns it.
+func Parse() *Config {
 	files := []string{filepath.Join(cwd, "file", "saku.ini"), "/usr/local/etc/saku/saku.ini", "/etc/saku/saku.ini"}
 	usr, err := user.Current()
 	if err == nil {
@@ -199,97 +207,119 @@ func Parse() {
 			}
 		}
 	}
-	initVariables(i)
-	InitNode = util.NewConfList(InitnodeList, defaultInitNode)
+	return New(i)
 }
 
-func networkMode(i *ini.File) {
+//New makes config from the ini file i. default values are used for keys which i doesn't have.
+func New(i *ini.File) *Config {
+	c := &Config{}
+	c.initVariables(i)
+	c.InitNode = util.NewConfList(c.InitnodeList, defaultInitNode)
+	c.Moderators = util.NewConfList(c.ModeratorFile, nil)
+	c.Spam = util.NewRegexpList(c.SpamList)
+	return c
+}
+
+func (c *Config) networkMode(i *ini.File) {
 	networkModeStr := getStringValue(i, "Network", "mode", "port_opened") //port_opened,upnp,relay
 	switch networkModeStr {
 	case "port_opened":
-		NetworkMode = Normal
+		c.NetworkMode = Normal
 	case "upnp":
-		NetworkMode = UPnP
+		c.NetworkMode = UPnP
 	default:
 		log.Println("cannot understand mode", networkModeStr)
-		NetworkMode = Normal
+		c.NetworkMode = Normal
 	}
 }
 
-//initVariables initializes some global and map vars.
-func initVariables(i *ini.File) {
-	DefaultPort = getIntValue(i, "Network", "port", 8000)
-	networkMode(i)
+//initVariables initializes config params from i.
+func (c *Config) initVariables(i *ini.File) {
+	c.DefaultPort = getIntValue(i, "Network", "port", 8000)
+	c.networkMode(i)
 	if !android {
-		Docroot = getPathValue(i, "Path", "docroot", "./www")                                     //path from cwd
-		RunDir = getRelativePathValue(i, "Path", "run_dir", "../run", Docroot)                    //path from docroot
-		FileDir = getRelativePathValue(i, "Path", "file_dir", "../file", Docroot)                 //path from docroot
-		TemplateDir = getRelativePathValue(i, "Path", "template_dir", "../gou_template", Docroot) //path from docroot
-		LogDir = getPathValue(i, "Path", "log_dir", "./log")                                      //path from cwd
-		SpamList = getRelativePathValue(i, "Path", "spam_list", "../file/spam.txt", Docroot)
-		InitnodeList = getRelativePathValue(i, "Path", "initnode_list", "../file/initnode.txt", Docroot)
-		NodeAllowFile = getRelativePathValue(i, "Path", "node_allow", "../file/node_allow.txt", Docroot)
-		NodeDenyFile = getRelativePathValue(i, "Path", "node_deny", "../file/node_deny.txt", Docroot)
+		c.Docroot = getPathValue(i, "Path", "docroot", "./www")                                       //path from cwd
+		c.RunDir = getRelativePathValue(i, "Path", "run_dir", "../run", c.Docroot)                    //path from docroot
+		c.BackupDir = getRelativePathValue(i, "Path", "backup_dir", "../backup", c.Docroot)           //path from docroot
+		c.FileDir = getRelativePathValue(i, "Path", "file_dir", "../file", c.Docroot)                 //path from docroot
+		c.TemplateDir = getRelativePathValue(i, "Path", "template_dir", "../gou_template", c.Docroot) //path from docroot
+		c.LogDir = getPathValue(i, "Path", "log_dir", "./log")                                        //path from cwd
+		c.SpamList = getRelativePathValue(i, "Path", "spam_list", "../file/spam.txt", c.Docroot)
+		c.InitnodeList = getRelativePathValue(i, "Path", "initnode_list", "../file/initnode.txt", c.Docroot)
+		c.NodeAllowFile = getRelativePathValue(i, "Path", "node_allow", "../file/node_allow.txt", c.Docroot)
+		c.NodeDenyFile = getRelativePathValue(i, "Path", "node_deny", "../file/node_deny.txt", c.Docroot)
+		c.ModeratorFile = getRelativePathValue(i, "Path", "moderator", "../file/moderator.txt", c.Docroot)
 	} else {
-		Docroot = filepath.Join(cwd, "www")
-		RunDir = filepath.Join(cwd, "run")
-		FileDir = filepath.Join(cwd, "file")
-		TemplateDir = filepath.Join(cwd, "gou_template")
-		LogDir = filepath.Join(cwd, "log")
-		SpamList = filepath.Join(cwd, "file", "spam.txt")
-		InitnodeList = filepath.Join(cwd, "file", "initnode.txt")
-		NodeAllowFile = filepath.Join(cwd, "file", "node_allow.txt")
-		NodeDenyFile = filepath.Join(cwd, "file", "node_deny.txt")
+		c.Docroot = filepath.Join(cwd, "www")
+		c.RunDir = filepath.Join(cwd, "run")
+		c.BackupDir = filepath.Join(cwd, "backup")
+		c.FileDir = filepath.Join(cwd, "file")
+		c.TemplateDir = filepath.Join(cwd, "gou_template")
+		c.LogDir = filepath.Join(cwd, "log")
+		c.SpamList = filepath.Join(cwd, "file", "spam.txt")
+		c.InitnodeList = filepath.Join(cwd, "file", "initnode.txt")
+		c.NodeAllowFile = filepath.Join(cwd, "file", "node_allow.txt")
+		c.NodeDenyFile = filepath.Join(cwd, "file", "node_deny.txt")
+		c.ModeratorFile = filepath.Join(cwd, "file", "moderator.txt")
+	}
+	c.MaxConnection = getIntValue(i, "Network", "max_connection", 100)
+	c.UpdateWorkers = getIntValue(i, "Network", "update_workers", 10)
+	c.UpdateDeadline = getInt64Value(i, "Network", "update_deadline", 30)
+	c.ReAdminStr = getStringValue(i, "Gateway", "admin", "^(127|\\[::1\\])")
+	c.ReFriendStr = getStringValue(i, "Gateway", "friend", "^(127|\\[::1\\])")
+	c.ReVisitorStr = getStringValue(i, "Gateway", "visitor", ".")
+	c.ServerName = getStringValue(i, "Gateway", "server_name", "")
+	c.TagSize = getIntValue(i, "Gateway", "tag_size", 20)
+	c.RSSRange = getInt64Value(i, "Gateway", "rss_range", 3*24*60*60)
+	c.TopRecentRange = getInt64Value(i, "Gateway", "top_recent_range", 3*24*60*60)
+	c.RecentRange = getInt64Value(i, "Gateway", "recent_range", 31*24*60*60)
+	c.RecordLimit = getIntValue(i, "Gateway", "record_limit", 2048)
+	c.Enable2ch = getBoolValue(i, "Gateway", "enable_2ch", false)
+	c.EnableProf = getBoolValue(i, "Gateway", "enable_prof", false)
+	c.HeavyMoon = getBoolValue(i, "Gateway", "moonlight", false)
+	c.EnableEmbed = getBoolValue(i, "Gateway", "enable_embed", true)
+	c.ThreadPageSize = getIntValue(i, "Application Thread", "page_size", 50)
+	c.DefaultThumbnailSize = getStringValue(i, "Application Thread", "thumbnail_size", "")
+	c.ForceThumbnail = getBoolValue(i, "Application Thread", "force_thumbnail", false)
+	c.LegacySign = getBoolValue(i, "Application Thread", "legacy_sign", true)
+	c.CacheHashMethod = getStringValue(i, "Application Thread", "cache_hash_method", "asis")
+	if _, err := util.GetHasher(c.CacheHashMethod); err != nil {
+		log.Fatal(err)
 	}
-	MaxConnection = getIntValue(i, "Network", "max_connection", 100)
-	ReAdminStr = getStringValue(i, "Gateway", "admin", "^(127|\\[::1\\])")
-	ReFriendStr = getStringValue(i, "Gateway", "friend", "^(127|\\[::1\\])")
-	ReVisitorStr = getStringValue(i, "Gateway", "visitor", ".")
-	ServerName = getStringValue(i, "Gateway", "server_name", "")
-	TagSize = getIntValue(i, "Gateway", "tag_size", 20)
-	RSSRange = getInt64Value(i, "Gateway", "rss_range", 3*24*60*60)
-	TopRecentRange = getInt64Value(i, "Gateway", "top_recent_range", 3*24*60*60)
-	RecentRange = getInt64Value(i, "Gateway", "recent_range", 31*24*60*60)
-	RecordLimit = getIntValue(i, "Gateway", "record_limit", 2048)
-	Enable2ch = getBoolValue(i, "Gateway", "enable_2ch", false)
-	EnableProf = getBoolValue(i, "Gateway", "enable_prof", false)
-	HeavyMoon = getBoolValue(i, "Gateway", "moonlight", false)
-	EnableEmbed = getBoolValue(i, "Gateway", "enable_embed", true)
-	ThreadPageSize = getIntValue(i, "Application Thread", "page_size", 50)
-	DefaultThumbnailSize = getStringValue(i, "Application Thread", "thumbnail_size", "")
-	ForceThumbnail = getBoolValue(i, "Application Thread", "force_thumbnail", false)
+	c.BackupInterval = getInt64Value(i, "Database", "backup_interval", 24*60*60)
+	c.BackupCount = getIntValue(i, "Database", "backup_count", 7)
 	ctype := "Application Thread"
-	SaveRecord = getInt64Value(i, ctype, "save_record", 0)
-	SaveSize = getIntValue(i, ctype, "save_size", 1)
-	GetRange = getInt64Value(i, ctype, "get_range", 31*24*60*60)
-	if GetRange > time.Now().Unix() {
+	c.SaveRecord = getInt64Value(i, ctype, "save_record", 0)
+	c.SaveSize = getIntValue(i, ctype, "save_size", 1)
+	c.GetRange = getInt64Value(i, ctype, "get_range", 31*24*60*60)
+	if c.GetRange > time.Now().Unix() {
 		log.Fatal("get_range is too big")
 	}
-	SyncRange = getInt64Value(i, ctype, "sync_range", 10*24*60*60)
-	if SyncRange > time.Now().Unix() {
+	c.SyncRange = getInt64Value(i, ctype, "sync_range", 10*24*60*60)
+	if c.SyncRange > time.Now().Unix() {
 		log.Fatal("sync_range is too big")
 	}
-	SaveRemoved = getInt64Value(i, ctype, "save_removed", 50*24*60*60)
-	if SaveRemoved > time.Now().Unix() {
+	c.SaveRemoved = getInt64Value(i, ctype, "save_removed", 50*24*60*60)
+	if c.SaveRemoved > time.Now().Unix() {
 		log.Fatal("save_removed is too big")
 	}
 
-	if SyncRange == 0 {
-		SaveRecord = 0
+	if c.SyncRange == 0 {
+		c.SaveRecord = 0
 	}
 
-	if SaveRemoved != 0 && SaveRemoved <= SyncRange {
-		SyncRange = SyncRange + 1
+	if c.SaveRemoved != 0 && c.SaveRemoved <= c.SyncRange {
+		c.SyncRange = c.SyncRange + 1
 	}
 
 }
 
 //Motd returns path to motd.txt
-func Motd() string {
-	return FileDir + "/motd.txt"
+func (c *Config) Motd() string {
+	return c.FileDir + "/motd.txt"
 }
 
 //PID returns path to pid.txt
-func PID() string {
-	return RunDir + "/pid.txt"
+func (c *Config) PID() string {
+	return c.RunDir + "/pid.txt"
 }
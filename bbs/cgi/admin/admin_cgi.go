@@ -34,17 +34,18 @@ import (
 	"log"
 	"math/rand"
 	"net/http"
-	"regexp"
 	"runtime"
-	"sort"
 	"strconv"
 	"strings"
 	"time"
 	"errors"
 
+	"bbs/bundle"
 	"bbs/cfg"
 	"bbs/cgi"
-	"bbs/myself"
+	"bbs/db"
+	"bbs/export"
+	"bbs/fsck"
 	"bbs/node"
 	"bbs/node/manager"
 	"bbs/recentlist"
@@ -53,25 +54,31 @@ import (
 	"bbs/tag/suggest"
 	"bbs/tag/user"
 	"bbs/thread"
+	"bbs/updateque"
 	"bbs/util"
 )
 
-var adminSID = ""
-
 //Setup registers handlers for admin.cgi
-func Setup(s *cgi.LoggingServeMux) {
-	s.RegistCompressHandler(cfg.AdminURL+"/status", printStatus)
-	s.RegistCompressHandler(cfg.AdminURL+"/edittag", printEdittag)
-	s.RegistCompressHandler(cfg.AdminURL+"/savetag", saveTagCGI)
-	s.RegistCompressHandler(cfg.AdminURL+"/search", printSearch)
-	s.RegistCompressHandler(cfg.AdminURL+"/", execCmd)
+func Setup(s *cgi.LoggingServeMux, e *cgi.Env) {
+	s.RegistCompressHandler(cfg.AdminURL+"/status", e.Handle(printStatus))
+	s.RegistCompressHandler(cfg.AdminURL+"/edittag", e.Handle(printEdittag))
+	s.RegistCompressHandler(cfg.AdminURL+"/savetag", e.Handle(saveTagCGI))
+	s.RegistCompressHandler(cfg.AdminURL+"/search", e.Handle(printSearch))
+	s.RegistCompressHandler(cfg.AdminURL+"/moderation", e.Handle(printModeration))
+	s.RegistCompressHandler(cfg.AdminURL+"/updateque", e.Handle(printUpdateque))
+	s.RegistCompressHandler(cfg.AdminURL+"/delivery", e.Handle(printDelivery))
+	s.RegistCompressHandler(cfg.AdminURL+"/backup", e.Handle(printBackup))
+	s.RegistCompressHandler(cfg.AdminURL+"/fsck", e.Handle(printFsck))
+	s.RegistCompressHandler(cfg.AdminURL+"/bundle", e.Handle(printBundle))
+	s.RegistCompressHandler(cfg.AdminURL+"/export", e.Handle(printExport))
+	s.RegistCompressHandler(cfg.AdminURL+"/", e.Handle(execCmd))
 }
 
 //execCmd execute command specified cmd form.
 //i.e. confirmagion page for deleting rec/file(rdel/fdel) and for deleting.
 //(xrdel/xfdel)
-func execCmd(w http.ResponseWriter, r *http.Request) {
-	a, err := new(w, r)
+func execCmd(e *cgi.Env, w http.ResponseWriter, r *http.Request) {
+	a, err := new(e, w, r)
 	if err != nil {
 		log.Println(err)
 		return
@@ -92,37 +99,29 @@ func execCmd(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
-//printSearch renders the page for searching if query=""
-//or do query if query!=""
-func printSearch(w http.ResponseWriter, r *http.Request) {
-	a, err := new(w, r)
+//printSearch renders the page for searching,
+//and threads and records that match query if query!="".
+func printSearch(e *cgi.Env, w http.ResponseWriter, r *http.Request) {
+	a, err := new(e, w, r)
 	if err != nil {
 		log.Println(err)
 		return
 	}
-	query := a.Req.FormValue("query")
-	if query == "" {
-		a.Header(a.M["search"], "", nil, true)
-		fmt.Fprintf(a.WR, "<p>%s</p>", a.M["desc_search"])
-		a.printSearchForm("")
-		a.Footer(nil)
-	} else {
-		a.printSearchResult(query)
-	}
+	a.PrintSearch(cfg.AdminURL, a.Req.FormValue("query"))
 }
 
 //printStatus renders status info, including
 //#linknodes,#knownNodes,#files,#records,cacheSize,selfnode/linknodes/knownnodes
 // ip:port,
-func printStatus(w http.ResponseWriter, r *http.Request) {
-	a, err := new(w, r)
+func printStatus(e *cgi.Env, w http.ResponseWriter, r *http.Request) {
+	a, err := new(e, w, r)
 	if err != nil {
 		log.Println()
 		return
 	}
 	records := 0
 	var size int64
-	for _, ca := range thread.AllCaches() {
+	for _, ca := range thread.AllCaches(e.My) {
 		records += ca.Len(record.All)
 		size += ca.Size()
 	}
@@ -131,7 +130,7 @@ func printStatus(w http.ResponseWriter, r *http.Request) {
 	runtime.ReadMemStats(&mem)
 
 	var port0 string
-	switch myself.GetStatus() {
+	switch e.My.GetStatus() {
 	case cfg.Normal:
 		port0 = a.M["opened"]
 	case cfg.UPnP:
@@ -143,18 +142,18 @@ func printStatus(w http.ResponseWriter, r *http.Request) {
 	}
 
 	s := map[string]string{
-		"known_nodes":       strconv.Itoa(manager.NodeLen()),
-		"linked_nodes":      strconv.Itoa(manager.ListLen()),
-		"files":             strconv.Itoa(thread.Len()),
+		"known_nodes":       strconv.Itoa(manager.NodeLen(e.My)),
+		"linked_nodes":      strconv.Itoa(manager.ListLen(e.My)),
+		"files":             strconv.Itoa(thread.Len(e.My)),
 		"records":           strconv.Itoa(records),
 		"cache_size":        fmt.Sprintf("%.1f%s", float64(size)/1024/1024, a.M["mb"]),
-		"self_node":         node.Me(false).Nodestr,
+		"self_node":         node.Me(e.My, false).Nodestr,
 		"alloc_mem":         fmt.Sprintf("%.1f%s", float64(mem.Alloc)/1024/1024, a.M["mb"]),
 		"connection_status": port0,
 	}
 	ns := map[string][]string{
-		"known_nodes":  manager.GetNodestrSlice(),
-		"linked_nodes": manager.GetNodestrSliceInList(),
+		"known_nodes":  manager.GetNodestrSlice(e.My),
+		"linked_nodes": manager.GetNodestrSliceInList(e.My),
 	}
 
 	d := struct {
@@ -167,20 +166,446 @@ func printStatus(w http.ResponseWriter, r *http.Request) {
 		a.M,
 	}
 	a.Header(a.M["status"], "", nil, true)
-	cgi.RenderTemplate("status", d, a.WR)
+	cgi.RenderTemplate(e.My.Cfg.TemplateDir, "status", d, a.WR)
+	a.Footer(nil)
+}
+
+//printBackup sends a snapshot of the whole db as a file, without stopping the node.
+func printBackup(e *cgi.Env, w http.ResponseWriter, r *http.Request) {
+	a, err := new(e, w, r)
+	if err != nil {
+		log.Println(err)
+		return
+	}
+	fname := fmt.Sprintf("gou_bolt.db.%d.snapshot", time.Now().Unix())
+	a.WR.Header().Set("Content-Type", "application/octet-stream")
+	a.WR.Header().Set("Content-Disposition", "attachment; filename="+fname)
+	if err := db.WriteTo(a.My.DB, a.WR); err != nil {
+		log.Println(err)
+	}
+}
+
+//printFsck renders inconsistencies in the db,
+//and repairs them if requested with cheking sid.
+func printFsck(e *cgi.Env, w http.ResponseWriter, r *http.Request) {
+	a, err := new(e, w, r)
+	if err != nil {
+		log.Println(err)
+		return
+	}
+	repair := a.Req.FormValue("cmd") == "repair"
+	if repair && (a.Req.Method != "POST" || !a.checkSid()) {
+		a.Print404(nil, "")
+		return
+	}
+	ps, err := fsck.Check(e.My.DB, repair)
+	if err != nil {
+		log.Println(err)
+	}
+	d := struct {
+		Message  cgi.Message
+		AdminCGI string
+		Classes  []string
+		Count    map[string]int
+		Problems []*fsck.Problem
+		Repaired bool
+		Error    error
+		Sid      string
+	}{
+		a.M,
+		cfg.AdminURL,
+		fsck.Classes,
+		fsck.Count(ps),
+		ps,
+		repair,
+		err,
+		a.makeSid(),
+	}
+	a.Header(a.M["fsck"], "", nil, true)
+	cgi.RenderTemplate(e.My.Cfg.TemplateDir, "fsck", d, a.WR)
+	a.Footer(nil)
+}
+
+//printBundle renders forms for exporting and importing bundles for offline nodes.
+//it sends a bundle if cmd is "export", and imports the posted bundle
+//with cheking sid if cmd is "import".
+func printBundle(e *cgi.Env, w http.ResponseWriter, r *http.Request) {
+	a, err := new(e, w, r)
+	if err != nil {
+		log.Println(err)
+		return
+	}
+	var res *bundle.Result
+	switch a.Req.FormValue("cmd") {
+	case "export":
+		if err = a.exportBundle(); err == nil {
+			return
+		}
+	case "import":
+		if a.Req.Method != "POST" || !a.checkSid() {
+			a.Print404(nil, "")
+			return
+		}
+		res, err = a.importBundle()
+	}
+	if err != nil {
+		log.Println(err)
+	}
+	d := struct {
+		Message  cgi.Message
+		AdminCGI string
+		Result   *bundle.Result
+		Error    error
+		Sid      string
+	}{
+		a.M,
+		cfg.AdminURL,
+		res,
+		err,
+		a.makeSid(),
+	}
+	a.Header(a.M["bundle"], "", nil, true)
+	cgi.RenderTemplate(e.My.Cfg.TemplateDir, "bundle", d, a.WR)
+	a.Footer(nil)
+}
+
+//exportBundle sends records in threads whose titles are in form "thread" or which have
+//tag in form "tag", since form "since" as a bundle file.
+func (a *adminCGI) exportBundle() error {
+	since, err := bundle.ParseSince(strings.TrimSpace(a.Req.FormValue("since")))
+	if err != nil {
+		return err
+	}
+	f := &bundle.Filter{
+		Tag:   strings.TrimSpace(a.Req.FormValue("tag")),
+		Since: since,
+	}
+	for _, title := range strings.Split(a.Req.FormValue("thread"), "\n") {
+		if title = strings.TrimSpace(title); title != "" {
+			f.Threads = append(f.Threads, util.FileEncode("thread", title))
+		}
+	}
+	fname := fmt.Sprintf("gou_bundle.%d.gz", time.Now().Unix())
+	a.WR.Header().Set("Content-Type", "application/octet-stream")
+	a.WR.Header().Set("Content-Disposition", "attachment; filename="+fname)
+	if _, err := bundle.Export(a.My, a.WR, f); err != nil {
+		log.Println(err)
+	}
+	return nil
+}
+
+//importBundle imports the bundle file in form "bundle" and tells new records to other nodes.
+func (a *adminCGI) importBundle() (*bundle.Result, error) {
+	f, _, err := a.Req.FormFile("bundle")
+	if err != nil {
+		return nil, err
+	}
+	defer util.Fclose(f)
+	res, err := bundle.Import(a.My, f, a.Queue)
+	if err == nil && res.Queued > 0 {
+		go a.Queue.RetryDue()
+	}
+	return res, err
+}
+
+//printExport renders the form for exporting threads,
+//and sends the thread whose title is form "thread" or threads which have tag in form "tag"
+//in form "format" if requested.
+func printExport(e *cgi.Env, w http.ResponseWriter, r *http.Request) {
+	a, err := new(e, w, r)
+	if err != nil {
+		log.Println(err)
+		return
+	}
+	title := strings.TrimSpace(a.Req.FormValue("thread"))
+	tag := strings.TrimSpace(a.Req.FormValue("tag"))
+	format := a.Req.FormValue("format")
+	if (title != "" || tag != "") && util.HasString(export.Formats, format) {
+		if err = a.exportThreads(title, tag, format); err == nil {
+			return
+		}
+		log.Println(err)
+	}
+	d := struct {
+		Message  cgi.Message
+		AdminCGI string
+		Formats  []string
+		Error    error
+	}{
+		a.M,
+		cfg.AdminURL,
+		export.Formats,
+		err,
+	}
+	a.Header(a.M["export"], "", nil, true)
+	cgi.RenderTemplate(e.My.Cfg.TemplateDir, "export_form", d, a.WR)
+	a.Footer(nil)
+}
+
+//exportThreads sends the thread whose title is title, or threads which have tag as a zip file.
+//the file is streamed to the client, so errors after sending headers are only logged.
+func (a *adminCGI) exportThreads(title, tag, format string) error {
+	e := &export.Exporter{
+		My:     a.My,
+		Format: format,
+		Host:   a.Req.Host,
+		M:      a.M,
+	}
+	if tag != "" {
+		ds, err := e.TagThreads(tag)
+		if err != nil {
+			return err
+		}
+		a.WR.Header().Set("Content-Type", "application/zip")
+		a.WR.Header().Set("Content-Disposition", "attachment; filename=gou_export.zip")
+		if err := e.Zip(a.WR, ds); err != nil {
+			log.Println(err)
+		}
+		return nil
+	}
+	datfile := util.FileEncode("thread", title)
+	if !thread.NewCache(a.My, datfile).Exists() {
+		return errors.New("thread not found")
+	}
+	a.WR.Header().Set("Content-Type", e.ContentType())
+	a.WR.Header().Set("Content-Disposition", "attachment; filename="+datfile+"."+format)
+	if err := e.Thread(a.WR, datfile); err != nil {
+		log.Println(err)
+	}
+	return nil
+}
+
+//printModeration renders removals by trusted moderators,
+//and applies or dismisses the one in review queue specified by form "target" and "remover"
+//if requested.
+func printModeration(e *cgi.Env, w http.ResponseWriter, r *http.Request) {
+	a, err := new(e, w, r)
+	if err != nil {
+		log.Println(err)
+		return
+	}
+	if cmd := a.Req.FormValue("cmd"); cmd != "" {
+		a.doModeration(cmd)
+		return
+	}
+	rms, err := record.ModeratorRemovals(e.My)
+	if err != nil {
+		log.Println(err)
+	}
+	type removal struct {
+		*record.Removal
+		Title   string
+		ShortID string
+	}
+	removals := make([]removal, len(rms))
+	for i, rm := range rms {
+		id8 := rm.Target.ID
+		if len(id8) > 8 {
+			id8 = id8[:8]
+		}
+		removals[i] = removal{
+			rm,
+			util.FileDecode(rm.Target.Datfile),
+			id8,
+		}
+	}
+	d := struct {
+		Message   cgi.Message
+		AdminCGI  string
+		ThreadCGI string
+		Removals  []removal
+		Sid       string
+	}{
+		a.M,
+		cfg.AdminURL,
+		cfg.ThreadURL,
+		removals,
+		a.makeSid(),
+	}
+	a.Header(a.M["moderation"], "", nil, true)
+	cgi.RenderTemplate(e.My.Cfg.TemplateDir, "moderation", d, a.WR)
+	a.Footer(nil)
+}
+
+//doModeration applies or dismisses a removal in review queue with cheking sid,
+//and 302 to moderation page.
+func (a *adminCGI) doModeration(cmd string) {
+	if a.Req.Method != "POST" || !a.checkSid() {
+		a.Print404(nil, "")
+		return
+	}
+	datfile := a.Req.FormValue("file")
+	target, err := record.NewIDstr(a.My, datfile, a.Req.FormValue("target"))
+	if err != nil {
+		a.Print404(nil, "")
+		return
+	}
+	remover, err := record.NewIDstr(a.My, datfile, a.Req.FormValue("remover"))
+	if err != nil {
+		a.Print404(nil, "")
+		return
+	}
+	rm, err := record.GetRemoval(a.My, target.Head, remover.Head)
+	if err != nil {
+		log.Println(err)
+		a.Print404(nil, "")
+		return
+	}
+	switch cmd {
+	case "apply":
+		err = rm.Apply(a.My)
+	case "dismiss":
+		err = rm.Dismiss(a.My)
+	}
+	if err != nil {
+		log.Println(err)
+	}
+	a.Print302(cfg.AdminURL + "/moderation")
+}
+
+//printUpdateque renders updates waiting to be told or gotten in the update queue,
+//and retries or removes updates specified by form "item" if requested.
+func printUpdateque(e *cgi.Env, w http.ResponseWriter, r *http.Request) {
+	a, err := new(e, w, r)
+	if err != nil {
+		log.Println(err)
+		return
+	}
+	if cmd := a.Req.FormValue("cmd"); cmd != "" {
+		a.doUpdateque(cmd)
+		return
+	}
+	type item struct {
+		*updateque.Item
+		Title   string
+		ShortID string
+		IsFetch bool
+	}
+	its := e.Queue.Items()
+	items := make([]item, len(its))
+	for i, it := range its {
+		id8 := it.ID
+		if len(id8) > 8 {
+			id8 = id8[:8]
+		}
+		items[i] = item{
+			it,
+			util.FileDecode(it.Datfile),
+			id8,
+			it.Kind == updateque.Fetch,
+		}
+	}
+	d := struct {
+		Message   cgi.Message
+		AdminCGI  string
+		ThreadCGI string
+		Items     []item
+		Sid       string
+	}{
+		a.M,
+		cfg.AdminURL,
+		cfg.ThreadURL,
+		items,
+		a.makeSid(),
+	}
+	a.Header(a.M["updateque"], "", nil, true)
+	cgi.RenderTemplate(e.My.Cfg.TemplateDir, "updateque", d, a.WR)
+	a.Footer(nil)
+}
+
+//doUpdateque retries or removes updates in the update queue with cheking sid,
+//and 302 to update queue page.
+func (a *adminCGI) doUpdateque(cmd string) {
+	if a.Req.Method != "POST" || !a.checkSid() {
+		a.Print404(nil, "")
+		return
+	}
+	for _, key := range a.Req.Form["item"] {
+		it, err := a.Queue.Find(key)
+		if err != nil {
+			log.Println(err)
+			continue
+		}
+		switch cmd {
+		case "retry":
+			go a.Queue.Retry(it)
+		case "delete":
+			a.Queue.Remove(it)
+		}
+	}
+	a.Print302(cfg.AdminURL + "/updateque")
+}
+
+//printDelivery renders results of telling the update of the record specified by form "file" and "record"
+//to nodes, with scores of the nodes.
+func printDelivery(e *cgi.Env, w http.ResponseWriter, r *http.Request) {
+	a, err := new(e, w, r)
+	if err != nil {
+		log.Println(err)
+		return
+	}
+	datfile := a.Req.FormValue("file")
+	rec, err := record.NewIDstr(a.My, datfile, a.Req.FormValue("record"))
+	if err != nil || datfile == "" || rec.Stamp == 0 {
+		a.Print404(nil, "")
+		return
+	}
+	type delivery struct {
+		*manager.Delivery
+		*manager.Score
+		Percent int
+	}
+	ds := manager.Deliveries(a.My, datfile, rec.Stamp, rec.LegacyID())
+	ns := make([]string, len(ds))
+	for i, d := range ds {
+		ns[i] = d.Node
+	}
+	scores := manager.Scores(a.My, ns)
+	deliveries := make([]delivery, len(ds))
+	accepted := 0
+	for i, d := range ds {
+		s := scores[d.Node]
+		deliveries[i] = delivery{d, s, int(s.Rate*100 + 0.5)}
+		if d.Result == manager.DeliveryOK {
+			accepted++
+		}
+	}
+	id8 := rec.ID
+	if len(id8) > 8 {
+		id8 = id8[:8]
+	}
+	d := struct {
+		Message    cgi.Message
+		ThreadCGI  string
+		Title      string
+		ShortID    string
+		Stamp      int64
+		Deliveries []delivery
+		Accepted   int
+	}{
+		a.M,
+		cfg.ThreadURL,
+		util.FileDecode(datfile),
+		id8,
+		rec.Stamp,
+		deliveries,
+		accepted,
+	}
+	a.Header(a.M["delivery"], "", nil, true)
+	cgi.RenderTemplate(e.My.Cfg.TemplateDir, "delivery", d, a.WR)
 	a.Footer(nil)
 }
 
 //printEdittag renders the page for editing tags in thread specified by form "file".
-func printEdittag(w http.ResponseWriter, r *http.Request) {
-	a, err := new(w, r)
+func printEdittag(e *cgi.Env, w http.ResponseWriter, r *http.Request) {
+	a, err := new(e, w, r)
 	if err != nil {
 		log.Println(err)
 		return
 	}
 	datfile := a.Req.FormValue("file")
 	strTitle := util.FileDecode(datfile)
-	ca := thread.NewCache(datfile)
+	ca := thread.NewCache(e.My, datfile)
 	datfile = html.EscapeString(datfile)
 
 	if !ca.Exists() {
@@ -198,18 +623,18 @@ func printEdittag(w http.ResponseWriter, r *http.Request) {
 		a.M,
 		cfg.AdminURL,
 		datfile,
-		user.String(ca.Datfile),
-		suggest.Get(ca.Datfile, nil),
-		user.GetByThread(ca.Datfile),
+		user.String(e.My.DB, ca.Datfile),
+		suggest.Get(e.My, ca.Datfile, nil),
+		user.GetByThread(e.My.DB, ca.Datfile),
 	}
 	a.Header(fmt.Sprintf("%s: %s", a.M["edit_tag"], strTitle), "", nil, true)
-	cgi.RenderTemplate("edit_tag", d, a.WR)
+	cgi.RenderTemplate(e.My.Cfg.TemplateDir, "edit_tag", d, a.WR)
 	a.Footer(nil)
 }
 
 //saveTagCGI saves edited tags of file and render this file with 302.
-func saveTagCGI(w http.ResponseWriter, r *http.Request) {
-	a, err := new(w, r)
+func saveTagCGI(e *cgi.Env, w http.ResponseWriter, r *http.Request) {
+	a, err := new(e, w, r)
 	if err != nil {
 		log.Println(err)
 		return
@@ -219,12 +644,12 @@ func saveTagCGI(w http.ResponseWriter, r *http.Request) {
 	if datfile == "" {
 		return
 	}
-	ca := thread.NewCache(datfile)
+	ca := thread.NewCache(e.My, datfile)
 	if !ca.Exists() {
 		a.Print404(nil, "")
 	}
 	tl := strings.Fields(tags)
-	user.Set(datfile, tl)
+	user.Set(e.My.DB, datfile, tl)
 	var next string
 	title := util.StrEncode(util.FileDecode(datfile))
 	if strings.HasPrefix(datfile, "thread_") {
@@ -242,8 +667,8 @@ type adminCGI struct {
 
 //new returns adminCGI obj if client is admin.
 //if not render 403.
-func new(w http.ResponseWriter, r *http.Request) (*adminCGI, error) {
-	c, err := cgi.NewCGI(w, r)
+func new(e *cgi.Env, w http.ResponseWriter, r *http.Request) (*adminCGI, error) {
+	c, err := cgi.NewCGI(e, w, r)
 	if err != nil {
 		return nil, err
 	}
@@ -263,16 +688,14 @@ func (a *adminCGI) makeSid() string {
 	for i := 0; i < 4; i++ {
 		r += strconv.Itoa(rand.Int())
 	}
-	adminSID = util.MD5digest(r)
-	return adminSID
+	sid := util.MD5digest(r)
+	a.SetSID(sid)
+	return sid
 }
 
 //checkSid returns true if form value of "sid" == saved sid.
 func (a *adminCGI) checkSid() bool {
-	sid := a.Req.FormValue("sid")
-	r := (adminSID != "" && adminSID == sid)
-	adminSID = ""
-	return r
+	return a.CheckSID(a.Req.FormValue("sid"))
 }
 
 //DeleteRecord is for renderring confirmation to a delete record.
@@ -296,7 +719,7 @@ func (a *adminCGI) printDeleteRecord(rmFiles []string, records []string) {
 	recs := make([]*record.Record, len(records))
 	var err error
 	for i, v := range records {
-		recs[i], err = record.NewIDstr(datfile, v)
+		recs[i], err = record.NewIDstr(a.My, datfile, v)
 		if err != nil {
 			log.Println(err)
 		}
@@ -309,7 +732,7 @@ func (a *adminCGI) printDeleteRecord(rmFiles []string, records []string) {
 		sid,
 	}
 	a.Header(a.M["del_record"], "", nil, true)
-	cgi.RenderTemplate("delete_record", d, a.WR)
+	cgi.RenderTemplate(a.My.Cfg.TemplateDir, "delete_record", d, a.WR)
 	a.Footer(nil)
 }
 
@@ -330,9 +753,9 @@ func (a *adminCGI) doDeleteRecord(rmFiles []string, records []string, dopost str
 	if strings.HasPrefix(title, "thread_") {
 		next = cfg.ThreadURL + "/" + title
 	}
-	ca := thread.NewCache(datfile)
+	ca := thread.NewCache(a.My, datfile)
 	for _, r := range records {
-		rec, err := record.NewIDstr(datfile, r)
+		rec, err := record.NewIDstr(a.My, datfile, r)
 		if err != nil || rec.Remove() == nil && dopost != "" {
 			a.postDeleteMessage(ca, rec)
 			a.Print302(next)
@@ -353,12 +776,12 @@ func (a *adminCGI) postDeleteMessage(ca *thread.Cache, rec *record.Record) {
 		}
 	}
 	body["remove_stamp"] = strconv.FormatInt(rec.Stamp, 10)
-	body["remove_id"] = rec.ID
+	body["remove_id"] = rec.LegacyID()
 	passwd := a.Req.FormValue("passwd")
-	id := rec.Build(stamp, body, passwd)
+	rec.Build(stamp, body, passwd)
 	rec.Sync()
-	recentlist.Append(rec.Head)
-	go manager.TellUpdate(ca.Datfile, stamp, id, nil)
+	recentlist.Append(a.My, rec.LegacyHead())
+	go manager.TellUpdate(a.My, ca.Datfile, stamp, rec.LegacyID(), nil)
 }
 
 //printDeleteFile renders the page for confirmation of deleting file.
@@ -369,7 +792,7 @@ func (a *adminCGI) printDeleteFile(files []string) {
 	sid := a.makeSid()
 	cas := make([]*thread.Cache, len(files))
 	for i, v := range files {
-		cas[i] = thread.NewCache(v)
+		cas[i] = thread.NewCache(a.My, v)
 	}
 	d := struct {
 		Message  cgi.Message
@@ -383,7 +806,7 @@ func (a *adminCGI) printDeleteFile(files []string) {
 		sid,
 	}
 	a.Header(a.M["del_file"], "", nil, true)
-	cgi.RenderTemplate("delete_file", d, a.WR)
+	cgi.RenderTemplate(a.My.Cfg.TemplateDir, "delete_file", d, a.WR)
 	a.Footer(nil)
 }
 
@@ -397,48 +820,8 @@ func (a *adminCGI) doDeleteFile(files []string) {
 	}
 
 	for _, c := range files {
-		ca := thread.NewCache(c)
+		ca := thread.NewCache(a.My, c)
 		ca.Remove()
 	}
 	a.Print302(cfg.GatewayURL + "/" + "changes")
 }
-
-//printSearchForm renders search_form.txt
-func (a *adminCGI) printSearchForm(query string) {
-	d := struct {
-		Query    string
-		AdminCGI string
-		Message  cgi.Message
-	}{
-		query,
-		cfg.AdminURL,
-		a.M,
-	}
-	cgi.RenderTemplate("search_form", d, a.WR)
-}
-
-//printSearchResult renders cachelist that its datfile matches query.
-func (a *adminCGI) printSearchResult(query string) {
-	strQuery := html.EscapeString(query)
-	title := fmt.Sprintf("%s: %s", a.M["search"], strQuery)
-	a.Header(title, "", nil, true)
-	fmt.Fprintf(a.WR, "<p>%s</p>", a.M["desc_search"])
-	a.printSearchForm(strQuery)
-	reg := html.EscapeString(query)
-	regg, err := regexp.Compile(reg)
-	if err != nil {
-		log.Println(err)
-		return
-	}
-	result := thread.Search(reg)
-	for _, i := range thread.AllCaches() {
-		if result.Has(i) {
-			continue
-		}
-		if regg.MatchString(util.FileDecode(i.Datfile)) {
-			result = append(result, i)
-		}
-	}
-	sort.Sort(sort.Reverse(thread.NewSortByStamp(result, false)))
-	a.PrintIndexList(result, "", true, false, "", "")
-}
@@ -30,6 +30,7 @@ package cgi
 
 import (
 	"fmt"
+	"html"
 	"html/template"
 	"log"
 	"net/http"
@@ -37,14 +38,18 @@ import (
 	"regexp"
 	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/russross/blackfriday"
 	"bbs/cfg"
+	"bbs/myself"
+	"bbs/search"
 	"bbs/tag"
 	"bbs/tag/suggest"
 	"bbs/tag/user"
 	"bbs/thread"
+	"bbs/updateque"
 	"bbs/util"
 )
 
@@ -64,7 +69,7 @@ type ListItem struct {
 }
 
 //checkCache checks cache ca has specified tag and datfile doesn't contains filterd string.
-func checkCache(ca *thread.Cache, target, filter, tag string) (string, bool) {
+func checkCache(my *myself.Myself, ca *thread.Cache, target, filter, tag string) (string, bool) {
 	x := util.FileDecode(ca.Datfile)
 	if x == "" {
 		return "", false
@@ -74,8 +79,8 @@ func checkCache(ca *thread.Cache, target, filter, tag string) (string, bool) {
 	}
 	if tag != "" {
 		switch {
-		case user.Has(ca.Datfile, strings.ToLower(tag)):
-		case target == "recent" && suggest.HasTagstr(ca.Datfile, strings.ToLower(tag)):
+		case user.Has(my.DB, ca.Datfile, strings.ToLower(tag)):
+		case target == "recent" && suggest.HasTagstr(my, ca.Datfile, strings.ToLower(tag)):
 		default:
 			return "", false
 		}
@@ -84,14 +89,14 @@ func checkCache(ca *thread.Cache, target, filter, tag string) (string, bool) {
 }
 
 //NewListItem returns ListItem struct from caches.
-func NewListItem(caches []*thread.Cache, remove bool, target string, search bool, filter, tag string) *ListItem {
+func NewListItem(my *myself.Myself, caches []*thread.Cache, remove bool, target string, search bool, filter, tag string) *ListItem {
 	li := &ListItem{Remove: remove}
 	li.Caches = make([]*CacheInfo, 0, len(caches))
 	if search {
 		li.StrOpts = "?search_new_file=yes"
 	}
 	for _, ca := range caches {
-		x, ok := checkCache(ca, target, filter, tag)
+		x, ok := checkCache(my, ca, target, filter, tag)
 		if !ok {
 			continue
 		}
@@ -99,17 +104,17 @@ func NewListItem(caches []*thread.Cache, remove bool, target string, search bool
 		li.Caches = append(li.Caches, ci)
 		ci.Title = util.EscapeSpace(x)
 		if target == "recent" {
-			strTags := make([]string, user.Len(ca.Datfile))
-			for i, v := range user.GetByThread(ca.Datfile) {
+			strTags := make([]string, user.Len(my.DB, ca.Datfile))
+			for i, v := range user.GetByThread(my.DB, ca.Datfile) {
 				strTags[i] = strings.ToLower(v.Tagstr)
 			}
-			for _, st := range suggest.Get(ca.Datfile, nil) {
+			for _, st := range suggest.Get(my, ca.Datfile, nil) {
 				if !util.HasString(strTags, strings.ToLower(st.Tagstr)) {
 					ci.Sugtags = append(ci.Sugtags, st)
 				}
 			}
 		}
-		ci.Tags = user.GetByThread(ca.Datfile)
+		ci.Tags = user.GetByThread(my.DB, ca.Datfile)
 	}
 	return li
 }
@@ -134,8 +139,41 @@ type Defaults struct {
 	EmptyList   template.HTML
 }
 
+//Env is the node which http handlers serve, with its update queue.
+type Env struct {
+	My    *myself.Myself
+	Queue *updateque.Queue
+
+	sid      string //session id of the admin command waiting for confirmation
+	sidMutex sync.Mutex
+}
+
+//SetSID saves sid as the session id of the admin command.
+func (e *Env) SetSID(sid string) {
+	e.sidMutex.Lock()
+	e.sid = sid
+	e.sidMutex.Unlock()
+}
+
+//CheckSID returns true if sid is the saved session id, and clears it.
+func (e *Env) CheckSID(sid string) bool {
+	e.sidMutex.Lock()
+	defer e.sidMutex.Unlock()
+	ok := e.sid != "" && e.sid == sid
+	e.sid = ""
+	return ok
+}
+
+//Handle returns a http handler which calls fn with e.
+func (e *Env) Handle(fn func(*Env, http.ResponseWriter, *http.Request)) func(http.ResponseWriter, *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		fn(e, w, r)
+	}
+}
+
 //CGI is a base class for all http handlers.
 type CGI struct {
+	*Env
 	M        Message
 	JC       *jsCache
 	Req      *http.Request
@@ -145,11 +183,12 @@ type CGI struct {
 
 //NewCGI reads messages file, and set params , returns CGI obj.
 //CGI obj is cached.
-func NewCGI(w http.ResponseWriter, r *http.Request) (*CGI, error) {
+func NewCGI(e *Env, w http.ResponseWriter, r *http.Request) (*CGI, error) {
 	c := &CGI{
-		JC:  newJsCache(cfg.Docroot),
+		Env: e,
+		JC:  newJsCache(e.My.Cfg.Docroot),
 		WR:  w,
-		M:   SearchMessage(r.Header.Get("Accept-Language"), cfg.FileDir),
+		M:   SearchMessage(r.Header.Get("Accept-Language"), e.My.Cfg.FileDir),
 		Req: r,
 	}
 	err := r.ParseForm()
@@ -186,7 +225,7 @@ func (c *CGI) Defaults() *Defaults {
 
 //Host returns servername or host in http header.
 func (c *CGI) Host() string {
-	host := cfg.ServerName
+	host := c.My.Cfg.ServerName
 	if host == "" {
 		host = c.Req.Host
 	}
@@ -195,7 +234,7 @@ func (c *CGI) Host() string {
 
 //IsAdmin returns tur if matches admin regexp setted in config file.
 func (c *CGI) IsAdmin() bool {
-	m, err := regexp.MatchString(cfg.ReAdminStr, c.Req.RemoteAddr)
+	m, err := regexp.MatchString(c.My.Cfg.ReAdminStr, c.Req.RemoteAddr)
 	if err != nil {
 		log.Fatal(err)
 	}
@@ -204,7 +243,7 @@ func (c *CGI) IsAdmin() bool {
 
 //IsFriend returns tur if matches friend regexp setted in config file.
 func (c *CGI) IsFriend() bool {
-	m, err := regexp.MatchString(cfg.ReFriendStr, c.Req.RemoteAddr)
+	m, err := regexp.MatchString(c.My.Cfg.ReFriendStr, c.Req.RemoteAddr)
 	if err != nil {
 		log.Fatal(err)
 	}
@@ -213,7 +252,7 @@ func (c *CGI) IsFriend() bool {
 
 //isVisitor returns tur if matches visitor regexp setted in config file.
 func (c *CGI) isVisitor() bool {
-	m, err := regexp.MatchString(cfg.ReVisitorStr, c.Req.RemoteAddr)
+	m, err := regexp.MatchString(c.My.Cfg.ReVisitorStr, c.Req.RemoteAddr)
 	if err != nil {
 		log.Fatal(err)
 	}
@@ -246,8 +285,8 @@ func (c *CGI) extension(suffix string) []string {
 			filename = append(filename, fname)
 		}
 	}
-	if util.IsDir(cfg.Docroot) {
-		err = util.EachFiles(cfg.Docroot, func(f os.FileInfo) error {
+	if util.IsDir(c.My.Cfg.Docroot) {
+		err = util.EachFiles(c.My.Cfg.Docroot, func(f os.FileInfo) error {
 			i := f.Name()
 			if util.HasExt(i, suffix) {
 				if !util.HasString(filename, i) {
@@ -273,7 +312,7 @@ func (c *CGI) Footer(menubar *Menubar) {
 		menubar,
 		cfg.Version,
 	}
-	RenderTemplate("footer", g, c.WR)
+	RenderTemplate(c.My.Cfg.TemplateDir, "footer", g, c.WR)
 }
 
 //RFC822Time convers stamp to "2006-01-02 15:04:05"
@@ -339,7 +378,7 @@ func (c *CGI) Header(title, rss string, cookie []*http.Cookie, denyRobot bool) {
 			http.SetCookie(c.WR, co)
 		}
 	}
-	RenderTemplate("header", h, c.WR)
+	RenderTemplate(c.My.Cfg.TemplateDir, "header", h, c.WR)
 }
 
 //ResAnchor returns a href  string with url.
@@ -367,7 +406,7 @@ func (c *CGI) HTMLFormat(plain, appli string, title string, absuri bool) string
 
 	buf = util.Escape(buf)
 	regLink := regexp.MustCompile(`https?://[^\x00-\x20"'\(\)<>\[\]\x7F-\xFF]{2,}`)
-	if cfg.EnableEmbed {
+	if c.My.Cfg.EnableEmbed {
 		var strs []string
 		for _, str := range strings.Split(buf, "<br>") {
 			s := regLink.ReplaceAllString(str, `<a href="$0">$0</a>`)
@@ -447,7 +486,7 @@ func (c *CGI) RemoveFileForm(ca *thread.Cache, title string) {
 		title,
 		*c.Defaults(),
 	}
-	RenderTemplate("remove_file_form", s, c.WR)
+	RenderTemplate(c.My.Cfg.TemplateDir, "remove_file_form", s, c.WR)
 }
 
 //printJump render jump (redirect)page.
@@ -457,7 +496,7 @@ func (c *CGI) printJump(next string) {
 	}{
 		template.HTML(next),
 	}
-	RenderTemplate("jump", s, c.WR)
+	RenderTemplate(c.My.Cfg.TemplateDir, "jump", s, c.WR)
 }
 
 //Print302 renders jump page(meaning found and redirect)
@@ -509,18 +548,54 @@ func (c *CGI) PrintIndexList(cl []*thread.Cache, target string, footer bool, sea
 		target,
 		filter,
 		tagg,
-		user.Get(),
+		user.Get(c.My.DB),
 		len(cl) == 0,
 		*c.Defaults(),
-		*NewListItem(cl, true, target, searchNewFile, filter, tagg),
+		*NewListItem(c.My, cl, true, target, searchNewFile, filter, tagg),
 	}
-	RenderTemplate("index_list", s, c.WR)
+	RenderTemplate(c.My.Cfg.TemplateDir, "index_list", s, c.WR)
 	if footer {
 		c.PrintNewElementForm()
 		c.Footer(nil)
 	}
 }
 
+//PrintSearch renders the search form and threads and records that match query if query!="".
+//action is the url of cgi which handles "/search".
+//only admin can search by queries which need scanning all records.
+func (c *CGI) PrintSearch(action, query string) {
+	title := c.M["search"]
+	if query != "" {
+		title = fmt.Sprintf("%s: %s", c.M["search"], html.EscapeString(query))
+	}
+	c.Header(title, "", nil, true)
+	fmt.Fprintf(c.WR, "<p>%s</p>", c.M["desc_search"])
+	d := struct {
+		Query     string
+		Action    string
+		AdminCGI  string
+		ThreadCGI string
+		Results   []*search.Result
+		Error     string
+		Message   Message
+	}{
+		Query:     query,
+		Action:    action,
+		AdminCGI:  cfg.AdminURL,
+		ThreadCGI: cfg.ThreadURL,
+		Message:   c.M,
+	}
+	RenderTemplate(c.My.Cfg.TemplateDir, "search_form", d, c.WR)
+	if query != "" {
+		var err error
+		if d.Results, err = search.Run(c.My, query, c.IsAdmin()); err != nil {
+			d.Error = err.Error()
+		}
+		RenderTemplate(c.My.Cfg.TemplateDir, "search_result", d, c.WR)
+	}
+	c.Footer(nil)
+}
+
 //PrintNewElementForm renders new_element_form.txt for posting new thread.
 func (c *CGI) PrintNewElementForm() {
 	const titleLimit = 30 //Charactors
@@ -537,7 +612,7 @@ func (c *CGI) PrintNewElementForm() {
 		titleLimit,
 		*c.Defaults(),
 	}
-	RenderTemplate("new_element_form", s, c.WR)
+	RenderTemplate(c.My.Cfg.TemplateDir, "new_element_form", s, c.WR)
 }
 
 //IsBot returns true if client is bot.
@@ -36,6 +36,7 @@ import (
 	"html"
 	"io/ioutil"
 	"log"
+	"math"
 	"net/http"
 	"regexp"
 	"sort"
@@ -56,41 +57,42 @@ import (
 const xslURL = "/rss1.xsl"
 
 //Setup setups handlers for gateway.cgi
-func Setup(s *cgi.LoggingServeMux) {
-	s.RegistCompressHandler(cfg.GatewayURL+"/motd", printMotd)
-	s.RegistCompressHandler(cfg.GatewayURL+"/mergedjs", printMergedJS)
-	s.RegistCompressHandler(cfg.GatewayURL+"/rss", printRSS)
-	s.RegistCompressHandler(cfg.GatewayURL+"/recent_rss", printRecentRSS)
-	s.RegistCompressHandler(cfg.GatewayURL+"/index", printGatewayIndex)
-	s.RegistCompressHandler(cfg.GatewayURL+"/changes", printIndexChanges)
-	s.RegistCompressHandler(cfg.GatewayURL+"/recent", printRecent)
-	s.RegistCompressHandler(cfg.GatewayURL+"/new", printNew)
-	s.RegistCompressHandler(cfg.GatewayURL+"/thread", printGatewayThread)
-	s.RegistCompressHandler(cfg.GatewayURL+"/", PrintTitle)
-	s.RegistCompressHandler(cfg.GatewayURL+"/csv/index/", printCSV)
-	s.RegistCompressHandler(cfg.GatewayURL+"/csv/changes/", printCSVChanges)
-	s.RegistCompressHandler(cfg.GatewayURL+"/csv/recent/", printCSVRecent)
+func Setup(s *cgi.LoggingServeMux, e *cgi.Env) {
+	s.RegistCompressHandler(cfg.GatewayURL+"/motd", e.Handle(printMotd))
+	s.RegistCompressHandler(cfg.GatewayURL+"/mergedjs", e.Handle(printMergedJS))
+	s.RegistCompressHandler(cfg.GatewayURL+"/rss", e.Handle(printRSS))
+	s.RegistCompressHandler(cfg.GatewayURL+"/recent_rss", e.Handle(printRecentRSS))
+	s.RegistCompressHandler(cfg.GatewayURL+"/index", e.Handle(printGatewayIndex))
+	s.RegistCompressHandler(cfg.GatewayURL+"/changes", e.Handle(printIndexChanges))
+	s.RegistCompressHandler(cfg.GatewayURL+"/recent", e.Handle(printRecent))
+	s.RegistCompressHandler(cfg.GatewayURL+"/new", e.Handle(printNew))
+	s.RegistCompressHandler(cfg.GatewayURL+"/thread", e.Handle(printGatewayThread))
+	s.RegistCompressHandler(cfg.GatewayURL+"/search", e.Handle(printSearch))
+	s.RegistCompressHandler(cfg.GatewayURL+"/", e.Handle(PrintTitle))
+	s.RegistCompressHandler(cfg.GatewayURL+"/csv/index/", e.Handle(printCSV))
+	s.RegistCompressHandler(cfg.GatewayURL+"/csv/changes/", e.Handle(printCSVChanges))
+	s.RegistCompressHandler(cfg.GatewayURL+"/csv/recent/", e.Handle(printCSVRecent))
 }
 
 //printGateway just redirects to correspoinding url using thread.cgi.
 //or renders only title.
-func printGatewayThread(w http.ResponseWriter, r *http.Request) {
+func printGatewayThread(e *cgi.Env, w http.ResponseWriter, r *http.Request) {
 	reg := regexp.MustCompile("^/gateway.cgi/(thread)/?([^/]*)$")
 	m := reg.FindStringSubmatch(r.URL.Path)
 	var uri string
 	switch {
 	case m == nil:
-		PrintTitle(w, r)
+		PrintTitle(e, w, r)
 		return
 	case m[2] != "":
 		uri = cfg.ThreadURL + "/" + util.StrEncode(m[2])
 	case r.URL.RawQuery != "":
 		uri = cfg.ThreadURL + "/" + r.URL.RawQuery
 	default:
-		PrintTitle(w, r)
+		PrintTitle(e, w, r)
 		return
 	}
-	g, err := new(w, r)
+	g, err := new(e, w, r)
 	if err != nil {
 		log.Println(err)
 		return
@@ -98,31 +100,42 @@ func printGatewayThread(w http.ResponseWriter, r *http.Request) {
 	g.Print302(uri)
 }
 
+//printSearch renders the page for searching,
+//and threads and records that match query if query!="".
+func printSearch(e *cgi.Env, w http.ResponseWriter, r *http.Request) {
+	g, err := new(e, w, r)
+	if err != nil {
+		log.Println(err)
+		return
+	}
+	g.PrintSearch(cfg.GatewayURL, g.Req.FormValue("query"))
+}
+
 //printCSV renders csv of caches saved in disk.
-func printCSV(w http.ResponseWriter, r *http.Request) {
-	g, err := new(w, r)
+func printCSV(e *cgi.Env, w http.ResponseWriter, r *http.Request) {
+	g, err := new(e, w, r)
 	if err != nil {
 		log.Println(err)
 		return
 	}
-	g.renderCSV(thread.AllCaches())
+	g.renderCSV(thread.AllCaches(e.My))
 }
 
 //printCSVChanges renders csv of caches which changes recently and are in disk(validstamp is newer).
-func printCSVChanges(w http.ResponseWriter, r *http.Request) {
-	g, err := new(w, r)
+func printCSVChanges(e *cgi.Env, w http.ResponseWriter, r *http.Request) {
+	g, err := new(e, w, r)
 	if err != nil {
 		log.Println(err)
 		return
 	}
-	all := thread.AllCaches()
+	all := thread.AllCaches(e.My)
 	sort.Sort(sort.Reverse(thread.NewSortByStamp(all, false)))
 	g.renderCSV(all)
 }
 
 //printCSVRecent renders csv of caches which are written recently(are updated remotely).
-func printCSVRecent(w http.ResponseWriter, r *http.Request) {
-	g, err := new(w, r)
+func printCSVRecent(e *cgi.Env, w http.ResponseWriter, r *http.Request) {
+	g, err := new(e, w, r)
 	if err != nil {
 		log.Println(err)
 		return
@@ -131,14 +144,14 @@ func printCSVRecent(w http.ResponseWriter, r *http.Request) {
 		g.Print403()
 		return
 	}
-	cl := thread.MakeRecentCachelist()
+	cl := thread.MakeRecentCachelist(e.My)
 	g.renderCSV(cl)
 }
 
 //printRecentRSS renders rss of caches which are written recently(are updated remotely).
 //including title,tags,last-modified.
-func printRecentRSS(w http.ResponseWriter, r *http.Request) {
-	g, err := new(w, r)
+func printRecentRSS(e *cgi.Env, w http.ResponseWriter, r *http.Request) {
+	g, err := new(e, w, r)
 	if err != nil {
 		log.Println(err)
 		return
@@ -146,11 +159,11 @@ func printRecentRSS(w http.ResponseWriter, r *http.Request) {
 	rsss := cgi.NewRSS("UTF-8", "", fmt.Sprintf("%s - %s", g.M["recent"], g.M["logo"]),
 		"http://"+g.Host(), "",
 		"http://"+g.Host()+cfg.GatewayURL+"/"+"recent_rss", g.M["description"], xslURL)
-	cl := thread.MakeRecentCachelist()
+	cl := thread.MakeRecentCachelist(e.My)
 	for _, ca := range cl {
 		title := util.Escape(util.FileDecode(ca.Datfile))
-		tags := suggest.Get(ca.Datfile, nil)
-		tags = append(tags, user.GetByThread(ca.Datfile)...)
+		tags := suggest.Get(e.My, ca.Datfile, nil)
+		tags = append(tags, user.GetByThread(e.My.DB, ca.Datfile)...)
 		rsss.Append(cfg.ThreadURL[1:]+"/"+util.StrEncode(title),
 			title, "", "", html.EscapeString(title), tags.GetTagstrSlice(),
 			ca.RecentStamp(), false)
@@ -159,31 +172,31 @@ func printRecentRSS(w http.ResponseWriter, r *http.Request) {
 	if rsss.Len() != 0 {
 		g.WR.Header().Set("Last-Modified", g.RFC822Time(rsss.Feeds[0].Date))
 	}
-	rsss.MakeRSS1(g.WR)
+	rsss.MakeRSS1(g.My.Cfg.TemplateDir, g.WR)
 }
 
 //printRSS reneders rss including newer records.
-func printRSS(w http.ResponseWriter, r *http.Request) {
-	g, err := new(w, r)
+func printRSS(e *cgi.Env, w http.ResponseWriter, r *http.Request) {
+	g, err := new(e, w, r)
 	if err != nil {
 		log.Println(err)
 		return
 	}
 	rsss := cgi.NewRSS("UTF-8", "", g.M["logo"], "http://"+g.Host(), "",
 		"http://"+g.Host()+cfg.GatewayURL+"/"+"rss", g.M["description"], xslURL)
-	for _, ca := range thread.AllCaches() {
+	for _, ca := range thread.AllCaches(e.My) {
 		g.appendRSS(rsss, ca)
 	}
 	g.WR.Header().Set("Content-Type", "text/xml; charset=UTF-8")
 	if rsss.Len() != 0 {
 		g.WR.Header().Set("Last-Modified", g.RFC822Time(rsss.Feeds[0].Date))
 	}
-	rsss.MakeRSS1(g.WR)
+	rsss.MakeRSS1(g.My.Cfg.TemplateDir, g.WR)
 }
 
 //printMergedJS renders merged js with stamp.
-func printMergedJS(w http.ResponseWriter, r *http.Request) {
-	g, err := new(w, r)
+func printMergedJS(e *cgi.Env, w http.ResponseWriter, r *http.Request) {
+	g, err := new(e, w, r)
 	if err != nil {
 		log.Println(err)
 		return
@@ -198,15 +211,15 @@ func printMergedJS(w http.ResponseWriter, r *http.Request) {
 }
 
 //printMotd renders motd.
-func printMotd(w http.ResponseWriter, r *http.Request) {
-	g, err := new(w, r)
+func printMotd(e *cgi.Env, w http.ResponseWriter, r *http.Request) {
+	g, err := new(e, w, r)
 	if err != nil {
 		log.Println(err)
 		return
 	}
 
 	g.WR.Header().Set("Content-Type", "text/plain; charset=UTF-8")
-	c, err := ioutil.ReadFile(cfg.Motd())
+	c, err := ioutil.ReadFile(e.My.Cfg.Motd())
 	if err != nil {
 		log.Println(err)
 		return
@@ -218,8 +231,8 @@ func printMotd(w http.ResponseWriter, r *http.Request) {
 }
 
 //printNew renders the page for making new thread.
-func printNew(w http.ResponseWriter, r *http.Request) {
-	g, err := new(w, r)
+func printNew(e *cgi.Env, w http.ResponseWriter, r *http.Request) {
+	g, err := new(e, w, r)
 	if err != nil {
 		log.Println(err)
 		return
@@ -231,8 +244,8 @@ func printNew(w http.ResponseWriter, r *http.Request) {
 }
 
 //PrintTitle renders list of newer thread in the disk for the top page
-func PrintTitle(w http.ResponseWriter, r *http.Request) {
-	g, err := new(w, r)
+func PrintTitle(e *cgi.Env, w http.ResponseWriter, r *http.Request) {
+	g, err := new(e, w, r)
 	if err != nil {
 		log.Println(err)
 		return
@@ -241,11 +254,11 @@ func PrintTitle(w http.ResponseWriter, r *http.Request) {
 		g.jumpNewFile()
 		return
 	}
-	all := thread.AllCaches()
+	all := thread.AllCaches(e.My)
 	sort.Sort(sort.Reverse(thread.NewSortByStamp(all, false)))
-	outputCachelist := make([]*thread.Cache, 0, thread.Len())
+	outputCachelist := make([]*thread.Cache, 0, thread.Len(e.My))
 	for _, ca := range all {
-		if time.Now().Unix() <= ca.Stamp()+cfg.TopRecentRange {
+		if time.Now().Unix() <= ca.Stamp()+e.My.Cfg.TopRecentRange {
 			outputCachelist = append(outputCachelist, ca)
 		}
 	}
@@ -262,22 +275,22 @@ func PrintTitle(w http.ResponseWriter, r *http.Request) {
 		cgi.Defaults
 	}{
 		"changes",
-		user.Get(),
+		user.Get(e.My.DB),
 		g.mchURL(""),
 		g.mchCategories(),
 		"thread",
 		len(outputCachelist) == 0,
-		*cgi.NewListItem(outputCachelist, false, "changes", false, g.Filter, g.Tag),
+		*cgi.NewListItem(e.My, outputCachelist, false, "changes", false, g.Filter, g.Tag),
 		*g.Defaults(),
 	}
-	cgi.RenderTemplate("top", s, g.WR)
+	cgi.RenderTemplate(e.My.Cfg.TemplateDir, "top", s, g.WR)
 	g.PrintNewElementForm()
 	g.Footer(nil)
 }
 
 //printGatewayIndex renders list of new threads in the disk.
-func printGatewayIndex(w http.ResponseWriter, r *http.Request) {
-	g, err := new(w, r)
+func printGatewayIndex(e *cgi.Env, w http.ResponseWriter, r *http.Request) {
+	g, err := new(e, w, r)
 	if err != nil {
 		log.Println(err)
 		return
@@ -286,8 +299,8 @@ func printGatewayIndex(w http.ResponseWriter, r *http.Request) {
 }
 
 //printIndexChanges renders list of new threads in the disk sorted by velocity.
-func printIndexChanges(w http.ResponseWriter, r *http.Request) {
-	g, err := new(w, r)
+func printIndexChanges(e *cgi.Env, w http.ResponseWriter, r *http.Request) {
+	g, err := new(e, w, r)
 	if err != nil {
 		log.Println(err)
 		return
@@ -296,8 +309,8 @@ func printIndexChanges(w http.ResponseWriter, r *http.Request) {
 }
 
 //printRecent renders cache in recentlist.
-func printRecent(w http.ResponseWriter, r *http.Request) {
-	g, err := new(w, r)
+func printRecent(e *cgi.Env, w http.ResponseWriter, r *http.Request) {
+	g, err := new(e, w, r)
 	if err != nil {
 		log.Println(err)
 		return
@@ -308,7 +321,7 @@ func printRecent(w http.ResponseWriter, r *http.Request) {
 	}
 	g.Header(title, "", nil, true)
 	fmt.Fprintf(g.WR, "<p>%s</p>", g.M["desc_recent"])
-	cl := thread.MakeRecentCachelist()
+	cl := thread.MakeRecentCachelist(e.My)
 	g.PrintIndexList(cl, "recent", true, false, g.Filter, g.Tag)
 }
 
@@ -320,8 +333,8 @@ type gatewayCGI struct {
 }
 
 //new returns gatewayCGI obj with filter.tag value in form.
-func new(w http.ResponseWriter, r *http.Request) (*gatewayCGI, error) {
-	c, err := cgi.NewCGI(w, r)
+func new(e *cgi.Env, w http.ResponseWriter, r *http.Request) (*gatewayCGI, error) {
+	c, err := cgi.NewCGI(e, w, r)
 	if err != nil {
 		return nil, err
 	}
@@ -348,20 +361,22 @@ func new(w http.ResponseWriter, r *http.Request) (*gatewayCGI, error) {
 //appendRSS appends cache ca to rss with contents,url to records,stamp,attached file.
 func (g *gatewayCGI) appendRSS(rsss *cgi.RSS, ca *thread.Cache) {
 	now := time.Now().Unix()
-	if ca.Stamp()+cfg.RSSRange < now {
+	if ca.Stamp()+g.My.Cfg.RSSRange < now {
 		return
 	}
 	title := util.Escape(util.FileDecode(ca.Datfile))
 	path := cfg.ThreadURL + "/" + util.StrEncode(title)
-	recs := ca.LoadRecords(record.Alive)
-	for _, r := range recs {
-		if r.Stamp+cfg.RSSRange < now {
-			continue
-		}
-		if err := r.Load(); err != nil {
-			log.Println(err)
-			continue
-		}
+	rg := &record.Range{
+		Datfile: ca.Datfile,
+		Begin:   now - g.My.Cfg.RSSRange,
+		End:     math.MaxInt64,
+		Kind:    record.Alive,
+	}
+	page, err := thread.LoadPage(g.My, rg, false)
+	if err != nil {
+		log.Println(err)
+	}
+	for _, r := range page.Records {
 		desc := cgi.RSSTextFormat(r.GetBodyValue("body", ""))
 		content := g.rssHTMLFormat(r.GetBodyValue("body", ""), cfg.ThreadURL, title)
 		if attach := r.GetBodyValue("attach", ""); attach != "" {
@@ -372,8 +387,8 @@ func (g *gatewayCGI) appendRSS(rsss *cgi.RSS, ca *thread.Cache) {
 			content += fmt.Sprintf("\n    <p><a href=\"http://%s%s%s%s/%s/%d.%s\">%d.%s</a></p>",
 				g.Host(), cfg.ThreadURL, "/", ca.Datfile, r.ID, r.Stamp, suffix, r.Stamp, suffix)
 		}
-		permpath := fmt.Sprintf("%s/%s", path[1:], r.ID[:8])
-		rsss.Append(permpath, title, cgi.RSSTextFormat(r.GetBodyValue("name", "")), desc, content, user.GetStrings(ca.Datfile), r.Stamp, false)
+		permpath := fmt.Sprintf("%s/%s", path[1:], r.LegacyID()[:8])
+		rsss.Append(permpath, title, cgi.RSSTextFormat(r.GetBodyValue("name", "")), desc, content, user.GetStrings(g.My.DB, ca.Datfile), r.Stamp, false)
 	}
 }
 
@@ -401,9 +416,9 @@ func (g *gatewayCGI) makeOneRow(c string, ca *thread.Cache, p, title string) str
 	case "size":
 		return strconv.FormatInt(ca.Size(), 10)
 	case "tag":
-		return user.String(ca.Datfile)
+		return user.String(g.My.DB, ca.Datfile)
 	case "sugtag":
-		return suggest.String(ca.Datfile)
+		return suggest.String(g.My, ca.Datfile)
 	}
 	return ""
 }
@@ -449,7 +464,7 @@ func (g *gatewayCGI) printIndex(doChange bool) {
 	}
 	g.Header(title, "", nil, true)
 	fmt.Fprintf(g.WR, "<p>%s</p>", g.M["desc_"+str])
-	cl := thread.AllCaches()
+	cl := thread.AllCaches(g.My)
 	if doChange {
 		sort.Sort(sort.Reverse(thread.NewSortByStamp(cl, false)))
 	} else {
@@ -501,10 +516,10 @@ type mchCategory struct {
 //mchCategories returns slice of mchCategory whose tags are in tag.txt.
 func (g *gatewayCGI) mchCategories() []*mchCategory {
 	var categories []*mchCategory
-	if !cfg.Enable2ch {
+	if !g.My.Cfg.Enable2ch {
 		return categories
 	}
-	for _, t := range user.Get() {
+	for _, t := range user.Get(g.My.DB) {
 		tag := t.Tagstr
 		catURL := g.mchURL(tag)
 		categories = append(categories, &mchCategory{
@@ -522,11 +537,11 @@ func (g *gatewayCGI) mchURL(dat string) string {
 	if dat == "" {
 		path = "/2ch/subject.txt"
 	}
-	if !cfg.Enable2ch {
+	if !g.My.Cfg.Enable2ch {
 		return ""
 	}
-	if cfg.ServerName != "" {
-		return "//" + cfg.ServerName + path
+	if g.My.Cfg.ServerName != "" {
+		return "//" + g.My.Cfg.ServerName + path
 	}
 	return fmt.Sprintf("//%s%s", g.Host(), path)
 }
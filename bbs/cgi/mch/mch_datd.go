@@ -42,7 +42,6 @@ import (
 
 	"github.com/gorilla/handlers"
 	"github.com/gorilla/mux"
-	"bbs/cfg"
 	"bbs/cgi"
 	"bbs/mch"
 	"bbs/mch/keylib"
@@ -50,29 +49,28 @@ import (
 	"bbs/tag/user"
 	"bbs/thread"
 	"bbs/thread/download"
-	"bbs/updateque"
 	"bbs/util"
 )
 
 //Setup setups handlers for 2ch interface.
-func Setup(s *cgi.LoggingServeMux) {
+func Setup(s *cgi.LoggingServeMux, e *cgi.Env) {
 	log.Println("start 2ch interface")
 	rtr := mux.NewRouter()
 
-	cgi.RegistToRouter(rtr, "/2ch/", boardApp)
-	cgi.RegistToRouter(rtr, "/2ch/dat/{datkey:[^\\.]+}.dat", threadApp)
-	cgi.RegistToRouter(rtr, "/2ch/{board:[^/]+}/subject.txt", subjectApp)
-	cgi.RegistToRouter(rtr, "/2ch/subject.txt", subjectApp)
-	cgi.RegistToRouter(rtr, "/2ch/{board:[^/]+}/head.txt", headApp)
-	cgi.RegistToRouter(rtr, "/2ch/head.txt", headApp)
+	cgi.RegistToRouter(rtr, "/2ch/", e.Handle(boardApp))
+	cgi.RegistToRouter(rtr, "/2ch/dat/{datkey:[^\\.]+}.dat", e.Handle(threadApp))
+	cgi.RegistToRouter(rtr, "/2ch/{board:[^/]+}/subject.txt", e.Handle(subjectApp))
+	cgi.RegistToRouter(rtr, "/2ch/subject.txt", e.Handle(subjectApp))
+	cgi.RegistToRouter(rtr, "/2ch/{board:[^/]+}/head.txt", e.Handle(headApp))
+	cgi.RegistToRouter(rtr, "/2ch/head.txt", e.Handle(headApp))
 	s.Handle("/2ch/", handlers.CompressHandler(rtr))
 
-	s.RegistCompressHandler("/test/bbs.cgi", postCommentApp)
+	s.RegistCompressHandler("/test/bbs.cgi", e.Handle(postCommentApp))
 }
 
 //boardApp just calls boardApp(), only print title.
-func boardApp(w http.ResponseWriter, r *http.Request) {
-	a, err := new(w, r)
+func boardApp(e *cgi.Env, w http.ResponseWriter, r *http.Request) {
+	a, err := new(e, w, r)
 	if err != nil {
 		log.Println(err)
 		return
@@ -81,8 +79,8 @@ func boardApp(w http.ResponseWriter, r *http.Request) {
 }
 
 //threadApp renders dat files(record data) in the thread.
-func threadApp(w http.ResponseWriter, r *http.Request) {
-	a, err := new(w, r)
+func threadApp(e *cgi.Env, w http.ResponseWriter, r *http.Request) {
+	a, err := new(e, w, r)
 	if err != nil {
 		log.Println(err)
 		return
@@ -96,8 +94,8 @@ func threadApp(w http.ResponseWriter, r *http.Request) {
 }
 
 //subjectApp renders time-subject lines of the thread.
-func subjectApp(w http.ResponseWriter, r *http.Request) {
-	a, err := new(w, r)
+func subjectApp(e *cgi.Env, w http.ResponseWriter, r *http.Request) {
+	a, err := new(e, w, r)
 	if err != nil {
 		log.Println(err)
 		return
@@ -107,8 +105,8 @@ func subjectApp(w http.ResponseWriter, r *http.Request) {
 }
 
 //postCommentApp posts one record to the thread.
-func postCommentApp(w http.ResponseWriter, r *http.Request) {
-	a, err := new(w, r)
+func postCommentApp(e *cgi.Env, w http.ResponseWriter, r *http.Request) {
+	a, err := new(e, w, r)
 	if err != nil {
 		log.Println(err)
 		return
@@ -117,8 +115,8 @@ func postCommentApp(w http.ResponseWriter, r *http.Request) {
 }
 
 //headApp just renders motd.
-func headApp(w http.ResponseWriter, r *http.Request) {
-	a, err := new(w, r)
+func headApp(e *cgi.Env, w http.ResponseWriter, r *http.Request) {
+	a, err := new(e, w, r)
 	if err != nil {
 		log.Println(err)
 		return
@@ -133,8 +131,8 @@ type mchCGI struct {
 
 //new returns mchCGI obj if visitor  is allowed.
 //if not allowed print 403.
-func new(w http.ResponseWriter, r *http.Request) (*mchCGI, error) {
-	c, err := cgi.NewCGI(w, r)
+func new(e *cgi.Env, w http.ResponseWriter, r *http.Request) (*mchCGI, error) {
+	c, err := cgi.NewCGI(e, w, r)
 	if err != nil {
 		w.WriteHeader(403)
 		fmt.Fprintf(w, "403 Forbidden")
@@ -165,7 +163,7 @@ func (m *mchCGI) boardApp() {
 	if l == "" {
 		l = "ja"
 	}
-	msg := cgi.SearchMessage(l, cfg.FileDir)
+	msg := cgi.SearchMessage(l, m.My.Cfg.FileDir)
 	m.WR.Header().Set("Content-Type", "text/html; charset=Shift_JIS")
 	board := util.Escape(util.GetBoard(m.Path()))
 	var text string
@@ -198,13 +196,13 @@ func (m *mchCGI) threadApp(board, datkey string) {
 		log.Println(err)
 		return
 	}
-	key := keylib.GetFilekey(n)
+	key := keylib.GetFilekey(m.My, n)
 	if err != nil {
 		m.WR.WriteHeader(404)
 		fmt.Fprintf(m.WR, "404 Not Found")
 		return
 	}
-	data := thread.NewCache(key)
+	data := thread.NewCache(m.My, key)
 
 	if !data.Exists() {
 		m.WR.WriteHeader(404)
@@ -213,10 +211,10 @@ func (m *mchCGI) threadApp(board, datkey string) {
 	}
 
 	if m.CheckGetCache() {
-		download.GetCache(true, data)
+		download.GetCache(m.My, true, data)
 	}
 
-	thread := keylib.MakeDat(data, board, m.Req.Host)
+	thread := keylib.MakeDat(m.My, data, board, m.Req.Host)
 	str := strings.Join(thread, "\n") + "\n"
 	m.serveContent("a.txt", time.Unix(data.Stamp(), 0), str)
 }
@@ -224,13 +222,13 @@ func (m *mchCGI) threadApp(board, datkey string) {
 //makeSubjectCachelist returns thread.Caches in all thread.Cache and in recentlist sorted by recent stamp.
 //if board is specified,  returns thread.Caches whose tagstr=board.
 func (m *mchCGI) makeSubjectCachelist(board string) []*thread.Cache {
-	result := thread.MakeRecentCachelist()
+	result := thread.MakeRecentCachelist(m.My)
 	if board == "" {
 		return result
 	}
 	var result2 []*thread.Cache
 	for _, c := range result {
-		if user.Has(c.Datfile, board) {
+		if user.Has(m.My.DB, c.Datfile, board) {
 			result2 = append(result2, c)
 		}
 	}
@@ -264,7 +262,7 @@ func (m *mchCGI) makeSubject(board string) ([]string, int64) {
 		if lastStamp < c.Stamp() {
 			lastStamp = c.Stamp()
 		}
-		key, err := keylib.GetDatkey(c.Datfile)
+		key, err := keylib.GetDatkey(m.My, c.Datfile)
 		if err != nil {
 			log.Println(err)
 			continue
@@ -284,7 +282,7 @@ func (m *mchCGI) makeSubject(board string) ([]string, int64) {
 func (m *mchCGI) headApp() {
 	m.WR.Header().Set("Content-Type", "text/plain; charset=Shift_JIS")
 	var body string
-	err := util.EachLine(cfg.Motd(), func(line string, i int) error {
+	err := util.EachLine(m.My.Cfg.Motd(), func(line string, i int) error {
 		line = strings.TrimSpace(line)
 		body += line + "<br>\n"
 		return nil
@@ -308,17 +306,17 @@ func (m *mchCGI) postComment(threadKey, name, mail, body, passwd, tag string) er
 	recbody["name"] = html.EscapeString(name)
 	recbody["mail"] = html.EscapeString(mail)
 
-	c := thread.NewCache(threadKey)
-	rec := record.New(c.Datfile, "", 0)
+	c := thread.NewCache(m.My, threadKey)
+	rec := record.New(m.My, c.Datfile, "", 0)
 	rec.Build(stamp, recbody, passwd)
 	if rec.IsSpam() {
 		return errSpamM
 	}
 	rec.Sync()
 	if tag != "" {
-		user.Set(c.Datfile, []string{tag})
+		user.Set(m.My.DB, c.Datfile, []string{tag})
 	}
-	go updateque.UpdateNodes(rec, nil)
+	go m.Queue.UpdateNodes(rec, nil)
 	return nil
 }
 
@@ -326,7 +324,7 @@ func (m *mchCGI) postComment(threadKey, name, mail, body, passwd, tag string) er
 func (m *mchCGI) errorResp(msg string, info map[string]string) {
 	m.WR.Header().Set("Content-Type", "text/html; charset=Shift_JIS")
 	info["message"] = msg
-	cgi.RenderTemplate("2ch_error", info, m.WR)
+	cgi.RenderTemplate(m.My.Cfg.TemplateDir, "2ch_error", info, m.WR)
 }
 
 //getCP932 returns form value of key with cp932 code.
@@ -361,14 +359,14 @@ func (m *mchCGI) checkInfo(info map[string]string) string {
 			m.errorResp(err.Error(), info)
 			return ""
 		}
-		key = keylib.GetFilekey(n)
+		key = keylib.GetFilekey(m.My, n)
 	}
 
 	switch {
 	case info["body"] == "":
 		m.errorResp("本文がありません.", info)
 		return ""
-	case thread.NewCache(key).Exists(), m.HasAuth():
+	case thread.NewCache(m.My, key).Exists(), m.HasAuth():
 	case info["subject"] != "":
 		m.errorResp("掲示版を作る権限がありません", info)
 		return ""
@@ -406,7 +404,7 @@ func (m *mchCGI) postCommentApp() {
 	if ma := reg.FindStringSubmatch(referer); ma != nil && m.HasAuth() {
 		tag = util.FileDecode("dummy_" + ma[1])
 	}
-	table := mch.NewResTable(thread.NewCache(key))
+	table := mch.NewResTable(m.My, thread.NewCache(m.My, key))
 	reg = regexp.MustCompile(">>([1-9][0-9]*)")
 	body := reg.ReplaceAllStringFunc(info["body"], func(str string) string {
 		noStr := reg.FindStringSubmatch(str)[1]
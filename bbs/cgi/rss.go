@@ -124,13 +124,13 @@ func (r *RSS) Append(link, title, creator, description, content string, subject
 	r.Feeds = append(r.Feeds, i)
 }
 
-//MakeRSS1 renders template.
-func (r *RSS) MakeRSS1(wr io.Writer) {
+//MakeRSS1 renders template in templateDir.
+func (r *RSS) MakeRSS1(templateDir string, wr io.Writer) {
 	for _, c := range r.Feeds {
 		c.Content = strings.Replace(c.content, "]]", "&#93;&#93;>", -1)
 	}
 	sort.Sort(sort.Reverse(r))
-	RenderRSS(*r, wr)
+	RenderRSS(templateDir, *r, wr)
 }
 
 //W3cdate returns RSS formated date string.
@@ -49,26 +49,25 @@ import (
 	"bbs/record"
 	"bbs/tag/user"
 	"bbs/thread"
-	"bbs/updateque"
 )
 
 //Setup setups handlers for server.cgi
-func Setup(s *cgi.LoggingServeMux) {
-	s.RegistCompressHandler(cfg.ServerURL+"/ping", doPing)
-	s.RegistCompressHandler(cfg.ServerURL+"/node", doNode)
-	s.RegistCompressHandler(cfg.ServerURL+"/join/", doJoin)
-	s.RegistCompressHandler(cfg.ServerURL+"/bye/", doBye)
-	s.RegistCompressHandler(cfg.ServerURL+"/have/", doHave)
-	s.RegistCompressHandler(cfg.ServerURL+"/get/", doGetHead)
-	s.RegistCompressHandler(cfg.ServerURL+"/head/", doGetHead)
-	s.RegistCompressHandler(cfg.ServerURL+"/update/", doUpdate)
-	s.RegistCompressHandler(cfg.ServerURL+"/recent/", doRecent)
-	s.RegistCompressHandler(cfg.ServerURL+"/", doMotd)
+func Setup(s *cgi.LoggingServeMux, e *cgi.Env) {
+	s.RegistCompressHandler(cfg.ServerURL+"/ping", e.Handle(doPing))
+	s.RegistCompressHandler(cfg.ServerURL+"/node", e.Handle(doNode))
+	s.RegistCompressHandler(cfg.ServerURL+"/join/", e.Handle(doJoin))
+	s.RegistCompressHandler(cfg.ServerURL+"/bye/", e.Handle(doBye))
+	s.RegistCompressHandler(cfg.ServerURL+"/have/", e.Handle(doHave))
+	s.RegistCompressHandler(cfg.ServerURL+"/get/", e.Handle(doGetHead))
+	s.RegistCompressHandler(cfg.ServerURL+"/head/", e.Handle(doGetHead))
+	s.RegistCompressHandler(cfg.ServerURL+"/update/", e.Handle(doUpdate))
+	s.RegistCompressHandler(cfg.ServerURL+"/recent/", e.Handle(doRecent))
+	s.RegistCompressHandler(cfg.ServerURL+"/", e.Handle(doMotd))
 
 }
 
 //doPing just resopnse PONG with remote addr.
-func doPing(w http.ResponseWriter, r *http.Request) {
+func doPing(e *cgi.Env, w http.ResponseWriter, r *http.Request) {
 	log.Println(r.Header)
 	host, _, err := net.SplitHostPort(r.RemoteAddr)
 	if err != nil {
@@ -79,18 +78,18 @@ func doPing(w http.ResponseWriter, r *http.Request) {
 }
 
 //doNode returns one of nodelist. if nodelist.len=0 returns one of initNode.
-func doNode(w http.ResponseWriter, r *http.Request) {
-	if manager.ListLen() > 0 {
-		fmt.Fprintln(w, manager.GetNodestrSliceInList()[0])
+func doNode(e *cgi.Env, w http.ResponseWriter, r *http.Request) {
+	if manager.ListLen(e.My) > 0 {
+		fmt.Fprintln(w, manager.GetNodestrSliceInList(e.My)[0])
 	} else {
-		fmt.Fprintln(w, cfg.InitNode.GetData()[0])
+		fmt.Fprintln(w, e.My.Cfg.InitNode.GetData()[0])
 	}
 }
 
 //doJoin adds node specified in url to searchlist and nodelist.
 //if nodelist>#defaultnode removes and says bye one node in nodelist and returns welcome its ip:port.
-func doJoin(w http.ResponseWriter, r *http.Request) {
-	s, err := new(w, r)
+func doJoin(e *cgi.Env, w http.ResponseWriter, r *http.Request) {
+	s, err := new(e, w, r)
 	if err != nil {
 		log.Println(err)
 		return
@@ -101,13 +100,13 @@ func doJoin(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	n, err := node.MakeNode(host, path, port)
-	if err != nil || !n.IsAllowed() {
+	if err != nil || !n.IsAllowed(e.My) {
 		return
 	}
-	if _, err := n.Ping(); err != nil {
+	if _, err := n.Ping(e.My); err != nil {
 		return
 	}
-	suggest := manager.ReplaceNodeInList(n)
+	suggest := manager.ReplaceNodeInList(e.My, n)
 	if suggest == nil {
 		fmt.Fprintln(s.WR, "WELCOME")
 		return
@@ -116,8 +115,8 @@ func doJoin(w http.ResponseWriter, r *http.Request) {
 }
 
 //doBye  removes from nodelist and says bye to the node specified in url.
-func doBye(w http.ResponseWriter, r *http.Request) {
-	s, err := new(w, r)
+func doBye(e *cgi.Env, w http.ResponseWriter, r *http.Request) {
+	s, err := new(e, w, r)
 	if err != nil {
 		log.Println(err)
 		return
@@ -129,14 +128,14 @@ func doBye(w http.ResponseWriter, r *http.Request) {
 	}
 	n, err := node.MakeNode(host, path, port)
 	if err == nil {
-		manager.RemoveFromList(n)
+		manager.RemoveFromList(e.My, n)
 	}
 	fmt.Fprintln(s.WR, "BYEBYE")
 }
 
 //doHave checks existance of cache whose name is specified in url.
-func doHave(w http.ResponseWriter, r *http.Request) {
-	s, err := new(w, r)
+func doHave(e *cgi.Env, w http.ResponseWriter, r *http.Request) {
+	s, err := new(e, w, r)
 	if err != nil {
 		log.Println(err)
 		return
@@ -148,7 +147,7 @@ func doHave(w http.ResponseWriter, r *http.Request) {
 		log.Println("illegal url")
 		return
 	}
-	ca := thread.NewCache(m[1])
+	ca := thread.NewCache(e.My, m[1])
 	if ca.HasRecord() {
 		fmt.Fprintln(w, "YES")
 	} else {
@@ -158,8 +157,8 @@ func doHave(w http.ResponseWriter, r *http.Request) {
 
 //doUpdate adds remote node to searchlist and lookuptable with datfile specified in url.
 //if stamp is in range of defaultUpdateRange adds to updateque.
-func doUpdate(w http.ResponseWriter, r *http.Request) {
-	s, err := new(w, r)
+func doUpdate(e *cgi.Env, w http.ResponseWriter, r *http.Request) {
+	s, err := new(e, w, r)
 	if err != nil {
 		log.Println(err)
 		log.Println("failed to create cgi struct")
@@ -189,11 +188,11 @@ func doUpdate(w http.ResponseWriter, r *http.Request) {
 	}
 
 	n, err := node.MakeNode(host, path, port)
-	if err != nil || !n.IsAllowed() {
+	if err != nil || !n.IsAllowed(e.My) {
 		log.Println("detects spam")
 		return
 	}
-	manager.AppendToTable(datfile, n)
+	manager.AppendToTable(e.My, datfile, n)
 	nstamp, err := strconv.ParseInt(stamp, 10, 64)
 	if err != nil {
 		log.Println(err)
@@ -203,14 +202,14 @@ func doUpdate(w http.ResponseWriter, r *http.Request) {
 	if !recentlist.IsInUpdateRange(nstamp) {
 		return
 	}
-	rec := record.New(datfile, id, nstamp)
-	go updateque.UpdateNodes(rec, n)
+	rec := record.New(e.My, datfile, id, nstamp)
+	go e.Queue.UpdateNodes(rec, n)
 	fmt.Fprintln(w, "OK")
 }
 
 //doRecent renders records whose timestamp is in range of one specified in url.
-func doRecent(w http.ResponseWriter, r *http.Request) {
-	s, err := new(w, r)
+func doRecent(e *cgi.Env, w http.ResponseWriter, r *http.Request) {
+	s, err := new(e, w, r)
 	if err != nil {
 		log.Println(err)
 		return
@@ -222,16 +221,13 @@ func doRecent(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	stamp := m[1]
-	last := time.Now().Unix() + cfg.RecentRange
+	last := time.Now().Unix() + e.My.Cfg.RecentRange
 	begin, end, _ := s.parseStamp(stamp, last)
-	for _, i := range recentlist.GetRecords() {
-		if begin > i.Stamp || i.Stamp > end {
-			continue
-		}
-		ca := thread.NewCache(i.Datfile)
+	for _, i := range recentlist.Range(e.My, begin, end) {
+		ca := thread.NewCache(e.My, i.Datfile)
 		cont := fmt.Sprintf("%d<>%s<>%s", i.Stamp, i.ID, i.Datfile)
-		if user.Len(ca.Datfile) > 0 {
-			cont += "<>tag:" + user.String(ca.Datfile)
+		if user.Len(e.My.DB, ca.Datfile) > 0 {
+			cont += "<>tag:" + user.String(e.My.DB, ca.Datfile)
 		}
 		_, err := fmt.Fprintf(w, "%s\n", cont)
 		if err != nil {
@@ -241,8 +237,8 @@ func doRecent(w http.ResponseWriter, r *http.Request) {
 }
 
 //doMotd simply renders motd file.
-func doMotd(w http.ResponseWriter, r *http.Request) {
-	f, err := ioutil.ReadFile(cfg.Motd())
+func doMotd(e *cgi.Env, w http.ResponseWriter, r *http.Request) {
+	f, err := ioutil.ReadFile(e.My.Cfg.Motd())
 	if err != nil {
 		log.Println(err)
 		return
@@ -252,8 +248,9 @@ func doMotd(w http.ResponseWriter, r *http.Request) {
 
 //doGetHead renders records contents(get) or id+timestamp(head) who has id and
 // whose stamp is in range of one specified by url.
-func doGetHead(w http.ResponseWriter, r *http.Request) {
-	s, err := new(w, r)
+//lines are sent after reading records, not to keep the transaction open while sending.
+func doGetHead(e *cgi.Env, w http.ResponseWriter, r *http.Request) {
+	s, err := new(e, w, r)
 	if err != nil {
 		log.Println(err)
 		return
@@ -265,29 +262,39 @@ func doGetHead(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	method, datfile, stamp := m[1], m[2], m[3]
-	ca := thread.NewCache(datfile)
 	begin, end, id := s.parseStamp(stamp, math.MaxInt32)
-	var recs record.Map
+	rg := &record.Range{
+		Datfile: datfile,
+		Begin:   begin,
+		End:     end,
+		ID:      id,
+		ExactID: true,
+		Kind:    record.Alive,
+		Head:    method != "get",
+	}
 	if method == "removed" {
-		recs = ca.LoadRecords(record.Removed)
-	} else {
-		recs = ca.LoadRecords(record.Alive)
-	}
-	for _, r := range recs {
-		if r.InRange(begin, end, id) {
-			if method == "get" {
-				if err := r.Load(); err != nil {
-					log.Println(err)
-					continue
-				}
-				fmt.Fprintln(s.WR, r.Recstr())
-				continue
-			}
-			fmt.Fprintln(s.WR, strings.Replace(r.Idstr(), "_", "<>", -1))
+		rg.Kind = record.Removed
+	}
+	var lines []string
+	err = rg.Each(e.My, func(r *record.Record) error {
+		if method == "get" {
+			lines = append(lines, r.Recstr())
+			return nil
+		}
+		lines = append(lines, strings.Replace(r.LegacyHead().Idstr(), "_", "<>", -1))
+		return nil
+	})
+	if err != nil {
+		log.Println(err)
+	}
+	for _, line := range lines {
+		if _, err := fmt.Fprintln(s.WR, line); err != nil {
+			log.Println(err)
+			break
 		}
 	}
 	if method == "get" {
-		updateque.UpdatedRecord.Inform(datfile, id, begin, end)
+		e.Queue.Inform(datfile, id, begin, end)
 	}
 }
 
@@ -297,8 +304,8 @@ type serverCGI struct {
 }
 
 //new set content-type to text and  returns serverCGI obj.
-func new(w http.ResponseWriter, r *http.Request) (*serverCGI, error) {
-	c, err := cgi.NewCGI(w, r)
+func new(e *cgi.Env, w http.ResponseWriter, r *http.Request) (*serverCGI, error) {
+	c, err := cgi.NewCGI(e, w, r)
 	if err != nil {
 		return nil, err
 	}
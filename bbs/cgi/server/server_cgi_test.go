@@ -0,0 +1,109 @@
+/*
+ * Copyright (c) 2015, Shinya Yagyu
+ * All rights reserved.
+ * Redistribution and use in source and binary forms, with or without
+ * modification, are permitted provided that the following conditions are met:
+ *
+ * 1. Redistributions of source code must retain the above copyright notice,
+ *    this list of conditions and the following disclaimer.
+ * 2. Redistributions in binary form must reproduce the above copyright notice,
+ *    this list of conditions and the following disclaimer in the documentation
+ *    and/or other materials provided with the distribution.
+ * 3. Neither the name of the copyright holder nor the names of its
+ *    contributors may be used to endorse or promote products derived from this
+ *    software without specific prior written permission.
+ *
+ * THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+ * AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+ * IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+ * ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+ * LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+ * CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+ * SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+ * INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+ * CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+ * ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+ * POSSIBILITY OF SUCH DAMAGE.
+ */
+
+package server
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"bbs/cfg"
+	"bbs/cgi"
+	"bbs/myself"
+	"bbs/record"
+	"bbs/thread"
+	"bbs/updateque"
+	"bbs/util"
+)
+
+func TestGetHead(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gou")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	my := myself.NewMemory(dir)
+	e := &cgi.Env{
+		My:    my,
+		Queue: updateque.New(my),
+	}
+
+	datfile := util.FileEncode("thread", "gethead")
+	thread.NewCache(my, datfile).Subscribe()
+	var recs []*record.Record
+	for j, stamp := range []int64{1500000000, 1500000010, 1500000020} {
+		rec := record.New(my, datfile, "", 0)
+		rec.Build(stamp, map[string]string{"body": fmt.Sprint("body", j)}, "")
+		rec.Sync()
+		recs = append(recs, rec)
+	}
+	if err := recs[2].Remove(); err != nil {
+		t.Fatal(err)
+	}
+	head := func(r *record.Record) string {
+		return fmt.Sprintf("%d<>%s", r.Stamp, r.LegacyID())
+	}
+	r0 := recs[0]
+	tests := map[string][]string{
+		"/head/" + datfile:                                     {head(recs[0]), head(recs[1])},
+		"/head/" + datfile + "/1500000010-":                    {head(recs[1])},
+		"/head/" + datfile + "/-1500000005":                    {head(recs[0])},
+		"/removed/" + datfile:                                  {head(recs[2])},
+		"/get/" + datfile + "/1500000000":                      {r0.Recstr()},
+		"/get/" + datfile + "/1500000000/" + r0.LegacyID():     {r0.Recstr()},
+		"/get/" + datfile + "/1500000000/" + r0.LegacyID()[:8]: nil,
+		"/get/" + datfile + "/1500000010/" + r0.LegacyID():     nil,
+		"/get/thread_XX":                                       nil,
+	}
+	for path, lines := range tests {
+		w := httptest.NewRecorder()
+		doGetHead(e, w, httptest.NewRequest("GET", cfg.ServerURL+path, nil))
+		var res []string
+		if s := strings.TrimSpace(w.Body.String()); s != "" {
+			res = strings.Split(s, "\n")
+		}
+		if strings.Join(res, "\n") != strings.Join(lines, "\n") {
+			t.Fatal(path, "returns", res)
+		}
+	}
+
+	//my update which other node got is removed from the queue.
+	e.Queue.Add(recs[1])
+	if its := e.Queue.Items(); len(its) != 1 {
+		t.Fatal(len(its), "updates are queued")
+	}
+	path := cfg.ServerURL + "/get/" + datfile + "/1500000010/" + recs[1].LegacyID()
+	doGetHead(e, httptest.NewRecorder(), httptest.NewRequest("GET", path, nil))
+	if its := e.Queue.Items(); len(its) != 0 {
+		t.Fatal(len(its), "updates are left in the queue")
+	}
+}
@@ -35,18 +35,20 @@ import (
 	"log"
 	"path"
 	"path/filepath"
+	"sync"
 	textTemplate "text/template"
 	"time"
 
-	"bbs/cfg"
 	"bbs/util"
 )
 
 var (
-	//tmpH is template for html.
-	tmpH *Htemplate
-	//tmpT is template for text(rss).
-	tmpT *Ttemplate
+	//tmpH is templates for html in each template dir.
+	tmpH = make(map[string]*Htemplate)
+	//tmpT is templates for text(rss) in each template dir.
+	tmpT = make(map[string]*Ttemplate)
+	//tmpMutex is for tmpH and tmpT.
+	tmpMutex sync.Mutex
 )
 
 var funcMap = map[string]interface{}{
@@ -138,22 +140,30 @@ func newHtemplate(templateDir string) *Htemplate {
 	return t
 }
 
-//RenderTemplate executes template and write to wr.
-func RenderTemplate(file string, st interface{}, wr io.Writer) {
-	if tmpH == nil {
-		tmpH = newHtemplate(cfg.TemplateDir)
+//RenderTemplate executes template in templateDir and write to wr.
+func RenderTemplate(templateDir, file string, st interface{}, wr io.Writer) {
+	tmpMutex.Lock()
+	t, exist := tmpH[templateDir]
+	if !exist {
+		t = newHtemplate(templateDir)
+		tmpH[templateDir] = t
 	}
-	if err := tmpH.ExecuteTemplate(wr, file, st); err != nil {
+	tmpMutex.Unlock()
+	if err := t.ExecuteTemplate(wr, file, st); err != nil {
 		log.Println(err)
 	}
 }
 
-//RenderRSS executes rss template and write to wr.
-func RenderRSS(st interface{}, wr io.Writer) {
-	if tmpT == nil {
-		tmpT = newTtemplate(cfg.TemplateDir)
+//RenderRSS executes rss template in templateDir and write to wr.
+func RenderRSS(templateDir string, st interface{}, wr io.Writer) {
+	tmpMutex.Lock()
+	t, exist := tmpT[templateDir]
+	if !exist {
+		t = newTtemplate(templateDir)
+		tmpT[templateDir] = t
 	}
-	if err := tmpT.ExecuteTemplate(wr, "rss1", st); err != nil {
+	tmpMutex.Unlock()
+	if err := t.ExecuteTemplate(wr, "rss1", st); err != nil {
 		log.Println(err)
 	}
 }
@@ -55,43 +55,42 @@ import (
 	"bbs/tag/user"
 	"bbs/thread"
 	"bbs/thread/download"
-	"bbs/updateque"
 	"bbs/util"
 )
 
 //Setup setups handlers for thread.cgi
-func Setup(s *cgi.LoggingServeMux) {
+func Setup(s *cgi.LoggingServeMux, e *cgi.Env) {
 	rtr := mux.NewRouter()
 
-	cgi.RegistToRouter(rtr, cfg.ThreadURL+"/", printThreadIndex)
+	cgi.RegistToRouter(rtr, cfg.ThreadURL+"/", e.Handle(printThreadIndex))
 
 	reg := cfg.ThreadURL + "/{datfile:thread_[0-9A-F]+}/{id:[0-9a-f]{32}}/s{stamp:\\d+}.{thumbnailSize:\\d+x\\d+}.{suffix:.*}"
-	cgi.RegistToRouter(rtr, reg, printAttach)
+	cgi.RegistToRouter(rtr, reg, e.Handle(printAttach))
 
 	reg = cfg.ThreadURL + "/{datfile:thread_[0-9A-F]+}/{id:[0-9a-f]{32}}/{stamp:\\d+}.{suffix:.*}"
-	cgi.RegistToRouter(rtr, reg, printAttach)
+	cgi.RegistToRouter(rtr, reg, e.Handle(printAttach))
 
 	reg = cfg.ThreadURL + "/{path:[^/]+}{end:/?$}"
-	cgi.RegistToRouter(rtr, reg, printThread)
+	cgi.RegistToRouter(rtr, reg, e.Handle(printThread))
 
 	reg = cfg.ThreadURL + "/{path:[^/]+}/{id:[0-9a-f]{8}}{end:$}"
-	cgi.RegistToRouter(rtr, reg, printThread)
+	cgi.RegistToRouter(rtr, reg, e.Handle(printThread))
 
 	reg = cfg.ThreadURL + "/{path:[^/]+}/p{page:[0-9]+}{end:$}"
-	cgi.RegistToRouter(rtr, reg, printThread)
+	cgi.RegistToRouter(rtr, reg, e.Handle(printThread))
 
 	s.Handle(cfg.ThreadURL+"/", handlers.CompressHandler(rtr))
 }
 
 //printThreadIndex adds records in multiform and redirect to its thread page.
-func printThreadIndex(w http.ResponseWriter, r *http.Request) {
-	if a, err := new(w, r); err == nil {
+func printThreadIndex(e *cgi.Env, w http.ResponseWriter, r *http.Request) {
+	if a, err := new(e, w, r); err == nil {
 		a.printThreadIndex()
 	}
 }
 
-func printAttach(w http.ResponseWriter, r *http.Request) {
-	a, err := new(w, r)
+func printAttach(e *cgi.Env, w http.ResponseWriter, r *http.Request) {
+	a, err := new(e, w, r)
 	if err != nil {
 		return
 	}
@@ -109,8 +108,8 @@ func printAttach(w http.ResponseWriter, r *http.Request) {
 }
 
 //printThread renders whole thread list page.
-func printThread(w http.ResponseWriter, r *http.Request) {
-	a, errr := new(w, r)
+func printThread(e *cgi.Env, w http.ResponseWriter, r *http.Request) {
+	a, errr := new(e, w, r)
 	if errr != nil {
 		return
 	}
@@ -137,8 +136,8 @@ type threadCGI struct {
 }
 
 //new returns threadCGI obj.
-func new(w http.ResponseWriter, r *http.Request) (*threadCGI, error) {
-	c, err := cgi.NewCGI(w, r)
+func new(e *cgi.Env, w http.ResponseWriter, r *http.Request) (*threadCGI, error) {
+	c, err := cgi.NewCGI(e, w, r)
 	if err != nil {
 		c.Print403()
 		return nil, err
@@ -157,7 +156,7 @@ func new(w http.ResponseWriter, r *http.Request) (*threadCGI, error) {
 
 //printThreadIndex adds records in multiform and redirect to its thread page.
 func (t *threadCGI) printThreadIndex() {
-	err := t.Req.ParseMultipartForm(int64(cfg.RecordLimit) << 10)
+	err := t.Req.ParseMultipartForm(int64(t.My.Cfg.RecordLimit) << 10)
 	if err != nil {
 		t.Print404(nil, "")
 		return
@@ -199,8 +198,8 @@ func (t *threadCGI) setCookie(ca *thread.Cache, access string) []*http.Cookie {
 //printPageNavi renders page_navi.txt, part for paging.
 func (t *threadCGI) printPageNavi(path string, page int, ca *thread.Cache, id string) {
 	len := ca.Len(record.Alive)
-	first := len / cfg.ThreadPageSize
-	if len%cfg.ThreadPageSize == 0 {
+	first := len / t.My.Cfg.ThreadPageSize
+	if len%t.My.Cfg.ThreadPageSize == 0 {
 		first++
 	}
 	pages := make([]int, first+1)
@@ -225,10 +224,10 @@ func (t *threadCGI) printPageNavi(path string, page int, ca *thread.Cache, id st
 		first,
 		cfg.ThreadURL,
 		t.M,
-		cfg.ThreadPageSize,
+		t.My.Cfg.ThreadPageSize,
 		pages,
 	}
-	cgi.RenderTemplate("page_navi", s, t.WR)
+	cgi.RenderTemplate(t.My.Cfg.TemplateDir, "page_navi", s, t.WR)
 }
 
 //printTag renders thread_tags.txt , part for displayng tags.
@@ -241,12 +240,12 @@ func (t *threadCGI) printTag(ca *thread.Cache) {
 		cgi.Defaults
 	}{
 		ca.Datfile,
-		user.GetStrings(ca.Datfile),
+		user.GetStrings(t.My.DB, ca.Datfile),
 		"tags",
 		"changes",
 		*t.Defaults(),
 	}
-	cgi.RenderTemplate("thread_tags", s, t.WR)
+	cgi.RenderTemplate(t.My.Cfg.TemplateDir, "thread_tags", s, t.WR)
 }
 
 //printThreadHead renders head part of thread page with cookie.
@@ -254,14 +253,14 @@ func (t *threadCGI) printThreadHead(path, id string, page int, ca *thread.Cache,
 	switch {
 	case ca.HasRecord():
 		if !t.IsBot() {
-			download.GetCache(true, ca)
+			download.GetCache(t.My, true, ca)
 		} else {
 			log.Println("bot detected, not get cache")
 		}
 	case t.CheckGetCache():
 		ca.Subscribe()
 		if t.Req.FormValue("search_new_file") == "" {
-			download.GetCache(true, ca)
+			download.GetCache(t.My, true, ca)
 		}
 	default:
 		t.Print404(nil, id)
@@ -282,15 +281,48 @@ func (t *threadCGI) printThreadHead(path, id string, page int, ca *thread.Cache,
 	return nil
 }
 
+//loadPage loads records in the nPage-th page, or the record whose id is id, of thread ca
+//and records referred by them.
+func (t *threadCGI) loadPage(id string, nPage int, ca *thread.Cache) *thread.Page {
+	n := ca.Len(record.Alive)
+	from := n - t.My.Cfg.ThreadPageSize*(nPage+1)
+	to := n - t.My.Cfg.ThreadPageSize*(nPage)
+	if from < 0 {
+		from = 0
+	}
+	if to < 0 {
+		to = 0
+	}
+	rg := &record.Range{
+		Datfile: ca.Datfile,
+		End:     math.MaxInt64,
+		Kind:    record.Alive,
+	}
+	switch {
+	case id != "":
+		rg.ID = id
+	case nPage > 0:
+		if to == from {
+			return &thread.Page{}
+		}
+		rg.Skip, rg.Limit = from, to-from
+	default:
+		rg.Skip = from
+	}
+	page, err := thread.LoadPage(t.My, rg, true)
+	if err != nil {
+		log.Println(err)
+	}
+	return page
+}
+
 //printThreadTop renders toppart of thread page.
-func (t *threadCGI) printThreadTop(path, id string, nPage int, ca *thread.Cache) {
+func (t *threadCGI) printThreadTop(path, id string, nPage int, ca *thread.Cache, page *thread.Page) {
 	var lastrec *record.Record
 	var resAnchor string
-	recs := ca.LoadRecords(record.Alive)
-	ids := recs.Keys()
-	if ca.HasRecord() && nPage == 0 && id == "" && len(ids) > 0 {
-		lastrec = recs[ids[len(ids)-1]]
-		resAnchor = t.ResAnchor(lastrec.ID[:8], cfg.ThreadURL, t.Path(), false)
+	if n := len(page.Records); n > 0 && nPage == 0 && id == "" {
+		lastrec = page.Records[n-1]
+		resAnchor = t.ResAnchor(lastrec.LegacyID()[:8], cfg.ThreadURL, t.Path(), false)
 	}
 	s := struct {
 		Path      string
@@ -305,39 +337,24 @@ func (t *threadCGI) printThreadTop(path, id string, nPage int, ca *thread.Cache)
 		template.HTML(resAnchor),
 		*t.Defaults(),
 	}
-	cgi.RenderTemplate("thread_top", s, t.WR)
+	cgi.RenderTemplate(t.My.Cfg.TemplateDir, "thread_top", s, t.WR)
 }
 
-//printThreadBody renders body(records list) part of thread page with paging.
-func (t *threadCGI) printThreadBody(id string, nPage int, ca *thread.Cache) {
-	recs := ca.LoadRecords(record.Alive)
-	ids := recs.Keys()
+//printThreadBody renders body(records list) part of thread page with paging,
+//and hidden records referred by anchors for popups.
+func (t *threadCGI) printThreadBody(ca *thread.Cache, page *thread.Page) {
 	fmt.Fprintln(t.WR, "</p>\n<dl id=\"records\">")
-	from := len(ids) - cfg.ThreadPageSize*(nPage+1)
-	to := len(ids) - cfg.ThreadPageSize*(nPage)
-	if from < 0 {
-		from = 0
+	for _, rec := range page.Records {
+		t.printRecord(ca, rec)
 	}
-	if to < 0 {
-		to = 0
-	}
-	var inrange []string
-	switch {
-	case id != "":
-		inrange = ids
-	case nPage > 0:
-		inrange = ids[from:to]
-	default:
-		inrange = ids[from:]
+	fmt.Fprintln(t.WR, "</dl>")
+	if len(page.Anchors) == 0 {
+		return
 	}
-
-	for _, k := range inrange {
-		rec := recs.Get(k, nil)
-		if (id == "" || rec.ID[:8] == id) && rec.Load() == nil {
-			t.printRecord(ca, rec)
-		}
+	fmt.Fprintln(t.WR, "<dl id=\"anchors\" style=\"display:none\">")
+	for _, rec := range page.Anchors {
+		t.printRecord(ca, rec)
 	}
-
 	fmt.Fprintln(t.WR, "</dl>")
 }
 
@@ -348,19 +365,20 @@ func (t *threadCGI) printThread(path, id string, nPage int) {
 		return
 	}
 	filePath := util.FileEncode("thread", path)
-	ca := thread.NewCache(filePath)
+	ca := thread.NewCache(t.My, filePath)
 	rss := cfg.GatewayURL + "/rss"
 	if t.printThreadHead(path, id, nPage, ca, rss) != nil {
 		return
 	}
 	tags := strings.Fields(strings.TrimSpace(t.Req.FormValue("tag")))
 	if t.IsAdmin() && len(tags) > 0 {
-		user.Add(ca.Datfile, tags)
+		user.Add(t.My.DB, ca.Datfile, tags)
 	}
 	t.printTag(ca)
-	t.printThreadTop(path, id, nPage, ca)
+	page := t.loadPage(id, nPage, ca)
+	t.printThreadTop(path, id, nPage, ca, page)
 	t.printPageNavi(path, nPage, ca, id)
-	t.printThreadBody(id, nPage, ca)
+	t.printThreadBody(ca, page)
 
 	escapedPath := html.EscapeString(path)
 	escapedPath = strings.Replace(escapedPath, "  ", "&nbsp;&nbsp;", -1)
@@ -371,7 +389,7 @@ func (t *threadCGI) printThread(path, id string, nPage int) {
 		ca,
 		t.M,
 	}
-	cgi.RenderTemplate("thread_bottom", ss, t.WR)
+	cgi.RenderTemplate(t.My.Cfg.TemplateDir, "thread_bottom", ss, t.WR)
 
 	if ca.HasRecord() {
 		t.printPageNavi(path, nPage, ca, id)
@@ -387,17 +405,24 @@ func (t *threadCGI) printThread(path, id string, nPage int) {
 func (t *threadCGI) printThreadAjax(id string) {
 	th := strings.Split(t.Path(), "/")[0]
 	filePath := util.FileEncode("thread", th)
-	ca := thread.NewCache(filePath)
+	ca := thread.NewCache(t.My, filePath)
 	if !ca.HasRecord() {
 		log.Println(filePath, "not found")
 		return
 	}
 	fmt.Fprintln(t.WR, "<dl>")
-	recs := ca.LoadRecords(record.Alive)
-	for _, rec := range recs {
-		if id == "" || rec.ID[:8] == id && rec.Load() == nil {
-			t.printRecord(ca, rec)
-		}
+	rg := &record.Range{
+		Datfile: ca.Datfile,
+		End:     math.MaxInt64,
+		Kind:    record.Alive,
+		ID:      id,
+	}
+	page, err := thread.LoadPage(t.My, rg, false)
+	if err != nil {
+		log.Println(err)
+	}
+	for _, rec := range page.Records {
+		t.printRecord(ca, rec)
 	}
 	fmt.Fprintln(t.WR, "</dl>")
 }
@@ -420,7 +445,7 @@ func (t *threadCGI) printRecord(ca *thread.Cache, rec *record.Record) {
 			typ = "text/plain"
 		}
 		if util.IsValidImage(typ, attachFile) {
-			thumbnailSize = cfg.DefaultThumbnailSize
+			thumbnailSize = t.My.Cfg.DefaultThumbnailSize
 		}
 	}
 	body := rec.GetBodyValue("body", "")
@@ -431,7 +456,7 @@ func (t *threadCGI) printRecord(ca *thread.Cache, rec *record.Record) {
 	}
 	resAnchor := t.ResAnchor(removeID, cfg.ThreadURL, t.Path(), false)
 
-	id8 := rec.ID
+	id8 := rec.LegacyID()
 	if len(id8) > 8 {
 		id8 = id8[:8]
 	}
@@ -460,7 +485,7 @@ func (t *threadCGI) printRecord(ca *thread.Cache, rec *record.Record) {
 		resAnchor,
 		*t.Defaults(),
 	}
-	cgi.RenderTemplate("record", s, t.WR)
+	cgi.RenderTemplate(t.My.Cfg.TemplateDir, "record", s, t.WR)
 }
 
 //printPostForm renders post_form.txt,page for posting attached file.
@@ -477,10 +502,10 @@ func (t *threadCGI) printPostForm(ca *thread.Cache) {
 	}{
 		ca,
 		mimes,
-		cfg.RecordLimit * 3 >> 2,
+		t.My.Cfg.RecordLimit * 3 >> 2,
 		*t.Defaults(),
 	}
-	cgi.RenderTemplate("post_form", s, t.WR)
+	cgi.RenderTemplate(t.My.Cfg.TemplateDir, "post_form", s, t.WR)
 }
 
 //renderAttach render the content of attach file with content-type=typ.
@@ -504,7 +529,7 @@ func (t *threadCGI) renderAttach(rec *record.Record, suffix string, stamp int64,
 		t.Print404(nil, "")
 		return
 	}
-	if thumbnailSize != "" && (cfg.ForceThumbnail || thumbnailSize == cfg.DefaultThumbnailSize) {
+	if thumbnailSize != "" && (t.My.Cfg.ForceThumbnail || thumbnailSize == t.My.Cfg.DefaultThumbnailSize) {
 		decoded = util.MakeThumbnail(decoded, suffix, thumbnailSize)
 	}
 	_, err = t.WR.Write(decoded)
@@ -516,16 +541,16 @@ func (t *threadCGI) renderAttach(rec *record.Record, suffix string, stamp int64,
 
 //printAttach renders the content of attach file and makes thumnail if needed and possible.
 func (t *threadCGI) printAttach(datfile, id string, stamp int64, thumbnailSize, suffix string) {
-	ca := thread.NewCache(datfile)
+	ca := thread.NewCache(t.My, datfile)
 	switch {
 	case ca.HasRecord():
 	case t.CheckGetCache():
-		download.GetCache(true, ca)
+		download.GetCache(t.My, true, ca)
 	default:
 		t.Print404(ca, "")
 		return
 	}
-	rec := record.New(ca.Datfile, id, stamp)
+	rec := record.New(t.My, ca.Datfile, id, stamp)
 	if !rec.Exists() {
 		t.Print404(ca, "")
 		return
@@ -594,7 +619,7 @@ func (t *threadCGI) makeRecord(at *attached, suffix string, ca *thread.Cache) (*
 	if t.Req.FormValue("error") != "" {
 		stamp = t.errorTime()
 	}
-	rec := record.New(ca.Datfile, "", 0)
+	rec := record.New(t.My, ca.Datfile, "", 0)
 	passwd := t.Req.FormValue("passwd")
 	rec.Build(stamp, body, passwd)
 	return rec, nil
@@ -608,7 +633,7 @@ func (t *threadCGI) doPost() string {
 		log.Println(attachedErr)
 	}
 	suffix := t.guessSuffix(attached)
-	ca := thread.NewCache(t.Req.FormValue("file"))
+	ca := thread.NewCache(t.My, t.Req.FormValue("file"))
 	rec, err := t.makeRecord(attached, suffix, ca)
 	if err != nil {
 		return ""
@@ -616,7 +641,7 @@ func (t *threadCGI) doPost() string {
 	proxyClient := t.Req.Header.Get("X_FORWARDED_FOR")
 	log.Printf("post %s/%d_%s from %s/%s\n", ca.Datfile, ca.Stamp(), rec.ID, t.Req.RemoteAddr, proxyClient)
 
-	if len(rec.Recstr()) > cfg.RecordLimit<<10 {
+	if len(rec.Recstr()) > t.My.Cfg.RecordLimit<<10 {
 		t.Header(t.M["big_file"], "", nil, true)
 		t.Footer(nil)
 		return ""
@@ -636,10 +661,10 @@ func (t *threadCGI) doPost() string {
 
 	if t.Req.FormValue("dopost") != "" {
 		log.Println(rec.Datfile, rec.ID, "is queued")
-		go updateque.UpdateNodes(rec, nil)
+		go t.Queue.UpdateNodes(rec, nil)
 	}
 
-	return rec.ID[:8]
+	return rec.LegacyID()[:8]
 
 }
 
@@ -652,7 +677,7 @@ type attached struct {
 //parseAttached reads attached file and returns attached obj.
 //if size>recordLimit renders error page.
 func (t *threadCGI) parseAttached() (*attached, error) {
-	err := t.Req.ParseMultipartForm(int64(cfg.RecordLimit) << 10)
+	err := t.Req.ParseMultipartForm(int64(t.My.Cfg.RecordLimit) << 10)
 	if err != nil {
 		return nil, err
 	}
@@ -670,9 +695,9 @@ func (t *threadCGI) parseAttached() (*attached, error) {
 		log.Println(err)
 		return nil, err
 	}
-	var strAttach = make([]byte, cfg.RecordLimit<<10)
+	var strAttach = make([]byte, t.My.Cfg.RecordLimit<<10)
 	s, err := f.Read(strAttach)
-	if s > cfg.RecordLimit<<10 {
+	if s > t.My.Cfg.RecordLimit<<10 {
 		log.Println("attached file is too big")
 		t.Header(t.M["big_file"], "", nil, true)
 		t.Footer(nil)
@@ -0,0 +1,264 @@
+/*
+ * Copyright (c) 2015, Shinya Yagyu
+ * All rights reserved.
+ * Redistribution and use in source and binary forms, with or without
+ * modification, are permitted provided that the following conditions are met:
+ *
+ * 1. Redistributions of source code must retain the above copyright notice,
+ *    this list of conditions and the following disclaimer.
+ * 2. Redistributions in binary form must reproduce the above copyright notice,
+ *    this list of conditions and the following disclaimer in the documentation
+ *    and/or other materials provided with the distribution.
+ * 3. Neither the name of the copyright holder nor the names of its
+ *    contributors may be used to endorse or promote products derived from this
+ *    software without specific prior written permission.
+ *
+ * THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+ * AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+ * IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+ * ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+ * LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+ * CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+ * SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+ * INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+ * CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+ * ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+ * POSSIBILITY OF SUCH DAMAGE.
+ */
+package db
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+	"bbs/cfg"
+)
+
+//snapshot files are named gou_bolt.db.<unix time>.snapshot in BackupDir of config.
+const (
+	snapshotPrefix = "gou_bolt.db."
+	snapshotSuffix = ".snapshot"
+)
+
+//WriteTo writes whole db s to w in one read transaction, without stopping writers.
+func WriteTo(s Store, w io.Writer) error {
+	b, ok := s.(*Bolt)
+	if !ok {
+		return errNotFile
+	}
+	return b.db.View(func(tx *bolt.Tx) error {
+		_, err := tx.WriteTo(w)
+		return err
+	})
+}
+
+//Snapshot writes whole db s to a new snapshot file in c.BackupDir and returns its path.
+func Snapshot(s Store, c *cfg.Config) (string, error) {
+	if err := os.MkdirAll(c.BackupDir, 0755); err != nil {
+		return "", err
+	}
+	fname := filepath.Join(c.BackupDir, fmt.Sprintf("%s%d%s", snapshotPrefix, time.Now().Unix(), snapshotSuffix))
+	tmp := fname + ".tmp"
+	f, err := os.OpenFile(tmp, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return "", err
+	}
+	err = WriteTo(s, f)
+	if errr := f.Close(); err == nil {
+		err = errr
+	}
+	if err != nil {
+		if errr := os.Remove(tmp); errr != nil {
+			log.Println(errr)
+		}
+		return "", err
+	}
+	return fname, os.Rename(tmp, fname)
+}
+
+//Snapshots returns paths of snapshot files in c.BackupDir, oldest first.
+func Snapshots(c *cfg.Config) ([]string, error) {
+	files, err := ioutil.ReadDir(c.BackupDir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	type snapshot struct {
+		name  string
+		stamp int64
+	}
+	var ss []snapshot
+	for _, f := range files {
+		n := f.Name()
+		if !strings.HasPrefix(n, snapshotPrefix) || !strings.HasSuffix(n, snapshotSuffix) {
+			continue
+		}
+		stamp, err := strconv.ParseInt(strings.TrimSuffix(strings.TrimPrefix(n, snapshotPrefix), snapshotSuffix), 10, 64)
+		if err != nil {
+			continue
+		}
+		ss = append(ss, snapshot{n, stamp})
+	}
+	sort.Slice(ss, func(i, j int) bool {
+		return ss[i].stamp < ss[j].stamp
+	})
+	r := make([]string, len(ss))
+	for i, s := range ss {
+		r[i] = filepath.Join(c.BackupDir, s.name)
+	}
+	return r, nil
+}
+
+//PruneSnapshots removes old snapshot files in c.BackupDir except newest count files.
+func PruneSnapshots(c *cfg.Config, count int) error {
+	ss, err := Snapshots(c)
+	if err != nil {
+		return err
+	}
+	for i := 0; i < len(ss)-count; i++ {
+		if err := os.Remove(ss[i]); err != nil {
+			return err
+		}
+		log.Println("removed old snapshot", ss[i])
+	}
+	return nil
+}
+
+//ScheduledSnapshot takes a snapshot of s if the newest one is older than c.BackupInterval,
+//and removes old ones except newest c.BackupCount files.
+func ScheduledSnapshot(s Store, c *cfg.Config) {
+	if c.BackupInterval <= 0 {
+		return
+	}
+	ss, err := Snapshots(c)
+	if err != nil {
+		log.Println(err)
+		return
+	}
+	if len(ss) > 0 {
+		st, err := os.Stat(ss[len(ss)-1])
+		if err == nil && st.ModTime().Unix() > time.Now().Unix()-c.BackupInterval {
+			return
+		}
+	}
+	fname, err := Snapshot(s, c)
+	if err != nil {
+		log.Println(err)
+		return
+	}
+	log.Println("took a snapshot of db", fname)
+	if err := PruneSnapshots(c, c.BackupCount); err != nil {
+		log.Println(err)
+	}
+}
+
+//CheckSnapshot checks consistency of the db file and returns its schema version.
+//it returns an error if the version is newer than SchemaVersion().
+func CheckSnapshot(fname string) (int, error) {
+	s, err := bolt.Open(fname, 0600, &bolt.Options{ReadOnly: true, Timeout: time.Second})
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		if err := s.Close(); err != nil {
+			log.Println(err)
+		}
+	}()
+	var ver int
+	err = s.View(func(tx *bolt.Tx) error {
+		var cerr error
+		for err := range tx.Check() {
+			if cerr == nil {
+				cerr = err
+			}
+		}
+		if cerr != nil {
+			return cerr
+		}
+		var err error
+		ver, err = GetVersion(&boltTx{tx})
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+	if ver > SchemaVersion() {
+		return ver, fmt.Errorf("schema version of snapshot %d is newer than %d", ver, SchemaVersion())
+	}
+	return ver, nil
+}
+
+//Restore replaces the db with the snapshot file after checking it.
+//the db must not be opened. the replaced db is renamed to gou_bolt.db.<unix time>.replaced.
+//the restored db is migrated to the newest schema in Setup().
+func Restore(c *cfg.Config, fname string) error {
+	ver, err := CheckSnapshot(fname)
+	if err != nil {
+		return err
+	}
+	dbpath := filepath.Join(c.RunDir, "gou_bolt.db")
+	_, err = os.Stat(dbpath)
+	exists := err == nil
+	if exists {
+		//fails if the db is used by others.
+		d, err := bolt.Open(dbpath, 0644, &bolt.Options{Timeout: time.Second})
+		if err != nil {
+			return errors.New("cannot lock db, gou may be running: " + err.Error())
+		}
+		if err := d.Close(); err != nil {
+			return err
+		}
+	}
+	tmp := dbpath + ".restore"
+	if err := copyFile(fname, tmp); err != nil {
+		return err
+	}
+	if exists {
+		old := fmt.Sprintf("%s.%d.replaced", dbpath, time.Now().Unix())
+		if err := os.Rename(dbpath, old); err != nil {
+			return err
+		}
+		log.Println("moved db to", old)
+	}
+	if err := os.Rename(tmp, dbpath); err != nil {
+		return err
+	}
+	log.Println("restored db from", fname, "version", ver)
+	return nil
+}
+
+//copyFile copies the file from to the file to.
+func copyFile(from, to string) error {
+	src, err := os.Open(from)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := src.Close(); err != nil {
+			log.Println(err)
+		}
+	}()
+	dst, err := os.OpenFile(to, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(dst, src); err != nil {
+		if errr := dst.Close(); errr != nil {
+			log.Println(errr)
+		}
+		return err
+	}
+	return dst.Close()
+}
@@ -0,0 +1,210 @@
+/*
+ * Copyright (c) 2015, Shinya Yagyu
+ * All rights reserved.
+ * Redistribution and use in source and binary forms, with or without
+ * modification, are permitted provided that the following conditions are met:
+ *
+ * 1. Redistributions of source code must retain the above copyright notice,
+ *    this list of conditions and the following disclaimer.
+ * 2. Redistributions in binary form must reproduce the above copyright notice,
+ *    this list of conditions and the following disclaimer in the documentation
+ *    and/or other materials provided with the distribution.
+ * 3. Neither the name of the copyright holder nor the names of its
+ *    contributors may be used to endorse or promote products derived from this
+ *    software without specific prior written permission.
+ *
+ * THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+ * AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+ * IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+ * ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+ * LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+ * CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+ * SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+ * INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+ * CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+ * ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+ * POSSIBILITY OF SUCH DAMAGE.
+ */
+package db
+
+import (
+	"errors"
+	"fmt"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+//Bolt is Store which saves data to a file by bbolt.
+//set type values are saved as nested buckets whose keys are members.
+type Bolt struct {
+	db *bolt.DB
+}
+
+//OpenBolt opens the bbolt file and returns Bolt.
+func OpenBolt(fname string, opts *bolt.Options) (*Bolt, error) {
+	d, err := bolt.Open(fname, 0644, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &Bolt{d}, nil
+}
+
+//View runs fn in a read-only transaction.
+func (s *Bolt) View(fn func(Tx) error) error {
+	return s.db.View(func(tx *bolt.Tx) error {
+		return fn(&boltTx{tx})
+	})
+}
+
+//Update runs fn in a read-write transaction.
+func (s *Bolt) Update(fn func(Tx) error) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return fn(&boltTx{tx})
+	})
+}
+
+//Close closes the db file.
+func (s *Bolt) Close() error {
+	return s.db.Close()
+}
+
+//boltTx is Tx of Bolt.
+type boltTx struct {
+	tx *bolt.Tx
+}
+
+//Get returns the value of key in bucket.
+func (t *boltTx) Get(bucket string, key []byte) []byte {
+	b := t.tx.Bucket([]byte(bucket))
+	if b == nil {
+		return nil
+	}
+	return b.Get(key)
+}
+
+//Put sets the value of key in bucket.
+func (t *boltTx) Put(bucket string, key, value []byte) error {
+	b, err := t.tx.CreateBucketIfNotExists([]byte(bucket))
+	if err != nil {
+		return fmt.Errorf("create bucket: %s", err)
+	}
+	return b.Put(key, value)
+}
+
+//Delete deletes key in bucket.
+func (t *boltTx) Delete(bucket string, key []byte) error {
+	b := t.tx.Bucket([]byte(bucket))
+	if b == nil {
+		return errors.New("bucket not found " + bucket)
+	}
+	if b.Bucket(key) != nil {
+		return b.DeleteBucket(key)
+	}
+	return b.Delete(key)
+}
+
+//HasBucket returns true if bucket exists.
+func (t *boltTx) HasBucket(bucket string) bool {
+	return t.tx.Bucket([]byte(bucket)) != nil
+}
+
+//DeleteBucket deletes bucket.
+func (t *boltTx) DeleteBucket(bucket string) error {
+	return t.tx.DeleteBucket([]byte(bucket))
+}
+
+//Buckets returns names of all buckets.
+func (t *boltTx) Buckets() []string {
+	var names []string
+	err := t.tx.ForEach(func(name []byte, b *bolt.Bucket) error {
+		names = append(names, string(name))
+		return nil
+	})
+	if err != nil {
+		return nil
+	}
+	return names
+}
+
+//Cursor returns a cursor of bucket.
+func (t *boltTx) Cursor(bucket string) Cursor {
+	b := t.tx.Bucket([]byte(bucket))
+	if b == nil {
+		return emptyCursor{}
+	}
+	return b.Cursor()
+}
+
+//set returns nested bucket of set type value.
+func (t *boltTx) set(bucket string, key []byte) (*bolt.Bucket, error) {
+	b := t.tx.Bucket([]byte(bucket))
+	if b == nil {
+		return nil, errors.New("bucket not found " + bucket)
+	}
+	m := b.Bucket(key)
+	if m == nil {
+		return nil, errors.New("key not found")
+	}
+	return m, nil
+}
+
+//AddMember adds member to the set of key in bucket.
+func (t *boltTx) AddMember(bucket string, key []byte, member string) error {
+	b, err := t.tx.CreateBucketIfNotExists([]byte(bucket))
+	if err != nil {
+		return fmt.Errorf("create bucket: %s", err)
+	}
+	m, err := b.CreateBucketIfNotExists(key)
+	if err != nil {
+		return fmt.Errorf("create bucket: %s", err)
+	}
+	return m.Put([]byte(member), []byte{})
+}
+
+//DelMember deletes member from the set of key in bucket.
+func (t *boltTx) DelMember(bucket string, key []byte, member string) error {
+	m, err := t.set(bucket, key)
+	if err != nil {
+		return err
+	}
+	if err := m.Delete([]byte(member)); err != nil {
+		return err
+	}
+	if k, _ := m.Cursor().First(); k == nil {
+		return t.Delete(bucket, key)
+	}
+	return nil
+}
+
+//Members returns members of the set of key in bucket.
+func (t *boltTx) Members(bucket string, key []byte) ([]string, error) {
+	m, err := t.set(bucket, key)
+	if err != nil {
+		return nil, err
+	}
+	var r []string
+	err = m.ForEach(func(k, v []byte) error {
+		r = append(r, string(k))
+		return nil
+	})
+	return r, err
+}
+
+//IsMember returns true if the set of key in bucket has member.
+func (t *boltTx) IsMember(bucket string, key []byte, member string) bool {
+	m, err := t.set(bucket, key)
+	if err != nil {
+		return false
+	}
+	k, _ := m.Cursor().Seek([]byte(member))
+	return k != nil && string(k) == member
+}
+
+//emptyCursor is Cursor of the bucket which doesn't exist.
+type emptyCursor struct{}
+
+func (emptyCursor) First() ([]byte, []byte)      { return nil, nil }
+func (emptyCursor) Last() ([]byte, []byte)       { return nil, nil }
+func (emptyCursor) Seek([]byte) ([]byte, []byte) { return nil, nil }
+func (emptyCursor) Next() ([]byte, []byte)       { return nil, nil }
+func (emptyCursor) Prev() ([]byte, []byte)       { return nil, nil }
@@ -32,13 +32,11 @@ import (
 	"bytes"
 	"encoding/binary"
 	"errors"
-	"fmt"
 	"log"
 	"path"
 
 	"encoding/json"
 
-	"github.com/boltdb/bolt"
 	"bbs/cfg"
 )
 
@@ -47,14 +45,26 @@ bucket key value
 
 keylibST Stamp Thread
 keylibTS Thread Stamp
-lookupT Thread json(map[addr]struct{})
-lookupA Addr json(map[threads]struct{})
+lookupT Thread bucket(addr "")
+lookupA Addr bucket(threads "")
 thread Thread ""
-sugtag Thread json(map[tags]struct{})
-usertag Thread json(map[tags]struct{})
-usertagTag Tag json(map[threads]struct{})
+sugtag Thread bucket(tags "")
+usertag Thread bucket(tags "")
+usertagTag Tag bucket(threads "")
 recent thread:stamp:hash json(Datfile,Stamp.ID)
-record thread:stamp:hash json(Datfile,Stamp.ID,Body,Deleted)
+record thread:stamp:hash json(Datfile,Stamp.ID,Body,Deleted,Verified,LegacyID,Attach,AttachLen)
+blob sha256 attached file
+blobref sha256 # of records which refer the blob
+remove thread:stamp:hash:stamp:hash json(Target,Stamp,ID,Pubkey,Moderator,Applied)
+threadstat Thread json(Alive,Removed,Size,First,Last,LastAlive,Days)
+index term:thread:stamp:hash json(Datfile,Stamp,ID,TF,Len)
+titleindex term:thread ""
+updateque thread:stamp:hash:node json(Datfile,Stamp,ID,Kind,Node,Added,Tries,Next,Err)
+updated hash stamp
+delivery thread:stamp:hash:node json(Node,Result,Stamp)
+nodescore node json(OK,NG,Timeout,Refused,Error,Refusals,Rate,Last)
+meta "version" schema version
+meta "index" json(Docs,Tokens)
 
 
 var tables = []string{
@@ -68,17 +78,23 @@ var tables = []string{
 }
 */
 
-//DB is bolt.DB for operating database.
-var DB *bolt.DB
+//Setup opens db in c.RunDir and migrates it to the newest schema.
+func Setup(c *cfg.Config) Store {
+	s := Open(c)
+	if err := Migrate(s, c, false); err != nil {
+		log.Fatal(err)
+	}
+	return s
+}
 
-//Setup setups db.
-func Setup() {
-	dbpath := path.Join(cfg.RunDir, "gou_bolt.db")
-	var err error
-	DB, err = bolt.Open(dbpath, 0644, nil)
+//Open opens db in c.RunDir.
+func Open(c *cfg.Config) Store {
+	dbpath := path.Join(c.RunDir, "gou_bolt.db")
+	s, err := OpenBolt(dbpath, nil)
 	if err != nil {
 		log.Fatal(err)
 	}
+	return s
 }
 
 // Tob returns an 8-byte big endian representation of v.
@@ -148,12 +164,11 @@ func b2v(from []byte, to interface{}) error {
 }
 
 //Get gets one value from db and converts it to value type.
-func Get(tx *bolt.Tx, bucket string, key []byte, value interface{}) ([]byte, error) {
-	b := tx.Bucket([]byte(bucket))
-	if b == nil {
+func Get(tx Tx, bucket string, key []byte, value interface{}) ([]byte, error) {
+	if !tx.HasBucket(bucket) {
 		return nil, errors.New("bucket not found " + bucket)
 	}
-	v := b.Get(key)
+	v := tx.Get(bucket, key)
 	if v == nil {
 		return nil, errors.New("key not found")
 	}
@@ -161,69 +176,59 @@ func Get(tx *bolt.Tx, bucket string, key []byte, value interface{}) ([]byte, err
 }
 
 //Put sets one key/value pair.
-func Put(tx *bolt.Tx, bucket string, key []byte, value interface{}) error {
+func Put(tx Tx, bucket string, key []byte, value interface{}) error {
 	val, err := Tob(value)
 	if err != nil {
 		return err
 	}
-	b, errr := tx.CreateBucketIfNotExists([]byte(bucket))
-	if errr != nil {
-		return fmt.Errorf("create bucket: %s", err)
-	}
-	return b.Put(key, val)
+	return tx.Put(bucket, key, val)
 }
 
 //HasKey returns true if db has key.
-func HasKey(tx *bolt.Tx, bucket string, key []byte) (bool, error) {
-	var v []byte
-	b := tx.Bucket([]byte(bucket))
-	if b == nil {
+func HasKey(tx Tx, bucket string, key []byte) (bool, error) {
+	if !tx.HasBucket(bucket) {
 		return false, errors.New("bucket not found " + bucket)
 	}
-	v = b.Get(key)
-	return v != nil, nil
+	return tx.Get(bucket, key) != nil, nil
 }
 
 //Count counts #data whose key has prefix.
-func Count(tx *bolt.Tx, bucket string, prefix []byte) (int, error) {
+func Count(tx Tx, bucket string, prefix []byte) (int, error) {
 	var cnt int
-	b := tx.Bucket([]byte(bucket))
-	if b == nil {
+	if !tx.HasBucket(bucket) {
 		return 0, errors.New("bucket not found " + bucket)
 	}
-	c := b.Cursor()
-	for k, _ := c.Seek(prefix); bytes.HasPrefix(k, prefix); k, _ = c.Next() {
+	err := ForEachPrefix(tx, bucket, prefix, func(k, v []byte) error {
 		cnt++
-	}
-	return cnt, nil
+		return nil
+	})
+	return cnt, err
 }
 
 //GetStrings returns string values whose key has prefix.
-func GetStrings(tx *bolt.Tx, bucket string, prefix []byte) ([]string, error) {
+func GetStrings(tx Tx, bucket string, prefix []byte) ([]string, error) {
 	var cnt []string
-	b := tx.Bucket([]byte(bucket))
-	if b == nil {
+	if !tx.HasBucket(bucket) {
 		return nil, errors.New("bucket not found " + bucket)
 	}
-	c := b.Cursor()
-	for k, v := c.Seek(prefix); bytes.HasPrefix(k, prefix); k, v = c.Next() {
+	err := ForEachPrefix(tx, bucket, prefix, func(k, v []byte) error {
 		var str string
 		if err := b2v(v, &str); err != nil {
-			return nil, err
+			return err
 		}
 		cnt = append(cnt, str)
-	}
-	return cnt, nil
+		return nil
+	})
+	return cnt, err
 }
 
 //KeyStrings returns string keys.
-func KeyStrings(tx *bolt.Tx, bucket string) ([]string, error) {
+func KeyStrings(tx Tx, bucket string) ([]string, error) {
 	var cnt []string
-	b := tx.Bucket([]byte(bucket))
-	if b == nil {
+	if !tx.HasBucket(bucket) {
 		return nil, errors.New("bucket not found " + bucket)
 	}
-	err := b.ForEach(func(k, v []byte) error {
+	err := ForEach(tx, bucket, func(k, v []byte) error {
 		var str string
 		if err := b2v(k, &str); err != nil {
 			return err
@@ -234,80 +239,97 @@ func KeyStrings(tx *bolt.Tx, bucket string) ([]string, error) {
 	return cnt, err
 }
 
-//Del deletes one key-value pair.
-func Del(tx *bolt.Tx, bucket string, key []byte) error {
-	b := tx.Bucket([]byte(bucket))
-	if b == nil {
-		return errors.New("bucket not found " + bucket)
+//Del deletes one key-value pair, or set type value.
+func Del(tx Tx, bucket string, key []byte) error {
+	return tx.Delete(bucket, key)
+}
+
+//GetMap gets set type value as map[string]struct{}.
+func GetMap(tx Tx, bucket string, key []byte) (map[string]struct{}, error) {
+	ms, err := tx.Members(bucket, key)
+	if err != nil {
+		return nil, err
+	}
+	rs := make(map[string]struct{})
+	for _, m := range ms {
+		rs[m] = struct{}{}
 	}
-	return b.Delete(key)
+	return rs, nil
 }
 
-//GetMap gets map[string]struct{} value.
-func GetMap(tx *bolt.Tx, bucket string, key []byte) (map[string]struct{}, error) {
-	var rs map[string]struct{}
-	_, err := Get(tx, bucket, key, &rs)
-	return rs, err
+//PutMap adds val to set type value.
+func PutMap(tx Tx, bucket string, key []byte, val string) error {
+	return tx.AddMember(bucket, key, val)
 }
 
-//PutMap adds val to map[string]struct{} type value.
-func PutMap(tx *bolt.Tx, bucket string, key []byte, val string) error {
-	rs, err := GetMap(tx, bucket, key)
-	if err != nil {
-		rs = make(map[string]struct{})
-	}
-	rs[val] = struct{}{}
-	return Put(tx, bucket, key, rs)
+//DelMap deletes val from set type value.
+//if set becomes empty, deletes the key.
+func DelMap(tx Tx, bucket string, key []byte, val string) error {
+	return tx.DelMember(bucket, key, val)
 }
 
-//DelMap deletes val from map[string]struct{} type value.
-func DelMap(tx *bolt.Tx, bucket string, key []byte, val string) error {
-	rs, err := GetMap(tx, bucket, key)
-	if err != nil {
-		return err
-	}
-	delete(rs, val)
-	if len(rs) == 0 {
-		return Del(tx, bucket, key)
-	}
-	return Put(tx, bucket, key, rs)
+//MapKeys returns []string from members of set type value.
+func MapKeys(tx Tx, bucket string, key []byte) ([]string, error) {
+	return tx.Members(bucket, key)
 }
 
-//MapKeys returns []string from keys of map[string]struct{} type value
-func MapKeys(tx *bolt.Tx, bucket string, key []byte) ([]string, error) {
-	m, err := GetMap(tx, bucket, key)
-	if err != nil {
-		return nil, err
-	}
-	r := make([]string, len(m))
-	i := 0
-	for k := range m {
-		r[i] = k
-		i++
-	}
-	return r, nil
+//HasVal returns true if set type values has val.
+func HasVal(tx Tx, bucket string, key []byte, val string) bool {
+	return tx.IsMember(bucket, key, val)
 }
 
-//HasVal returns true if map[string]struct{} type values has val.
-func HasVal(tx *bolt.Tx, bucket string, key []byte, val string) bool {
-	m, err := GetMap(tx, bucket, key)
-	if err != nil {
-		return false
+//mapBuckets are buckets which have set type values.
+var mapBuckets = []string{"lookupT", "lookupA", "sugtag", "usertag", "usertagTag"}
+
+func init() {
+	RegisterMigration(&Migration{
+		Version: 1,
+		Name:    "json sets to nested buckets",
+		Migrate: migrateMapBuckets,
+	})
+}
+
+//migrateMapBuckets converts json encoded map[string]struct{} values to set type values.
+func migrateMapBuckets(tx Tx) error {
+	for _, bucket := range mapBuckets {
+		sets := make(map[string]map[string]struct{})
+		err := ForEach(tx, bucket, func(k, v []byte) error {
+			if v == nil {
+				return nil
+			}
+			var rs map[string]struct{}
+			if err := json.Unmarshal(v, &rs); err != nil {
+				return err
+			}
+			sets[string(k)] = rs
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		for key, rs := range sets {
+			if err := tx.Delete(bucket, []byte(key)); err != nil {
+				return err
+			}
+			for val := range rs {
+				if err := PutMap(tx, bucket, []byte(key), val); err != nil {
+					return err
+				}
+			}
+		}
 	}
-	_, exist := m[val]
-	return exist
+	return nil
 }
 
 //GetPrefixs get string prefixs of keys.
-func GetPrefixs(tx *bolt.Tx, bucket string) ([]string, error) {
+func GetPrefixs(tx Tx, bucket string) ([]string, error) {
 	var cnt []string
 	var last string
 	var blast []byte
-	b := tx.Bucket([]byte(bucket))
-	if b == nil {
+	if !tx.HasBucket(bucket) {
 		return nil, errors.New("bucket not found " + bucket)
 	}
-	err := b.ForEach(func(k, v []byte) error {
+	err := ForEach(tx, bucket, func(k, v []byte) error {
 		if blast != nil && bytes.HasPrefix(k, blast) {
 			return nil
 		}
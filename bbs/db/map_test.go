@@ -0,0 +1,149 @@
+/*
+ * Copyright (c) 2015, Shinya Yagyu
+ * All rights reserved.
+ * Redistribution and use in source and binary forms, with or without
+ * modification, are permitted provided that the following conditions are met:
+ *
+ * 1. Redistributions of source code must retain the above copyright notice,
+ *    this list of conditions and the following disclaimer.
+ * 2. Redistributions in binary form must reproduce the above copyright notice,
+ *    this list of conditions and the following disclaimer in the documentation
+ *    and/or other materials provided with the distribution.
+ * 3. Neither the name of the copyright holder nor the names of its
+ *    contributors may be used to endorse or promote products derived from this
+ *    software without specific prior written permission.
+ *
+ * THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+ * AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+ * IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+ * ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+ * LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+ * CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+ * SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+ * INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+ * CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+ * ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+ * POSSIBILITY OF SUCH DAMAGE.
+ */
+package db
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"sort"
+	"testing"
+)
+
+//openTempDB opens db in a temporary directory and returns it and func for cleanup.
+func openTempDB(t testing.TB) (*Bolt, func()) {
+	dir, err := ioutil.TempDir("", "gou")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := OpenBolt(path.Join(dir, "gou_bolt.db"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s, func() {
+		s.Close()
+		os.RemoveAll(dir)
+	}
+}
+
+//putMapJSON is the old PutMap which rewrites whole json map, for comparison.
+func putMapJSON(tx Tx, bucket string, key []byte, val string) error {
+	var rs map[string]struct{}
+	if _, err := Get(tx, bucket, key, &rs); err != nil {
+		rs = make(map[string]struct{})
+	}
+	rs[val] = struct{}{}
+	return Put(tx, bucket, key, rs)
+}
+
+func TestMap(t *testing.T) {
+	s, cleanup := openTempDB(t)
+	defer cleanup()
+	err := s.Update(func(tx Tx) error {
+		for _, v := range []string{"b", "a", "c"} {
+			if err := PutMap(tx, "lookupA", []byte("node"), v); err != nil {
+				return err
+			}
+		}
+		return DelMap(tx, "lookupA", []byte("node"), "c")
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.View(func(tx Tx) error {
+		keys, err := MapKeys(tx, "lookupA", []byte("node"))
+		sort.Strings(keys)
+		if err != nil || fmt.Sprint(keys) != "[a b]" {
+			t.Fatal("keys unmatch", keys, err)
+		}
+		if !HasVal(tx, "lookupA", []byte("node"), "a") || HasVal(tx, "lookupA", []byte("node"), "c") {
+			t.Fatal("HasVal failed")
+		}
+		return nil
+	})
+	err = s.Update(func(tx Tx) error {
+		DelMap(tx, "lookupA", []byte("node"), "a")
+		DelMap(tx, "lookupA", []byte("node"), "b")
+		if _, err := GetMap(tx, "lookupA", []byte("node")); err == nil {
+			t.Fatal("empty set remains")
+		}
+		if err := putMapJSON(tx, "lookupT", []byte("thread"), "node"); err != nil {
+			return err
+		}
+		return migrateMapBuckets(tx)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.View(func(tx Tx) error {
+		if !HasVal(tx, "lookupT", []byte("thread"), "node") {
+			t.Fatal("migration failed")
+		}
+		return nil
+	})
+}
+
+//benchmarkPutMap adds one thread to a node which already has nthreads threads.
+func benchmarkPutMap(b *testing.B, nthreads int, put func(Tx, string, []byte, string) error) {
+	s, cleanup := openTempDB(b)
+	defer cleanup()
+	err := s.Update(func(tx Tx) error {
+		for i := 0; i < nthreads; i++ {
+			if err := put(tx, "lookupA", []byte("node"), fmt.Sprintf("thread_%08d", i)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		b.Fatal(err)
+	}
+	before := s.db.Stats().TxStats
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		err := s.Update(func(tx Tx) error {
+			return put(tx, "lookupA", []byte("node"), fmt.Sprintf("new_%08d", i))
+		})
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+	b.StopTimer()
+	after := s.db.Stats().TxStats
+	diff := after.Sub(&before)
+	b.ReportMetric(float64(diff.PageAlloc)/float64(b.N), "pagebytes/op")
+}
+
+func BenchmarkPutMap5000(b *testing.B) {
+	benchmarkPutMap(b, 5000, PutMap)
+}
+
+func BenchmarkPutMapJSON5000(b *testing.B) {
+	benchmarkPutMap(b, 5000, putMapJSON)
+}
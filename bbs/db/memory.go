@@ -0,0 +1,381 @@
+/*
+ * Copyright (c) 2015, Shinya Yagyu
+ * All rights reserved.
+ * Redistribution and use in source and binary forms, with or without
+ * modification, are permitted provided that the following conditions are met:
+ *
+ * 1. Redistributions of source code must retain the above copyright notice,
+ *    this list of conditions and the following disclaimer.
+ * 2. Redistributions in binary form must reproduce the above copyright notice,
+ *    this list of conditions and the following disclaimer in the documentation
+ *    and/or other materials provided with the distribution.
+ * 3. Neither the name of the copyright holder nor the names of its
+ *    contributors may be used to endorse or promote products derived from this
+ *    software without specific prior written permission.
+ *
+ * THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+ * AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+ * IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+ * ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+ * LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+ * CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+ * SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+ * INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+ * CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+ * ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+ * POSSIBILITY OF SUCH DAMAGE.
+ */
+package db
+
+import (
+	"errors"
+	"sort"
+	"sync"
+)
+
+var errClosed = errors.New("db is closed")
+
+//Memory is Store which keeps data in memory, mainly for tests.
+//Update works on a copy of whole data, which replaces the data when committed,
+//so View can read the last committed data without waiting Update.
+type Memory struct {
+	update  sync.Mutex //serializes Updates
+	mutex   sync.RWMutex
+	buckets map[string]*memBucket
+}
+
+//NewMemory returns an empty Memory.
+func NewMemory() *Memory {
+	return &Memory{
+		buckets: make(map[string]*memBucket),
+	}
+}
+
+//data returns the last committed data.
+func (s *Memory) data() map[string]*memBucket {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return s.buckets
+}
+
+//View runs fn in a read-only transaction.
+func (s *Memory) View(fn func(Tx) error) error {
+	d := s.data()
+	if d == nil {
+		return errClosed
+	}
+	return fn(&memTx{buckets: d})
+}
+
+//Update runs fn in a read-write transaction.
+func (s *Memory) Update(fn func(Tx) error) error {
+	s.update.Lock()
+	defer s.update.Unlock()
+	d := s.data()
+	if d == nil {
+		return errClosed
+	}
+	tx := &memTx{
+		buckets:  make(map[string]*memBucket, len(d)),
+		writable: true,
+	}
+	for name, b := range d {
+		tx.buckets[name] = b.clone()
+	}
+	if err := fn(tx); err != nil {
+		return err
+	}
+	s.mutex.Lock()
+	s.buckets = tx.buckets
+	s.mutex.Unlock()
+	return nil
+}
+
+//Close discards all data.
+func (s *Memory) Close() error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.buckets == nil {
+		return errClosed
+	}
+	s.buckets = nil
+	return nil
+}
+
+//memBucket is a bucket of Memory.
+type memBucket struct {
+	keys []string //sorted
+	vals map[string][]byte
+	sets map[string]map[string]struct{}
+}
+
+//newMemBucket returns an empty bucket.
+func newMemBucket() *memBucket {
+	return &memBucket{
+		vals: make(map[string][]byte),
+		sets: make(map[string]map[string]struct{}),
+	}
+}
+
+//clone returns a copy of b. values are shared because they are not modified.
+func (b *memBucket) clone() *memBucket {
+	c := &memBucket{
+		keys: append([]string{}, b.keys...),
+		vals: make(map[string][]byte, len(b.vals)),
+		sets: make(map[string]map[string]struct{}, len(b.sets)),
+	}
+	for k, v := range b.vals {
+		c.vals[k] = v
+	}
+	for k, set := range b.sets {
+		cs := make(map[string]struct{}, len(set))
+		for m := range set {
+			cs[m] = struct{}{}
+		}
+		c.sets[k] = cs
+	}
+	return c
+}
+
+//has returns true if b has key k.
+func (b *memBucket) has(k string) bool {
+	i := sort.SearchStrings(b.keys, k)
+	return i < len(b.keys) && b.keys[i] == k
+}
+
+//add adds key k to sorted keys if not exists.
+func (b *memBucket) add(k string) {
+	i := sort.SearchStrings(b.keys, k)
+	if i < len(b.keys) && b.keys[i] == k {
+		return
+	}
+	b.keys = append(b.keys, "")
+	copy(b.keys[i+1:], b.keys[i:])
+	b.keys[i] = k
+}
+
+//del deletes key k and its value or set.
+func (b *memBucket) del(k string) {
+	i := sort.SearchStrings(b.keys, k)
+	if i < len(b.keys) && b.keys[i] == k {
+		b.keys = append(b.keys[:i], b.keys[i+1:]...)
+	}
+	delete(b.vals, k)
+	delete(b.sets, k)
+}
+
+//memTx is Tx of Memory.
+type memTx struct {
+	buckets  map[string]*memBucket
+	writable bool
+}
+
+var errReadOnly = errors.New("tx is read-only")
+
+//Get returns the value of key in bucket.
+func (t *memTx) Get(bucket string, key []byte) []byte {
+	b := t.buckets[bucket]
+	if b == nil {
+		return nil
+	}
+	return b.vals[string(key)]
+}
+
+//Put sets the value of key in bucket.
+func (t *memTx) Put(bucket string, key, value []byte) error {
+	if !t.writable {
+		return errReadOnly
+	}
+	if len(key) == 0 {
+		return errors.New("key required")
+	}
+	b := t.bucket(bucket)
+	k := string(key)
+	if _, ok := b.sets[k]; ok {
+		return errors.New("incompatible value")
+	}
+	b.add(k)
+	b.vals[k] = append([]byte{}, value...)
+	return nil
+}
+
+//bucket returns bucket and creates it if not exists.
+func (t *memTx) bucket(bucket string) *memBucket {
+	b := t.buckets[bucket]
+	if b == nil {
+		b = newMemBucket()
+		t.buckets[bucket] = b
+	}
+	return b
+}
+
+//Delete deletes key in bucket.
+func (t *memTx) Delete(bucket string, key []byte) error {
+	if !t.writable {
+		return errReadOnly
+	}
+	b := t.buckets[bucket]
+	if b == nil {
+		return errors.New("bucket not found " + bucket)
+	}
+	b.del(string(key))
+	return nil
+}
+
+//HasBucket returns true if bucket exists.
+func (t *memTx) HasBucket(bucket string) bool {
+	return t.buckets[bucket] != nil
+}
+
+//DeleteBucket deletes bucket.
+func (t *memTx) DeleteBucket(bucket string) error {
+	if !t.writable {
+		return errReadOnly
+	}
+	if t.buckets[bucket] == nil {
+		return errors.New("bucket not found")
+	}
+	delete(t.buckets, bucket)
+	return nil
+}
+
+//Buckets returns names of all buckets in byte order.
+func (t *memTx) Buckets() []string {
+	var names []string
+	for name := range t.buckets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+//Cursor returns a cursor of bucket.
+func (t *memTx) Cursor(bucket string) Cursor {
+	b := t.buckets[bucket]
+	if b == nil {
+		return emptyCursor{}
+	}
+	return &memCursor{b: b}
+}
+
+//AddMember adds member to the set of key in bucket.
+func (t *memTx) AddMember(bucket string, key []byte, member string) error {
+	if !t.writable {
+		return errReadOnly
+	}
+	if len(key) == 0 || member == "" {
+		return errors.New("key required")
+	}
+	b := t.bucket(bucket)
+	k := string(key)
+	if _, ok := b.vals[k]; ok {
+		return errors.New("incompatible value")
+	}
+	set := b.sets[k]
+	if set == nil {
+		set = make(map[string]struct{})
+		b.sets[k] = set
+		b.add(k)
+	}
+	set[member] = struct{}{}
+	return nil
+}
+
+//DelMember deletes member from the set of key in bucket.
+func (t *memTx) DelMember(bucket string, key []byte, member string) error {
+	if !t.writable {
+		return errReadOnly
+	}
+	set, err := t.set(bucket, key)
+	if err != nil {
+		return err
+	}
+	delete(set, member)
+	if len(set) == 0 {
+		t.buckets[bucket].del(string(key))
+	}
+	return nil
+}
+
+//set returns the set of key in bucket.
+func (t *memTx) set(bucket string, key []byte) (map[string]struct{}, error) {
+	b := t.buckets[bucket]
+	if b == nil {
+		return nil, errors.New("bucket not found " + bucket)
+	}
+	set := b.sets[string(key)]
+	if set == nil {
+		return nil, errors.New("key not found")
+	}
+	return set, nil
+}
+
+//Members returns members of the set of key in bucket in byte order.
+func (t *memTx) Members(bucket string, key []byte) ([]string, error) {
+	set, err := t.set(bucket, key)
+	if err != nil {
+		return nil, err
+	}
+	r := make([]string, 0, len(set))
+	for m := range set {
+		r = append(r, m)
+	}
+	sort.Strings(r)
+	return r, nil
+}
+
+//IsMember returns true if the set of key in bucket has member.
+func (t *memTx) IsMember(bucket string, key []byte, member string) bool {
+	set, err := t.set(bucket, key)
+	if err != nil {
+		return false
+	}
+	_, ok := set[member]
+	return ok
+}
+
+//memCursor is Cursor of memBucket.
+//it remembers the current key instead of the index, so that keys can be added or deleted
+//while iterating.
+type memCursor struct {
+	b   *memBucket
+	key string
+}
+
+//at returns key and value at i-th key.
+func (c *memCursor) at(i int) ([]byte, []byte) {
+	if i < 0 || i >= len(c.b.keys) {
+		return nil, nil
+	}
+	c.key = c.b.keys[i]
+	return []byte(c.key), c.b.vals[c.key]
+}
+
+//First moves to the first key.
+func (c *memCursor) First() ([]byte, []byte) {
+	return c.at(0)
+}
+
+//Last moves to the last key.
+func (c *memCursor) Last() ([]byte, []byte) {
+	return c.at(len(c.b.keys) - 1)
+}
+
+//Seek moves to the first key which is seek or after.
+func (c *memCursor) Seek(seek []byte) ([]byte, []byte) {
+	return c.at(sort.SearchStrings(c.b.keys, string(seek)))
+}
+
+//Next moves to the next key.
+func (c *memCursor) Next() ([]byte, []byte) {
+	i := sort.SearchStrings(c.b.keys, c.key)
+	if i < len(c.b.keys) && c.b.keys[i] == c.key {
+		i++
+	}
+	return c.at(i)
+}
+
+//Prev moves to the previous key.
+func (c *memCursor) Prev() ([]byte, []byte) {
+	return c.at(sort.SearchStrings(c.b.keys, c.key) - 1)
+}
@@ -0,0 +1,180 @@
+/*
+ * Copyright (c) 2015, Shinya Yagyu
+ * All rights reserved.
+ * Redistribution and use in source and binary forms, with or without
+ * modification, are permitted provided that the following conditions are met:
+ *
+ * 1. Redistributions of source code must retain the above copyright notice,
+ *    this list of conditions and the following disclaimer.
+ * 2. Redistributions in binary form must reproduce the above copyright notice,
+ *    this list of conditions and the following disclaimer in the documentation
+ *    and/or other materials provided with the distribution.
+ * 3. Neither the name of the copyright holder nor the names of its
+ *    contributors may be used to endorse or promote products derived from this
+ *    software without specific prior written permission.
+ *
+ * THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+ * AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+ * IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+ * ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+ * LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+ * CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+ * SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+ * INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+ * CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+ * ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+ * POSSIBILITY OF SUCH DAMAGE.
+ */
+package db
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"path"
+	"sort"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+	"bbs/cfg"
+)
+
+//Migration converts db from the previous version to Version.
+type Migration struct {
+	Version int
+	Name    string
+	Migrate func(tx Tx) error
+}
+
+var migrations []*Migration
+
+var errDryRun = errors.New("dry run")
+
+var errNotFile = errors.New("db is not a file")
+
+//RegisterMigration registers a migration. migrations are run in order of Version.
+func RegisterMigration(m *Migration) {
+	for _, mm := range migrations {
+		if mm.Version == m.Version {
+			log.Fatal("duplicate migration version ", m.Version, m.Name, mm.Name)
+		}
+	}
+	migrations = append(migrations, m)
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+}
+
+//SchemaVersion returns the newest schema version of db.
+func SchemaVersion() int {
+	if len(migrations) == 0 {
+		return 0
+	}
+	return migrations[len(migrations)-1].Version
+}
+
+//GetVersion returns schema version of db in tx.
+//if db doesn't have meta bucket, returns 0 for old db, or SchemaVersion() for empty db.
+func GetVersion(tx Tx) (int, error) {
+	var ver int
+	_, err := Get(tx, "meta", []byte("version"), &ver)
+	if err == nil {
+		return ver, nil
+	}
+	if tx.HasBucket("meta") {
+		return 0, err
+	}
+	if len(tx.Buckets()) == 0 {
+		return SchemaVersion(), nil
+	}
+	return 0, nil
+}
+
+//setVersion sets schema version of db.
+func setVersion(tx Tx, ver int) error {
+	return Put(tx, "meta", []byte("version"), ver)
+}
+
+//Backup writes whole db s to the file.
+//db which is not saved to a file, e.g. Memory, cannot be backed up.
+func Backup(s Store, fname string) error {
+	b, ok := s.(*Bolt)
+	if !ok {
+		return errNotFile
+	}
+	return b.db.View(func(tx *bolt.Tx) error {
+		return tx.CopyFile(fname, 0600)
+	})
+}
+
+//Migrate runs registered migrations newer than the version of db s in order.
+//db is backed up to c.RunDir once before the first migration, and each migration runs in one transaction.
+//if dryRun, runs all migrations in one transaction and rollbacks it without backing up.
+func Migrate(s Store, c *cfg.Config, dryRun bool) error {
+	var ver int
+	err := s.View(func(tx Tx) error {
+		var err error
+		ver, err = GetVersion(tx)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	if ver > SchemaVersion() {
+		return fmt.Errorf("schema version of db %d is newer than %d", ver, SchemaVersion())
+	}
+	var pending []*Migration
+	for _, m := range migrations {
+		if m.Version > ver {
+			pending = append(pending, m)
+		}
+	}
+	if dryRun {
+		return dryMigrate(s, ver, pending)
+	}
+	if len(pending) > 0 {
+		fname := path.Join(c.RunDir, fmt.Sprintf("gou_bolt.db.v%d.%d.bak", ver, time.Now().Unix()))
+		switch err := Backup(s, fname); err {
+		case nil:
+			log.Println("backed up db to", fname)
+		case errNotFile:
+		default:
+			return err
+		}
+	}
+	for _, m := range pending {
+		err := s.Update(func(tx Tx) error {
+			if err := m.Migrate(tx); err != nil {
+				return err
+			}
+			return setVersion(tx, m.Version)
+		})
+		if err != nil {
+			return fmt.Errorf("migration %d %s failed: %s", m.Version, m.Name, err)
+		}
+		log.Println("migrated db to version", m.Version, m.Name)
+		ver = m.Version
+	}
+	return s.Update(func(tx Tx) error {
+		return setVersion(tx, ver)
+	})
+}
+
+//dryMigrate runs migrations from version ver in one transaction and rollbacks it.
+func dryMigrate(s Store, ver int, pending []*Migration) error {
+	err := s.Update(func(tx Tx) error {
+		for _, m := range pending {
+			log.Println("migrating db from version", ver, "to", m.Version, m.Name)
+			if err := m.Migrate(tx); err != nil {
+				return fmt.Errorf("migration %d %s failed: %s", m.Version, m.Name, err)
+			}
+			ver = m.Version
+		}
+		return errDryRun
+	})
+	if err != errDryRun {
+		return err
+	}
+	log.Println("dry run finished, db version would be", ver)
+	return nil
+}
@@ -0,0 +1,97 @@
+/*
+ * Copyright (c) 2015, Shinya Yagyu
+ * All rights reserved.
+ * Redistribution and use in source and binary forms, with or without
+ * modification, are permitted provided that the following conditions are met:
+ *
+ * 1. Redistributions of source code must retain the above copyright notice,
+ *    this list of conditions and the following disclaimer.
+ * 2. Redistributions in binary form must reproduce the above copyright notice,
+ *    this list of conditions and the following disclaimer in the documentation
+ *    and/or other materials provided with the distribution.
+ * 3. Neither the name of the copyright holder nor the names of its
+ *    contributors may be used to endorse or promote products derived from this
+ *    software without specific prior written permission.
+ *
+ * THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+ * AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+ * IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+ * ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+ * LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+ * CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+ * SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+ * INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+ * CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+ * ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+ * POSSIBILITY OF SUCH DAMAGE.
+ */
+package db
+
+import "bytes"
+
+//Store is a transactional key/value store which has buckets of keys sorted in byte order.
+type Store interface {
+	//View runs fn in a read-only transaction.
+	View(fn func(Tx) error) error
+	//Update runs fn in a read-write transaction, which is rolled back if fn returns an error.
+	Update(fn func(Tx) error) error
+	Close() error
+}
+
+//Tx is a transaction of Store.
+//values returned by Tx are valid only in the transaction.
+//a key in a bucket has a value, or a set of strings which is set type value.
+type Tx interface {
+	//Get returns the value of key in bucket, or nil if not found or the key has a set.
+	Get(bucket string, key []byte) []byte
+	//Put sets the value of key in bucket, and creates the bucket if not exists.
+	Put(bucket string, key, value []byte) error
+	//Delete deletes key in bucket, and its value or set.
+	Delete(bucket string, key []byte) error
+	HasBucket(bucket string) bool
+	DeleteBucket(bucket string) error
+	//Buckets returns names of all buckets.
+	Buckets() []string
+	//Cursor returns a cursor of bucket, which has no keys if bucket doesn't exist.
+	Cursor(bucket string) Cursor
+	//AddMember adds member to the set of key in bucket, and creates them if not exist.
+	AddMember(bucket string, key []byte, member string) error
+	//DelMember deletes member from the set of key in bucket. key is deleted if the set becomes empty.
+	DelMember(bucket string, key []byte, member string) error
+	//Members returns members of the set of key in bucket in byte order.
+	Members(bucket string, key []byte) ([]string, error)
+	IsMember(bucket string, key []byte, member string) bool
+}
+
+//Cursor iterates keys in a bucket in byte order.
+//the value of the key which has a set is nil.
+//it returns nil key if there is no key at the position.
+type Cursor interface {
+	First() (key, value []byte)
+	Last() (key, value []byte)
+	Seek(seek []byte) (key, value []byte)
+	Next() (key, value []byte)
+	Prev() (key, value []byte)
+}
+
+//ForEach calls fn with each key/value in bucket in byte order.
+func ForEach(tx Tx, bucket string, fn func(k, v []byte) error) error {
+	c := tx.Cursor(bucket)
+	for k, v := c.First(); k != nil; k, v = c.Next() {
+		if err := fn(k, v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+//ForEachPrefix calls fn with each key/value whose key has prefix in bucket in byte order.
+func ForEachPrefix(tx Tx, bucket string, prefix []byte, fn func(k, v []byte) error) error {
+	c := tx.Cursor(bucket)
+	for k, v := c.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, v = c.Next() {
+		if err := fn(k, v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
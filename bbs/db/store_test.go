@@ -0,0 +1,156 @@
+/*
+ * Copyright (c) 2015, Shinya Yagyu
+ * All rights reserved.
+ * Redistribution and use in source and binary forms, with or without
+ * modification, are permitted provided that the following conditions are met:
+ *
+ * 1. Redistributions of source code must retain the above copyright notice,
+ *    this list of conditions and the following disclaimer.
+ * 2. Redistributions in binary form must reproduce the above copyright notice,
+ *    this list of conditions and the following disclaimer in the documentation
+ *    and/or other materials provided with the distribution.
+ * 3. Neither the name of the copyright holder nor the names of its
+ *    contributors may be used to endorse or promote products derived from this
+ *    software without specific prior written permission.
+ *
+ * THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+ * AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+ * IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+ * ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+ * LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+ * CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+ * SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+ * INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+ * CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+ * ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+ * POSSIBILITY OF SUCH DAMAGE.
+ */
+package db
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+)
+
+//eachStore calls f with an empty Bolt and Memory.
+func eachStore(t *testing.T, f func(t *testing.T, s Store)) {
+	dir, err := ioutil.TempDir("", "gou")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	b, err := OpenBolt(path.Join(dir, "gou_bolt.db"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer b.Close()
+	t.Run("bolt", func(t *testing.T) { f(t, b) })
+	t.Run("memory", func(t *testing.T) { f(t, NewMemory()) })
+}
+
+//keys returns keys in bucket as a string.
+func keys(tx Tx, bucket string) string {
+	var ks []string
+	ForEach(tx, bucket, func(k, v []byte) error {
+		ks = append(ks, string(k))
+		return nil
+	})
+	return fmt.Sprint(ks)
+}
+
+func TestStore(t *testing.T) {
+	eachStore(t, func(t *testing.T, s Store) {
+		err := s.Update(func(tx Tx) error {
+			for _, k := range []string{"b", "d", "a", "c"} {
+				if err := tx.Put("bucket", []byte(k), []byte("v"+k)); err != nil {
+					return err
+				}
+			}
+			if err := tx.AddMember("bucket", []byte("e"), "m2"); err != nil {
+				return err
+			}
+			return tx.AddMember("bucket", []byte("e"), "m1")
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		errRollback := errors.New("rollback")
+		err = s.Update(func(tx Tx) error {
+			if err := tx.Put("bucket", []byte("f"), []byte("vf")); err != nil {
+				return err
+			}
+			if err := tx.Delete("bucket", []byte("a")); err != nil {
+				return err
+			}
+			return errRollback
+		})
+		if err != errRollback {
+			t.Fatal(err)
+		}
+		s.View(func(tx Tx) error {
+			if ks := keys(tx, "bucket"); ks != "[a b c d e]" {
+				t.Fatal("keys unmatch", ks)
+			}
+			if v := tx.Get("bucket", []byte("b")); string(v) != "vb" {
+				t.Fatal("value unmatch", string(v))
+			}
+			if tx.Get("bucket", []byte("e")) != nil || tx.Get("none", []byte("a")) != nil {
+				t.Fatal("Get returns a value")
+			}
+			if ms, err := tx.Members("bucket", []byte("e")); err != nil || fmt.Sprint(ms) != "[m1 m2]" {
+				t.Fatal("members unmatch", ms, err)
+			}
+			if !tx.IsMember("bucket", []byte("e"), "m1") || tx.IsMember("bucket", []byte("e"), "m") {
+				t.Fatal("IsMember failed")
+			}
+			if fmt.Sprint(tx.Buckets()) != "[bucket]" || tx.HasBucket("none") {
+				t.Fatal("buckets unmatch", tx.Buckets())
+			}
+			c := tx.Cursor("bucket")
+			if k, _ := c.Seek([]byte("bb")); string(k) != "c" {
+				t.Fatal("Seek failed", string(k))
+			}
+			if k, _ := c.Prev(); string(k) != "b" {
+				t.Fatal("Prev failed", string(k))
+			}
+			if k, v := c.Last(); string(k) != "e" || v != nil {
+				t.Fatal("Last failed", string(k), v)
+			}
+			if k, _ := c.Next(); k != nil {
+				t.Fatal("Next failed", string(k))
+			}
+			if k, _ := tx.Cursor("none").First(); k != nil {
+				t.Fatal("cursor of no bucket has keys")
+			}
+			if err := tx.Put("bucket", []byte("z"), nil); err == nil {
+				t.Fatal("Put succeeded in read-only tx")
+			}
+			return nil
+		})
+		err = s.Update(func(tx Tx) error {
+			if err := tx.DelMember("bucket", []byte("e"), "m1"); err != nil {
+				return err
+			}
+			if err := tx.DelMember("bucket", []byte("e"), "m2"); err != nil {
+				return err
+			}
+			if err := tx.Delete("bucket", []byte("c")); err != nil {
+				return err
+			}
+			return tx.DeleteBucket("bucket")
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		s.View(func(tx Tx) error {
+			if len(tx.Buckets()) != 0 {
+				t.Fatal("bucket remains", tx.Buckets())
+			}
+			return nil
+		})
+	})
+}
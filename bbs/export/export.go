@@ -0,0 +1,233 @@
+/*
+ * Copyright (c) 2015, Shinya Yagyu
+ * All rights reserved.
+ * Redistribution and use in source and binary forms, with or without
+ * modification, are permitted provided that the following conditions are met:
+ *
+ * 1. Redistributions of source code must retain the above copyright notice,
+ *    this list of conditions and the following disclaimer.
+ * 2. Redistributions in binary form must reproduce the above copyright notice,
+ *    this list of conditions and the following disclaimer in the documentation
+ *    and/or other materials provided with the distribution.
+ * 3. Neither the name of the copyright holder nor the names of its
+ *    contributors may be used to endorse or promote products derived from this
+ *    software without specific prior written permission.
+ *
+ * THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+ * AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+ * IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+ * ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+ * LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+ * CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+ * SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+ * INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+ * CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+ * ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+ * POSSIBILITY OF SUCH DAMAGE.
+ */
+package export
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"log"
+	"math"
+	"strings"
+
+	"bbs/cfg"
+	"bbs/cgi"
+	"bbs/db"
+	"bbs/mch/keylib"
+	"bbs/myself"
+	"bbs/record"
+	"bbs/tag/user"
+	"bbs/thread"
+	"bbs/util"
+)
+
+//Formats are names of export formats.
+var Formats = []string{"json", "html", "dat", "mbox"}
+
+//Exporter writes threads in Format.
+type Exporter struct {
+	My     *myself.Myself //node which has threads
+	Format string         //one of Formats
+	Host   string         //host:port of this node, used for links in dat
+	M      cgi.Message    //messages for html
+}
+
+//ContentType returns the mime type of the format.
+func (e *Exporter) ContentType() string {
+	switch e.Format {
+	case "json":
+		return "application/json"
+	case "html":
+		return "text/html; charset=UTF-8"
+	case "dat":
+		return "text/plain; charset=Shift_JIS"
+	}
+	return "application/mbox"
+}
+
+//Thread writes the thread datfile to w.
+func (e *Exporter) Thread(w io.Writer, datfile string) error {
+	ca := thread.NewCache(e.My, datfile)
+	if !ca.Exists() {
+		return errors.New("thread not found")
+	}
+	switch e.Format {
+	case "json":
+		return e.json(w, ca)
+	case "html":
+		return e.html(w, ca)
+	case "dat":
+		dat := keylib.MakeDat(e.My, ca, "2ch", e.Host)
+		_, err := io.WriteString(w, util.ToSJIS(strings.Join(dat, "\n")+"\n"))
+		return err
+	case "mbox":
+		return e.mbox(w, ca)
+	}
+	return errors.New("unknown format " + e.Format)
+}
+
+//TagThreads returns threads which have the user tag.
+func (e *Exporter) TagThreads(tag string) ([]string, error) {
+	var ds []string
+	err := e.My.DB.View(func(tx db.Tx) error {
+		ds = user.ThreadsTX(tx, tag)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(ds) == 0 {
+		return nil, errors.New("no threads have the tag")
+	}
+	return ds, nil
+}
+
+//Zip writes threads datfiles to w as a zip file.
+func (e *Exporter) Zip(w io.Writer, ds []string) error {
+	zw := zip.NewWriter(w)
+	for _, datfile := range ds {
+		f, err := zw.Create(datfile + "." + e.Format)
+		if err != nil {
+			return err
+		}
+		if err := e.Thread(f, datfile); err != nil {
+			return err
+		}
+	}
+	return zw.Close()
+}
+
+//Tag writes all threads which have the user tag to w as a zip file,
+//and returns # of threads.
+func (e *Exporter) Tag(w io.Writer, tag string) (int, error) {
+	ds, err := e.TagThreads(tag)
+	if err != nil {
+		return 0, err
+	}
+	return len(ds), e.Zip(w, ds)
+}
+
+//records returns alive records in the thread with bodies and attached files, sorted by stamp.
+//they are read in one transaction, and their legacy ids are known without db.
+func records(my *myself.Myself, datfile string) []*record.Record {
+	var rs []*record.Record
+	rg := &record.Range{
+		Datfile: datfile,
+		End:     math.MaxInt64,
+		Kind:    record.Alive,
+	}
+	err := rg.Each(my, func(rec *record.Record) error {
+		rs = append(rs, rec)
+		return nil
+	})
+	if err != nil {
+		log.Println(err)
+	}
+	return rs
+}
+
+//plain returns plain text of the body value.
+func plain(v string) string {
+	return html.UnescapeString(strings.Replace(v, "<br>", "\n", -1))
+}
+
+//jsonThread is a thread in json format.
+type jsonThread struct {
+	Title   string
+	Datfile string
+	Tags    []string
+	Records []*jsonRecord
+}
+
+//jsonRecord is a record in json format.
+type jsonRecord struct {
+	Stamp    int64
+	ID       string //md5 id, which anchors refer
+	Name     string `json:",omitempty"`
+	Mail     string `json:",omitempty"`
+	Body     string
+	Pubkey   string      `json:",omitempty"`
+	Verified bool        `json:",omitempty"`
+	Attach   *jsonAttach `json:",omitempty"`
+}
+
+//jsonAttach is an attached file in json format.
+type jsonAttach struct {
+	Suffix string
+	Data   string //base64 encoded
+}
+
+//json writes the thread as json.
+func (e *Exporter) json(w io.Writer, ca *thread.Cache) error {
+	t := jsonThread{
+		Title:   util.FileDecode(ca.Datfile),
+		Datfile: ca.Datfile,
+		Tags:    user.GetStrings(e.My.DB, ca.Datfile),
+	}
+	for _, rec := range records(e.My, ca.Datfile) {
+		r := &jsonRecord{
+			Stamp:    rec.Stamp,
+			ID:       rec.LegacyID(),
+			Name:     plain(rec.GetBodyValue("name", "")),
+			Mail:     plain(rec.GetBodyValue("mail", "")),
+			Body:     plain(rec.GetBodyValue("body", "")),
+			Pubkey:   rec.Pubkey(),
+			Verified: rec.IsVerified(),
+		}
+		if at := rec.GetBodyValue("attach", ""); at != "" {
+			r.Attach = &jsonAttach{
+				Suffix: rec.GetBodyValue("suffix", cfg.SuffixTXT),
+				Data:   at,
+			}
+		}
+		t.Records = append(t.Records, r)
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(&t)
+}
+
+//id8s returns map from 8 characters of ids to md5 ids of records, which are used in anchors.
+func id8s(rs []*record.Record) map[string]string {
+	m := make(map[string]string)
+	for _, rec := range rs {
+		id := rec.LegacyID()
+		if len(id) >= 8 {
+			m[id[:8]] = id
+		}
+	}
+	return m
+}
+
+//attachName returns the file name of the attached file in the record.
+func attachName(rec *record.Record) string {
+	return fmt.Sprintf("%d.%s", rec.Stamp, rec.GetBodyValue("suffix", cfg.SuffixTXT))
+}
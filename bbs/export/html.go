@@ -0,0 +1,117 @@
+/*
+ * Copyright (c) 2015, Shinya Yagyu
+ * All rights reserved.
+ * Redistribution and use in source and binary forms, with or without
+ * modification, are permitted provided that the following conditions are met:
+ *
+ * 1. Redistributions of source code must retain the above copyright notice,
+ *    this list of conditions and the following disclaimer.
+ * 2. Redistributions in binary form must reproduce the above copyright notice,
+ *    this list of conditions and the following disclaimer in the documentation
+ *    and/or other materials provided with the distribution.
+ * 3. Neither the name of the copyright holder nor the names of its
+ *    contributors may be used to endorse or promote products derived from this
+ *    software without specific prior written permission.
+ *
+ * THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+ * AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+ * IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+ * ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+ * LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+ * CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+ * SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+ * INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+ * CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+ * ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+ * POSSIBILITY OF SUCH DAMAGE.
+ */
+package export
+
+import (
+	"encoding/base64"
+	"html/template"
+	"io"
+	"mime"
+	"regexp"
+
+	"bbs/cfg"
+	"bbs/cgi"
+	"bbs/record"
+	"bbs/tag/user"
+	"bbs/thread"
+	"bbs/util"
+)
+
+//defaultThumbnailSize is the thumbnail size in html archives if thumbnail_size is not set.
+//its width is decided by the aspect ratio.
+const defaultThumbnailSize = "0x210"
+
+//htmlRecord is a record in html archive.
+type htmlRecord struct {
+	*record.Record
+	ID8        string
+	Body       template.HTML
+	AttachName string
+	Attach     template.URL //data uri of the attached file
+	Thumbnail  template.URL //data uri of the thumbnail if the attached file is an image
+}
+
+//html writes the thread as a html file which has attached files and thumbnails in itself.
+//anchors to records in the thread are changed to links in the file.
+func (e *Exporter) html(w io.Writer, ca *thread.Cache) error {
+	title := util.FileDecode(ca.Datfile)
+	c := &cgi.CGI{Env: &cgi.Env{My: e.My}}
+	anchor := regexp.MustCompile(`href="` + regexp.QuoteMeta(cfg.ThreadURL+"/"+util.StrEncode(title)+"/") + `([0-9a-f]{8})"`)
+	var hs []*htmlRecord
+	for _, rec := range records(e.My, ca.Datfile) {
+		body := c.HTMLFormat(rec.GetBodyValue("body", ""), cfg.ThreadURL, title, false)
+		h := &htmlRecord{
+			Record: rec,
+			ID8:    rec.LegacyID()[:8],
+			Body:   template.HTML(anchor.ReplaceAllString(body, `href="#r$1"`)),
+		}
+		if at := rec.GetBodyValue("attach", ""); at != "" {
+			h.AttachName = attachName(rec)
+			h.Attach, h.Thumbnail = e.dataURI(at, rec.GetBodyValue("suffix", cfg.SuffixTXT))
+		}
+		hs = append(hs, h)
+	}
+	d := struct {
+		Title   string
+		Tags    []string
+		Records []*htmlRecord
+		Message cgi.Message
+	}{
+		title,
+		user.GetStrings(e.My.DB, ca.Datfile),
+		hs,
+		e.M,
+	}
+	cgi.RenderTemplate(e.My.Cfg.TemplateDir, "export_html", d, w)
+	return nil
+}
+
+//dataURI returns data uri of the base64 encoded file at, and its thumbnail if it is an image.
+func (e *Exporter) dataURI(at, suffix string) (template.URL, template.URL) {
+	typ := mime.TypeByExtension("." + suffix)
+	if typ == "" {
+		typ = "application/octet-stream"
+	}
+	uri := template.URL("data:" + typ + ";base64," + at)
+	if !util.IsValidImage(typ, "a."+suffix) {
+		return uri, ""
+	}
+	decoded, err := base64.StdEncoding.DecodeString(at)
+	if err != nil {
+		return uri, ""
+	}
+	size := e.My.Cfg.DefaultThumbnailSize
+	if size == "" {
+		size = defaultThumbnailSize
+	}
+	thumb := util.MakeThumbnail(decoded, suffix, size)
+	if len(thumb) == 0 {
+		return uri, uri
+	}
+	return uri, template.URL("data:" + typ + ";base64," + base64.StdEncoding.EncodeToString(thumb))
+}
@@ -0,0 +1,160 @@
+/*
+ * Copyright (c) 2015, Shinya Yagyu
+ * All rights reserved.
+ * Redistribution and use in source and binary forms, with or without
+ * modification, are permitted provided that the following conditions are met:
+ *
+ * 1. Redistributions of source code must retain the above copyright notice,
+ *    this list of conditions and the following disclaimer.
+ * 2. Redistributions in binary form must reproduce the above copyright notice,
+ *    this list of conditions and the following disclaimer in the documentation
+ *    and/or other materials provided with the distribution.
+ * 3. Neither the name of the copyright holder nor the names of its
+ *    contributors may be used to endorse or promote products derived from this
+ *    software without specific prior written permission.
+ *
+ * THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+ * AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+ * IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+ * ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+ * LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+ * CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+ * SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+ * INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+ * CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+ * ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+ * POSSIBILITY OF SUCH DAMAGE.
+ */
+package export
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/textproto"
+	"regexp"
+	"strings"
+	"time"
+
+	"bbs/record"
+	"bbs/thread"
+	"bbs/util"
+)
+
+//mboxDomain is the domain part of message ids and addresses in mbox.
+const mboxDomain = "shingetsu.invalid"
+
+//anchorReg matches anchors to records in the body.
+var anchorReg = regexp.MustCompile(`&gt;&gt;([0-9a-f]{8})`)
+
+//mbox writes the thread as mbox, where each record is a message.
+//messages are threaded by anchors (>>id8) in the body.
+func (e *Exporter) mbox(w io.Writer, ca *thread.Cache) error {
+	title := util.FileDecode(ca.Datfile)
+	rs := records(e.My, ca.Datfile)
+	ids := id8s(rs)
+	for _, rec := range rs {
+		var refs []string
+		for _, m := range anchorReg.FindAllStringSubmatch(rec.GetBodyValue("body", ""), -1) {
+			if id, ok := ids[m[1]]; ok && !util.HasString(refs, id) {
+				refs = append(refs, id)
+			}
+		}
+		if err := writeMessage(w, title, rec, refs); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+//messageID returns the message id of the record whose md5 id is id.
+func messageID(id string) string {
+	return "<" + id + "@" + mboxDomain + ">"
+}
+
+//writeMessage writes the record as a message which replies to records whose ids are refs.
+func writeMessage(w io.Writer, title string, rec *record.Record, refs []string) error {
+	t := time.Unix(rec.Stamp, 0)
+	name := plain(rec.GetBodyValue("name", ""))
+	if name == "" {
+		name = "anonymous"
+	}
+	hs := [][2]string{
+		{"From", mime.QEncoding.Encode("UTF-8", name) + " <" + rec.LegacyID()[:8] + "@" + mboxDomain + ">"},
+		{"Subject", mime.QEncoding.Encode("UTF-8", title)},
+		{"Date", t.Format(time.RFC1123Z)},
+		{"Message-ID", messageID(rec.LegacyID())},
+	}
+	if len(refs) > 0 {
+		ms := make([]string, len(refs))
+		for i, id := range refs {
+			ms[i] = messageID(id)
+		}
+		hs = append(hs, [2]string{"In-Reply-To", ms[0]}, [2]string{"References", strings.Join(ms, " ")})
+	}
+	hs = append(hs, [2]string{"MIME-Version", "1.0"})
+	text := plain(rec.GetBodyValue("body", ""))
+	var body bytes.Buffer
+	if at := rec.GetBodyValue("attach", ""); at == "" {
+		hs = append(hs, [2]string{"Content-Type", "text/plain; charset=UTF-8"},
+			[2]string{"Content-Transfer-Encoding", "8bit"})
+		body.WriteString(text)
+	} else {
+		mw := multipart.NewWriter(&body)
+		hs = append(hs, [2]string{"Content-Type", "multipart/mixed; boundary=" + mw.Boundary()})
+		if err := writeAttach(mw, text, attachName(rec), at); err != nil {
+			return err
+		}
+	}
+	fmt.Fprintf(w, "From %s %s\n", rec.LegacyID()[:8]+"@"+mboxDomain, t.UTC().Format(time.ANSIC))
+	for _, h := range hs {
+		fmt.Fprintf(w, "%s: %s\n", h[0], h[1])
+	}
+	fmt.Fprintln(w)
+	for _, line := range strings.Split(strings.Replace(body.String(), "\r\n", "\n", -1), "\n") {
+		//quotes lines which look like the separator of messages.
+		if strings.HasPrefix(strings.TrimLeft(line, ">"), "From ") {
+			line = ">" + line
+		}
+		fmt.Fprintln(w, line)
+	}
+	_, err := fmt.Fprintln(w)
+	return err
+}
+
+//writeAttach writes text and the base64 encoded attached file at to mw as parts.
+func writeAttach(mw *multipart.Writer, text, fname, at string) error {
+	th := make(textproto.MIMEHeader)
+	th.Set("Content-Type", "text/plain; charset=UTF-8")
+	th.Set("Content-Transfer-Encoding", "8bit")
+	p, err := mw.CreatePart(th)
+	if err != nil {
+		return err
+	}
+	if _, err := io.WriteString(p, text); err != nil {
+		return err
+	}
+	typ := mime.TypeByExtension(fname[strings.LastIndex(fname, "."):])
+	if typ == "" {
+		typ = "application/octet-stream"
+	}
+	ah := make(textproto.MIMEHeader)
+	ah.Set("Content-Type", typ)
+	ah.Set("Content-Transfer-Encoding", "base64")
+	ah.Set("Content-Disposition", `attachment; filename="`+fname+`"`)
+	if p, err = mw.CreatePart(ah); err != nil {
+		return err
+	}
+	for len(at) > 76 {
+		if _, err := io.WriteString(p, at[:76]+"\r\n"); err != nil {
+			return err
+		}
+		at = at[76:]
+	}
+	if _, err := io.WriteString(p, at+"\r\n"); err != nil {
+		return err
+	}
+	return mw.Close()
+}
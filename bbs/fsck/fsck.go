@@ -0,0 +1,555 @@
+/*
+ * Copyright (c) 2015, Shinya Yagyu
+ * All rights reserved.
+ * Redistribution and use in source and binary forms, with or without
+ * modification, are permitted provided that the following conditions are met:
+ *
+ * 1. Redistributions of source code must retain the above copyright notice,
+ *    this list of conditions and the following disclaimer.
+ * 2. Redistributions in binary form must reproduce the above copyright notice,
+ *    this list of conditions and the following disclaimer in the documentation
+ *    and/or other materials provided with the distribution.
+ * 3. Neither the name of the copyright holder nor the names of its
+ *    contributors may be used to endorse or promote products derived from this
+ *    software without specific prior written permission.
+ *
+ * THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+ * AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+ * IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+ * ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+ * LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+ * CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+ * SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+ * INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+ * CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+ * ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+ * POSSIBILITY OF SUCH DAMAGE.
+ */
+package fsck
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"bbs/db"
+	"bbs/recentlist"
+	"bbs/record"
+	"bbs/util"
+)
+
+//Problem is an inconsistency found in the db.
+type Problem struct {
+	Class  string //kind of the inconsistency, one of Classes
+	Key    string //readable key of the broken entry
+	Detail string
+}
+
+//Classes are kinds of inconsistencies in checking order.
+//derived buckets are checked after "record", so that they are repaired
+//from records which are already repaired.
+var Classes = []string{"record", "thread", "threadstat", "blob", "index", "idindex", "recent", "keylib", "lookup"}
+
+//checker checks one class of inconsistencies and repairs them if repair is true.
+type checker func(tx db.Tx, repair bool) ([]*Problem, error)
+
+var checkers = map[string]checker{
+	"record":     checkRecords,
+	"thread":     checkThreads,
+	"threadstat": checkStats,
+	"blob":       checkBlobs,
+	"index":      checkIndex,
+	"idindex":    checkIDIndex,
+	"recent":     checkRecent,
+	"keylib":     checkKeylib,
+	"lookup":     checkLookup,
+}
+
+//Check checks all classes of inconsistencies and returns problems found.
+//if repair is true, repairs them in one transaction.
+func Check(s db.Store, repair bool) ([]*Problem, error) {
+	var ps []*Problem
+	check := func(tx db.Tx) error {
+		for _, c := range Classes {
+			p, err := checkers[c](tx, repair)
+			if err != nil {
+				return fmt.Errorf("%s: %s", c, err)
+			}
+			ps = append(ps, p...)
+		}
+		return nil
+	}
+	var err error
+	if repair {
+		err = s.Update(check)
+	} else {
+		err = s.View(check)
+	}
+	return ps, err
+}
+
+//Count returns # of problems for each class.
+func Count(ps []*Problem) map[string]int {
+	cnt := make(map[string]int)
+	for _, p := range ps {
+		cnt[p.Class]++
+	}
+	return cnt
+}
+
+//headKey returns readable key of the record.
+func headKey(h *record.Head) string {
+	return h.Datfile + "/" + h.Idstr()
+}
+
+//delKeys deletes keys in the bucket.
+func delKeys(tx db.Tx, bucket string, keys [][]byte) error {
+	for _, k := range keys {
+		if err := db.Del(tx, bucket, k); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+//copyKey returns a copy of k, which is valid after the transaction.
+func copyKey(k []byte) []byte {
+	return append([]byte{}, k...)
+}
+
+//verify returns why the record d is broken, or "" if d is correct.
+func verify(tx db.Tx, k []byte, d *record.DB) string {
+	if d.Head == nil || d.Datfile == "" {
+		return "no head"
+	}
+	if !bytes.Equal(k, d.Head.ToKey()) {
+		return "key mismatch"
+	}
+	body, err := d.BodyTX(tx)
+	if err != nil {
+		return "attached file is lost"
+	}
+	h := util.FindHasher(d.ID)
+	if h == nil || h.Digest(body) != d.ID {
+		return "id is not digest of body"
+	}
+	if d.LegacyID != "" && util.MD5digest(body) != d.LegacyID {
+		return "legacy id is not md5 of body"
+	}
+	return ""
+}
+
+//checkRecords checks that every record can be decoded, is saved under its own key,
+//and its ids are digests of its body. broken records are deleted.
+func checkRecords(tx db.Tx, repair bool) ([]*Problem, error) {
+	var ps []*Problem
+	var broken [][]byte
+	err := db.ForEach(tx, "record", func(k, v []byte) error {
+		d := record.DB{}
+		key := fmt.Sprintf("%q", k)
+		detail := "broken json"
+		if err := json.Unmarshal(v, &d); err == nil {
+			if detail = verify(tx, k, &d); detail == "" {
+				return nil
+			}
+			if d.Head != nil {
+				key = headKey(d.Head)
+			}
+		}
+		ps = append(ps, &Problem{"record", key, detail})
+		broken = append(broken, copyKey(k))
+		return nil
+	})
+	if err != nil || !repair {
+		return ps, err
+	}
+	return ps, delKeys(tx, "record", broken)
+}
+
+//datfiles returns thread names which have records.
+func datfiles(tx db.Tx) ([]string, error) {
+	if !tx.HasBucket("record") {
+		return nil, nil
+	}
+	return db.GetPrefixs(tx, "record")
+}
+
+//checkThreads checks that every thread which has records is in "thread" bucket.
+//missing threads are added.
+func checkThreads(tx db.Tx, repair bool) ([]*Problem, error) {
+	ds, err := datfiles(tx)
+	if err != nil {
+		return nil, err
+	}
+	var ps []*Problem
+	for _, datfile := range ds {
+		if has, _ := db.HasKey(tx, "thread", []byte(datfile)); has {
+			continue
+		}
+		ps = append(ps, &Problem{"thread", datfile, "records without thread"})
+		if !repair {
+			continue
+		}
+		if err := db.Put(tx, "thread", []byte(datfile), []byte("")); err != nil {
+			return ps, err
+		}
+		if err := record.IndexTitleTX(tx, datfile); err != nil {
+			return ps, err
+		}
+	}
+	return ps, nil
+}
+
+//checkStats checks that statistics of threads are same as the ones made from records.
+//counts of recent days are not checked because they depend on the time when they are made.
+func checkStats(tx db.Tx, repair bool) ([]*Problem, error) {
+	ds, err := datfiles(tx)
+	if err != nil {
+		return nil, err
+	}
+	var ps []*Problem
+	var wrong []string
+	has := make(map[string]bool)
+	for _, datfile := range ds {
+		has[datfile] = true
+		s, err := record.MakeStatTX(tx, datfile)
+		if err != nil {
+			continue //broken records are reported as "record"
+		}
+		old := record.GetStat(tx, datfile)
+		if s.Alive == old.Alive && s.Removed == old.Removed && s.Size == old.Size &&
+			s.First == old.First && s.Last == old.Last && s.LastAlive == old.LastAlive {
+			continue
+		}
+		ps = append(ps, &Problem{"threadstat", datfile,
+			fmt.Sprintf("saved %d/%d records, actual %d/%d", old.Alive, old.Len(record.All), s.Alive, s.Len(record.All))})
+		wrong = append(wrong, datfile)
+	}
+	err = db.ForEach(tx, "threadstat", func(k, v []byte) error {
+		if !has[string(k)] {
+			ps = append(ps, &Problem{"threadstat", string(k), "statistics without records"})
+			wrong = append(wrong, string(k))
+		}
+		return nil
+	})
+	if err != nil || !repair {
+		return ps, err
+	}
+	for _, datfile := range wrong {
+		if err := record.RebuildStatTX(tx, datfile); err != nil {
+			return ps, err
+		}
+	}
+	return ps, nil
+}
+
+//checkBlobs checks that reference counts of blobs are # of records which refer them,
+//and every blob is referred. counts are corrected and unreferred blobs are deleted.
+func checkBlobs(tx db.Tx, repair bool) ([]*Problem, error) {
+	refs := make(map[string]int)
+	err := db.ForEach(tx, "record", func(k, v []byte) error {
+		d := record.DB{}
+		if err := json.Unmarshal(v, &d); err == nil && d.Attach != "" {
+			refs[d.Attach]++
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	var ps []*Problem
+	saved := make(map[string]int)
+	err = db.ForEach(tx, "blobref", func(k, v []byte) error {
+		saved[string(k)] = -1
+		if len(v) == 8 {
+			saved[string(k)] = int(binary.BigEndian.Uint64(v))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	fix := make(map[string]int)
+	for hash, n := range refs {
+		if saved[hash] != n {
+			ps = append(ps, &Problem{"blob", hash, fmt.Sprintf("saved %d refs, actual %d", saved[hash], n)})
+			fix[hash] = n
+		}
+	}
+	var orphans [][]byte
+	err = db.ForEach(tx, "blob", func(k, v []byte) error {
+		if refs[string(k)] == 0 {
+			ps = append(ps, &Problem{"blob", string(k), "blob without records"})
+			orphans = append(orphans, copyKey(k))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	for hash, n := range saved {
+		if refs[hash] == 0 {
+			ps = append(ps, &Problem{"blob", hash, fmt.Sprintf("saved %d refs, actual 0", n)})
+			fix[hash] = 0
+		}
+	}
+	sort.SliceStable(ps, func(i, j int) bool {
+		return ps[i].Key < ps[j].Key
+	})
+	if !repair {
+		return ps, nil
+	}
+	for hash, n := range fix {
+		if n > 0 {
+			err = db.Put(tx, "blobref", []byte(hash), n)
+		} else {
+			err = db.Del(tx, "blobref", []byte(hash))
+		}
+		if err != nil {
+			return ps, err
+		}
+	}
+	return ps, delKeys(tx, "blob", orphans)
+}
+
+//posting is a value of "index" bucket.
+type posting struct {
+	*record.Head
+}
+
+//checkIndex checks that the full-text index has all terms of alive records and no others.
+//the index is rebuilt if broken.
+func checkIndex(tx db.Tx, repair bool) ([]*Problem, error) {
+	var ps []*Problem
+	var docs, tokens int
+	err := db.ForEach(tx, "record", func(k, v []byte) error {
+		d := record.DB{}
+		if err := json.Unmarshal(v, &d); err != nil || d.Head == nil || d.Deleted {
+			return nil
+		}
+		terms := util.IndexTerms(d.Text())
+		docs++
+		tokens += len(terms)
+		for _, t := range terms {
+			if tx.Get("index", db.ToKey(t, d.Datfile, d.Stamp, d.ID)) == nil {
+				ps = append(ps, &Problem{"index", headKey(d.Head), "term " + t + " is not indexed"})
+				break
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	err = db.ForEach(tx, "index", func(k, v []byte) error {
+		var p posting
+		if err := json.Unmarshal(v, &p); err != nil || p.Head == nil {
+			ps = append(ps, &Problem{"index", fmt.Sprintf("%q", k), "broken json"})
+			return nil
+		}
+		d, err := record.GetFromDB(tx, p.Head)
+		if err != nil || d.Deleted {
+			ps = append(ps, &Problem{"index", headKey(p.Head), "posting of removed record"})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	var s struct {
+		Docs   int
+		Tokens int
+	}
+	db.Get(tx, "meta", []byte("index"), &s) //zero if not found
+	if s.Docs != docs || s.Tokens != tokens {
+		ps = append(ps, &Problem{"index", "meta/index",
+			fmt.Sprintf("saved %d docs %d tokens, actual %d docs %d tokens", s.Docs, s.Tokens, docs, tokens)})
+	}
+	if len(ps) == 0 || !repair {
+		return ps, nil
+	}
+	return ps, record.RebuildIndexTX(tx)
+}
+
+//checkIDIndex checks that the id index has ids of all records and no others.
+//the index is rebuilt if broken.
+func checkIDIndex(tx db.Tx, repair bool) ([]*Problem, error) {
+	var ps []*Problem
+	keys := make(map[string]bool)
+	err := db.ForEach(tx, "record", func(k, v []byte) error {
+		d := record.DB{}
+		if err := json.Unmarshal(v, &d); err != nil || d.Head == nil {
+			return nil
+		}
+		for _, ik := range record.IDIndexKeys(&d) {
+			keys[string(ik)] = true
+			if tx.Get("idindex", ik) == nil {
+				ps = append(ps, &Problem{"idindex", headKey(d.Head), "id is not indexed"})
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	err = db.ForEach(tx, "idindex", func(k, v []byte) error {
+		if !keys[string(k)] {
+			ps = append(ps, &Problem{"idindex", fmt.Sprintf("%q", k), "id of missing record"})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(ps) == 0 || !repair {
+		return ps, nil
+	}
+	return ps, record.RebuildIDIndexTX(tx)
+}
+
+//checkRecent checks that every entry in recentlist can be decoded
+//and is saved under its own key, and that the stamp index has the same entries.
+//broken entries are deleted and the stamp index is repaired.
+func checkRecent(tx db.Tx, repair bool) ([]*Problem, error) {
+	var ps []*Problem
+	var broken, orphans [][]byte
+	var missing []*record.Head
+	index := make(map[string]bool)
+	err := db.ForEach(tx, "recent", func(k, v []byte) error {
+		h := record.Head{}
+		detail := "broken json"
+		key := fmt.Sprintf("%q", k)
+		if err := json.Unmarshal(v, &h); err == nil {
+			if bytes.Equal(k, h.ToKey()) && h.Datfile != "" {
+				sk := recentlist.StampKey(&h)
+				index[string(sk)] = true
+				if has, _ := db.HasKey(tx, "recentStamp", sk); !has {
+					ps = append(ps, &Problem{"recent", headKey(&h), "not in stamp index"})
+					missing = append(missing, &h)
+				}
+				return nil
+			}
+			detail = "key mismatch"
+		}
+		ps = append(ps, &Problem{"recent", key, detail})
+		broken = append(broken, copyKey(k))
+		return nil
+	})
+	if err != nil {
+		return ps, err
+	}
+	err = db.ForEach(tx, "recentStamp", func(k, v []byte) error {
+		if !index[string(k)] {
+			ps = append(ps, &Problem{"recent", fmt.Sprintf("%q", k), "stamp index without entry"})
+			orphans = append(orphans, copyKey(k))
+		}
+		return nil
+	})
+	if err != nil || !repair {
+		return ps, err
+	}
+	if err := delKeys(tx, "recent", broken); err != nil {
+		return ps, err
+	}
+	if err := delKeys(tx, "recentStamp", orphans); err != nil {
+		return ps, err
+	}
+	for _, h := range missing {
+		if err := db.Put(tx, "recentStamp", recentlist.StampKey(h), []byte{}); err != nil {
+			return ps, err
+		}
+	}
+	return ps, nil
+}
+
+//stampKey returns readable stamp key.
+func stampKey(k []byte) string {
+	if len(k) != 8 {
+		return fmt.Sprintf("%q", k)
+	}
+	return fmt.Sprintf("%d", int64(binary.BigEndian.Uint64(k)))
+}
+
+//checkKeylib checks that "keylibST" and "keylibTS" are inverse of each other.
+//entries which are not paired are deleted.
+func checkKeylib(tx db.Tx, repair bool) ([]*Problem, error) {
+	var ps []*Problem
+	var st, ts [][]byte
+	err := db.ForEach(tx, "keylibST", func(k, v []byte) error {
+		if len(k) == 8 && bytes.Equal(tx.Get("keylibTS", v), k) {
+			return nil
+		}
+		ps = append(ps, &Problem{"keylib", stampKey(k), "thread " + string(v) + " has another stamp"})
+		st = append(st, copyKey(k))
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	err = db.ForEach(tx, "keylibTS", func(k, v []byte) error {
+		if len(v) == 8 && bytes.Equal(tx.Get("keylibST", v), k) {
+			return nil
+		}
+		ps = append(ps, &Problem{"keylib", string(k), "stamp is not mapped to the thread"})
+		ts = append(ts, copyKey(k))
+		return nil
+	})
+	if err != nil || !repair {
+		return ps, err
+	}
+	if err := delKeys(tx, "keylibST", st); err != nil {
+		return ps, err
+	}
+	return ps, delKeys(tx, "keylibTS", ts)
+}
+
+//checkLookup checks that "lookupT" and "lookupA" are mirrors of each other.
+//missing pairs are added to the other side.
+func checkLookup(tx db.Tx, repair bool) ([]*Problem, error) {
+	type pair struct {
+		bucket, key, val string
+	}
+	var ps []*Problem
+	var missing, broken []pair
+	for _, bs := range [][2]string{{"lookupT", "lookupA"}, {"lookupA", "lookupT"}} {
+		bucket, mirror := bs[0], bs[1]
+		err := db.ForEach(tx, bucket, func(k, v []byte) error {
+			if v != nil {
+				ps = append(ps, &Problem{"lookup", bucket + "/" + string(k), "not a set"})
+				broken = append(broken, pair{bucket, string(k), ""})
+				return nil
+			}
+			vals, err := db.MapKeys(tx, bucket, k)
+			if err != nil {
+				return err
+			}
+			for _, val := range vals {
+				if db.HasVal(tx, mirror, []byte(val), string(k)) {
+					continue
+				}
+				ps = append(ps, &Problem{"lookup", bucket + "/" + string(k), val + " is not in " + mirror})
+				missing = append(missing, pair{mirror, val, string(k)})
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	if !repair {
+		return ps, nil
+	}
+	for _, p := range broken {
+		if err := db.Del(tx, p.bucket, []byte(p.key)); err != nil {
+			return ps, err
+		}
+	}
+	for _, p := range missing {
+		if err := db.PutMap(tx, p.bucket, []byte(p.key), p.val); err != nil {
+			return ps, err
+		}
+	}
+	return ps, nil
+}
@@ -0,0 +1,151 @@
+/*
+ * Copyright (c) 2015, Shinya Yagyu
+ * All rights reserved.
+ * Redistribution and use in source and binary forms, with or without
+ * modification, are permitted provided that the following conditions are met:
+ *
+ * 1. Redistributions of source code must retain the above copyright notice,
+ *    this list of conditions and the following disclaimer.
+ * 2. Redistributions in binary form must reproduce the above copyright notice,
+ *    this list of conditions and the following disclaimer in the documentation
+ *    and/or other materials provided with the distribution.
+ * 3. Neither the name of the copyright holder nor the names of its
+ *    contributors may be used to endorse or promote products derived from this
+ *    software without specific prior written permission.
+ *
+ * THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+ * AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+ * IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+ * ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+ * LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+ * CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+ * SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+ * INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+ * CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+ * ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+ * POSSIBILITY OF SUCH DAMAGE.
+ */
+package fsck
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"bbs/db"
+	"bbs/myself"
+	"bbs/recentlist"
+	"bbs/record"
+	"bbs/thread"
+	"bbs/util"
+)
+
+//setup returns Myself with a thread which has a record with an attached file and a plain record.
+func setup(t *testing.T, dir string) (*myself.Myself, *record.Record, *record.Record) {
+	my := myself.NewMemory(dir)
+	datfile := util.FileEncode("thread", "fsck")
+	thread.NewCache(my, datfile).Subscribe()
+	att := record.New(my, datfile, "", 0)
+	att.Build(1500000000, map[string]string{"body": "attached", "attach": "YXR0YWNo", "suffix": "png"}, "")
+	att.Sync()
+	plain := record.New(my, datfile, "", 0)
+	plain.Build(1500000010, map[string]string{"body": "plain"}, "")
+	plain.Sync()
+	return my, att, plain
+}
+
+//has returns true if ps has the problem in class c whose detail is detail.
+func has(ps []*Problem, c, detail string) bool {
+	for _, p := range ps {
+		if p.Class == c && p.Detail == detail {
+			return true
+		}
+	}
+	return false
+}
+
+func TestCheck(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gou")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	my, att, plain := setup(t, dir)
+	ps, err := Check(my.DB, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ps) != 0 {
+		t.Fatal("clean db has", len(ps), "problems:", ps[0])
+	}
+
+	tests := []struct {
+		class   string
+		detail  string
+		corrupt func(tx db.Tx) error
+	}{
+		{"lookup", "a is not in lookupA", func(tx db.Tx) error {
+			return db.PutMap(tx, "lookupT", []byte("t"), "a")
+		}},
+		{"keylib", "thread thread_00 has another stamp", func(tx db.Tx) error {
+			return db.Put(tx, "keylibST", db.ToKey(int64(1500000000)), "thread_00")
+		}},
+		{"recent", "stamp index without entry", func(tx db.Tx) error {
+			h := &record.Head{Datfile: plain.Datfile, Stamp: 1, ID: util.MD5digest("none")}
+			return db.Put(tx, "recentStamp", recentlist.StampKey(h), []byte{})
+		}},
+		{"blob", "saved 5 refs, actual 1", func(tx db.Tx) error {
+			d, err := record.GetFromDB(tx, att.Head)
+			if err != nil {
+				return err
+			}
+			return db.Put(tx, "blobref", []byte(d.Attach), 5)
+		}},
+		{"record", "id is not digest of body", func(tx db.Tx) error {
+			d, err := record.GetFromDB(tx, plain.Head)
+			if err != nil {
+				return err
+			}
+			d.Body = "body:changed"
+			v, err := json.Marshal(d)
+			if err != nil {
+				return err
+			}
+			return tx.Put("record", plain.Head.ToKey(), v)
+		}},
+	}
+	for _, test := range tests {
+		if err := my.DB.Update(test.corrupt); err != nil {
+			t.Fatal(test.class, err)
+		}
+		ps, err := Check(my.DB, false)
+		if err != nil {
+			t.Fatal(test.class, err)
+		}
+		if !has(ps, test.class, test.detail) {
+			t.Fatal(test.class, test.detail, "is not reported", len(ps))
+		}
+		if ps, err = Check(my.DB, true); err != nil {
+			t.Fatal(test.class, err)
+		}
+		if !has(ps, test.class, test.detail) {
+			t.Fatal(test.class, test.detail, "is not repaired")
+		}
+		if ps, err = Check(my.DB, false); err != nil {
+			t.Fatal(test.class, err)
+		}
+		if len(ps) != 0 {
+			t.Fatal(test.class, "has", len(ps), "problems after repair:", ps[0])
+		}
+	}
+	err = my.DB.View(func(tx db.Tx) error {
+		if exist, _ := db.HasKey(tx, "record", plain.Head.ToKey()); exist {
+			t.Fatal("broken record is not deleted")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
@@ -1,9 +1,8 @@
 module bbs
 
-go 1.12
+go 1.13
 
 require (
-	github.com/boltdb/bolt v1.3.1
 	github.com/gorilla/handlers v1.4.0
 	github.com/gorilla/mux v1.7.2
 	github.com/huin/goupnp v1.0.0 // indirect
@@ -13,6 +12,8 @@ require (
 	github.com/russross/blackfriday v2.0.0+incompatible
 	github.com/shingetsu-gou/go-nat v0.0.0-20151123072220-445e7fe128be
 	github.com/shurcooL/sanitized_anchor_name v1.0.0 // indirect
+	go.etcd.io/bbolt v1.3.6
+	golang.org/x/crypto v0.0.0-20200622213623-75b288015ac9
 	golang.org/x/net v0.0.0-20190603091049-60506f45cf65
 	golang.org/x/text v0.3.2
 	gopkg.in/ini.v1 v1.42.0
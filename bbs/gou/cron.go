@@ -32,9 +32,8 @@ import (
 	"log"
 	"time"
 
-	"bbs/cfg"
+	"bbs/db"
 	"bbs/mch/keylib"
-	"bbs/myself"
 	"bbs/node"
 	"bbs/node/manager"
 	"bbs/recentlist"
@@ -42,74 +41,113 @@ import (
 	"bbs/thread/download"
 )
 
-var running bool
-
-//cron runs cron, and update everything if it is after specified cycle.
-func cron() {
+//StartCron runs cron, and update everything if it is after specified cycle,
+//and retries the update queue, until the instance is closed.
+//Close waits for cron jobs to finish.
+func (in *Instance) StartCron() {
 	const (
 		shortCycle = 10 * time.Minute
 		longCycle  = time.Hour
+		retryCycle = time.Minute
 	)
+	my := in.My
 
+	in.wg.Add(3)
 	go func() {
-		getall := true
-		for {
-			log.Println("short cycle cron started")
-			myself.ResetPort()
-			ns := node.MustNew(cfg.InitNode.GetData())
-			if len(ns) == 0 {
-				log.Fatal("no init nodes")
-			}
-			for _, i := range ns {
-				if _, err := i.Ping(); err == nil {
-					manager.AppendToList(i)
-				}
+		defer in.wg.Done()
+		for getall := true; ; getall = false {
+			in.shortCron(getall)
+			select {
+			case <-in.done:
+				return
+			case <-time.After(shortCycle):
 			}
-			nodes := ns[0].GetherNodes()
-			doSync(getall)
-
-			manager.Initialize(nodes)
-			doSync(getall)
-			keylib.Load()
-			log.Println("short cycle cron finished")
-			getall = false
-			<-time.After(shortCycle)
 		}
 	}()
 	go func() {
+		defer in.wg.Done()
 		for {
-			<-time.After(longCycle)
+			select {
+			case <-in.done:
+				return
+			case <-time.After(longCycle):
+			}
 			log.Println("long cycle cron started")
-			recentlist.Getall(true)
-			thread.CleanRecords()
-			thread.RemoveRemoved()
+			recentlist.Getall(my, true)
+			thread.CleanRecords(my)
+			thread.RemoveRemoved(my)
+			manager.RemoveOldDeliveries(my)
+			db.ScheduledSnapshot(my.DB, my.Cfg)
 			log.Println("long cycle cron finished")
 		}
 	}()
+	go func() {
+		defer in.wg.Done()
+		for {
+			in.Queue.RetryDue()
+			select {
+			case <-in.done:
+				return
+			case <-time.After(retryCycle):
+			}
+		}
+	}()
+
+}
+
+//shortCron joins the network through init nodes, and syncs recentlist and records.
+//it does nothing if there are no init nodes.
+func (in *Instance) shortCron(getall bool) {
+	my := in.My
+	log.Println("short cycle cron started")
+	my.ResetPort()
+	ns := node.MustNew(my.Cfg.InitNode.GetData())
+	if len(ns) == 0 {
+		log.Println("no init nodes")
+		return
+	}
+	for _, i := range ns {
+		if _, err := i.Ping(my); err == nil {
+			manager.AppendToList(my, i)
+		}
+	}
+	nodes := ns[0].GetherNodes(my)
+	in.doSync(getall)
 
+	manager.Initialize(my, nodes)
+	in.doSync(getall)
+	keylib.Load(my)
+	log.Println("short cycle cron finished")
 }
 
 //doSync checks nodes in the nodelist are alive, reloads cachelist, removes old removed files,
 //reloads all tags from cachelist,reload srecent list from nodes in search list,
 //and reloads cache info from files in the disk.
-func doSync(fullRecent bool) {
-	if manager.ListLen() == 0 {
+func (in *Instance) doSync(fullRecent bool) {
+	my := in.My
+	if manager.ListLen(my) == 0 {
 		return
 	}
 	log.Println("recentList.getall start")
-	recentlist.Getall(fullRecent)
-	recentlist.RemoveOlds()
+	recentlist.Getall(my, fullRecent)
+	recentlist.RemoveOlds(my)
 	log.Println("recentList.getall finished")
 
-	if cfg.HeavyMoon && !running {
+	in.mutex.Lock()
+	defer in.mutex.Unlock()
+	if my.Cfg.HeavyMoon && !in.running {
 		log.Println("running heavymoon...")
-		thread.CreateAllCachedirs()
-		running = true
+		thread.CreateAllCachedirs(my)
+		in.running = true
+		in.wg.Add(1)
 		go func() {
+			defer in.wg.Done()
 			log.Println("cacheList.getall start")
-			download.Getall()
+			download.Getall(my)
 			log.Println("cacheList.getall finished")
-			running = false
+			in.mutex.Lock()
+			in.running = false
+			in.mutex.Unlock()
 			log.Println("heavymoon end")
 		}()
 	}
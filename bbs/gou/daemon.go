@@ -39,6 +39,7 @@ import (
 	"path"
 	"path/filepath"
 	"strconv"
+	"sync"
 	"time"
 
 	"golang.org/x/net/netutil"
@@ -50,64 +51,124 @@ import (
 	"bbs/cgi/mch"
 	"bbs/cgi/server"
 	"bbs/cgi/thread"
+	"bbs/db"
+	"bbs/myself"
+	"bbs/updateque"
 	"bbs/util"
 )
 
-//StartDaemon setups saves pid, start cron job and a http server.
-func StartDaemon() (net.Listener, chan error) {
-	p := os.Getpid()
-	err := ioutil.WriteFile(cfg.PID(), []byte(strconv.Itoa(p)), 0666)
-	if err != nil {
-		log.Fatal(err)
+//Instance is a node of shinGETsu which owns its config, db, update queue and http handlers.
+//instances are independent of each other, so that multiple ones can run in one process.
+type Instance struct {
+	My    *myself.Myself
+	Queue *updateque.Queue
+	Mux   *cgi.LoggingServeMux
+
+	mutex    sync.Mutex
+	listener net.Listener
+	done     chan struct{}
+	wg       sync.WaitGroup //cron jobs
+	running  bool           //true if heavymoon is running
+}
+
+//NewInstance returns Instance whose config is c and db is s, and registers its http handlers.
+func NewInstance(c *cfg.Config, s db.Store) *Instance {
+	my := myself.New(c, s)
+	in := &Instance{
+		My:    my,
+		Queue: updateque.New(my),
+		Mux:   cgi.NewLoggingServeMux(),
+		done:  make(chan struct{}),
+	}
+	e := &cgi.Env{
+		My:    in.My,
+		Queue: in.Queue,
 	}
+	admin.Setup(in.Mux, e)
+	server.Setup(in.Mux, e)
+	gateway.Setup(in.Mux, e)
+	thread.Setup(in.Mux, e)
 
-	h := fmt.Sprintf("0.0.0.0:%d", cfg.DefaultPort)
-	listener, err := net.Listen("tcp", h)
-	if err != nil {
-		log.Fatalln(err)
+	if c.Enable2ch {
+		fmt.Println("started 2ch interface...")
+		mch.Setup(in.Mux, e)
 	}
-	limitListener := netutil.LimitListener(listener, cfg.MaxConnection)
-	sm := cgi.NewLoggingServeMux()
+	if c.EnableProf {
+		in.Mux.RegisterPprof()
+	}
+	in.Mux.RegistCompressHandler("/", handleRoot(e))
+	return in
+}
+
+//Serve serves http requests to the instance from l until l is closed.
+func (in *Instance) Serve(l net.Listener) error {
+	in.mutex.Lock()
+	in.listener = l
+	in.mutex.Unlock()
 	s := &http.Server{
-		Addr:           h,
-		Handler:        sm,
+		Handler:        in.Mux,
 		ReadTimeout:    3 * time.Minute,
 		WriteTimeout:   3 * time.Minute,
 		MaxHeaderBytes: 1 << 20,
 	}
+	return s.Serve(l)
+}
 
-	go cron()
-
-	admin.Setup(sm)
-	server.Setup(sm)
-	gateway.Setup(sm)
-	thread.Setup(sm)
+//Close stops cron and the http server, and closes the db after cron jobs finish.
+func (in *Instance) Close() error {
+	in.mutex.Lock()
+	select {
+	case <-in.done:
+		in.mutex.Unlock()
+		return nil
+	default:
+	}
+	close(in.done)
+	l := in.listener
+	in.mutex.Unlock()
+	if l != nil {
+		if err := l.Close(); err != nil {
+			log.Println(err)
+		}
+	}
+	in.wg.Wait()
+	return in.My.DB.Close()
+}
 
-	if cfg.Enable2ch {
-		fmt.Println("started 2ch interface...")
-		mch.Setup(sm)
+//StartDaemon saves pid, and starts an instance whose config is c and db is s
+//with cron jobs and a http server.
+func StartDaemon(c *cfg.Config, s db.Store) (*Instance, chan error) {
+	p := os.Getpid()
+	err := ioutil.WriteFile(c.PID(), []byte(strconv.Itoa(p)), 0666)
+	if err != nil {
+		log.Fatal(err)
 	}
-	if cfg.EnableProf {
-		sm.RegisterPprof()
+
+	h := fmt.Sprintf("0.0.0.0:%d", c.DefaultPort)
+	listener, err := net.Listen("tcp", h)
+	if err != nil {
+		log.Fatalln(err)
 	}
-	sm.RegistCompressHandler("/", handleRoot())
+	limitListener := netutil.LimitListener(listener, c.MaxConnection)
+	in := NewInstance(c, s)
+	in.StartCron()
 	fmt.Println("started daemon and http server...")
 	ch := make(chan error)
 	go func() {
-		ch <- s.Serve(limitListener)
+		ch <- in.Serve(limitListener)
 	}()
-	return limitListener, ch
+	return in, ch
 }
 
 //handleRoot return handler that handles url not defined other handlers.
 //if root, print titles of threads. if not, serve files on disk.
-func handleRoot() func(http.ResponseWriter, *http.Request) {
+func handleRoot(e *cgi.Env) func(http.ResponseWriter, *http.Request) {
 	return func(w http.ResponseWriter, r *http.Request) {
 		if r.URL.Path == "/" {
-			gateway.PrintTitle(w, r)
+			gateway.PrintTitle(e, w, r)
 			return
 		}
-		pathOnDisk := filepath.Join(cfg.Docroot, r.URL.Path)
+		pathOnDisk := filepath.Join(e.My.Cfg.Docroot, r.URL.Path)
 
 		if util.IsFile(pathOnDisk) {
 			http.ServeFile(w, r, pathOnDisk)
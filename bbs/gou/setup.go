@@ -43,7 +43,7 @@ import (
 )
 
 //ExpandAssets expands files in /file in an Assets if not exist in disk.
-func ExpandAssets() {
+func ExpandAssets(c *cfg.Config) {
 	dir, err := util.AssetDir("file")
 	if err != nil {
 		log.Fatal(err)
@@ -52,7 +52,7 @@ func ExpandAssets() {
 		if fname == "message-ja.txt" || fname == "message-en.txt" {
 			continue
 		}
-		fnameDisk := path.Join(cfg.FileDir, fname)
+		fnameDisk := path.Join(c.FileDir, fname)
 		fnameDisk = filepath.FromSlash(fnameDisk)
 		if util.IsFile(fnameDisk) {
 			continue
@@ -77,11 +77,11 @@ func ExpandAssets() {
 }
 
 //SetLogger setups logger. whici outputs nothing, or file , or file and stdout
-func SetLogger(printLog, isSilent bool) {
+func SetLogger(c *cfg.Config, printLog, isSilent bool) {
 	log.SetFlags(log.Ldate | log.Ltime | log.Lshortfile)
 
 	l := &lumberjack.Logger{
-		Filename:   path.Join(cfg.LogDir, "gou.log"),
+		Filename:   path.Join(c.LogDir, "gou.log"),
 		MaxSize:    5, // megabytes
 		MaxBackups: 10,
 		MaxAge:     28, //days
@@ -91,18 +91,18 @@ func SetLogger(printLog, isSilent bool) {
 		fmt.Println("logging is discarded")
 		log.SetOutput(ioutil.Discard)
 	case printLog:
-		fmt.Println("outputs logs to stdout and ", cfg.LogDir)
+		fmt.Println("outputs logs to stdout and ", c.LogDir)
 		m := io.MultiWriter(os.Stdout, l)
 		log.SetOutput(m)
 	default:
-		fmt.Println("output logs to ", cfg.LogDir)
+		fmt.Println("output logs to ", c.LogDir)
 		log.SetOutput(l)
 	}
 }
 
 //SetupDirectories makes necessary dirs.
-func SetupDirectories() {
-	for _, j := range []string{cfg.RunDir, cfg.LogDir} {
+func SetupDirectories(c *cfg.Config) {
+	for _, j := range []string{c.RunDir, c.LogDir} {
 		if !util.IsDir(j) {
 			err := os.MkdirAll(j, 0755)
 			if err != nil {
@@ -0,0 +1,271 @@
+/*
+ * Copyright (c) 2015, Shinya Yagyu
+ * All rights reserved.
+ * Redistribution and use in source and binary forms, with or without
+ * modification, are permitted provided that the following conditions are met:
+ *
+ * 1. Redistributions of source code must retain the above copyright notice,
+ *    this list of conditions and the following disclaimer.
+ * 2. Redistributions in binary form must reproduce the above copyright notice,
+ *    this list of conditions and the following disclaimer in the documentation
+ *    and/or other materials provided with the distribution.
+ * 3. Neither the name of the copyright holder nor the names of its
+ *    contributors may be used to endorse or promote products derived from this
+ *    software without specific prior written permission.
+ *
+ * THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+ * AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+ * IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+ * ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+ * LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+ * CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+ * SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+ * INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+ * CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+ * ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+ * POSSIBILITY OF SUCH DAMAGE.
+ */
+package importer
+
+import (
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"bbs/db"
+	"bbs/mch"
+	"bbs/myself"
+	"bbs/record"
+	"bbs/thread"
+	"bbs/util"
+)
+
+//DatResult is # of data imported from dat files of 2ch.
+type DatResult struct {
+	Threads int
+	Records int
+	Exists  int //records which are already in db
+	Invalid int //lines which cannot be parsed, e.g. ones deleted by "あぼーん"
+}
+
+var (
+	jst       = time.FixedZone("JST", 9*60*60)
+	datDate   = regexp.MustCompile(`^(\d{2,4})/(\d{1,2})/(\d{1,2})\D*(\d{1,2}):(\d{2})(?::(\d{2}))?`)
+	datTag    = regexp.MustCompile(`<[^>]*>`)
+	datAnchor = regexp.MustCompile(`&gt;&gt;([1-9][0-9]*)(?:-([1-9][0-9]*))?`)
+)
+
+//datThread is a thread parsed from a dat file.
+type datThread struct {
+	title   string
+	datfile string
+	recs    []*record.Record
+	nos     []int //res numbers of recs
+	invalid int
+}
+
+//Dat imports threads from the dat file fname of 2ch, or from all *.dat files
+//if fname is a directory, and writes progress to w.
+//if dryrun, nothing is saved and records which would be created are written to w.
+func Dat(my *myself.Myself, fname string, dryrun bool, w io.Writer) (*DatResult, error) {
+	files := []string{fname}
+	if util.IsDir(fname) {
+		files = nil
+		err := util.EachFiles(fname, func(f os.FileInfo) error {
+			if !f.IsDir() && strings.HasSuffix(f.Name(), ".dat") {
+				files = append(files, path.Join(fname, f.Name()))
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	res := &DatResult{}
+	for i, f := range files {
+		dat, err := ioutil.ReadFile(f)
+		if err != nil {
+			return res, err
+		}
+		t, err := parseDat(my, util.FromSJIS(string(dat)))
+		if err != nil {
+			return res, fmt.Errorf("%s: %v", f, err)
+		}
+		res.Invalid += t.invalid
+		fmt.Fprintf(w, "%d/%d %s: %s (%s) %d records\n", i+1, len(files), path.Base(f), t.title, t.datfile, len(t.recs))
+		if dryrun {
+			for j, r := range t.recs {
+				fmt.Fprintf(w, "\t%d\t%s\t%s\t%s\n", t.nos[j], time.Unix(r.Stamp, 0).In(jst).Format("2006-01-02 15:04:05"),
+					r.LegacyID()[:8], r.GetBodyValue("name", ""))
+			}
+			res.Threads++
+			res.Records += len(t.recs)
+			continue
+		}
+		err = my.DB.Update(func(tx db.Tx) error {
+			return importDatThread(my, tx, t, res)
+		})
+		if err != nil {
+			return res, err
+		}
+		res.Threads++
+	}
+	return res, nil
+}
+
+//importDatThread saves records in thread t and subscribes it.
+func importDatThread(my *myself.Myself, tx db.Tx, t *datThread, res *DatResult) error {
+	thread.NewCache(my, t.datfile).SubscribeTX(tx)
+	for _, r := range t.recs {
+		if record.ResolveTX(tx, r.Head) != nil {
+			res.Exists++
+			continue
+		}
+		if err := r.SyncTX(tx, false); err != nil {
+			return err
+		}
+		res.Records++
+	}
+	return nil
+}
+
+//parseDat parses the dat of 2ch, whose lines are name<>mail<>date<>body<>title,
+//and returns the thread whose title is the one in the first line.
+//>>N and >>N-M anchors in bodies are replaced with >>id[:8] of the referred records.
+func parseDat(my *myself.Myself, dat string) (*datThread, error) {
+	lines := strings.Split(strings.TrimRight(strings.Replace(dat, "\r\n", "\n", -1), "\n"), "\n")
+	t := &datThread{}
+	if ls := strings.Split(lines[0], "<>"); len(ls) >= 5 {
+		t.title = html.UnescapeString(datText(ls[4]))
+	}
+	if t.title == "" {
+		return nil, errors.New("no title in the first line")
+	}
+	t.datfile = util.FileEncode("thread", t.title)
+	table := &mch.ResTable{
+		ID2num: make(map[string]int),
+		Num2id: make([]string, len(lines)+1),
+	}
+	for i, line := range lines {
+		no := i + 1
+		ls := strings.Split(line, "<>")
+		if len(ls) < 4 {
+			t.invalid++
+			continue
+		}
+		stamp, err := datStamp(ls[2])
+		if err != nil {
+			t.invalid++
+			continue
+		}
+		body := datBody(ls[3], table, no)
+		if body == "" {
+			t.invalid++
+			continue
+		}
+		mail := datText(ls[1])
+		if strings.ToLower(mail) == "sage" {
+			mail = ""
+		}
+		r, err := datRecord(my, t.datfile, stamp, datText(ls[0]), mail, body)
+		if err != nil {
+			t.invalid++
+			continue
+		}
+		table.Num2id[no] = r.LegacyID()[:8]
+		table.ID2num[r.LegacyID()[:8]] = no
+		t.recs = append(t.recs, r)
+		t.nos = append(t.nos, no)
+	}
+	return t, nil
+}
+
+//datRecord returns the record which has name, mail and body.
+func datRecord(my *myself.Myself, datfile string, stamp int64, name, mail, body string) (*record.Record, error) {
+	var bs []string
+	for _, kv := range [][2]string{{"body", body}, {"name", name}, {"mail", mail}} {
+		if kv[1] != "" {
+			bs = append(bs, kv[0]+":"+kv[1])
+		}
+	}
+	bodystr := strings.Join(bs, "<>")
+	r := record.New(my, datfile, "", 0)
+	err := r.Parse(fmt.Sprintf("%d<>%s<>%s", stamp, util.MD5digest(bodystr), bodystr))
+	return r, err
+}
+
+//datStamp returns the stamp of date in dat, e.g. "2005/01/02(日) 03:04:05.67 ID:abcdefgh",
+//which is in JST.
+func datStamp(date string) (int64, error) {
+	m := datDate.FindStringSubmatch(strings.TrimSpace(date))
+	if m == nil {
+		return 0, errors.New("bad date " + date)
+	}
+	var d [6]int
+	for i, s := range m[1:] {
+		if s != "" {
+			d[i], _ = strconv.Atoi(s)
+		}
+	}
+	switch {
+	case d[0] < 50:
+		d[0] += 2000
+	case d[0] < 100:
+		d[0] += 1900
+	}
+	t := time.Date(d[0], time.Month(d[1]), d[2], d[3], d[4], d[5], 0, jst)
+	return t.Unix(), nil
+}
+
+//datText returns str without html tags.
+func datText(str string) string {
+	return strings.TrimSpace(datTag.ReplaceAllString(str, ""))
+}
+
+//datBody returns the body of the noth record in a dat, whose links are removed
+//and >>N anchors to the former records are replaced with >>id[:8].
+//>>N-M anchors are replaced with anchors to each record from N to M.
+func datBody(body string, table *mch.ResTable, no int) string {
+	var lines []string
+	for _, l := range strings.Split(body, "<br>") {
+		lines = append(lines, datText(l))
+	}
+	for len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	body = strings.Join(lines, "<br>")
+	return datAnchor.ReplaceAllStringFunc(body, func(str string) string {
+		m := datAnchor.FindStringSubmatch(str)
+		begin, err := strconv.Atoi(m[1])
+		if err != nil {
+			return str
+		}
+		end := begin
+		if m[2] != "" {
+			if end, err = strconv.Atoi(m[2]); err != nil || end < begin {
+				return str
+			}
+		}
+		if end >= no {
+			end = no - 1
+		}
+		var anchors []string
+		for n := begin; n <= end; n++ {
+			if table.Num2id[n] != "" {
+				anchors = append(anchors, "&gt;&gt;"+table.Num2id[n])
+			}
+		}
+		if len(anchors) == 0 {
+			return str
+		}
+		return strings.Join(anchors, " ")
+	})
+}
@@ -0,0 +1,144 @@
+/*
+ * Copyright (c) 2015, Shinya Yagyu
+ * All rights reserved.
+ * Redistribution and use in source and binary forms, with or without
+ * modification, are permitted provided that the following conditions are met:
+ *
+ * 1. Redistributions of source code must retain the above copyright notice,
+ *    this list of conditions and the following disclaimer.
+ * 2. Redistributions in binary form must reproduce the above copyright notice,
+ *    this list of conditions and the following disclaimer in the documentation
+ *    and/or other materials provided with the distribution.
+ * 3. Neither the name of the copyright holder nor the names of its
+ *    contributors may be used to endorse or promote products derived from this
+ *    software without specific prior written permission.
+ *
+ * THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+ * AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+ * IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+ * ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+ * LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+ * CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+ * SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+ * INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+ * CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+ * ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+ * POSSIBILITY OF SUCH DAMAGE.
+ */
+
+package importer
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"bbs/cfg"
+	"bbs/db"
+	"bbs/mch"
+	"bbs/myself"
+
+	"gopkg.in/ini.v1"
+)
+
+func TestDatStamp(t *testing.T) {
+	tests := map[string]time.Time{
+		"2005/01/02(日) 03:04:05.67 ID:abcdefgh": time.Date(2005, 1, 2, 3, 4, 5, 0, jst),
+		"05/01/02(日) 03:04":                     time.Date(2005, 1, 2, 3, 4, 0, 0, jst),
+		"99/12/31(金) 23:59:59":                  time.Date(1999, 12, 31, 23, 59, 59, 0, jst),
+		" 2010/1/2 0:00:00 ":                    time.Date(2010, 1, 2, 0, 0, 0, 0, jst),
+	}
+	for date, tm := range tests {
+		stamp, err := datStamp(date)
+		if err != nil {
+			t.Fatal(date, err)
+		}
+		if stamp != tm.Unix() {
+			t.Fatal(date, "is parsed to", time.Unix(stamp, 0).In(jst))
+		}
+	}
+	if stamp := time.Date(2005, 1, 2, 3, 4, 5, 0, jst).Unix(); stamp != 1104602645 {
+		t.Fatal("JST is not +0900", stamp)
+	}
+	for _, date := range []string{"あぼーん", "", "ID:abcdefgh"} {
+		if _, err := datStamp(date); err == nil {
+			t.Fatal(date, "is parsed")
+		}
+	}
+}
+
+func TestDatBody(t *testing.T) {
+	table := &mch.ResTable{
+		Num2id: []string{"", "11111111", "", "33333333", "44444444", "55555555"},
+	}
+	tests := []struct {
+		body string
+		no   int
+		want string
+	}{
+		{"hello", 5, "hello"},
+		{"&gt;&gt;1", 5, "&gt;&gt;11111111"},
+		{"&gt;&gt;2", 5, "&gt;&gt;2"},
+		{"&gt;&gt;5", 5, "&gt;&gt;5"},
+		{"&gt;&gt;1-4", 5, "&gt;&gt;11111111 &gt;&gt;33333333 &gt;&gt;44444444"},
+		{"&gt;&gt;3-9", 5, "&gt;&gt;33333333 &gt;&gt;44444444"},
+		{"&gt;&gt;4-1", 5, "&gt;&gt;4-1"},
+		{"&gt;&gt;5-9", 5, "&gt;&gt;5-9"},
+		{`<a href="../test/read.cgi/x/1/1" target="_blank">&gt;&gt;1</a> hi <br> there <br> <br>`, 5, "&gt;&gt;11111111 hi<br>there"},
+	}
+	for _, tt := range tests {
+		if b := datBody(tt.body, table, tt.no); b != tt.want {
+			t.Fatal(tt.body, "is converted to", b)
+		}
+	}
+}
+
+func TestParseDat(t *testing.T) {
+	my := myself.New(cfg.New(ini.Empty()), db.NewMemory())
+	tests := []struct {
+		dat     string
+		title   string
+		nos     []int
+		invalid int
+		bodies  []string
+	}{
+		{
+			dat: "名無し<>sage<>2005/01/02(日) 03:04:05 ID:a<> first <>スレ &amp; タイトル\n" +
+				"あぼーん<>あぼーん<>あぼーん<>あぼーん<>\n" +
+				"名無し<><>2005/01/02(日) 03:05:00<> &gt;&gt;1-2 <>\r\n",
+			title:   "スレ & タイトル",
+			nos:     []int{1, 3},
+			invalid: 1,
+			bodies:  []string{"body:first<>name:名無し", "body:&gt;&gt;%s<>name:名無し"},
+		},
+		{
+			dat:    "名無し<><>05/01/02 03:04<>a<br>b<>title\n",
+			title:  "title",
+			nos:    []int{1},
+			bodies: []string{"body:a<br>b<>name:名無し"},
+		},
+	}
+	for _, tt := range tests {
+		th, err := parseDat(my, tt.dat)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if th.title != tt.title || !reflect.DeepEqual(th.nos, tt.nos) || th.invalid != tt.invalid {
+			t.Fatalf("unexpected thread %+v", th)
+		}
+		for i, r := range th.recs {
+			want := tt.bodies[i]
+			if i > 0 {
+				want = fmt.Sprintf(want, th.recs[0].LegacyID()[:8])
+			}
+			if b := r.Recstr(); !strings.HasSuffix(b, "<>"+want) {
+				t.Fatal(b, "is not", want)
+			}
+		}
+	}
+	if _, err := parseDat(my, "a<>b<>05/01/02 03:04<>c<>\n"); err == nil {
+		t.Fatal("dat without title is parsed")
+	}
+}
@@ -0,0 +1,177 @@
+/*
+ * Copyright (c) 2015, Shinya Yagyu
+ * All rights reserved.
+ * Redistribution and use in source and binary forms, with or without
+ * modification, are permitted provided that the following conditions are met:
+ *
+ * 1. Redistributions of source code must retain the above copyright notice,
+ *    this list of conditions and the following disclaimer.
+ * 2. Redistributions in binary form must reproduce the above copyright notice,
+ *    this list of conditions and the following disclaimer in the documentation
+ *    and/or other materials provided with the distribution.
+ * 3. Neither the name of the copyright holder nor the names of its
+ *    contributors may be used to endorse or promote products derived from this
+ *    software without specific prior written permission.
+ *
+ * THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+ * AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+ * IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+ * ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+ * LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+ * CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+ * SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+ * INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+ * CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+ * ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+ * POSSIBILITY OF SUCH DAMAGE.
+ */
+package importer
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"os"
+	"path"
+	"strings"
+
+	"bbs/db"
+	"bbs/myself"
+	"bbs/record"
+	"bbs/tag"
+	"bbs/tag/suggest"
+	"bbs/tag/user"
+	"bbs/thread"
+	"bbs/util"
+)
+
+//SakuResult is # of data imported from saku cache.
+type SakuResult struct {
+	Threads int
+	Records int
+	Removed int
+	Invalid int //records which cannot be parsed or whose id is not md5 of body
+	Attach  int //attached files, whose data are in records
+	Tags    int
+	SugTags int
+}
+
+//Saku imports all threads in cache directory of saku, and writes progress to w.
+//one thread is imported in one transaction.
+func Saku(my *myself.Myself, cachedir string, w io.Writer) (*SakuResult, error) {
+	var dirs []string
+	err := util.EachFiles(cachedir, func(f os.FileInfo) error {
+		if f.IsDir() && strings.HasPrefix(f.Name(), "thread_") && util.FileDecode(f.Name()) != "" {
+			dirs = append(dirs, f.Name())
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	res := &SakuResult{}
+	for i, dir := range dirs {
+		datfile := util.FileEncode("thread", util.FileDecode(dir))
+		n := res.Records + res.Removed
+		err := my.DB.Update(func(tx db.Tx) error {
+			return importSakuThread(my, tx, path.Join(cachedir, dir), datfile, res)
+		})
+		if err != nil {
+			return res, err
+		}
+		res.Threads++
+		fmt.Fprintf(w, "%d/%d %s: %d records\n", i+1, len(dirs), util.FileDecode(datfile), res.Records+res.Removed-n)
+	}
+	return res, nil
+}
+
+//importSakuThread imports records, removed records and tags in saku thread directory dir.
+func importSakuThread(my *myself.Myself, tx db.Tx, dir, datfile string, res *SakuResult) error {
+	thread.NewCache(my, datfile).SubscribeTX(tx)
+	attached := make(map[string]bool)
+	//removed records are imported first so that they are not revived by the same ones in "record".
+	for _, sub := range []string{"removed", "record"} {
+		deleted := sub == "removed"
+		err := eachSakuFile(path.Join(dir, sub), func(fname string) error {
+			dat, err := ioutil.ReadFile(path.Join(dir, sub, fname))
+			if err != nil {
+				return err
+			}
+			r := record.New(my, datfile, "", 0)
+			if err := r.Parse(string(dat)); err != nil || r.Idstr() != fname || !r.MD5check() {
+				log.Println(datfile, sub, fname, ": invalid record")
+				res.Invalid++
+				return nil
+			}
+			if err := r.SyncTX(tx, deleted); err != nil {
+				return err
+			}
+			if r.HasBodyValue("attach") {
+				attached[fname] = true
+			}
+			if deleted {
+				res.Removed++
+			} else {
+				res.Records++
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+	err := eachSakuFile(path.Join(dir, "attach"), func(fname string) error {
+		//s<idstr>.<size>.<suffix> is a thumbnail.
+		if strings.HasPrefix(fname, "s") {
+			return nil
+		}
+		res.Attach++
+		if idstr := strings.SplitN(fname, ".", 2)[0]; !attached[idstr] {
+			log.Println(datfile, fname, ": attached file without record")
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	tags := sakuTags(path.Join(dir, "tag.txt"))
+	if err := user.AddTX(tx, datfile, tags); err != nil {
+		return err
+	}
+	res.Tags += len(tags)
+	sugtags := sakuTags(path.Join(dir, "sugtag.txt"))
+	suggest.AddString(tx, datfile, sugtags)
+	res.SugTags += len(sugtags)
+	return nil
+}
+
+//eachSakuFile calls handler with the name of each file in dir.
+//it does nothing if dir doesn't exist.
+func eachSakuFile(dir string, handler func(fname string) error) error {
+	if !util.IsDir(dir) {
+		return nil
+	}
+	return util.EachFiles(dir, func(f os.FileInfo) error {
+		if f.IsDir() {
+			return nil
+		}
+		return handler(f.Name())
+	})
+}
+
+//sakuTags returns valid tags in tag file of saku, which has one tag in one line.
+func sakuTags(fname string) []string {
+	dat, err := ioutil.ReadFile(fname)
+	if err != nil {
+		return nil
+	}
+	var tags []string
+	for _, t := range strings.Split(string(dat), "\n") {
+		t = strings.TrimSpace(t)
+		if t != "" && tag.IsOK(t) && !util.HasString(tags, t) {
+			tags = append(tags, t)
+		}
+	}
+	return tags
+}
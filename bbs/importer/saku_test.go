@@ -0,0 +1,131 @@
+/*
+ * Copyright (c) 2015, Shinya Yagyu
+ * All rights reserved.
+ * Redistribution and use in source and binary forms, with or without
+ * modification, are permitted provided that the following conditions are met:
+ *
+ * 1. Redistributions of source code must retain the above copyright notice,
+ *    this list of conditions and the following disclaimer.
+ * 2. Redistributions in binary form must reproduce the above copyright notice,
+ *    this list of conditions and the following disclaimer in the documentation
+ *    and/or other materials provided with the distribution.
+ * 3. Neither the name of the copyright holder nor the names of its
+ *    contributors may be used to endorse or promote products derived from this
+ *    software without specific prior written permission.
+ *
+ * THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+ * AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+ * IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+ * ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+ * LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+ * CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+ * SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+ * INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+ * CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+ * ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+ * POSSIBILITY OF SUCH DAMAGE.
+ */
+package importer
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+
+	"bbs/myself"
+	"bbs/record"
+	"bbs/tag/suggest"
+	"bbs/tag/user"
+	"bbs/thread"
+	"bbs/util"
+)
+
+//writeSaku writes files in saku thread directory dir, whose keys are paths from dir.
+func writeSaku(t *testing.T, dir string, files map[string]string) {
+	for fname, dat := range files {
+		p := path.Join(dir, fname)
+		if err := os.MkdirAll(path.Dir(p), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(p, []byte(dat), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestSaku(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gou")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	my := myself.NewMemory(dir)
+
+	datfile := util.FileEncode("thread", "saku")
+	recs := make([]*record.Record, 4)
+	for i := range recs {
+		recs[i] = record.New(my, datfile, "", 0)
+		body := map[string]string{"body": fmt.Sprint("body", i)}
+		if i == 1 {
+			body["attach"] = "YXR0YWNo"
+			body["suffix"] = "png"
+		}
+		recs[i].Build(int64(1500000000+i*10), body, "")
+	}
+	fname := func(r *record.Record) string {
+		return fmt.Sprintf("%d_%s", r.Stamp, r.LegacyID())
+	}
+	bad := fmt.Sprintf("1500000040<>%s<>body:bad", util.MD5digest("body:good"))
+	cachedir := path.Join(dir, "cache")
+	writeSaku(t, path.Join(cachedir, datfile), map[string]string{
+		"record/" + fname(recs[0]):                         recs[0].Recstr(),
+		"record/" + fname(recs[1]):                         recs[1].Recstr(),
+		"record/" + fname(recs[2]):                         recs[2].Recstr(),
+		"removed/" + fname(recs[2]):                        recs[2].Recstr(),
+		"record/1500000030_" + util.MD5digest("x"):         recs[3].Recstr(),
+		"record/1500000040_" + util.MD5digest("body:good"): bad,
+		"attach/" + fname(recs[1]) + ".png":                "attach",
+		"attach/s" + fname(recs[1]) + ".100x100.png":       "thumbnail",
+		"tag.txt":    "news\nnews\na<b\n\nlinux\n",
+		"sugtag.txt": "foo\n",
+	})
+	if err := os.MkdirAll(path.Join(cachedir, "thread_zz"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	res, err := Saku(my, cachedir, ioutil.Discard)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := SakuResult{
+		Threads: 1,
+		Records: 3,
+		Removed: 1,
+		Invalid: 2,
+		Attach:  1,
+		Tags:    2,
+		SugTags: 1,
+	}
+	if *res != expected {
+		t.Fatalf("unexpected result %+v", res)
+	}
+	ca := thread.NewCache(my, datfile)
+	alive := ca.LoadRecords(record.Alive)
+	if len(alive) != 2 {
+		t.Fatal(len(alive), "alive records, expected 2")
+	}
+	if _, exist := alive[fname(recs[2])]; exist {
+		t.Fatal("removed record is revived")
+	}
+	if removed := ca.LoadRecords(record.Removed); len(removed) != 1 {
+		t.Fatal(len(removed), "removed records, expected 1")
+	}
+	if tags := user.GetStrings(my.DB, datfile); len(tags) != 2 || !util.HasString(tags, "news") || !util.HasString(tags, "linux") {
+		t.Fatal("unexpected tags", tags)
+	}
+	if sugtags := suggest.Get(my, datfile, nil); len(sugtags) != 1 {
+		t.Fatal("unexpected suggested tags", sugtags)
+	}
+}
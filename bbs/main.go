@@ -1,9 +1,18 @@
 package main
 
 import (
+	"bbs/bundle"
 	"bbs/cfg"
+	"bbs/cgi"
 	"bbs/db"
+	"bbs/export"
+	"bbs/fsck"
 	"bbs/gou"
+	"bbs/importer"
+	"bbs/myself"
+	"bbs/record"
+	"bbs/updateque"
+	"bbs/util"
 	"flag"
 	"fmt"
 	"log"
@@ -14,7 +23,10 @@ import (
 
 func main() {
 	fmt.Println("starting Gou", cfg.Version, "...")
-	var printLog, isSilent bool
+	var printLog, isSilent, dryRun, rebuildStat, fsckCheck, fsckRepair, datDryRun bool
+	var restore, sakuCache, datImport, exportBundle, importBundle, bundleTag, bundleSince string
+	var bundleThreads titles
+	var exportThread, exportTag, exportFormat, exportOut string
 	flag.Usage = func() {
 		fmt.Fprintf(os.Stderr, "P2P anonymous BBS shinGETsu Gou %s\n", cfg.Version)
 		fmt.Fprintf(os.Stderr, "%s <options>\n", os.Args[0])
@@ -23,26 +35,201 @@ func main() {
 	flag.BoolVar(&printLog, "verbose", false, "print logs")
 	flag.BoolVar(&printLog, "v", false, "print logs")
 	flag.BoolVar(&isSilent, "silent", false, "suppress logs")
+	flag.BoolVar(&dryRun, "dry-run-migration", false, "check db migrations without applying them and exit")
+	flag.BoolVar(&rebuildStat, "rebuild-threadstat", false, "rebuild statistics of all threads and exit")
+	flag.BoolVar(&fsckCheck, "fsck", false, "check consistency of db and exit")
+	flag.BoolVar(&fsckRepair, "fsck-repair", false, "check consistency of db, repair it and exit")
+	flag.StringVar(&sakuCache, "import-saku", "", "import threads from the cache directory of saku and exit")
+	flag.StringVar(&datImport, "import-dat", "", "import threads from the dat file of 2ch or the directory of dat files and exit")
+	flag.BoolVar(&datDryRun, "import-dat-dry-run", false, "show threads and records which -import-dat would create")
+	flag.StringVar(&exportBundle, "export-bundle", "", "export records to the bundle file and exit")
+	flag.StringVar(&importBundle, "import-bundle", "", "import records from the bundle file and exit")
+	flag.Var(&bundleThreads, "bundle-thread", "title of the thread to be exported (can be repeated)")
+	flag.StringVar(&bundleTag, "bundle-tag", "", "export threads which have the user tag")
+	flag.StringVar(&bundleSince, "bundle-since", "", "export records since the stamp or date (2006-01-02)")
+	flag.StringVar(&exportThread, "export-thread", "", "export the thread with the title and exit")
+	flag.StringVar(&exportTag, "export-tag", "", "export threads which have the user tag as a zip file and exit")
+	flag.StringVar(&exportFormat, "export-format", "json", "format of exported threads (json, html, dat or mbox)")
+	flag.StringVar(&exportOut, "export-out", "", "file name of exported threads")
+	flag.StringVar(&restore, "restore", "", "restore db from the snapshot file before starting")
 	flag.Parse()
-	cfg.Parse()
-	gou.SetupDirectories()
-	gou.SetLogger(printLog, isSilent)
+	c := cfg.Parse()
+	gou.SetupDirectories(c)
+	gou.SetLogger(c, printLog, isSilent)
 	log.Println("********************starting Gou", cfg.Version, "...******************")
-	gou.ExpandAssets()
-	db.Setup()
-	listener, ch := gou.StartDaemon()
-	c := make(chan os.Signal)
-	signal.Notify(c, os.Interrupt, syscall.SIGTERM)
+	gou.ExpandAssets(c)
+	if restore != "" {
+		if err := db.Restore(c, restore); err != nil {
+			log.Fatal(err)
+		}
+	}
+	if dryRun {
+		if err := db.Migrate(db.Open(c), c, true); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+	s := db.Setup(c)
+	my := myself.New(c, s)
+	if rebuildStat {
+		if err := record.RebuildStats(my); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+	if sakuCache != "" {
+		res, err := importer.Saku(my, sakuCache, os.Stdout)
+		if err != nil {
+			log.Fatal(err)
+		}
+		fmt.Printf("%+v\n", *res)
+		return
+	}
+	if datImport != "" {
+		res, err := importer.Dat(my, datImport, datDryRun, os.Stdout)
+		if err != nil {
+			log.Fatal(err)
+		}
+		fmt.Printf("%+v\n", *res)
+		return
+	}
+	if exportBundle != "" {
+		since, err := bundle.ParseSince(bundleSince)
+		if err != nil {
+			log.Fatal(err)
+		}
+		runExportBundle(my, exportBundle, &bundle.Filter{
+			Threads: bundleThreads,
+			Tag:     bundleTag,
+			Since:   since,
+		})
+		return
+	}
+	if exportThread != "" || exportTag != "" {
+		runExport(my, exportThread, exportTag, exportFormat, exportOut)
+		return
+	}
+	if importBundle != "" {
+		runImportBundle(my, importBundle)
+		return
+	}
+	if fsckCheck || fsckRepair {
+		if !runFsck(s, fsckRepair) {
+			os.Exit(1)
+		}
+		return
+	}
+	in, ch := gou.StartDaemon(c, s)
+	sig := make(chan os.Signal)
+	signal.Notify(sig, os.Interrupt, syscall.SIGTERM)
 	go func() {
-		for range c {
+		for range sig {
 			fmt.Println("exiting...")
-			if err := listener.Close(); err != nil {
-				log.Println(err)
-			}
-			if err := db.DB.Close(); err != nil {
+			if err := in.Close(); err != nil {
 				log.Println(err)
 			}
 		}
 	}()
 	log.Println(<-ch)
-}
\ No newline at end of file
+}
+
+//runFsck checks db and prints problems.
+//it returns false if problems are found and not repaired.
+func runFsck(s db.Store, repair bool) bool {
+	ps, err := fsck.Check(s, repair)
+	if err != nil {
+		log.Fatal(err)
+	}
+	for _, p := range ps {
+		fmt.Printf("%s\t%s\t%s\n", p.Class, p.Key, p.Detail)
+	}
+	cnt := fsck.Count(ps)
+	for _, c := range fsck.Classes {
+		fmt.Printf("%s: %d\n", c, cnt[c])
+	}
+	if repair && len(ps) > 0 {
+		fmt.Println(len(ps), "problems are repaired")
+	}
+	return repair || len(ps) == 0
+}
+
+//titles is thread names from titles in args.
+type titles []string
+
+func (t *titles) String() string {
+	return fmt.Sprint(*t)
+}
+
+//Set adds datfile of the title.
+func (t *titles) Set(title string) error {
+	*t = append(*t, util.FileEncode("thread", title))
+	return nil
+}
+
+//runExportBundle exports records selected by f to the bundle file fname.
+func runExportBundle(my *myself.Myself, fname string, f *bundle.Filter) {
+	fp, err := os.Create(fname)
+	if err != nil {
+		log.Fatal(err)
+	}
+	n, err := bundle.Export(my, fp, f)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := fp.Close(); err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(n, "records are exported to", fname)
+}
+
+//runImportBundle imports records from the bundle file fname.
+//new records are queued in the update queue, and are told to other nodes
+//after the node starts.
+func runImportBundle(my *myself.Myself, fname string) {
+	fp, err := os.Open(fname)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer fp.Close()
+	res, err := bundle.Import(my, fp, updateque.New(my))
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("%+v\n", *res)
+}
+
+//runExport exports the thread whose title is title, or threads which have tag, to the file out.
+func runExport(my *myself.Myself, title, tag, format, out string) {
+	if !util.HasString(export.Formats, format) {
+		log.Fatal("unknown format ", format)
+	}
+	e := &export.Exporter{
+		My:     my,
+		Format: format,
+		Host:   fmt.Sprintf("localhost:%d", my.Cfg.DefaultPort),
+		M:      cgi.SearchMessage("", my.Cfg.FileDir),
+	}
+	datfile := util.FileEncode("thread", title)
+	if out == "" {
+		out = datfile + "." + format
+		if tag != "" {
+			out = tag + ".zip"
+		}
+	}
+	fp, err := os.Create(out)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if tag != "" {
+		_, err = e.Tag(fp, tag)
+	} else {
+		err = e.Thread(fp, datfile)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := fp.Close(); err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println("exported to", out)
+}
@@ -31,26 +31,27 @@ package keylib
 import (
 	"fmt"
 	"log"
+	"math"
 	"regexp"
 	"time"
 
-	"github.com/boltdb/bolt"
 	"bbs/db"
 	"bbs/mch"
+	"bbs/myself"
 	"bbs/recentlist"
 	"bbs/record"
 	"bbs/thread"
 	"bbs/util"
 )
 
-func getThread(tx *bolt.Tx, stamp int64) (string, error) {
+func getThread(tx db.Tx, stamp int64) (string, error) {
 	var thread string
 	k := db.MustTob(stamp)
 	_, err := db.Get(tx, "keylibST", k, &thread)
 	return thread, err
 }
 
-func getTime(tx *bolt.Tx, thread string) (int64, error) {
+func getTime(tx db.Tx, thread string) (int64, error) {
 	var stamp int64
 	k := db.MustTob(thread)
 	_, err := db.Get(tx, "keylibTS", k, &stamp)
@@ -59,15 +60,15 @@ func getTime(tx *bolt.Tx, thread string) (int64, error) {
 
 //Load loads from the file, adds stamps/datfile pairs from cachelist and recentlist.
 //and saves to file.
-func Load() {
-	allCaches := thread.AllCaches()
-	allRecs := recentlist.GetRecords()
-	err := db.DB.Update(func(tx *bolt.Tx) error {
+func Load(my *myself.Myself) {
+	allCaches := thread.AllCaches(my)
+	allRecs := recentlist.GetRecords(my)
+	err := my.DB.Update(func(tx db.Tx) error {
 		for _, c := range allCaches {
 			setFromCache(tx, c)
 		}
 		for _, rec := range allRecs {
-			c := thread.NewCache(rec.Datfile)
+			c := thread.NewCache(my, rec.Datfile)
 			setFromCache(tx, c)
 		}
 		return nil
@@ -78,7 +79,7 @@ func Load() {
 }
 
 //setEntry stores stamp/value.
-func setEntry(tx *bolt.Tx, stamp int64, filekey string) {
+func setEntry(tx db.Tx, stamp int64, filekey string) {
 	sb := db.MustTob(stamp)
 	fb := db.MustTob(filekey)
 	err := db.Put(tx, "keylibST", sb, fb)
@@ -92,7 +93,7 @@ func setEntry(tx *bolt.Tx, stamp int64, filekey string) {
 }
 
 //setFromCache adds cache.datfile/timestamp pair if not exists.
-func setFromCache(tx *bolt.Tx, ca *thread.Cache) {
+func setFromCache(tx db.Tx, ca *thread.Cache) {
 	_, err := getTime(tx, ca.Datfile)
 	if err == nil {
 		return
@@ -119,15 +120,15 @@ func setFromCache(tx *bolt.Tx, ca *thread.Cache) {
 
 //GetDatkey returns stamp from filekey.
 //if not found, tries to read from cache.
-func GetDatkey(filekey string) (int64, error) {
+func GetDatkey(my *myself.Myself, filekey string) (int64, error) {
 	var v int64
-	err := db.DB.Update(func(tx *bolt.Tx) error {
+	err := my.DB.Update(func(tx db.Tx) error {
 		var errr error
 		v, errr = getTime(tx, filekey)
 		if errr == nil {
 			return nil
 		}
-		c := thread.NewCache(filekey)
+		c := thread.NewCache(my, filekey)
 		setFromCache(tx, c)
 		v, errr = getTime(tx, filekey)
 		return errr
@@ -136,9 +137,9 @@ func GetDatkey(filekey string) (int64, error) {
 }
 
 //GetFilekey returns value from datkey(stamp).
-func GetFilekey(nDatkey int64) string {
+func GetFilekey(my *myself.Myself, nDatkey int64) string {
 	var v string
-	err := db.DB.View(func(tx *bolt.Tx) error {
+	err := my.DB.View(func(tx db.Tx) error {
 		var errr error
 		v, errr = getThread(tx, nDatkey)
 		return errr
@@ -150,7 +151,7 @@ func GetFilekey(nDatkey int64) string {
 }
 
 //MakeBracketLink changes str in brackets to the html links format.
-func MakeBracketLink(body, datHost, board string, table *mch.ResTable) string {
+func MakeBracketLink(my *myself.Myself, body, datHost, board string, table *mch.ResTable) string {
 	regs := []*regexp.Regexp{
 		regexp.MustCompile("^(?P<title>[^/]+)$"),
 		regexp.MustCompile("^/(?P<type>[a-z]+)/(?P<title>[^/]+)$"),
@@ -176,7 +177,7 @@ func MakeBracketLink(body, datHost, board string, table *mch.ResTable) string {
 			result["type"] = "thread"
 		}
 		file := util.FileEncode(result["type"], result["title"])
-		datkey, err := GetDatkey(file)
+		datkey, err := GetDatkey(my, file)
 		if err != nil {
 			log.Println(err)
 			return body
@@ -185,8 +186,8 @@ func MakeBracketLink(body, datHost, board string, table *mch.ResTable) string {
 			url := fmt.Sprintf("http://%s/test/read.cgi/%s/%d/", datHost, board, datkey)
 			return fmt.Sprintf("[[%s(%s)]]", result["title"], url)
 		}
-		ca := thread.NewCache(file)
-		table = mch.NewResTable(ca)
+		ca := thread.NewCache(my, file)
+		table = mch.NewResTable(my, ca)
 		no := table.ID2num[result["id"]]
 		url := fmt.Sprintf("http://%s/test/read.cgi/%s/%d/%d", datHost, board, datkey, no)
 		return fmt.Sprintf("[[%s(&gt;&gt;%d %s)]]", result["title"], no, url)
@@ -194,43 +195,45 @@ func MakeBracketLink(body, datHost, board string, table *mch.ResTable) string {
 }
 
 //MakeBody makes a dat body(message) line after stamp.
-func MakeBody(rec *record.Record, host, board string, table *mch.ResTable) string {
+func MakeBody(my *myself.Myself, rec *record.Record, host, board string, table *mch.ResTable) string {
 	body := rec.GetBodyValue("body", "")
 	body += rec.MakeAttachLink(host)
 	body = table.MakeRSSAnchor(body)
-	body = MakeBracketLink(body, host, board, table)
+	body = MakeBracketLink(my, body, host, board, table)
 	return body
 }
 
 //MakeDat makes dat lines of 2ch from cache.
-func MakeDat(ca *thread.Cache, board, host string) []string {
-	recs := ca.LoadRecords(record.Alive)
-	dat := make([]string, len(recs))
-	table := mch.NewResTable(ca)
-
-	i := 0
-	for _, datfile := range recs.Keys() {
-		rec := recs[datfile]
-		err := rec.Load()
-		if err != nil {
-			log.Println(err)
-			continue
-		}
+func MakeDat(my *myself.Myself, ca *thread.Cache, board, host string) []string {
+	rg := &record.Range{
+		Datfile: ca.Datfile,
+		End:     math.MaxInt64,
+		Kind:    record.Alive,
+	}
+	page, err := thread.LoadPage(my, rg, false)
+	if err != nil {
+		log.Println(err)
+	}
+	dat := make([]string, len(page.Records))
+	table := mch.MakeResTable(page.Records)
+	for i, rec := range page.Records {
 		name := rec.GetBodyValue("name", "")
 		if name == "" {
 			name = "名無しさん"
 		}
-		if rec.GetBodyValue("pubkey", "") != "" {
-			name += "◆" + rec.GetBodyValue("pubkey", "")[:10]
+		if pubkey := rec.Pubkey(); pubkey != "" {
+			if rec.IsVerified() {
+				name += "◆" + pubkey[:10]
+			} else {
+				name += "◇" + pubkey[:10]
+			}
 		}
 		comment := fmt.Sprintf("%s<>%s<>%s<>%s<>",
-			name, rec.GetBodyValue("main", ""), util.Datestr2ch(rec.Stamp), MakeBody(rec, host, board, table))
+			name, rec.GetBodyValue("main", ""), util.Datestr2ch(rec.Stamp), MakeBody(my, rec, host, board, table))
 		if i == 0 {
 			comment += util.FileDecode(ca.Datfile)
 		}
 		dat[i] = comment
-		i++
 	}
-
 	return dat
 }
@@ -0,0 +1,120 @@
+/*
+ * Copyright (c) 2015, Shinya Yagyu
+ * All rights reserved.
+ * Redistribution and use in source and binary forms, with or without
+ * modification, are permitted provided that the following conditions are met:
+ *
+ * 1. Redistributions of source code must retain the above copyright notice,
+ *    this list of conditions and the following disclaimer.
+ * 2. Redistributions in binary form must reproduce the above copyright notice,
+ *    this list of conditions and the following disclaimer in the documentation
+ *    and/or other materials provided with the distribution.
+ * 3. Neither the name of the copyright holder nor the names of its
+ *    contributors may be used to endorse or promote products derived from this
+ *    software without specific prior written permission.
+ *
+ * THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+ * AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+ * IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+ * ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+ * LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+ * CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+ * SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+ * INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+ * CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+ * ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+ * POSSIBILITY OF SUCH DAMAGE.
+ */
+
+package keylib
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	"bbs/myself"
+	"bbs/record"
+	"bbs/thread"
+	"bbs/util"
+)
+
+//post saves a record whose stamp is stamp and body is body in the thread title.
+func post(my *myself.Myself, title string, stamp int64, body string) *record.Record {
+	datfile := util.FileEncode("thread", title)
+	thread.NewCache(my, datfile).Subscribe()
+	rec := record.New(my, datfile, "", 0)
+	rec.Build(stamp, map[string]string{"body": body}, "")
+	rec.Sync()
+	return rec
+}
+
+func TestDatkey(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gou")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	my := myself.NewMemory(dir)
+	a := post(my, "a", 1500000000, "a1")
+	post(my, "a", 1500000010, "a2")
+	b := post(my, "b", 1500000000, "b1")
+	Load(my)
+
+	ka, err := GetDatkey(my, a.Datfile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	kb, err := GetDatkey(my, b.Datfile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	//threads whose first stamps are same get different datkeys.
+	if ka == kb || ka+kb != 1500000000+1500000001 {
+		t.Fatal("illegal datkeys", ka, kb)
+	}
+	if f := GetFilekey(my, ka); f != a.Datfile {
+		t.Fatal(ka, "is the datkey of", f)
+	}
+	if f := GetFilekey(my, 1); f != "" {
+		t.Fatal(1, "is the datkey of", f)
+	}
+
+	//thread which is not in keylib is added by GetDatkey.
+	c := post(my, "c", 1500000100, "c1")
+	kc, err := GetDatkey(my, c.Datfile)
+	if err != nil || kc != 1500000100 {
+		t.Fatal("illegal datkey", kc, err)
+	}
+}
+
+func TestMakeDat(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gou")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	my := myself.NewMemory(dir)
+	r1 := post(my, "dat", 1500000000, "first")
+	post(my, "dat", 1500000010, "&gt;&gt;"+r1.LegacyID()[:8]+" [[other]]")
+	post(my, "other", 1500000020, "other")
+	Load(my)
+
+	dat := MakeDat(my, thread.NewCache(my, r1.Datfile), "2ch", "localhost:8000")
+	if len(dat) != 2 {
+		t.Fatal("dat has", len(dat), "lines")
+	}
+	if !strings.HasPrefix(dat[0], "名無しさん<>") || !strings.HasSuffix(dat[0], "<>first<>dat") {
+		t.Fatal("illegal first line", dat[0])
+	}
+	ko, err := GetDatkey(my, util.FileEncode("thread", "other"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	body := fmt.Sprintf("&gt;&gt;1 [[other(http://localhost:8000/test/read.cgi/2ch/%d/)]]", ko)
+	if !strings.HasSuffix(dat[1], "<>"+body+"<>") {
+		t.Fatal("illegal second line", dat[1])
+	}
+}
@@ -29,9 +29,12 @@
 package mch
 
 import (
+	"log"
+	"math"
 	"regexp"
 	"strconv"
 
+	"bbs/myself"
 	"bbs/record"
 	"bbs/thread"
 )
@@ -43,16 +46,30 @@ type ResTable struct {
 }
 
 //NewResTable creates ane returns a resTable instance.
-func NewResTable(ca *thread.Cache) *ResTable {
+func NewResTable(my *myself.Myself, ca *thread.Cache) *ResTable {
+	rg := &record.Range{
+		Datfile: ca.Datfile,
+		End:     math.MaxInt64,
+		Kind:    record.Alive,
+		Head:    true,
+	}
+	page, err := thread.LoadPage(my, rg, false)
+	if err != nil {
+		log.Println(err)
+	}
+	return MakeResTable(page.Records)
+}
+
+//MakeResTable returns a resTable instance for recs, which are all alive records in a thread
+//in order of stamp.
+func MakeResTable(recs []*record.Record) *ResTable {
 	r := &ResTable{
 		make(map[string]int),
-		make([]string, ca.Len(record.Alive)+1),
+		make([]string, len(recs)+1),
 	}
-	recs := ca.LoadRecords(record.Alive)
-	for i, k := range recs.Keys() {
-		rec := recs.Get(k, nil)
-		r.Num2id[i+1] = rec.ID[:8]
-		r.ID2num[rec.ID[:8]] = i + 1
+	for i, rec := range recs {
+		r.Num2id[i+1] = rec.LegacyID()[:8]
+		r.ID2num[rec.LegacyID()[:8]] = i + 1
 	}
 	return r
 }
@@ -7,84 +7,115 @@ import (
 	"time"
 
 	nat "github.com/shingetsu-gou/go-nat"
+	"gopkg.in/ini.v1"
 	"bbs/cfg"
+	"bbs/db"
 )
 
-var ip string
-var externalPort *int32
-var mutex sync.RWMutex
-var status int
+//Myself represents this node, which has its config, db and connection status.
+type Myself struct {
+	Cfg          *cfg.Config
+	DB           db.Store
+	ip           string
+	externalPort *int32
+	mutex        sync.RWMutex
+	status       int
 
-//init returns Myself obj.
-func init() {
-	resetConnection()
+	//Dial connects to addr within timeout when talking with other nodes.
+	//it can be replaced e.g. for simulating a network.
+	Dial func(network, addr string, timeout time.Duration) (net.Conn, error)
+
+	//Downloads has download managers of threads being downloaded, keyed by datfile.
+	//it is used by package download and freed with Myself.
+	Downloads sync.Map
+}
+
+//New returns Myself whose config is c and db is s.
+func New(c *cfg.Config, s db.Store) *Myself {
+	m := &Myself{
+		Cfg:  c,
+		DB:   s,
+		Dial: net.DialTimeout,
+	}
+	m.resetConnection()
+	return m
+}
+
+//NewMemory returns Myself with the default config and a memory db whose files are in docroot.
+//it is for tests.
+func NewMemory(docroot string) *Myself {
+	i := ini.Empty()
+	i.Section("Path").Key("docroot").SetValue(docroot)
+	return New(cfg.New(i), db.NewMemory())
 }
 
 //resetConnectiontPort sets externalPort to internalPort.
-func resetConnection() {
-	mutex.Lock()
-	defer mutex.Unlock()
-	p := int32(cfg.DefaultPort)
-	externalPort = &p
-	status = cfg.Disconnected
+func (m *Myself) resetConnection() {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	p := int32(m.Cfg.DefaultPort)
+	m.externalPort = &p
+	m.status = cfg.Disconnected
 }
 
 //GetStatus returns status.
-func GetStatus() int {
-	mutex.RLock()
-	defer mutex.RUnlock()
-	return status
+func (m *Myself) GetStatus() int {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	return m.status
 }
 
 //SetStatus set connection status.
-func SetStatus(stat int) {
-	mutex.Lock()
-	status = stat
-	mutex.Unlock()
+func (m *Myself) SetStatus(stat int) {
+	m.mutex.Lock()
+	m.status = stat
+	m.mutex.Unlock()
 }
 
 //GetIPPort returns ip address and external port number.
-func GetIPPort() (string, int32) {
-	mutex.RLock()
-	defer mutex.RUnlock()
-	return ip, *externalPort
+func (m *Myself) GetIPPort() (string, int32) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	return m.ip, *m.externalPort
 }
 
 //SetIP set my IP.
-func SetIP(ips string) {
-	mutex.Lock()
-	defer mutex.Unlock()
+func (m *Myself) SetIP(ips string) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
 	var nip net.IP
 	if nip = net.ParseIP(ips); nip == nil {
 		log.Println("ip", ips, "is illegal format")
 		return
 	}
 	if nat.IsGlobalIP(nip) != "" {
-		ip = ips
+		m.ip = ips
 	}
 }
 
 //useUPnP gets external port by upnp and return external port.
 //returns defaultPort if failed.
-func useUPnP() bool {
+func (m *Myself) useUPnP() bool {
 	nt, err := nat.NewNetStatus()
 	if err != nil {
 		log.Println(err)
 		return false
 	}
 	log.Println("openning port by upnp...")
-	ma, err := nt.LoopPortMapping("tcp", cfg.DefaultPort, "shingetsu-gou", 10*time.Minute)
+	ma, err := nt.LoopPortMapping("tcp", m.Cfg.DefaultPort, "shingetsu-gou", 10*time.Minute)
 	if err != nil {
 		log.Println(err)
 		return false
 	}
 	log.Println("openned port by upnp.")
-	externalPort = ma.ExternalPort
+	m.mutex.Lock()
+	m.externalPort = ma.ExternalPort
+	m.mutex.Unlock()
 	return true
 }
 
-func connectionString() string {
-	switch GetStatus() {
+func (m *Myself) connectionString() string {
+	switch m.GetStatus() {
 	case cfg.UPnP:
 		return "uPnP"
 	case cfg.Port0:
@@ -99,18 +130,18 @@ func connectionString() string {
 }
 
 //ResetPort setups connection.
-func ResetPort() {
-	if GetStatus() == cfg.Normal || GetStatus() == cfg.UPnP {
+func (m *Myself) ResetPort() {
+	if m.GetStatus() == cfg.Normal || m.GetStatus() == cfg.UPnP {
 		return
 	}
-	switch cfg.NetworkMode {
+	switch m.Cfg.NetworkMode {
 	case cfg.Normal:
-		resetConnection()
+		m.resetConnection()
 	case cfg.UPnP:
-		if useUPnP() {
-			SetStatus(cfg.UPnP)
+		if m.useUPnP() {
+			m.SetStatus(cfg.UPnP)
 		}
 	}
-	con := connectionString()
+	con := m.connectionString()
 	log.Println("openned", con)
 }
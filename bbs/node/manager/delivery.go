@@ -0,0 +1,254 @@
+/*
+ * Copyright (c) 2015, Shinya Yagyu
+ * All rights reserved.
+ * Redistribution and use in source and binary forms, with or without
+ * modification, are permitted provided that the following conditions are met:
+ *
+ * 1. Redistributions of source code must retain the above copyright notice,
+ *    this list of conditions and the following disclaimer.
+ * 2. Redistributions in binary form must reproduce the above copyright notice,
+ *    this list of conditions and the following disclaimer in the documentation
+ *    and/or other materials provided with the distribution.
+ * 3. Neither the name of the copyright holder nor the names of its
+ *    contributors may be used to endorse or promote products derived from this
+ *    software without specific prior written permission.
+ *
+ * THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+ * AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+ * IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+ * ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+ * LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+ * CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+ * SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+ * INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+ * CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+ * ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+ * POSSIBILITY OF SUCH DAMAGE.
+ */
+
+package manager
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net"
+	"sort"
+	"syscall"
+	"time"
+
+	"bbs/db"
+	"bbs/myself"
+	"bbs/node"
+)
+
+//results of telling an update to a node.
+const (
+	DeliveryOK      = "ok"      //the node accepted the update
+	DeliveryNG      = "ng"      //the node responded but didn't accept the update
+	DeliveryTimeout = "timeout" //the node didn't respond in time
+	DeliveryRefused = "refused" //the node refused the connection
+	DeliveryError   = "error"   //other errors, e.g. the name of the node is not resolved
+	DeliverySkipped = "skipped" //the node was not told before the deadline
+)
+
+const (
+	keepDelivery = 7 * 24 * time.Hour //deliveries and scores are removed after this duration
+	scoreWeight  = 0.2                //weight of the newest result in Score.Rate
+	defaultRate  = 0.5                //rate of nodes which have never been told
+	maxRefusals  = 5                  //nodes refused in a row this times are not selected randomly
+	refusedWait  = 24 * time.Hour     //until this duration after the last refusal
+)
+
+//Delivery is the result of telling an update to a node, saved in "delivery" bucket.
+type Delivery struct {
+	Node   string //nodestr of the node
+	Result string //one of DeliveryOK, DeliveryNG, DeliveryTimeout, DeliveryRefused, DeliveryError, DeliverySkipped
+	Stamp  int64  //unixtime when told
+}
+
+//result classifies the response res and err of telling an update.
+func result(res []string, err error) string {
+	if err == nil {
+		if len(res) > 0 && res[0] == "OK" {
+			return DeliveryOK
+		}
+		return DeliveryNG
+	}
+	if e, ok := err.(net.Error); ok && e.Timeout() {
+		return DeliveryTimeout
+	}
+	if errors.Is(err, syscall.ECONNREFUSED) {
+		return DeliveryRefused
+	}
+	return DeliveryError
+}
+
+//Score is the statistics of telling updates to a node, saved in "nodescore" bucket.
+type Score struct {
+	OK       int
+	NG       int
+	Timeout  int
+	Refused  int
+	Error    int
+	Refusals int     //# of refusals in a row
+	Rate     float64 //moving average of success, 1 if the node always accepted updates
+	Last     int64   //unixtime when told last
+}
+
+//add adds the result of d to s.
+func (s *Score) add(d *Delivery) {
+	v := 0.0
+	switch d.Result {
+	case DeliveryOK:
+		s.OK++
+		v = 1
+	case DeliveryNG:
+		s.NG++
+		v = 0.5
+	case DeliveryTimeout:
+		s.Timeout++
+	case DeliveryRefused:
+		s.Refused++
+	case DeliveryError:
+		s.Error++
+	}
+	if d.Result == DeliveryRefused {
+		s.Refusals++
+	} else {
+		s.Refusals = 0
+	}
+	s.Rate = s.Rate*(1-scoreWeight) + v*scoreWeight
+	s.Last = d.Stamp
+}
+
+//isRefused returns true if the node keeps refusing connections
+//and was tried in refusedWait.
+func (s *Score) isRefused(now time.Time) bool {
+	return s.Refusals >= maxRefusals && now.Before(time.Unix(s.Last, 0).Add(refusedWait))
+}
+
+//getScore returns the score of nodestr, or the default one if never told.
+func getScore(tx db.Tx, nodestr string) *Score {
+	s := &Score{Rate: defaultRate}
+	if _, err := db.Get(tx, "nodescore", []byte(nodestr), s); err != nil {
+		return &Score{Rate: defaultRate}
+	}
+	return s
+}
+
+//Scores returns scores of nodes ns.
+func Scores(my *myself.Myself, ns []string) map[string]*Score {
+	r := make(map[string]*Score)
+	err := my.DB.View(func(tx db.Tx) error {
+		for _, n := range ns {
+			r[n] = getScore(tx, n)
+		}
+		return nil
+	})
+	if err != nil {
+		log.Println(err)
+	}
+	return r
+}
+
+//sortByScore sorts ns by their scores, from the best one.
+func sortByScore(my *myself.Myself, ns node.Slice) {
+	ss := Scores(my, ns.GetNodestrSlice())
+	sort.SliceStable(ns, func(i, j int) bool {
+		return ss[ns[i].Nodestr].Rate > ss[ns[j].Nodestr].Rate
+	})
+}
+
+//saveDeliveries saves the results of telling the update of record id in datfile with stamp,
+//and adds them to scores of nodes except skipped ones.
+func saveDeliveries(my *myself.Myself, datfile string, stamp int64, id string, ds []*Delivery) {
+	err := my.DB.Update(func(tx db.Tx) error {
+		for _, d := range ds {
+			if err := db.Put(tx, "delivery", db.ToKey(datfile, stamp, id, d.Node), d); err != nil {
+				return err
+			}
+			if d.Result == DeliverySkipped {
+				continue
+			}
+			s := getScore(tx, d.Node)
+			s.add(d)
+			if err := db.Put(tx, "nodescore", []byte(d.Node), s); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		log.Println(err)
+	}
+}
+
+//Deliveries returns the results of telling the update of record id in datfile with stamp.
+func Deliveries(my *myself.Myself, datfile string, stamp int64, id string) []*Delivery {
+	var ds []*Delivery
+	err := my.DB.View(func(tx db.Tx) error {
+		return db.ForEachPrefix(tx, "delivery", db.ToKey(datfile, stamp, id), func(k, v []byte) error {
+			var d Delivery
+			if err := json.Unmarshal(v, &d); err != nil {
+				return err
+			}
+			ds = append(ds, &d)
+			return nil
+		})
+	})
+	if err != nil {
+		log.Println(err)
+	}
+	return ds
+}
+
+//RemoveOldDeliveries removes deliveries which were told before keepDelivery,
+//and scores of nodes which have not been told since then.
+func RemoveOldDeliveries(my *myself.Myself) {
+	old := time.Now().Add(-keepDelivery).Unix()
+	err := my.DB.Update(func(tx db.Tx) error {
+		var keys [][]byte
+		err := db.ForEach(tx, "delivery", func(k, v []byte) error {
+			var d Delivery
+			if err := json.Unmarshal(v, &d); err != nil {
+				return err
+			}
+			if d.Stamp < old {
+				keys = append(keys, append([]byte(nil), k...))
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		for _, k := range keys {
+			if err := db.Del(tx, "delivery", k); err != nil {
+				return err
+			}
+		}
+		var nodes [][]byte
+		err = db.ForEach(tx, "nodescore", func(k, v []byte) error {
+			var s Score
+			if err := json.Unmarshal(v, &s); err != nil {
+				return err
+			}
+			if s.Last < old {
+				nodes = append(nodes, append([]byte(nil), k...))
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		for _, k := range nodes {
+			if err := db.Del(tx, "nodescore", k); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		log.Println(err)
+	}
+}
@@ -31,12 +31,14 @@ package manager
 import (
 	"errors"
 	"log"
+	"math"
 	"math/rand"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
+	"time"
 
-	"github.com/boltdb/bolt"
 	"bbs/cfg"
 	"bbs/db"
 	"bbs/myself"
@@ -54,9 +56,9 @@ var list = string([]byte{0x01})
 //Manager represents the map that maps datfile to it's source node list.
 
 //getFromList returns one node  in the nodelist.
-func getFromList() *node.Node {
+func getFromList(my *myself.Myself) *node.Node {
 	var rs map[string]struct{}
-	err := db.DB.View(func(tx *bolt.Tx) error {
+	err := my.DB.View(func(tx db.Tx) error {
 		var err error
 		rs, err = db.GetMap(tx, "lookupT", []byte(list))
 		return err
@@ -83,19 +85,19 @@ func getFromList() *node.Node {
 }
 
 //NodeLen returns size of all nodes.
-func NodeLen() int {
-	ns := getAllNodes()
+func NodeLen(my *myself.Myself) int {
+	ns := getAllNodes(my)
 	return ns.Len()
 }
 
 //ListLen returns size of nodelist.
-func ListLen() int {
-	return listLen(list)
+func ListLen(my *myself.Myself) int {
+	return listLen(my, list)
 }
 
-func listLen(datfile string) int {
+func listLen(my *myself.Myself, datfile string) int {
 	var rs map[string]struct{}
-	err := db.DB.View(func(tx *bolt.Tx) error {
+	err := my.DB.View(func(tx db.Tx) error {
 		var err error
 		rs, err = db.GetMap(tx, "lookupT", []byte(datfile))
 		return err
@@ -108,14 +110,14 @@ func listLen(datfile string) int {
 }
 
 //GetNodestrSlice returns Nodestr of all nodes.
-func GetNodestrSlice() []string {
-	return getAllNodes().GetNodestrSlice()
+func GetNodestrSlice(my *myself.Myself) []string {
+	return getAllNodes(my).GetNodestrSlice()
 }
 
 //getAllNodes returns all nodes in table.
-func getAllNodes() node.Slice {
+func getAllNodes(my *myself.Myself) node.Slice {
 	var r []string
-	err := db.DB.View(func(tx *bolt.Tx) error {
+	err := my.DB.View(func(tx db.Tx) error {
 		var err error
 		r, err = db.KeyStrings(tx, "lookupA")
 		return err
@@ -129,8 +131,8 @@ func getAllNodes() node.Slice {
 
 //Get returns rawnodelist associated with datfile
 //if not found returns def
-func Get(datfile string, def node.Slice) node.Slice {
-	str := GetNodestrSliceInTable(datfile)
+func Get(my *myself.Myself, datfile string, def node.Slice) node.Slice {
+	str := GetNodestrSliceInTable(my, datfile)
 	if str == nil {
 		return def
 	}
@@ -138,14 +140,14 @@ func Get(datfile string, def node.Slice) node.Slice {
 }
 
 //GetNodestrSliceInList returns Nodestr slice of nodes in list.
-func GetNodestrSliceInList() []string {
-	return GetNodestrSliceInTable(list)
+func GetNodestrSliceInList(my *myself.Myself) []string {
+	return GetNodestrSliceInTable(my, list)
 }
 
 //GetNodestrSliceInTable returns Nodestr slice of nodes associated datfile thread.
-func GetNodestrSliceInTable(datfile string) []string {
+func GetNodestrSliceInTable(my *myself.Myself, datfile string) []string {
 	var r []string
-	err := db.DB.View(func(tx *bolt.Tx) error {
+	err := my.DB.View(func(tx db.Tx) error {
 		var err error
 		r, err = db.MapKeys(tx, "lookupT", []byte(datfile))
 		return err
@@ -157,43 +159,49 @@ func GetNodestrSliceInTable(datfile string) []string {
 	return r
 }
 
-//Random selects # of min(all # of nodes,n) nodes randomly except exclude nodes.
-func Random(exclude node.Slice, num int) []*node.Node {
-	all := getAllNodes()
-	if exclude != nil {
-		cand := make([]*node.Node, 0, len(all))
-		m := exclude.ToMap()
-		for _, n := range all {
-			if _, exist := m[n.Nodestr]; !exist {
-				cand = append(cand, n)
-			}
+//minWeight is added to Score.Rate so that nodes with rate 0 can be selected.
+const minWeight = 0.05
+
+//Random selects # of min(all # of nodes,n) nodes randomly except exclude nodes
+//and nodes which keep refusing connections.
+//nodes with higher Score.Rate are more likely to be selected.
+func Random(my *myself.Myself, exclude node.Slice, num int) []*node.Node {
+	all := getAllNodes(my)
+	m := exclude.ToMap()
+	ss := Scores(my, all.GetNodestrSlice())
+	now := time.Now()
+	cand := make([]*node.Node, 0, len(all))
+	for _, n := range all {
+		if _, exist := m[n.Nodestr]; !exist && !ss[n.Nodestr].isRefused(now) {
+			cand = append(cand, n)
 		}
-		all = cand
 	}
-	n := all.Len()
-	if num < n && num != 0 {
-		n = num
+	//weighted random sampling by Efraimidis and Spirakis.
+	keys := make(map[string]float64, len(cand))
+	for _, n := range cand {
+		keys[n.Nodestr] = math.Pow(rand.Float64(), 1/(ss[n.Nodestr].Rate+minWeight))
 	}
-	r := make([]*node.Node, n)
-	rs := rand.Perm(all.Len())
-	for i := 0; i < n; i++ {
-		r[i] = all[rs[i]]
+	sort.Slice(cand, func(i, j int) bool {
+		return keys[cand[i].Nodestr] > keys[cand[j].Nodestr]
+	})
+	if num < len(cand) && num != 0 {
+		cand = cand[:num]
 	}
-	return r
+	return cand
 }
 
-func appendable(datfile string, n *node.Node) bool {
-	l := listLen(datfile)
+func appendable(my *myself.Myself, datfile string, n *node.Node) bool {
+	l := listLen(my, datfile)
 	return ((datfile != "" && l < shareNodes) ||
 		(datfile == "" && l < defaultNodes)) &&
-		n != nil && n.IsAllowed() && !hasNodeInTable(datfile, n)
+		n != nil && n.IsAllowed(my) && !hasNodeInTable(my, datfile, n)
 
 }
 
 //AppendToTable add node n to table if it is allowd and list doesn't have it.
-func AppendToTable(datfile string, n *node.Node) {
-	err := db.DB.Update(func(tx *bolt.Tx) error {
-		AppendToTableTX(tx, datfile, n)
+func AppendToTable(my *myself.Myself, datfile string, n *node.Node) {
+	err := my.DB.Update(func(tx db.Tx) error {
+		AppendToTableTX(my, tx, datfile, n)
 		return nil
 	})
 	if err != nil {
@@ -202,8 +210,8 @@ func AppendToTable(datfile string, n *node.Node) {
 }
 
 //AppendToTableTX add node n to table if it is allowd and list doesn't have it.
-func AppendToTableTX(tx *bolt.Tx, datfile string, n *node.Node) {
-	if !appendable(datfile, n) {
+func AppendToTableTX(my *myself.Myself, tx db.Tx, datfile string, n *node.Node) {
+	if !appendable(my, datfile, n) {
 		return
 	}
 	err := db.PutMap(tx, "lookupT", []byte(datfile), n.Nodestr)
@@ -217,30 +225,30 @@ func AppendToTableTX(tx *bolt.Tx, datfile string, n *node.Node) {
 }
 
 //AppendToList add node n to nodelist if it is allowd and list doesn't have it.
-func AppendToList(n *node.Node) {
-	AppendToTable(list, n)
+func AppendToList(my *myself.Myself, n *node.Node) {
+	AppendToTable(my, list, n)
 }
 
 //appendToList add node n to nodelist if it is allowd and list doesn't have it.
-func appendToList(tx *bolt.Tx, n *node.Node) {
-	AppendToTableTX(tx, list, n)
+func appendToList(my *myself.Myself, tx db.Tx, n *node.Node) {
+	AppendToTableTX(my, tx, list, n)
 }
 
 //ReplaceNodeInList removes one node and say bye to the node and add n in nodelist.
 //if len(node)>defaultnode
-func ReplaceNodeInList(n *node.Node) *node.Node {
-	l := ListLen()
-	if !n.IsAllowed() || hasNodeInTable(list, n) {
+func ReplaceNodeInList(my *myself.Myself, n *node.Node) *node.Node {
+	l := ListLen(my)
+	if !n.IsAllowed(my) || hasNodeInTable(my, list, n) {
 		return nil
 	}
 	var old *node.Node
 	if l >= defaultNodes {
-		old = getFromList()
-		RemoveFromList(old)
-		old.Bye()
+		old = getFromList(my)
+		RemoveFromList(my, old)
+		old.Bye(my)
 	}
-	err := db.DB.Update(func(tx *bolt.Tx) error {
-		appendToList(tx, n)
+	err := my.DB.Update(func(tx db.Tx) error {
+		appendToList(my, tx, n)
 		return nil
 	})
 	if err != nil {
@@ -250,9 +258,9 @@ func ReplaceNodeInList(n *node.Node) *node.Node {
 }
 
 //hasNodeInTable returns true if nodelist has n.
-func hasNodeInTable(datfile string, n *node.Node) bool {
+func hasNodeInTable(my *myself.Myself, datfile string, n *node.Node) bool {
 	var r bool
-	err := db.DB.View(func(tx *bolt.Tx) error {
+	err := my.DB.View(func(tx db.Tx) error {
 		r = db.HasVal(tx, "lookupT", []byte(datfile), n.Nodestr)
 		return nil
 	})
@@ -264,7 +272,7 @@ func hasNodeInTable(datfile string, n *node.Node) bool {
 
 //removeFromTable removes node n and return true if exists.
 //or returns false if not exists.
-func removeFromTable(tx *bolt.Tx, datfile string, n *node.Node) error {
+func removeFromTable(tx db.Tx, datfile string, n *node.Node) error {
 	if n == nil {
 		err := errors.New("n is nil")
 		log.Println(err)
@@ -282,8 +290,8 @@ func removeFromTable(tx *bolt.Tx, datfile string, n *node.Node) error {
 
 //RemoveFromTable removes node n and return true if exists.
 //or returns false if not exists.
-func RemoveFromTable(datfile string, n *node.Node) bool {
-	err := db.DB.Update(func(tx *bolt.Tx) error {
+func RemoveFromTable(my *myself.Myself, datfile string, n *node.Node) bool {
+	err := my.DB.Update(func(tx db.Tx) error {
 		return removeFromTable(tx, datfile, n)
 	})
 	if err != nil {
@@ -295,14 +303,14 @@ func RemoveFromTable(datfile string, n *node.Node) bool {
 
 //RemoveFromList removes node n from nodelist and return true if exists.
 //or returns false if not exists.
-func RemoveFromList(n *node.Node) bool {
-	return RemoveFromTable(list, n)
+func RemoveFromList(my *myself.Myself, n *node.Node) bool {
+	return RemoveFromTable(my, list, n)
 }
 
 //RemoveFromAllTable removes node n from all tables and return true if exists.
 //or returns false if not exists.
-func RemoveFromAllTable(n *node.Node) bool {
-	err := db.DB.Update(func(tx *bolt.Tx) error {
+func RemoveFromAllTable(my *myself.Myself, n *node.Node) bool {
+	err := my.DB.Update(func(tx db.Tx) error {
 		threads, err := db.GetMap(tx, "lookupA", []byte(n.Nodestr))
 		if err != nil {
 			return err
@@ -328,7 +336,7 @@ func RemoveFromAllTable(n *node.Node) bool {
 
 //Initialize pings one of initNode except myself and added it if success,
 //and get another node info from each nodes in nodelist.
-func Initialize(allnodes node.Slice) {
+func Initialize(my *myself.Myself, allnodes node.Slice) {
 	inodes := allnodes
 	if len(allnodes) > defaultNodes {
 		inodes = inodes[:defaultNodes]
@@ -336,30 +344,30 @@ func Initialize(allnodes node.Slice) {
 	var wg sync.WaitGroup
 	var mutex sync.Mutex
 	port0 := true
-	for i := 0; i < len(inodes) && ListLen() < defaultNodes; i++ {
+	for i := 0; i < len(inodes) && ListLen(my) < defaultNodes; i++ {
 		wg.Add(1)
 		go func(inode *node.Node) {
-			if _, err := inode.Ping(); err != nil {
+			if _, err := inode.Ping(my); err != nil {
 				wg.Done()
 				return
 			}
 			go func(inode *node.Node) {
-				if Join(inode) {
+				if Join(my, inode) {
 					mutex.Lock()
 					port0 = false
 					mutex.Unlock()
 				}
-				AppendToList(inode)
+				AppendToList(my, inode)
 				wg.Done()
 			}(inode)
 		}(inodes[i])
 	}
 	wg.Wait()
 
-	log.Println("# of nodelist:", ListLen())
+	log.Println("# of nodelist:", ListLen(my))
 	if port0 {
 		log.Println("port0")
-		myself.SetStatus(cfg.Port0)
+		my.SetStatus(cfg.Port0)
 	} else {
 		log.Println("opened")
 	}
@@ -368,22 +376,22 @@ func Initialize(allnodes node.Slice) {
 //Join tells n to join and adds n to nodelist if welcomed.
 //if n returns another nodes, repeats it and return true..
 //removes fron nodelist if not welcomed and return false.
-func Join(n *node.Node) bool {
+func Join(my *myself.Myself, n *node.Node) bool {
 	const retryJoin = 2 // Times; Join network
 	if n == nil {
 		return false
 	}
-	if hasNodeInTable(list, n) || node.Me(false).Nodestr == n.Nodestr {
+	if hasNodeInTable(my, list, n) || node.Me(my, false).Nodestr == n.Nodestr {
 		return false
 	}
 	flag := false
-	for count := 0; count < retryJoin && ListLen() < defaultNodes; count++ {
-		extnode, err := n.Join()
+	for count := 0; count < retryJoin && ListLen(my) < defaultNodes; count++ {
+		extnode, err := n.Join(my)
 		if err != nil {
-			RemoveFromTable(list, n)
+			RemoveFromTable(my, list, n)
 			return false
 		}
-		AppendToList(n)
+		AppendToList(my, n)
 		flag = true
 		if extnode == nil {
 			return true
@@ -395,36 +403,78 @@ func Join(n *node.Node) bool {
 
 //TellUpdate makes mynode info from node or dnsname or ip addr,
 //and broadcast the updates of record id=id in cache c.datfile with stamp.
-func TellUpdate(datfile string, stamp int64, id string, n *node.Node) {
+//nodes are told in order of their scores by UpdateWorkers workers at once until UpdateDeadline,
+//and results for them are saved and returned. nodes not told until the deadline are DeliverySkipped
+//and don't affect their scores.
+func TellUpdate(my *myself.Myself, datfile string, stamp int64, id string, n *node.Node) []*Delivery {
 	const updateNodes = 10
 
-	tellstr := node.Me(true).Toxstring()
+	tellstr := node.Me(my, true).Toxstring()
 	if n != nil {
 		tellstr = n.Toxstring()
 	}
 	msg := strings.Join([]string{"/update", datfile, strconv.FormatInt(stamp, 10), id, tellstr}, "/")
 
-	ns := Get(datfile, nil)
-	ns = ns.Extend(Get(list, nil))
-	ns = ns.Extend(Random(ns, updateNodes))
+	ns := Get(my, datfile, nil)
+	ns = ns.Extend(Get(my, list, nil))
+	ns = ns.Extend(Random(my, ns, updateNodes))
+	sortByScore(my, ns)
 	log.Println("telling #", len(ns))
-	for _, n := range ns {
-		_, err := n.Talk(msg, nil)
-		if err != nil {
-			log.Println(err)
-		}
+
+	deadline := time.Now().Add(time.Duration(my.Cfg.UpdateDeadline) * time.Second)
+	ds := make([]*Delivery, len(ns))
+	ch := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < my.Cfg.UpdateWorkers || w == 0; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range ch {
+				ds[i] = tell(my, ns[i], msg, deadline)
+			}
+		}()
+	}
+	for i := range ns {
+		ch <- i
+	}
+	close(ch)
+	wg.Wait()
+	saveDeliveries(my, datfile, stamp, id, ds)
+	return ds
+}
+
+//tell sends msg to n until deadline and returns the result.
+func tell(my *myself.Myself, n *node.Node, msg string, deadline time.Time) *Delivery {
+	const tellTimeout = 15 * time.Second
+	d := &Delivery{
+		Node:   n.Nodestr,
+		Result: DeliverySkipped,
+		Stamp:  time.Now().Unix(),
+	}
+	timeout := time.Until(deadline)
+	if timeout <= 0 {
+		return d
+	}
+	if timeout > tellTimeout {
+		timeout = tellTimeout
+	}
+	res, err := n.TalkTimeout(my, msg, timeout, nil)
+	if err != nil {
+		log.Println(err)
 	}
+	d.Result = result(res, err)
+	return d
 }
 
 //NodesForGet returns nodes which has datfile cache , and that extends nodes to #searchDepth .
-func NodesForGet(datfile string, searchDepth int) node.Slice {
+func NodesForGet(my *myself.Myself, datfile string, searchDepth int) node.Slice {
 	var ns, ns2 node.Slice
-	ns = ns.Extend(Get(datfile, nil))
-	ns = ns.Extend(Get(list, nil))
-	ns = ns.Extend(Random(ns, 0))
+	ns = ns.Extend(Get(my, datfile, nil))
+	ns = ns.Extend(Get(my, list, nil))
+	ns = ns.Extend(Random(my, ns, 0))
 
 	for _, n := range ns {
-		if !n.Equals(node.Me(true)) && n.IsAllowed() {
+		if !n.Equals(node.Me(my, true)) && n.IsAllowed(my) {
 			ns2 = append(ns2, n)
 		}
 	}
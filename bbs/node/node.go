@@ -82,7 +82,7 @@ func New(nodestr string) (*Node, error) {
 }
 
 //urlopen retrievs html data from url
-func (n *Node) urlopen(url string, timeout time.Duration, fn func(string) error) error {
+func (n *Node) urlopen(my *myself.Myself, url string, timeout time.Duration, fn func(string) error) error {
 	ua := "shinGETsuPlus/1.0alpha (Gou/" + cfg.Version + ")"
 
 	req, err := http.NewRequest("GET", url, nil)
@@ -94,7 +94,7 @@ func (n *Node) urlopen(url string, timeout time.Duration, fn func(string) error)
 
 	transport := http.Transport{
 		Dial: func(network, addr string) (net.Conn, error) {
-			con, errr := net.DialTimeout(network, addr, timeout)
+			con, errr := my.Dial(network, addr, timeout)
 			if errr != nil {
 				return nil, errr
 			}
@@ -138,9 +138,14 @@ func (n *Node) Toxstring() string {
 	return strings.Replace(n.Nodestr, "/", "+", -1)
 }
 
-//Talk talks with n with the message and returns data.
-func (n *Node) Talk(message string, fn func(string) error) ([]string, error) {
+//Talk talks with n from my with the message and returns data.
+func (n *Node) Talk(my *myself.Myself, message string, fn func(string) error) ([]string, error) {
 	const defaultTimeout = 15 * time.Second // Seconds; Timeout for TCP
+	return n.TalkTimeout(my, message, defaultTimeout, fn)
+}
+
+//TalkTimeout is same as Talk but gives up talking after timeout.
+func (n *Node) TalkTimeout(my *myself.Myself, message string, timeout time.Duration, fn func(string) error) ([]string, error) {
 	var res []string
 	if fn == nil {
 		fn = func(line string) error {
@@ -160,7 +165,7 @@ func (n *Node) Talk(message string, fn func(string) error) ([]string, error) {
 	msg := "http://" + n.Nodestr + message
 
 	log.Println("Talk:", msg)
-	err := n.urlopen(msg, defaultTimeout, fn)
+	err := n.urlopen(my, msg, timeout, fn)
 	if err != nil {
 		log.Println(msg, err)
 	}
@@ -168,25 +173,26 @@ func (n *Node) Talk(message string, fn func(string) error) ([]string, error) {
 }
 
 //Ping pings to n and return response.
-func (n *Node) Ping() (string, error) {
-	res, err := n.Talk("/ping", nil)
+//my IP is set from the response.
+func (n *Node) Ping(my *myself.Myself) (string, error) {
+	res, err := n.Talk(my, "/ping", nil)
 	if err != nil {
 		log.Println("/ping", n.Nodestr, err)
 		return "", err
 	}
 	if len(res) == 2 && res[0] == "PONG" {
 		log.Println("ponged,i am", res[1])
-		myself.SetIP(res[1])
+		my.SetIP(res[1])
 		return res[1], nil
 	}
 	log.Println("/ping", n.Nodestr, "error")
 	return "", errors.New("connected,but not ponged")
 }
 
-//IsAllowed returns fase if n is not allowed and denied.
-func (n *Node) IsAllowed() bool {
-	nodeAllow := util.NewRegexpList(cfg.NodeAllowFile)
-	nodeDeny := util.NewRegexpList(cfg.NodeDenyFile)
+//IsAllowed returns fase if n is not allowed and denied by my config.
+func (n *Node) IsAllowed(my *myself.Myself) bool {
+	nodeAllow := util.NewRegexpList(my.Cfg.NodeAllowFile)
+	nodeDeny := util.NewRegexpList(my.Cfg.NodeDenyFile)
 
 	if !nodeAllow.Check(n.Nodestr) && nodeDeny.Check(n.Nodestr) {
 		return false
@@ -195,12 +201,12 @@ func (n *Node) IsAllowed() bool {
 }
 
 //Join requests n to Join me and return true and other node name if success.
-func (n *Node) Join() (*Node, error) {
-	if !n.IsAllowed() {
+func (n *Node) Join(my *myself.Myself) (*Node, error) {
+	if !n.IsAllowed(my) {
 		err := errors.New(fmt.Sprintln(n.Nodestr, "is not allowd"))
 		return nil, err
 	}
-	res, err := n.Talk("/join/"+Me(true).Toxstring(), nil)
+	res, err := n.Talk(my, "/join/"+Me(my, true).Toxstring(), nil)
 	if err != nil {
 		return nil, err
 	}
@@ -226,8 +232,8 @@ func (n *Node) Join() (*Node, error) {
 }
 
 //getNode requests n to pass me another node info and returns another node.
-func (n *Node) getNode() (*Node, error) {
-	res, err := n.Talk("/node", nil)
+func (n *Node) getNode(my *myself.Myself) (*Node, error) {
+	res, err := n.Talk(my, "/node", nil)
 	if err != nil {
 		err := errors.New(fmt.Sprintln("/node", n.Nodestr, "error"))
 		return nil, err
@@ -239,8 +245,8 @@ func (n *Node) getNode() (*Node, error) {
 }
 
 //Bye says goodBye to n and returns true if success.
-func (n *Node) Bye() bool {
-	res, err := n.Talk("/bye/"+Me(true).Toxstring(), nil)
+func (n *Node) Bye(my *myself.Myself) bool {
+	res, err := n.Talk(my, "/bye/"+Me(my, true).Toxstring(), nil)
 	if err != nil {
 		log.Println("/bye", n.Nodestr, "error")
 		return false
@@ -249,7 +255,7 @@ func (n *Node) Bye() bool {
 }
 
 //GetherNodes gethers nodes from n.
-func (n *Node) GetherNodes() []*Node {
+func (n *Node) GetherNodes(my *myself.Myself) []*Node {
 	ns := map[string]*Node{
 		n.Nodestr: n,
 	}
@@ -260,7 +266,7 @@ func (n *Node) GetherNodes() []*Node {
 			wg.Add(1)
 			go func(nn *Node) {
 				defer wg.Done()
-				NewN, err := nn.getNode()
+				NewN, err := nn.getNode(my)
 				if err != nil {
 					log.Println(err)
 					return
@@ -349,12 +355,12 @@ func (ns Slice) Extend(a Slice) Slice {
 	return nn.Uniq()
 }
 
-// Me converts myself to *Node.
-func Me(servernameIfExist bool) *Node {
-	ip, port := myself.GetIPPort()
+// Me converts my to *Node.
+func Me(my *myself.Myself, servernameIfExist bool) *Node {
+	ip, port := my.GetIPPort()
 	var serverName string
 	if servernameIfExist {
-		serverName = cfg.ServerName
+		serverName = my.Cfg.ServerName
 	}
 	if serverName == "" {
 		serverName = ip
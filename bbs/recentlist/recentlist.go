@@ -29,6 +29,7 @@
 package recentlist
 
 import (
+	"encoding/binary"
 	"errors"
 	"log"
 	"strconv"
@@ -38,9 +39,8 @@ import (
 
 	"encoding/json"
 
-	"github.com/boltdb/bolt"
-	"bbs/cfg"
 	"bbs/db"
+	"bbs/myself"
 	"bbs/node"
 	"bbs/node/manager"
 	"bbs/record"
@@ -61,25 +61,39 @@ func IsInUpdateRange(nstamp int64) bool {
 //RecentList represents records list udpated by remote host and
 //gotten by /gateway.cgi/Recent
 
-//Datfiles returns all datfile names in recentlist.
-func Datfiles() []string {
-	var datfile []string
-	err := db.DB.View(func(tx *bolt.Tx) error {
-		var err error
-		datfile, err = db.GetPrefixs(tx, "recent")
-		return err
+//Datfiles returns datfile names in recentlist which have records whose stamps are
+//begin or after, in order of the newest stamp.
+func Datfiles(my *myself.Myself, begin int64) []string {
+	var datfiles []string
+	has := make(map[string]bool)
+	err := my.DB.View(func(tx db.Tx) error {
+		c := tx.Cursor("recentStamp")
+		for k, _ := c.Last(); k != nil; k, _ = c.Prev() {
+			h := headFromStampKey(k)
+			if h == nil {
+				continue
+			}
+			if h.Stamp < begin {
+				break
+			}
+			if !has[h.Datfile] {
+				has[h.Datfile] = true
+				datfiles = append(datfiles, h.Datfile)
+			}
+		}
+		return nil
 	})
 	if err != nil {
 		log.Print(err)
 	}
-	return datfile
+	return datfiles
 }
 
 //Newest returns newest record of datfile in the list.
 //if not found returns nil.
-func Newest(datfile string) (*record.Head, error) {
+func Newest(my *myself.Myself, datfile string) (*record.Head, error) {
 	var rows []string
-	err := db.DB.View(func(tx *bolt.Tx) error {
+	err := my.DB.View(func(tx db.Tx) error {
 		var err error
 		rows, err = db.GetStrings(tx, "recent", []byte(datfile))
 		return err
@@ -101,20 +115,23 @@ func Newest(datfile string) (*record.Head, error) {
 }
 
 //appendHead add a infos generated from the record.
-func appendHead(tx *bolt.Tx, rec *record.Head) {
-	if find(rec) {
+func appendHead(tx db.Tx, rec *record.Head) {
+	k := rec.ToKey()
+	if has, _ := db.HasKey(tx, "recent", k); has {
 		return
 	}
-	k := rec.ToKey()
 	err := db.Put(tx, "recent", k, rec)
 	if err != nil {
 		log.Print(err)
 	}
+	if err := db.Put(tx, "recentStamp", StampKey(rec), []byte{}); err != nil {
+		log.Print(err)
+	}
 }
 
 //Append add a infos generated from the record.
-func Append(rec *record.Head) {
-	err := db.DB.Update(func(tx *bolt.Tx) error {
+func Append(my *myself.Myself, rec *record.Head) {
+	err := my.DB.Update(func(tx db.Tx) error {
 		appendHead(tx, rec)
 		return nil
 	})
@@ -123,43 +140,26 @@ func Append(rec *record.Head) {
 	}
 }
 
-//find finds records and returns index. returns -1 if not found.
-func find(rec *record.Head) bool {
-	k := rec.ToKey()
-	var r int
-	err := db.DB.View(func(tx *bolt.Tx) error {
-		var err error
-		r, err = db.Count(tx, "recent", k)
-		return err
-	})
-	if err != nil {
-		log.Print(err)
-	}
-
-	return r > 0
-}
-
 //RemoveOlds remove old records..
-func RemoveOlds() {
-	if cfg.RecentRange <= 0 {
+func RemoveOlds(my *myself.Myself) {
+	if my.Cfg.RecentRange <= 0 {
 		return
 	}
-	t := time.Now().Unix() - cfg.RecentRange
-	err := db.DB.Update(func(tx *bolt.Tx) error {
-		ba := tx.Bucket([]byte("recent"))
-		if ba == nil {
-			return errors.New("bucket is not found")
+	t := time.Now().Unix() - my.Cfg.RecentRange
+	err := my.DB.Update(func(tx db.Tx) error {
+		var olds []*record.Head
+		err := eachStamp(tx, 0, t, func(h *record.Head) error {
+			olds = append(olds, h)
+			return nil
+		})
+		if err != nil {
+			return err
 		}
-		c := ba.Cursor()
-		for k, v := c.First(); k != nil; k, v = c.Next() {
-			var m record.Head
-			if err := json.Unmarshal(v, &m); err != nil {
-				return err
-			}
-			if m.Stamp > t {
-				continue
+		for _, h := range olds {
+			if err := db.Del(tx, "recent", h.ToKey()); err != nil {
+				log.Println(err)
 			}
-			if err := c.Delete(); err != nil {
+			if err := db.Del(tx, "recentStamp", StampKey(h)); err != nil {
 				log.Println(err)
 			}
 		}
@@ -174,36 +174,36 @@ func RemoveOlds() {
 //tags are shuffled and truncated to tagsize and stored to sugtags in cache.
 //also source nodes are stored into lookuptable.
 //also tags which Recentlist doen't have in sugtagtable are truncated
-func Getall(all bool) {
+func Getall(my *myself.Myself, all bool) {
 	const searchNodes = 100
 
 	var begin int64
-	if cfg.RecentRange > 0 && !all {
-		begin = time.Now().Unix() - cfg.RecentRange
+	if my.Cfg.RecentRange > 0 && !all {
+		begin = time.Now().Unix() - my.Cfg.RecentRange
 	}
-	nodes := manager.Random(nil, searchNodes)
+	nodes := manager.Random(my, nil, searchNodes)
 	var wg sync.WaitGroup
 	for _, n := range nodes {
 		wg.Add(1)
-		go get(begin, &wg, n)
+		go get(my, begin, &wg, n)
 	}
 	wg.Wait()
-	suggest.Prune(GetRecords())
+	suggest.Prune(my, GetRecords(my))
 }
 
-func get(begin int64, wg *sync.WaitGroup, n *node.Node) {
+func get(my *myself.Myself, begin int64, wg *sync.WaitGroup, n *node.Node) {
 	defer wg.Done()
 	var res []string
 	var err error
-	res, err = n.Talk("/recent/"+strconv.FormatInt(begin, 10)+"-", nil)
+	res, err = n.Talk(my, "/recent/"+strconv.FormatInt(begin, 10)+"-", nil)
 	if err != nil {
-		manager.RemoveFromAllTable(n)
+		manager.RemoveFromAllTable(my, n)
 		log.Println(err)
 		return
 	}
-	err = db.DB.Update(func(tx *bolt.Tx) error {
+	err = my.DB.Update(func(tx db.Tx) error {
 		for _, line := range res {
-			rec, errr := record.Make(line)
+			rec, errr := record.Make(my, line)
 			if errr != nil {
 				continue
 			}
@@ -211,7 +211,7 @@ func get(begin int64, wg *sync.WaitGroup, n *node.Node) {
 			tags := strings.Fields(strings.TrimSpace(rec.GetBodyValue("tag", "")))
 			if len(tags) > 0 {
 				suggest.AddString(tx, rec.Datfile, tags)
-				manager.AppendToTableTX(tx, rec.Datfile, n)
+				manager.AppendToTableTX(my, tx, rec.Datfile, n)
 			}
 		}
 		return nil
@@ -223,15 +223,14 @@ func get(begin int64, wg *sync.WaitGroup, n *node.Node) {
 }
 
 //GetRecords copies and returns recorcds in recentlist.
-func GetRecords() []*record.Head {
+func GetRecords(my *myself.Myself) []*record.Head {
 	var inf []*record.Head
 
-	err := db.DB.View(func(tx *bolt.Tx) error {
-		b := tx.Bucket([]byte("recent"))
-		if b == nil {
+	err := my.DB.View(func(tx db.Tx) error {
+		if !tx.HasBucket("recent") {
 			return errors.New("bucket is not found")
 		}
-		errr := b.ForEach(func(k, v []byte) error {
+		errr := db.ForEach(tx, "recent", func(k, v []byte) error {
 			r := record.Head{}
 			if err := json.Unmarshal(v, &r); err != nil {
 				return err
@@ -247,3 +246,82 @@ func GetRecords() []*record.Head {
 	}
 	return inf
 }
+
+//Range returns heads in recentlist whose stamps are from begin to end in order of stamp.
+func Range(my *myself.Myself, begin, end int64) []*record.Head {
+	var hs []*record.Head
+	err := my.DB.View(func(tx db.Tx) error {
+		return eachStamp(tx, begin, end, func(h *record.Head) error {
+			hs = append(hs, h)
+			return nil
+		})
+	})
+	if err != nil {
+		log.Print(err)
+		return nil
+	}
+	return hs
+}
+
+//eachStamp calls f with heads whose stamps are from begin to end in order of stamp
+//by seeking "recentStamp" bucket.
+func eachStamp(tx db.Tx, begin, end int64, f func(*record.Head) error) error {
+	c := tx.Cursor("recentStamp")
+	for k, _ := c.Seek(db.MustTob(begin)); k != nil; k, _ = c.Next() {
+		h := headFromStampKey(k)
+		if h == nil {
+			continue
+		}
+		if h.Stamp > end {
+			break
+		}
+		if err := f(h); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+//StampKey returns the key of h in "recentStamp" bucket, which is ordered by stamp.
+func StampKey(h *record.Head) []byte {
+	return db.ToKey(h.Stamp, h.Datfile, h.ID)
+}
+
+//headFromStampKey returns the head from the key in "recentStamp" bucket.
+//it returns nil if k is broken.
+func headFromStampKey(k []byte) *record.Head {
+	if len(k) < 8 {
+		return nil
+	}
+	ss := strings.Split(string(k[8:]), "\x00")
+	if len(ss) != 3 || ss[0] == "" || ss[1] == "" || ss[2] != "" {
+		return nil
+	}
+	stamp := int64(binary.BigEndian.Uint64(k[:8]))
+	return &record.Head{Datfile: ss[0], Stamp: stamp, ID: ss[1]}
+}
+
+func init() {
+	db.RegisterMigration(&db.Migration{
+		Version: 5,
+		Name:    "stamp index of recentlist",
+		Migrate: RebuildStampIndexTX,
+	})
+}
+
+//RebuildStampIndexTX rebuilds "recentStamp" bucket from "recent" bucket.
+func RebuildStampIndexTX(tx db.Tx) error {
+	if tx.HasBucket("recentStamp") {
+		if err := tx.DeleteBucket("recentStamp"); err != nil {
+			return err
+		}
+	}
+	return db.ForEach(tx, "recent", func(k, v []byte) error {
+		var h record.Head
+		if err := json.Unmarshal(v, &h); err != nil {
+			log.Println(err)
+			return nil
+		}
+		return db.Put(tx, "recentStamp", StampKey(&h), []byte{})
+	})
+}
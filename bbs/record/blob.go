@@ -0,0 +1,185 @@
+/*
+ * Copyright (c) 2015, Shinya Yagyu
+ * All rights reserved.
+ * Redistribution and use in source and binary forms, with or without
+ * modification, are permitted provided that the following conditions are met:
+ *
+ * 1. Redistributions of source code must retain the above copyright notice,
+ *    this list of conditions and the following disclaimer.
+ * 2. Redistributions in binary form must reproduce the above copyright notice,
+ *    this list of conditions and the following disclaimer in the documentation
+ *    and/or other materials provided with the distribution.
+ * 3. Neither the name of the copyright holder nor the names of its
+ *    contributors may be used to endorse or promote products derived from this
+ *    software without specific prior written permission.
+ *
+ * THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+ * AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+ * IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+ * ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+ * LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+ * CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+ * SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+ * INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+ * CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+ * ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+ * POSSIBILITY OF SUCH DAMAGE.
+ */
+package record
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"strings"
+
+	"bbs/db"
+)
+
+func init() {
+	db.RegisterMigration(&db.Migration{
+		Version: 4,
+		Name:    "attached files to blob store",
+		Migrate: migrateBlobs,
+	})
+}
+
+//splitAttach moves the base64 attached file in d.Body to "blob" bucket, keyed by sha256 digest of the file,
+//and leaves "attach:" in d.Body.
+//it does nothing if the attach is not canonical base64, because the record must be rebuilt as it was.
+func splitAttach(tx db.Tx, d *DB) error {
+	if d.Attach != "" {
+		return nil
+	}
+	kvs := strings.Split(d.Body, "<>")
+	for i, kv := range kvs {
+		if !strings.HasPrefix(kv, "attach:") {
+			continue
+		}
+		at := kv[len("attach:"):]
+		data, err := base64.StdEncoding.DecodeString(at)
+		if at == "" || err != nil || base64.StdEncoding.EncodeToString(data) != at {
+			return nil
+		}
+		sum := sha256.Sum256(data)
+		hash := hex.EncodeToString(sum[:])
+		if has, _ := db.HasKey(tx, "blob", []byte(hash)); !has {
+			if err := db.Put(tx, "blob", []byte(hash), data); err != nil {
+				return err
+			}
+		}
+		kvs[i] = "attach:"
+		d.Body = strings.Join(kvs, "<>")
+		d.Attach = hash
+		d.AttachLen = len(at)
+		return nil
+	}
+	return nil
+}
+
+//getBlob returns the base64 encoded attached file whose digest is hash.
+func getBlob(tx db.Tx, hash string) (string, error) {
+	if !tx.HasBucket("blob") {
+		return "", errors.New("bucket not found blob")
+	}
+	v := tx.Get("blob", []byte(hash))
+	if v == nil {
+		return "", errors.New("blob not found " + hash)
+	}
+	return base64.StdEncoding.EncodeToString(v), nil
+}
+
+//refBlob adds n to reference count of the blob, and deletes it if no records refer it.
+func refBlob(tx db.Tx, hash string, n int) error {
+	if hash == "" {
+		return nil
+	}
+	var cnt int
+	if _, err := db.Get(tx, "blobref", []byte(hash), &cnt); err != nil {
+		cnt = 0
+	}
+	cnt += n
+	if cnt > 0 {
+		return db.Put(tx, "blobref", []byte(hash), cnt)
+	}
+	if err := db.Del(tx, "blobref", []byte(hash)); err != nil {
+		return err
+	}
+	return db.Del(tx, "blob", []byte(hash))
+}
+
+//updateBlobTX splits the attach in d and updates reference counts of blobs when old record is replaced by d.
+//old or d is nil when the record is added or deleted.
+func updateBlobTX(tx db.Tx, old, d *DB) error {
+	var oldHash, hash string
+	if old != nil {
+		oldHash = old.Attach
+	}
+	if d != nil {
+		hash = d.Attach
+	}
+	if oldHash == hash {
+		return nil
+	}
+	if err := refBlob(tx, hash, 1); err != nil {
+		return err
+	}
+	return refBlob(tx, oldHash, -1)
+}
+
+//BodyTX returns the body with the attached file.
+func (d *DB) BodyTX(tx db.Tx) (string, error) {
+	if d.Attach == "" {
+		return d.Body, nil
+	}
+	at, err := getBlob(tx, d.Attach)
+	if err != nil {
+		return "", err
+	}
+	kvs := strings.Split(d.Body, "<>")
+	for i, kv := range kvs {
+		if kv == "attach:" {
+			kvs[i] += at
+			break
+		}
+	}
+	return strings.Join(kvs, "<>"), nil
+}
+
+//migrateBlobs moves attached files in all records to blob store.
+func migrateBlobs(tx db.Tx) error {
+	if !tx.HasBucket("record") {
+		return nil
+	}
+	var heads []*Head
+	err := ForEach(tx, func(d *DB) error {
+		if d.Attach == "" && strings.Contains(d.Body, "attach:") {
+			h := *d.Head
+			heads = append(heads, &h)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	for _, h := range heads {
+		d, err := GetFromDB(tx, h)
+		if err != nil {
+			return err
+		}
+		if err := splitAttach(tx, d); err != nil {
+			return err
+		}
+		if d.Attach == "" {
+			continue
+		}
+		if err := db.Put(tx, "record", h.ToKey(), d); err != nil {
+			return err
+		}
+		if err := refBlob(tx, d.Attach, 1); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -29,14 +29,13 @@
 package record
 
 import (
-	"crypto/md5"
 	"errors"
 	"fmt"
 	"log"
 	"strconv"
 	"strings"
 
-	"github.com/boltdb/bolt"
+	"bbs/cfg"
 	"bbs/db"
 )
 
@@ -52,42 +51,19 @@ func (u *Head) ToKey() []byte {
 	return db.ToKey(u.Datfile, u.Stamp, u.ID)
 }
 
-//Exists return true if record file exists.
-func (u *Head) Exists() bool {
-	var r bool
-	err := db.DB.View(func(tx *bolt.Tx) error {
-		var err error
-		r, err = db.HasKey(tx, "record", u.ToKey())
-		return err
-	})
-	if err != nil {
-		log.Print(err)
-		return false
-	}
-	return r
-}
-
-//Remove moves the record file  to remove path
-func (u *Head) Remove() error {
-	var d *DB
-	err := db.DB.Update(func(tx *bolt.Tx) error {
-		var err error
-		d, err = GetFromDB(tx, u)
-		if err != nil {
-			return err
-		}
-		d.Deleted = true
-		return d.Put(tx)
-	})
+//RemoveTX marks the record as deleted within tx.
+func (u *Head) RemoveTX(tx db.Tx) error {
+	d, err := GetFromDB(tx, u)
 	if err != nil {
-		log.Print(err)
+		return err
 	}
-	return err
+	d.Deleted = true
+	return d.Put(tx)
 }
 
-//Hash returns md5 of Head.
-func (u *Head) Hash() [16]byte {
-	return md5.Sum([]byte(u.Recstr()))
+//Hash returns digest of Head by cache_hash_method in c.
+func (u *Head) Hash(c *cfg.Config) string {
+	return hasher(c).Digest(u.Recstr())
 }
 
 //Recstr returns one line of update/recentlist file.
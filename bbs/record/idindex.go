@@ -0,0 +1,148 @@
+/*
+ * Copyright (c) 2015, Shinya Yagyu
+ * All rights reserved.
+ * Redistribution and use in source and binary forms, with or without
+ * modification, are permitted provided that the following conditions are met:
+ *
+ * 1. Redistributions of source code must retain the above copyright notice,
+ *    this list of conditions and the following disclaimer.
+ * 2. Redistributions in binary form must reproduce the above copyright notice,
+ *    this list of conditions and the following disclaimer in the documentation
+ *    and/or other materials provided with the distribution.
+ * 3. Neither the name of the copyright holder nor the names of its
+ *    contributors may be used to endorse or promote products derived from this
+ *    software without specific prior written permission.
+ *
+ * THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+ * AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+ * IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+ * ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+ * LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+ * CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+ * SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+ * INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+ * CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+ * ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+ * POSSIBILITY OF SUCH DAMAGE.
+ */
+
+package record
+
+import (
+	"encoding/json"
+	"sort"
+
+	"bbs/db"
+	"bbs/myself"
+)
+
+//id8Len is the length of ids in anchors (>>id[:8]).
+const id8Len = 8
+
+func init() {
+	db.RegisterMigration(&db.Migration{
+		Version: 6,
+		Name:    "id index for anchors",
+		Migrate: RebuildIDIndexTX,
+	})
+}
+
+//IDIndexKeys returns keys of "idindex" bucket for the record d,
+//which are datfile+id[:8]+stamp+id for its id and legacy id.
+func IDIndexKeys(d *DB) [][]byte {
+	keys := [][]byte{db.ToKey(d.Datfile, id8(d.ID), d.Stamp, d.ID)}
+	if d.LegacyID != "" && id8(d.LegacyID) != id8(d.ID) {
+		keys = append(keys, db.ToKey(d.Datfile, id8(d.LegacyID), d.Stamp, d.ID))
+	}
+	return keys
+}
+
+//id8 returns the first 8 characters of id.
+func id8(id string) string {
+	if len(id) > id8Len {
+		return id[:id8Len]
+	}
+	return id
+}
+
+//updateIDIndexTX updates the id index when old record is replaced by d.
+//old or d is nil when the record is added or deleted.
+func updateIDIndexTX(tx db.Tx, old, d *DB) error {
+	if old != nil && d != nil && old.LegacyID == d.LegacyID {
+		return nil
+	}
+	if old != nil {
+		for _, k := range IDIndexKeys(old) {
+			if err := db.Del(tx, "idindex", k); err != nil {
+				return err
+			}
+		}
+	}
+	if d == nil {
+		return nil
+	}
+	for _, k := range IDIndexKeys(d) {
+		if err := db.Put(tx, "idindex", k, d.Head); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+//RebuildIDIndexTX rebuilds the id index from all records.
+func RebuildIDIndexTX(tx db.Tx) error {
+	if tx.HasBucket("idindex") {
+		if err := tx.DeleteBucket("idindex"); err != nil {
+			return err
+		}
+	}
+	if !tx.HasBucket("record") {
+		return nil
+	}
+	return ForEach(tx, func(d *DB) error {
+		return updateIDIndexTX(tx, nil, d)
+	})
+}
+
+//AnchorsTX returns alive records in datfile whose id or legacy id starts with one of id8s,
+//sorted by stamp. bodies are parsed with attached files.
+func AnchorsTX(my *myself.Myself, tx db.Tx, datfile string, id8s []string) ([]*Record, error) {
+	if !tx.HasBucket("idindex") {
+		return nil, nil
+	}
+	found := make(map[string]bool)
+	var rs []*Record
+	for _, id := range id8s {
+		var hs []*Head
+		err := db.ForEachPrefix(tx, "idindex", db.ToKey(datfile, id), func(k, v []byte) error {
+			var h Head
+			if err := json.Unmarshal(v, &h); err != nil {
+				return err
+			}
+			hs = append(hs, &h)
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, h := range hs {
+			if found[h.Idstr()] {
+				continue
+			}
+			found[h.Idstr()] = true
+			d, err := GetFromDB(tx, h)
+			if err != nil || d.Deleted {
+				continue
+			}
+			r, err := recordTX(my, tx, d, false)
+			if err != nil {
+				return nil, err
+			}
+			rs = append(rs, r)
+		}
+	}
+	sort.Slice(rs, func(i, j int) bool {
+		return rs[i].Stamp < rs[j].Stamp
+	})
+	return rs, nil
+}
@@ -0,0 +1,329 @@
+/*
+ * Copyright (c) 2015, Shinya Yagyu
+ * All rights reserved.
+ * Redistribution and use in source and binary forms, with or without
+ * modification, are permitted provided that the following conditions are met:
+ *
+ * 1. Redistributions of source code must retain the above copyright notice,
+ *    this list of conditions and the following disclaimer.
+ * 2. Redistributions in binary form must reproduce the above copyright notice,
+ *    this list of conditions and the following disclaimer in the documentation
+ *    and/or other materials provided with the distribution.
+ * 3. Neither the name of the copyright holder nor the names of its
+ *    contributors may be used to endorse or promote products derived from this
+ *    software without specific prior written permission.
+ *
+ * THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+ * AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+ * IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+ * ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+ * LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+ * CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+ * SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+ * INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+ * CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+ * ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+ * POSSIBILITY OF SUCH DAMAGE.
+ */
+package record
+
+import (
+	"bytes"
+	"encoding/json"
+	"html"
+	"log"
+	"math"
+	"strings"
+
+	"bbs/db"
+	"bbs/myself"
+	"bbs/util"
+)
+
+//parameters of BM25 ranking.
+const (
+	bm25K1 = 1.2
+	bm25B  = 0.75
+)
+
+//snippetLen is the # of runes of snippets.
+const snippetLen = 80
+
+//indexKeys are keys of record body which are indexed.
+var indexKeys = []string{"name", "body"}
+
+func init() {
+	db.RegisterMigration(&db.Migration{
+		Version: 3,
+		Name:    "full-text index",
+		Migrate: RebuildIndexTX,
+	})
+}
+
+//posting is a value of "index" bucket, which represents a record which has the term.
+type posting struct {
+	*Head
+	TF  int //# of the term in the record
+	Len int //# of all terms in the record
+}
+
+//indexStat is statistics of the index for ranking.
+type indexStat struct {
+	Docs   int
+	Tokens int
+}
+
+//Hit is a record which matches a search query.
+//for thread level matches, Head has only Datfile.
+type Hit struct {
+	*Head
+	Score    float64
+	Snippet  string
+	legacyID string
+}
+
+//bodyText returns plain text of indexed parts in the record body.
+func bodyText(body string) string {
+	var text []string
+	for _, kv := range strings.Split(body, "<>") {
+		buf := strings.SplitN(kv, ":", 2)
+		if len(buf) < 2 || !util.HasString(indexKeys, buf[0]) {
+			continue
+		}
+		v := strings.Replace(buf[1], "<br>", "\n", -1)
+		text = append(text, html.UnescapeString(v))
+	}
+	return strings.Join(text, "\n")
+}
+
+//termFreq returns # of each terms in text and # of all terms.
+func termFreq(text string) (map[string]int, int) {
+	terms := util.IndexTerms(text)
+	tf := make(map[string]int)
+	for _, t := range terms {
+		tf[t]++
+	}
+	return tf, len(terms)
+}
+
+//getIndexStat returns statistics of the index.
+func getIndexStat(tx db.Tx) *indexStat {
+	s := indexStat{}
+	if _, err := db.Get(tx, "meta", []byte("index"), &s); err != nil {
+		return &indexStat{}
+	}
+	return &s
+}
+
+//indexTX adds terms in the record d to the index.
+func indexTX(tx db.Tx, d *DB) error {
+	tf, n := termFreq(bodyText(d.Body))
+	for t, c := range tf {
+		p := &posting{Head: d.Head, TF: c, Len: n}
+		if err := db.Put(tx, "index", db.ToKey(t, d.Datfile, d.Stamp, d.ID), p); err != nil {
+			return err
+		}
+	}
+	s := getIndexStat(tx)
+	s.Docs++
+	s.Tokens += n
+	return db.Put(tx, "meta", []byte("index"), s)
+}
+
+//unindexTX removes terms in the record d from the index.
+func unindexTX(tx db.Tx, d *DB) error {
+	tf, n := termFreq(bodyText(d.Body))
+	for t := range tf {
+		if err := db.Del(tx, "index", db.ToKey(t, d.Datfile, d.Stamp, d.ID)); err != nil {
+			return err
+		}
+	}
+	s := getIndexStat(tx)
+	if s.Docs > 0 {
+		s.Docs--
+		s.Tokens -= n
+	}
+	return db.Put(tx, "meta", []byte("index"), s)
+}
+
+//updateIndexTX updates the index when old record is replaced by d.
+//only alive records are indexed.
+//old or d is nil when the record is added or deleted.
+func updateIndexTX(tx db.Tx, old, d *DB) error {
+	wasAlive := old != nil && !old.Deleted
+	isAlive := d != nil && !d.Deleted
+	switch {
+	case wasAlive && !isAlive:
+		return unindexTX(tx, old)
+	case !wasAlive && isAlive:
+		return indexTX(tx, d)
+	}
+	return nil
+}
+
+//IndexTitleTX adds terms in the title of the thread to the title index.
+func IndexTitleTX(tx db.Tx, datfile string) error {
+	for _, t := range util.IndexTerms(util.FileDecode(datfile)) {
+		if err := db.Put(tx, "titleindex", db.ToKey(t, datfile), ""); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+//UnindexTitleTX removes terms in the title of the thread from the title index.
+func UnindexTitleTX(tx db.Tx, datfile string) error {
+	if !tx.HasBucket("titleindex") {
+		return nil
+	}
+	for _, t := range util.IndexTerms(util.FileDecode(datfile)) {
+		if err := db.Del(tx, "titleindex", db.ToKey(t, datfile)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+//postings returns postings of the term, keyed by record key.
+func postings(tx db.Tx, term string) (map[string]*posting, error) {
+	ps := make(map[string]*posting)
+	prefix := db.ToKey(term)
+	c := tx.Cursor("index")
+	for k, v := c.Seek(prefix); bytes.HasPrefix(k, prefix); k, v = c.Next() {
+		p := posting{}
+		if err := json.Unmarshal(v, &p); err != nil {
+			return nil, err
+		}
+		ps[string(k[len(prefix):])] = &p
+	}
+	return ps, nil
+}
+
+//TermHitsTX returns records which have the term, keyed by record key, with BM25 scores.
+func TermHitsTX(tx db.Tx, term string) (map[string]*Hit, error) {
+	ps, err := postings(tx, term)
+	if err != nil {
+		return nil, err
+	}
+	s := getIndexStat(tx)
+	avgdl := 1.0
+	if s.Docs > 0 {
+		avgdl = float64(s.Tokens) / float64(s.Docs)
+	}
+	df := float64(len(ps))
+	idf := math.Log(1 + (float64(s.Docs)-df+0.5)/(df+0.5))
+	hits := make(map[string]*Hit)
+	for k, p := range ps {
+		tf := float64(p.TF)
+		hits[k] = &Hit{
+			Head:  p.Head,
+			Score: idf * tf * (bm25K1 + 1) / (tf + bm25K1*(1-bm25B+bm25B*float64(p.Len)/avgdl)),
+		}
+	}
+	return hits, nil
+}
+
+//Load sets legacy id and a part of the record body around the first term in terms.
+func (h *Hit) Load(tx db.Tx, terms []string) error {
+	d, err := GetFromDB(tx, h.Head)
+	if err != nil {
+		return err
+	}
+	h.legacyID = d.ID
+	if d.LegacyID != "" {
+		h.legacyID = d.LegacyID
+	}
+	h.Snippet = snippet(bodyText(d.Body), terms)
+	return nil
+}
+
+//ShortID returns first 8 chars of legacy id, which is used in anchors.
+func (h *Hit) ShortID() string {
+	if len(h.legacyID) > 8 {
+		return h.legacyID[:8]
+	}
+	return h.legacyID
+}
+
+//snippet returns a part of text around the first token in terms.
+func snippet(text string, terms []string) string {
+	rs := []rune(text)
+	pos := 0
+	for _, t := range util.IndexTokens(text) {
+		if util.HasString(terms, t.Term) {
+			pos = t.Pos
+			break
+		}
+	}
+	begin := pos - snippetLen/4
+	if begin < 0 {
+		begin = 0
+	}
+	end := begin + snippetLen
+	if end > len(rs) {
+		end = len(rs)
+	}
+	str := strings.Replace(string(rs[begin:end]), "\n", " ", -1)
+	if begin > 0 {
+		str = "..." + str
+	}
+	if end < len(rs) {
+		str += "..."
+	}
+	return str
+}
+
+//TitleThreadsTX returns threads whose titles have the term.
+func TitleThreadsTX(tx db.Tx, term string) []string {
+	var result []string
+	prefix := db.ToKey(term)
+	c := tx.Cursor("titleindex")
+	for k, _ := c.Seek(prefix); bytes.HasPrefix(k, prefix); k, _ = c.Next() {
+		result = append(result, string(bytes.TrimRight(k[len(prefix):], "\x00")))
+	}
+	return result
+}
+
+//RebuildIndexTX rebuilds the full-text index of all records and thread titles.
+func RebuildIndexTX(tx db.Tx) error {
+	for _, bucket := range []string{"index", "titleindex"} {
+		if !tx.HasBucket(bucket) {
+			continue
+		}
+		if err := tx.DeleteBucket(bucket); err != nil {
+			return err
+		}
+	}
+	if err := db.Put(tx, "meta", []byte("index"), &indexStat{}); err != nil {
+		return err
+	}
+	if tx.HasBucket("thread") {
+		datfiles, err := db.KeyStrings(tx, "thread")
+		if err != nil {
+			return err
+		}
+		for _, datfile := range datfiles {
+			if err := IndexTitleTX(tx, datfile); err != nil {
+				return err
+			}
+		}
+	}
+	if !tx.HasBucket("record") {
+		return nil
+	}
+	return ForEach(tx, func(d *DB) error {
+		if d.Deleted {
+			return nil
+		}
+		return indexTX(tx, d)
+	})
+}
+
+//RebuildIndex rebuilds the full-text index of all records and thread titles in db of my.
+func RebuildIndex(my *myself.Myself) error {
+	err := my.DB.Update(RebuildIndexTX)
+	if err != nil {
+		log.Println(err)
+	}
+	return err
+}
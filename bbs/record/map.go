@@ -32,8 +32,8 @@ import (
 	"fmt"
 	"sort"
 
-	"github.com/boltdb/bolt"
 	"bbs/db"
+	"bbs/myself"
 )
 
 //Map is a map key=stamp_id, value=record.
@@ -48,10 +48,10 @@ const (
 	All = 3
 )
 
-//FromRecordDB makes record map from record db.
-func FromRecordDB(datfile string, kind int) (Map, error) {
+//FromRecordDB makes record map from record db of my.
+func FromRecordDB(my *myself.Myself, datfile string, kind int) (Map, error) {
 	var r []*DB
-	err := db.DB.View(func(tx *bolt.Tx) error {
+	err := my.DB.View(func(tx db.Tx) error {
 		var err error
 		r, err = GetFromDBs(tx, datfile)
 		return err
@@ -62,7 +62,9 @@ func FromRecordDB(datfile string, kind int) (Map, error) {
 	m := make(Map)
 	for _, rr := range r {
 		rec := &Record{
-			Head: rr.Head,
+			my:       my,
+			Head:     rr.Head,
+			legacyID: rr.LegacyID,
 		}
 		idd := fmt.Sprintf("%d_%s", rr.Stamp, rr.ID)
 		switch kind {
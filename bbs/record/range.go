@@ -0,0 +1,145 @@
+/*
+ * Copyright (c) 2015, Shinya Yagyu
+ * All rights reserved.
+ * Redistribution and use in source and binary forms, with or without
+ * modification, are permitted provided that the following conditions are met:
+ *
+ * 1. Redistributions of source code must retain the above copyright notice,
+ *    this list of conditions and the following disclaimer.
+ * 2. Redistributions in binary form must reproduce the above copyright notice,
+ *    this list of conditions and the following disclaimer in the documentation
+ *    and/or other materials provided with the distribution.
+ * 3. Neither the name of the copyright holder nor the names of its
+ *    contributors may be used to endorse or promote products derived from this
+ *    software without specific prior written permission.
+ *
+ * THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+ * AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+ * IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+ * ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+ * LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+ * CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+ * SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+ * INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+ * CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+ * ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+ * POSSIBILITY OF SUCH DAMAGE.
+ */
+package record
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"bbs/db"
+	"bbs/myself"
+)
+
+//Range selects records of a thread whose stamps are from Begin to End, which are iterated
+//in order of stamp by seeking "record" bucket to datfile+Begin.
+type Range struct {
+	Datfile string
+	Begin   int64
+	End     int64
+	Kind    int    //Alive, Removed or All. All if 0
+	ID      string //only records whose id or legacy id starts with ID if not ""
+	ExactID bool   //ID must be same as id or legacy id, not their prefix, if true
+	Skip    int    //# of the first records to be skipped, of which only ids and deleted flags are decoded
+	Limit   int    //max # of records, no limit if 0
+	Head    bool   //bodies are not decoded if true
+}
+
+//EachTX calls fn with records of my in the range in tx.
+//record bodies are parsed with attached files unless rg.Head.
+func (rg *Range) EachTX(my *myself.Myself, tx db.Tx, fn func(*Record) error) error {
+	prefix := db.ToKey(rg.Datfile)
+	c := tx.Cursor("record")
+	var n int
+	for k, v := c.Seek(db.ToKey(rg.Datfile, rg.Begin)); bytes.HasPrefix(k, prefix); k, v = c.Next() {
+		if len(k) < len(prefix)+8 {
+			continue
+		}
+		if int64(binary.BigEndian.Uint64(k[len(prefix):])) > rg.End {
+			break
+		}
+		var m matcher
+		if err := json.Unmarshal(v, &m); err != nil {
+			return err
+		}
+		if !rg.match(&m) {
+			continue
+		}
+		if n++; n <= rg.Skip {
+			continue
+		}
+		d := DB{}
+		if err := json.Unmarshal(v, &d); err != nil {
+			return err
+		}
+		r, err := recordTX(my, tx, &d, rg.Head)
+		if err != nil {
+			return err
+		}
+		if err := fn(r); err != nil {
+			return err
+		}
+		if rg.Limit > 0 && n-rg.Skip >= rg.Limit {
+			break
+		}
+	}
+	return nil
+}
+
+//recordTX returns the record of my parsed from d with the attached file in tx.
+//the body is not parsed if head.
+func recordTX(my *myself.Myself, tx db.Tx, d *DB, head bool) (*Record, error) {
+	r := &Record{
+		my:       my,
+		Head:     d.Head,
+		verified: d.Verified,
+		legacyID: d.LegacyID,
+	}
+	if head {
+		return r, nil
+	}
+	body, err := d.BodyTX(tx)
+	if err != nil {
+		return nil, err
+	}
+	return r, r.Parse(fmt.Sprintf("%d<>%s<>%s", d.Stamp, d.ID, body))
+}
+
+//Each calls fn with records of my in the range in one read transaction.
+func (rg *Range) Each(my *myself.Myself, fn func(*Record) error) error {
+	return my.DB.View(func(tx db.Tx) error {
+		return rg.EachTX(my, tx, fn)
+	})
+}
+
+//matcher is a part of DB decoded to check if the record is in the range,
+//so that bodies of records which are not in the range or are skipped are not copied.
+type matcher struct {
+	ID       string
+	LegacyID string
+	Deleted  bool
+}
+
+//match returns true if d is the kind and has the id of rg.
+func (rg *Range) match(d *matcher) bool {
+	switch {
+	case rg.Kind == Alive && d.Deleted:
+		return false
+	case rg.Kind == Removed && !d.Deleted:
+		return false
+	}
+	switch {
+	case rg.ID == "":
+		return true
+	case rg.ExactID:
+		return d.ID == rg.ID || d.LegacyID == rg.ID
+	}
+	return strings.HasPrefix(d.ID, rg.ID) || d.LegacyID != "" && strings.HasPrefix(d.LegacyID, rg.ID)
+}
@@ -39,52 +39,131 @@ import (
 
 	"encoding/json"
 
-	"github.com/boltdb/bolt"
 	"bbs/cfg"
 	"bbs/db"
+	"bbs/myself"
 	"bbs/node"
 	"bbs/util"
 )
 
-var cachedRule *util.RegexpList
-
 //DB represents one record in db.
 type DB struct {
 	*Head
-	Body    string
-	Deleted bool
+	Body      string
+	Deleted   bool
+	Verified  bool
+	LegacyID  string //md5 id for nodes which use md5 ids, if ID is not md5
+	Attach    string //sha256 digest of the attached file in "blob" bucket, which is removed from Body
+	AttachLen int    //length of base64 encoded attached file
 }
 
 //Del deletes data from db.
-func (d *DB) Del(tx *bolt.Tx) {
+func (d *DB) Del(tx db.Tx) {
+	old, err := GetFromDB(tx, d.Head)
+	if err != nil {
+		log.Println(err)
+		return
+	}
 	if err := db.Del(tx, "record", d.Head.ToKey()); err != nil {
 		log.Println(err)
+		return
+	}
+	if err := updateStatTX(tx, old, nil); err != nil {
+		log.Println(err)
+	}
+	if err := updateIndexTX(tx, old, nil); err != nil {
+		log.Println(err)
+	}
+	if err := updateIDIndexTX(tx, old, nil); err != nil {
+		log.Println(err)
+	}
+	if err := updateBlobTX(tx, old, nil); err != nil {
+		log.Println(err)
+	}
+}
+
+//Put puts this one to db and updates the thread statistics and the full-text index.
+//the attached file is moved to blob store.
+func (d *DB) Put(tx db.Tx) error {
+	old, err := GetFromDB(tx, d.Head)
+	if err != nil {
+		old = nil
+	}
+	if err := splitAttach(tx, d); err != nil {
+		return err
+	}
+	if err := db.Put(tx, "record", d.Head.ToKey(), d); err != nil {
+		return err
+	}
+	if err := updateStatTX(tx, old, d); err != nil {
+		return err
+	}
+	if err := updateIndexTX(tx, old, d); err != nil {
+		return err
+	}
+	if err := updateIDIndexTX(tx, old, d); err != nil {
+		return err
 	}
+	return updateBlobTX(tx, old, d)
 }
 
-//Put puts this one to db.
-func (d *DB) Put(tx *bolt.Tx) error {
-	return db.Put(tx, "record", d.Head.ToKey(), d)
+//Record returns the record of my parsed from d.
+//its attached file is not loaded.
+func (d *DB) Record(my *myself.Myself) (*Record, error) {
+	r := &Record{
+		my:       my,
+		Head:     d.Head,
+		verified: d.Verified,
+		legacyID: d.LegacyID,
+	}
+	return r, r.Parse(fmt.Sprintf("%d<>%s<>%s", d.Stamp, d.ID, d.Body))
+}
+
+//Text returns plain text of name and body in d, which is indexed.
+func (d *DB) Text() string {
+	return bodyText(d.Body)
+}
+
+//DelDBs deletes all records whose thread name is datfile, their statistics, indexes and blobs.
+func DelDBs(tx db.Tx, datfile string) error {
+	r, err := GetFromDBs(tx, datfile)
+	if err != nil {
+		return err
+	}
+	for _, rr := range r {
+		if err := db.Del(tx, "record", rr.Head.ToKey()); err != nil {
+			return err
+		}
+		if err := updateIndexTX(tx, rr, nil); err != nil {
+			return err
+		}
+		if err := updateIDIndexTX(tx, rr, nil); err != nil {
+			return err
+		}
+		if err := updateBlobTX(tx, rr, nil); err != nil {
+			return err
+		}
+	}
+	return delStatTX(tx, datfile)
 }
 
 //GetFromDB gets DB db.
-func GetFromDB(tx *bolt.Tx, h *Head) (*DB, error) {
+func GetFromDB(tx db.Tx, h *Head) (*DB, error) {
 	d := DB{}
 	_, err := db.Get(tx, "record", h.ToKey(), &d)
 	return &d, err
 }
 
 //GetFromDBs gets DBs whose thread name is datfile.
-func GetFromDBs(tx *bolt.Tx, datfile string) ([]*DB, error) {
+func GetFromDBs(tx db.Tx, datfile string) ([]*DB, error) {
 	var cnt []*DB
 	bdatfile := make([]byte, len(datfile)+1)
 	copy(bdatfile, datfile)
 	bdatfile[len(datfile)] = 0x0
-	b := tx.Bucket([]byte("record"))
-	if b == nil {
+	if !tx.HasBucket("record") {
 		return nil, errors.New("bucket not found record")
 	}
-	c := b.Cursor()
+	c := tx.Cursor("record")
 	for k, v := c.Seek(bdatfile); bytes.HasPrefix(k, bdatfile); k, v = c.Next() {
 		str := DB{}
 		if err := json.Unmarshal(v, &str); err != nil {
@@ -97,12 +176,11 @@ func GetFromDBs(tx *bolt.Tx, datfile string) ([]*DB, error) {
 }
 
 //ForEach do eachDo for each k/v to "record" db.
-func ForEach(tx *bolt.Tx, eachDo func(*DB) error) error {
-	b := tx.Bucket([]byte("record"))
-	if b == nil {
+func ForEach(tx db.Tx, eachDo func(*DB) error) error {
+	if !tx.HasBucket("record") {
 		return errors.New("bucket not found record")
 	}
-	c := b.Cursor()
+	c := tx.Cursor("record")
 	d := DB{}
 	for k, v := c.First(); k != nil; k, v = c.Next() {
 		if errr := json.Unmarshal(v, &d); errr != nil {
@@ -119,15 +197,18 @@ func ForEach(tx *bolt.Tx, eachDo func(*DB) error) error {
 //Record represents one record.
 type Record struct {
 	*Head
+	my       *myself.Myself
 	contents map[string]string
 	keyOrder []string
+	verified bool
+	legacyID string
 }
 
-//NewIDstr parse idstr unixtime+"_"+md5(bodystr)), set stamp and id, and return record obj.
+//NewIDstr parse idstr unixtime+"_"+md5(bodystr)), set stamp and id, and return record obj of my.
 //if parse failes returns nil.
-func NewIDstr(datfile, idstr string) (*Record, error) {
+func NewIDstr(my *myself.Myself, datfile, idstr string) (*Record, error) {
 	if idstr == "" {
-		return New(datfile, "", 0), nil
+		return New(my, datfile, "", 0), nil
 	}
 	buf := strings.Split(idstr, "_")
 	if len(buf) != 2 {
@@ -141,12 +222,13 @@ func NewIDstr(datfile, idstr string) (*Record, error) {
 		log.Println(idstr, ":bad format")
 		return nil, err
 	}
-	return New(datfile, buf[1], stamp), nil
+	return New(my, datfile, buf[1], stamp), nil
 }
 
-//New makes Record struct.
-func New(datfile, id string, stamp int64) *Record {
+//New makes Record struct of my.
+func New(my *myself.Myself, datfile, id string, stamp int64) *Record {
 	return &Record{
+		my: my,
 		Head: &Head{
 			Datfile: datfile,
 			Stamp:   stamp,
@@ -164,8 +246,8 @@ func (r *Record) CopyHead() Head {
 	}
 }
 
-//Make makes and returns record from Recstr
-func Make(line string) (*Record, error) {
+//Make makes and returns record of my from Recstr
+func Make(my *myself.Myself, line string) (*Record, error) {
 	line = strings.TrimRight(line, "\r\n")
 	buf := strings.Split(line, "<>")
 	if len(buf) <= 2 || buf[0] == "" || buf[1] == "" || buf[2] == "" {
@@ -180,7 +262,7 @@ func Make(line string) (*Record, error) {
 		return nil, err
 	}
 	buf[2] = util.FileEncode("thread", dec)
-	vr, err := NewIDstr(buf[2], idstr)
+	vr, err := NewIDstr(my, buf[2], idstr)
 	if err != nil {
 		return nil, err
 	}
@@ -218,9 +300,73 @@ func (r *Record) GetBodyValue(k string, def string) string {
 	return def
 }
 
-//Recstr returns one line in the record file.
+//Recstr returns one line in the record file with legacy id.
 func (r *Record) Recstr() string {
-	return fmt.Sprintf("%d<>%s<>%s", r.Stamp, r.ID, r.bodystr())
+	return fmt.Sprintf("%d<>%s<>%s", r.Stamp, r.LegacyID(), r.bodystr())
+}
+
+//hasher returns the hasher for record ids specified by cache_hash_method in c.
+func hasher(c *cfg.Config) util.Hasher {
+	h, err := util.GetHasher(c.CacheHashMethod)
+	if err != nil {
+		h, _ = util.GetHasher("md5")
+	}
+	return h
+}
+
+//LegacyID returns md5 id of the record, which is used between nodes
+//and in anchors.
+func (r *Record) LegacyID() string {
+	if id, ok := r.knownLegacyID(); ok {
+		return id
+	}
+	var id string
+	err := r.my.DB.View(func(tx db.Tx) error {
+		id = r.LegacyIDTX(tx)
+		return nil
+	})
+	if err != nil {
+		log.Println(err)
+		return r.ID
+	}
+	return id
+}
+
+//LegacyIDTX is LegacyID which reads the record in db within tx if needed,
+//used when getting legacy ids of many records.
+func (r *Record) LegacyIDTX(tx db.Tx) string {
+	if id, ok := r.knownLegacyID(); ok {
+		return id
+	}
+	d, err := GetFromDB(tx, r.Head)
+	if err != nil || d.LegacyID == "" {
+		return r.ID
+	}
+	r.legacyID = d.LegacyID
+	return r.legacyID
+}
+
+//knownLegacyID returns legacy id and true if it can be known without db.
+func (r *Record) knownLegacyID() (string, bool) {
+	switch {
+	case r.legacyID != "":
+	case len(r.ID) == len(util.MD5digest("")):
+		r.legacyID = r.ID
+	case r.contents != nil:
+		r.legacyID = util.MD5digest(r.bodystr())
+	default:
+		return "", false
+	}
+	return r.legacyID, true
+}
+
+//LegacyHead returns Head with legacy id.
+func (r *Record) LegacyHead() *Head {
+	return &Head{
+		Datfile: r.Datfile,
+		Stamp:   r.Stamp,
+		ID:      r.LegacyID(),
+	}
 }
 
 //Parse parses one line in record file and response of /recent/ and set params to record r.
@@ -285,28 +431,112 @@ func (r *Record) Load() error {
 		}
 		return errors.New("file not found")
 	}
-	var d *DB
-	err := db.DB.View(func(tx *bolt.Tx) error {
-		var err error
-		d, err = GetFromDB(tx, r.Head)
-		return err
+	err := r.my.DB.View(func(tx db.Tx) error {
+		return r.loadTX(tx)
 	})
 	if err != nil {
 		log.Println(err)
-		return err
 	}
-	return r.Parse(fmt.Sprintf("%d<>%s<>%s", r.Stamp, r.ID, d.Body))
+	return err
 }
 
-//ShortPubkey returns short version of pubkey.
+//ShortPubkey returns short version of pubkey, preferring pubkey2 (ed25519).
 //used in templates
 func (r *Record) ShortPubkey() string {
-	if v, exist := r.contents["pubkey"]; exist {
+	if v := r.Pubkey(); v != "" {
 		return util.CutKey(v)
 	}
 	return ""
 }
 
+//Pubkey returns pubkey2 (ed25519) if sign2 is verified by it, or pubkey (apollo).
+//pubkey2 without verified sign2 is ignored, because anyone can copy others' pubkey2.
+func (r *Record) Pubkey() string {
+	if r.IsSigned2() && r.CheckSign2() {
+		return r.GetBodyValue("pubkey2", "")
+	}
+	return r.GetBodyValue("pubkey", "")
+}
+
+//IsSigned returns true if the record has pubkey, sign and target fields.
+func (r *Record) IsSigned() bool {
+	return r.HasBodyValue("pubkey") && r.HasBodyValue("sign") && r.HasBodyValue("target")
+}
+
+//IsSigned2 returns true if the record has pubkey2, sign2 and target fields.
+func (r *Record) IsSigned2() bool {
+	return r.HasBodyValue("pubkey2") && r.HasBodyValue("sign2") && r.HasBodyValue("target")
+}
+
+//targetstr returns the fields listed in target joined by "<>".
+func (r *Record) targetstr() (string, bool) {
+	targets := strings.Split(r.GetBodyValue("target", ""), ",")
+	rs := make([]string, len(targets))
+	for i, k := range targets {
+		v, exist := r.contents[k]
+		if !exist {
+			return "", false
+		}
+		rs[i] = k + ":" + v
+	}
+	return strings.Join(rs, "<>"), true
+}
+
+//CheckSign returns true if sign is verified by pubkey over md5 of the fields listed in target.
+func (r *Record) CheckSign() bool {
+	str, ok := r.targetstr()
+	if !ok {
+		return false
+	}
+	md := util.MD5digest(str)
+	return util.Verify(md, r.GetBodyValue("sign", ""), r.GetBodyValue("pubkey", ""))
+}
+
+//CheckSign2 returns true if sign2 is verified by pubkey2 over sha256 of the fields listed in target.
+func (r *Record) CheckSign2() bool {
+	str, ok := r.targetstr()
+	if !ok {
+		return false
+	}
+	return util.VerifyEd25519(str, r.GetBodyValue("sign2", ""), r.GetBodyValue("pubkey2", ""))
+}
+
+//checkSigns returns true as signed if the record is signed, and true as ok if the sign is verified.
+//ed25519 sign is preferred to apollo sign.
+func (r *Record) checkSigns() (signed bool, ok bool) {
+	switch {
+	case r.IsSigned2():
+		return true, r.CheckSign2()
+	case r.IsSigned():
+		return true, r.CheckSign()
+	}
+	return false, false
+}
+
+//IsForged returns true if the record is signed but its sign is not verified.
+func (r *Record) IsForged() bool {
+	signed, ok := r.checkSigns()
+	return signed && !ok
+}
+
+//verifiedPubkeys returns pubkeys whose signs are verified.
+func (r *Record) verifiedPubkeys() []string {
+	var keys []string
+	if r.IsSigned2() && r.CheckSign2() {
+		keys = append(keys, r.GetBodyValue("pubkey2", ""))
+	}
+	if r.IsSigned() && r.CheckSign() {
+		keys = append(keys, r.GetBodyValue("pubkey", ""))
+	}
+	return keys
+}
+
+//IsVerified returns true if sign of the record was verified when saved.
+//used in templates
+func (r *Record) IsVerified() bool {
+	return r.verified
+}
+
 //Build sets params in record from args and return id.
 func (r *Record) Build(stamp int64, body map[string]string, passwd string) string {
 	r.contents = make(map[string]string)
@@ -319,28 +549,53 @@ func (r *Record) Build(stamp int64, body map[string]string, passwd string) strin
 		r.keyOrder = append(r.keyOrder, key)
 	}
 	if passwd != "" {
+		r.sign(passwd)
+	}
+
+	r.ID = hasher(r.my.Cfg).Digest(r.bodystr())
+	r.legacyID = ""
+	return r.ID
+}
+
+//sign signs the record by ed25519 key made from passwd, and by apollo key
+//if legacy_sign for nodes which don't know ed25519.
+func (r *Record) sign(passwd string) {
+	str := r.bodystr()
+	target := strings.Join(r.keyOrder, ",")
+	if r.my.Cfg.LegacySign {
 		k, err := util.MakePrivateKey(passwd)
 		if err == nil {
 			pubkey, _ := k.GetKeys()
-			md := util.MD5digest(r.bodystr())
+			md := util.MD5digest(str)
 			sign := k.Sign(md)
 			r.contents["pubkey"] = pubkey
 			r.contents["sign"] = sign
-			r.contents["target"] = strings.Join(r.keyOrder, ",")
 			r.keyOrder = append(r.keyOrder, "pubkey")
 			r.keyOrder = append(r.keyOrder, "sign")
-			r.keyOrder = append(r.keyOrder, "target")
+		} else {
+			log.Println(err)
 		}
 	}
+	k := util.MakeEd25519Key(passwd)
+	r.contents["target"] = target
+	r.contents["pubkey2"] = k.PublicKey()
+	r.contents["sign2"] = k.Sign(str)
+	r.keyOrder = append(r.keyOrder, "target")
+	r.keyOrder = append(r.keyOrder, "pubkey2")
+	r.keyOrder = append(r.keyOrder, "sign2")
+}
 
-	id := util.MD5digest(r.bodystr())
-	r.ID = id
-	return r.ID
+//hashcheck return true if digest of bodystr is same as r.id.
+//hasher is selected by length of r.id.
+func (r *Record) hashcheck() bool {
+	h := util.FindHasher(r.ID)
+	return h != nil && h.Digest(r.bodystr()) == r.ID
 }
 
-//md5check return true if md5 of bodystr is same as r.id.
-func (r *Record) md5check() bool {
-	return util.MD5digest(r.bodystr()) == r.ID
+//MD5check return true if md5 digest of bodystr is same as r.id,
+//which is the id of records saved by saku.
+func (r *Record) MD5check() bool {
+	return r.ID == util.MD5digest(r.bodystr())
 }
 
 //AttachPath returns attach path
@@ -360,7 +615,15 @@ func (r *Record) AttachPath(thumbnailSize string) string {
 
 //SyncTX saves Recstr to the file. if attached file exists, saves it to attached path.
 //if signed, also saves body part.
-func (r *Record) SyncTX(tx *bolt.Tx, deleted bool) error {
+//id of r is changed to digest by cache_hash_method, and its md5 id is saved as legacy id.
+//if r is a removal record signed by the author of the target, or the author has
+//already requested removal of r, the target is marked as deleted.
+func (r *Record) SyncTX(tx db.Tx, deleted bool) error {
+	if r.hashcheck() {
+		legacyID := r.LegacyID()
+		r.ID = hasher(r.my.Cfg).Digest(r.bodystr())
+		r.legacyID = legacyID
+	}
 	has, err := db.HasKey(tx, "record", r.Head.ToKey())
 	if err != nil {
 		log.Println(err)
@@ -368,18 +631,32 @@ func (r *Record) SyncTX(tx *bolt.Tx, deleted bool) error {
 	if has {
 		return nil
 	}
+	_, r.verified = r.checkSigns()
 	d := DB{
-		Head:    r.Head,
-		Body:    r.bodystr(),
-		Deleted: deleted,
+		Head:     r.Head,
+		Body:     r.bodystr(),
+		Deleted:  deleted,
+		Verified: r.verified,
+	}
+	if r.LegacyID() != r.ID {
+		d.LegacyID = r.LegacyID()
+	}
+	if deleted {
+		return d.Put(tx)
 	}
-	return d.Put(tx)
+	if d.Deleted, err = r.removedTX(tx); err != nil {
+		return err
+	}
+	if err := d.Put(tx); err != nil {
+		return err
+	}
+	return r.removeTargetTX(tx)
 }
 
 //Sync saves Recstr to the file. if attached file exists, saves it to attached path.
 //if signed, also saves body part.
 func (r *Record) Sync() {
-	err := db.DB.Update(func(tx *bolt.Tx) error {
+	err := r.my.DB.Update(func(tx db.Tx) error {
 		return r.SyncTX(tx, false)
 	})
 	if err != nil {
@@ -387,6 +664,32 @@ func (r *Record) Sync() {
 	}
 }
 
+//Exists return true if record file exists.
+func (r *Record) Exists() bool {
+	var has bool
+	err := r.my.DB.View(func(tx db.Tx) error {
+		var err error
+		has, err = db.HasKey(tx, "record", r.ToKey())
+		return err
+	})
+	if err != nil {
+		log.Print(err)
+		return false
+	}
+	return has
+}
+
+//Remove moves the record file  to remove path
+func (r *Record) Remove() error {
+	err := r.my.DB.Update(func(tx db.Tx) error {
+		return r.RemoveTX(tx)
+	})
+	if err != nil {
+		log.Print(err)
+	}
+	return err
+}
+
 //Getbody retuns contents of rec after loading if needed.
 //used in template
 func (r *Record) Getbody() string {
@@ -399,13 +702,13 @@ func (r *Record) Getbody() string {
 
 //Meets checks the record meets conditions of args
 func (r *Record) Meets(begin, end int64) bool {
-	md5ok := r.md5check()
+	hashok := r.hashcheck()
 	if begin > r.Stamp || (end > 0 && r.Stamp > end) {
 		log.Println("stamp range NG", begin, end, r.Stamp)
 		return false
 	}
-	if !md5ok {
-		log.Println("md5 NG")
+	if !hashok {
+		log.Println("hash NG")
 		return false
 	}
 	return true
@@ -413,10 +716,7 @@ func (r *Record) Meets(begin, end int64) bool {
 
 //IsSpam returns true if Recstr is listed in spam.txt
 func (r *Record) IsSpam() bool {
-	if cachedRule == nil {
-		cachedRule = util.NewRegexpList(cfg.SpamList)
-	}
-	return cachedRule.Check(r.Recstr())
+	return r.my.Cfg.Spam.Check(r.Recstr())
 }
 
 //MakeAttachLink makes and returns attached file link.
@@ -432,7 +732,7 @@ func (r *Record) MakeAttachLink(sakuHost string) string {
 //GetData gets records from node n and checks its is same as stamp and id in args.
 //save recs if success. returns errSpam or errGet.
 func (r *Record) GetData(n *node.Node) error {
-	res, err := n.Talk(fmt.Sprintf("/get/%s/%d/%s", r.Datfile, r.Stamp, r.ID), nil)
+	res, err := n.Talk(r.my, fmt.Sprintf("/get/%s/%d/%s", r.Datfile, r.Stamp, r.ID), nil)
 	if len(res) == 0 {
 		err = errors.New("no response")
 	}
@@ -450,13 +750,21 @@ func (r *Record) GetData(n *node.Node) error {
 //CheckData makes records from res and checks its records meets condisions of args.
 //adds the rec to cache if meets conditions.
 //if spam or big data, remove the rec from disk.
-//returns count of added records to the cache and spam/getting error.
+//if sign is forged, remove the rec from disk.
+//returns count of added records to the cache and spam/sign/getting error.
 func (r *Record) CheckData(begin, end int64) error {
 	if !r.Meets(begin, end) {
 		return cfg.ErrGet
 	}
+	if r.IsForged() {
+		log.Printf("warning:%s/%s:forged sign", r.Datfile, r.Idstr())
+		if errr := r.Remove(); errr != nil {
+			log.Println(errr)
+		}
+		return cfg.ErrSign
+	}
 	log.Println(r.Recstr(), r.IsSpam())
-	if len(r.Recstr()) > cfg.RecordLimit<<10 || r.IsSpam() {
+	if len(r.Recstr()) > r.my.Cfg.RecordLimit<<10 || r.IsSpam() {
 		log.Printf("warning:%s/%s:too large or spam record", r.Datfile, r.Idstr())
 		errr := r.Remove()
 		if errr != nil {
@@ -467,7 +775,10 @@ func (r *Record) CheckData(begin, end int64) error {
 	return nil
 }
 
-//InRange returns true if stamp  is in begin~end and idstr has id.
+//InRange returns true if stamp  is in begin~end and idstr or legacy idstr has id.
 func (r *Record) InRange(begin, end int64, id string) bool {
-	return begin <= r.Stamp && r.Stamp <= end && (id == "" || strings.HasSuffix(r.Idstr(), id))
+	if begin > r.Stamp || r.Stamp > end {
+		return false
+	}
+	return id == "" || strings.HasSuffix(r.Idstr(), id) || strings.HasSuffix(r.LegacyHead().Idstr(), id)
 }
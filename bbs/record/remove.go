@@ -0,0 +1,333 @@
+/*
+ * Copyright (c) 2015, Shinya Yagyu
+ * All rights reserved.
+ * Redistribution and use in source and binary forms, with or without
+ * modification, are permitted provided that the following conditions are met:
+ *
+ * 1. Redistributions of source code must retain the above copyright notice,
+ *    this list of conditions and the following disclaimer.
+ * 2. Redistributions in binary form must reproduce the above copyright notice,
+ *    this list of conditions and the following disclaimer in the documentation
+ *    and/or other materials provided with the distribution.
+ * 3. Neither the name of the copyright holder nor the names of its
+ *    contributors may be used to endorse or promote products derived from this
+ *    software without specific prior written permission.
+ *
+ * THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+ * AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+ * IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+ * ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+ * LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+ * CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+ * SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+ * INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+ * CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+ * ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+ * POSSIBILITY OF SUCH DAMAGE.
+ */
+
+package record
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+
+	"bbs/cfg"
+	"bbs/db"
+	"bbs/myself"
+	"bbs/util"
+)
+
+//modes of moderators in the moderator file.
+const (
+	moderatorAuto   = "auto"
+	moderatorReview = "review"
+)
+
+//moderatorMode returns the mode of pubkey written in the moderator file of c,
+//or "" if pubkey is not a trusted moderator.
+//    One pubkey and optional mode ("auto" or "review") per one line.
+func moderatorMode(c *cfg.Config, pubkey string) string {
+	if c.Moderators == nil || pubkey == "" {
+		return ""
+	}
+	for _, line := range c.Moderators.GetData() {
+		f := strings.Fields(line)
+		if len(f) == 0 || f[0] != pubkey {
+			continue
+		}
+		if len(f) > 1 && f[1] == moderatorReview {
+			return moderatorReview
+		}
+		return moderatorAuto
+	}
+	return ""
+}
+
+//Removal represents a request to remove a record, saved in "remove" bucket
+//with key target thread:stamp:legacy hash + remover stamp:hash.
+type Removal struct {
+	Target    *Head  //head with legacy id
+	Stamp     int64  //stamp of the removal record
+	ID        string //id of the removal record
+	Pubkey    string //pubkey which signed the removal record
+	Moderator bool   //true if Pubkey is a trusted moderator
+	Applied   bool
+}
+
+//Remover returns head of the removal record.
+func (rm *Removal) Remover() *Head {
+	return &Head{
+		Datfile: rm.Target.Datfile,
+		Stamp:   rm.Stamp,
+		ID:      rm.ID,
+	}
+}
+
+//ShortPubkey returns short version of pubkey.
+//used in templates
+func (rm *Removal) ShortPubkey() string {
+	return util.CutKey(rm.Pubkey)
+}
+
+//key returns key of "remove" bucket.
+func (rm *Removal) key() []byte {
+	return removalKey(rm.Target, rm.Remover())
+}
+
+//Apply marks the target record as deleted in db of my if it exists, and the request as applied
+//so that the target is removed when it arrives.
+func (rm *Removal) Apply(my *myself.Myself) error {
+	err := my.DB.Update(func(tx db.Tx) error {
+		if t := ResolveTX(tx, rm.Target); t != nil {
+			if err := t.RemoveTX(tx); err != nil {
+				return err
+			}
+		}
+		rm.Applied = true
+		return db.Put(tx, "remove", rm.key(), rm)
+	})
+	if err != nil {
+		log.Println(err)
+		return err
+	}
+	log.Println(rm.Target.Datfile, rm.Target.Idstr(), "was removed by moderator", rm.ShortPubkey())
+	return nil
+}
+
+//Dismiss deletes the request in db of my without removing the target.
+func (rm *Removal) Dismiss(my *myself.Myself) error {
+	err := my.DB.Update(func(tx db.Tx) error {
+		if !tx.HasBucket("remove") {
+			return errors.New("bucket not found remove")
+		}
+		return tx.Delete("remove", rm.key())
+	})
+	if err != nil {
+		log.Println(err)
+	}
+	return err
+}
+
+//GetRemoval returns the request to remove target by remover in db of my.
+func GetRemoval(my *myself.Myself, target, remover *Head) (*Removal, error) {
+	var rm Removal
+	err := my.DB.View(func(tx db.Tx) error {
+		_, err := db.Get(tx, "remove", removalKey(target, remover), &rm)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	rm.Target = target
+	return &rm, nil
+}
+
+//ModeratorRemovals returns all requests signed by trusted moderators in db of my.
+func ModeratorRemovals(my *myself.Myself) ([]*Removal, error) {
+	var rms []*Removal
+	err := my.DB.View(func(tx db.Tx) error {
+		return db.ForEach(tx, "remove", func(k, v []byte) error {
+			rm := Removal{}
+			if err := json.Unmarshal(v, &rm); err != nil {
+				return err
+			}
+			if rm.Moderator && rm.Target != nil {
+				rms = append(rms, &rm)
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		log.Println(err)
+	}
+	return rms, err
+}
+
+//ResolveTX returns head of the saved record whose id or legacy id is h.ID.
+//returns nil if not found.
+func ResolveTX(tx db.Tx, h *Head) *Head {
+	has, err := db.HasKey(tx, "record", h.ToKey())
+	if err != nil {
+		return nil
+	}
+	if has {
+		return h
+	}
+	prefix := db.ToKey(h.Datfile, h.Stamp)
+	c := tx.Cursor("record")
+	for k, v := c.Seek(prefix); bytes.HasPrefix(k, prefix); k, v = c.Next() {
+		d := DB{}
+		if err := json.Unmarshal(v, &d); err != nil {
+			log.Println(err)
+			continue
+		}
+		if d.LegacyID == h.ID {
+			return d.Head
+		}
+	}
+	return nil
+}
+
+//removeTarget returns head of the record which r requests to remove, with legacy id.
+//if r is not a removal record, returns nil.
+func (r *Record) removeTarget() *Head {
+	id := r.GetBodyValue("remove_id", "")
+	stamp, err := strconv.ParseInt(r.GetBodyValue("remove_stamp", ""), 10, 64)
+	if id == "" || err != nil {
+		return nil
+	}
+	return &Head{
+		Datfile: r.Datfile,
+		Stamp:   stamp,
+		ID:      id,
+	}
+}
+
+//removalKey returns key of "remove" bucket.
+func removalKey(target *Head, remover *Head) []byte {
+	return db.ToKey(target.Datfile, target.Stamp, target.ID, remover.Stamp, remover.ID)
+}
+
+//moderatorMode returns the strongest mode of verified pubkeys of r in the moderator file.
+func (r *Record) moderatorMode() string {
+	mode := ""
+	for _, pubkey := range r.verifiedPubkeys() {
+		switch moderatorMode(r.my.Cfg, pubkey) {
+		case moderatorAuto:
+			return moderatorAuto
+		case moderatorReview:
+			mode = moderatorReview
+		}
+	}
+	return mode
+}
+
+//canRemove returns true if r is a verified removal record signed by the same
+//pubkey as target, or by a moderator trusted to remove records automatically.
+func (r *Record) canRemove(target *Record) bool {
+	if !r.verified {
+		return false
+	}
+	tkeys := target.verifiedPubkeys()
+	for _, pubkey := range r.verifiedPubkeys() {
+		if util.HasString(tkeys, pubkey) {
+			return true
+		}
+	}
+	return r.moderatorMode() == moderatorAuto
+}
+
+//loadTX loads the record in db within tx and parses it.
+func (r *Record) loadTX(tx db.Tx) error {
+	d, err := GetFromDB(tx, r.Head)
+	if err != nil {
+		return err
+	}
+	r.verified = d.Verified
+	r.legacyID = d.LegacyID
+	body, err := d.BodyTX(tx)
+	if err != nil {
+		return err
+	}
+	return r.Parse(fmt.Sprintf("%d<>%s<>%s", r.Stamp, r.ID, body))
+}
+
+//removeTargetTX registers r as a removal request if r has remove_stamp and remove_id,
+//and removes the target record if it has already been saved and r can remove it.
+//requests by moderators in review mode are left for admin.
+func (r *Record) removeTargetTX(tx db.Tx) error {
+	t := r.removeTarget()
+	if t == nil || !r.verified {
+		return nil
+	}
+	pubkey := r.Pubkey()
+	rm := Removal{
+		Target:    t,
+		Stamp:     r.Stamp,
+		ID:        r.ID,
+		Pubkey:    pubkey,
+		Moderator: r.moderatorMode() != "",
+	}
+	target := &Record{Head: ResolveTX(tx, t), my: r.my}
+	if target.Head != nil && target.loadTX(tx) == nil && r.canRemove(target) {
+		if err := target.RemoveTX(tx); err != nil {
+			return err
+		}
+		rm.Applied = true
+		log.Println(t.Datfile, t.Idstr(), "was removed by", util.CutKey(pubkey), "with", r.Idstr())
+	}
+	return db.Put(tx, "remove", rm.key(), &rm)
+}
+
+//removedTX returns true if a removal request for r which was approved or can remove r
+//has been saved already, and marks the request applied.
+func (r *Record) removedTX(tx db.Tx) (bool, error) {
+	target := r.LegacyHead()
+	prefix := target.ToKey()
+	var found *Removal
+	c := tx.Cursor("remove")
+	for k, v := c.Seek(prefix); bytes.HasPrefix(k, prefix); k, v = c.Next() {
+		rm := Removal{}
+		if err := json.Unmarshal(v, &rm); err != nil {
+			return false, err
+		}
+		rm.Target = target
+		if rm.Applied {
+			found = &rm
+			break
+		}
+		remover := New(r.my, r.Datfile, rm.ID, rm.Stamp)
+		if err := remover.loadTX(tx); err != nil {
+			log.Println(err)
+			continue
+		}
+		if remover.canRemove(r) {
+			found = &rm
+			break
+		}
+	}
+	if found == nil {
+		return false, nil
+	}
+	found.Applied = true
+	log.Println(r.Datfile, r.Idstr(), "was removed by", found.ShortPubkey(), "with", found.Stamp, found.ID)
+	return true, db.Put(tx, "remove", found.key(), found)
+}
+
+//IsRemoveApplied returns true if r is a removal record and the target record was
+//removed by it.
+//used in templates
+func (r *Record) IsRemoveApplied() bool {
+	t := r.removeTarget()
+	if t == nil {
+		return false
+	}
+	rm, err := GetRemoval(r.my, t, r.Head)
+	return err == nil && rm.Applied
+}
@@ -0,0 +1,262 @@
+/*
+ * Copyright (c) 2015, Shinya Yagyu
+ * All rights reserved.
+ * Redistribution and use in source and binary forms, with or without
+ * modification, are permitted provided that the following conditions are met:
+ *
+ * 1. Redistributions of source code must retain the above copyright notice,
+ *    this list of conditions and the following disclaimer.
+ * 2. Redistributions in binary form must reproduce the above copyright notice,
+ *    this list of conditions and the following disclaimer in the documentation
+ *    and/or other materials provided with the distribution.
+ * 3. Neither the name of the copyright holder nor the names of its
+ *    contributors may be used to endorse or promote products derived from this
+ *    software without specific prior written permission.
+ *
+ * THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+ * AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+ * IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+ * ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+ * LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+ * CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+ * SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+ * INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+ * CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+ * ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+ * POSSIBILITY OF SUCH DAMAGE.
+ */
+package record
+
+import (
+	"log"
+	"time"
+
+	"bbs/db"
+	"bbs/myself"
+)
+
+//velocityDays is the number of days counted in velocity.
+const velocityDays = 7
+
+//Stat is statistics of records in one thread, saved in "threadstat" bucket.
+//it is updated when a record is put or deleted.
+type Stat struct {
+	Alive     int
+	Removed   int
+	Size      int64         //sum of body length including attached files
+	First     int64         //oldest stamp
+	Last      int64         //newest stamp
+	LastAlive int64         //newest stamp of alive records
+	Days      map[int64]int //# of records for each recent days (stamp/day)
+}
+
+func init() {
+	db.RegisterMigration(&db.Migration{
+		Version: 2,
+		Name:    "thread statistics",
+		Migrate: RebuildStatsTX,
+	})
+}
+
+//day returns the day number of stamp.
+func day(stamp int64) int64 {
+	return stamp / (24 * 60 * 60)
+}
+
+//Len returns # of records of kind.
+func (s *Stat) Len(kind int) int {
+	switch kind {
+	case Alive:
+		return s.Alive
+	case Removed:
+		return s.Removed
+	}
+	return s.Alive + s.Removed
+}
+
+//Velocity returns # of records in recent days.
+func (s *Stat) Velocity() int {
+	today := day(time.Now().Unix())
+	cnt := 0
+	for d, n := range s.Days {
+		if d > today-velocityDays {
+			cnt += n
+		}
+	}
+	return cnt
+}
+
+//add adds d to the stat.
+func (s *Stat) add(d *DB) {
+	if d.Deleted {
+		s.Removed++
+	} else {
+		s.Alive++
+		if d.Stamp > s.LastAlive {
+			s.LastAlive = d.Stamp
+		}
+	}
+	s.Size += int64(len(d.Body) + d.AttachLen)
+	if s.First == 0 || d.Stamp < s.First {
+		s.First = d.Stamp
+	}
+	if d.Stamp > s.Last {
+		s.Last = d.Stamp
+	}
+	today := day(time.Now().Unix())
+	if dd := day(d.Stamp); dd > today-velocityDays {
+		if s.Days == nil {
+			s.Days = make(map[int64]int)
+		}
+		s.Days[dd]++
+	}
+	for dd := range s.Days {
+		if dd <= today-velocityDays {
+			delete(s.Days, dd)
+		}
+	}
+}
+
+//sub removes d from the stat. it doesn't touch stamps.
+func (s *Stat) sub(d *DB) {
+	if d.Deleted {
+		s.Removed--
+	} else {
+		s.Alive--
+	}
+	s.Size -= int64(len(d.Body) + d.AttachLen)
+	dd := day(d.Stamp)
+	if s.Days[dd] > 0 {
+		s.Days[dd]--
+	}
+	if s.Days[dd] == 0 {
+		delete(s.Days, dd)
+	}
+}
+
+//GetStat returns statistics of records in the thread.
+func GetStat(tx db.Tx, datfile string) *Stat {
+	s := Stat{}
+	if _, err := db.Get(tx, "threadstat", []byte(datfile), &s); err != nil {
+		return &Stat{}
+	}
+	return &s
+}
+
+//Stats returns statistics of records in the thread in db of my.
+func Stats(my *myself.Myself, datfile string) *Stat {
+	s := &Stat{}
+	err := my.DB.View(func(tx db.Tx) error {
+		s = GetStat(tx, datfile)
+		return nil
+	})
+	if err != nil {
+		log.Println(err)
+	}
+	return s
+}
+
+//delStatTX deletes statistics of the thread.
+func delStatTX(tx db.Tx, datfile string) error {
+	if !tx.HasBucket("threadstat") {
+		return nil
+	}
+	return db.Del(tx, "threadstat", []byte(datfile))
+}
+
+//MakeStatTX makes statistics of the thread from all records in it.
+//it returns nil if the thread has no records.
+func MakeStatTX(tx db.Tx, datfile string) (*Stat, error) {
+	r, err := GetFromDBs(tx, datfile)
+	if err != nil || len(r) == 0 {
+		return nil, err
+	}
+	s := Stat{}
+	for _, d := range r {
+		s.add(d)
+	}
+	return &s, nil
+}
+
+//RebuildStatTX rebuilds statistics of the thread from all records in it.
+func RebuildStatTX(tx db.Tx, datfile string) error {
+	s, err := MakeStatTX(tx, datfile)
+	if err != nil {
+		return err
+	}
+	if s == nil {
+		return delStatTX(tx, datfile)
+	}
+	return db.Put(tx, "threadstat", []byte(datfile), s)
+}
+
+//updateStatTX updates the statistics of the thread when old record is replaced by d.
+//old or d is nil when the record is added or deleted.
+//it rebuilds the stat only when the stamp at the edge is gone.
+func updateStatTX(tx db.Tx, old, d *DB) error {
+	h := d
+	if h == nil {
+		h = old
+	}
+	s := GetStat(tx, h.Datfile)
+	switch {
+	case old == nil:
+		s.add(d)
+	case d == nil:
+		if s.Len(All) <= 1 {
+			return delStatTX(tx, h.Datfile)
+		}
+		if old.Stamp == s.First || old.Stamp == s.Last || old.Stamp == s.LastAlive {
+			return RebuildStatTX(tx, h.Datfile)
+		}
+		s.sub(old)
+	case old.Deleted != d.Deleted:
+		if d.Deleted && s.LastAlive == d.Stamp {
+			return RebuildStatTX(tx, h.Datfile)
+		}
+		if d.Deleted {
+			s.Alive--
+			s.Removed++
+		} else {
+			s.Alive++
+			s.Removed--
+			if d.Stamp > s.LastAlive {
+				s.LastAlive = d.Stamp
+			}
+		}
+	default:
+		return nil
+	}
+	return db.Put(tx, "threadstat", []byte(h.Datfile), s)
+}
+
+//RebuildStatsTX rebuilds statistics of all threads.
+func RebuildStatsTX(tx db.Tx) error {
+	if tx.HasBucket("threadstat") {
+		if err := tx.DeleteBucket("threadstat"); err != nil {
+			return err
+		}
+	}
+	if !tx.HasBucket("record") {
+		return nil
+	}
+	datfiles, err := db.GetPrefixs(tx, "record")
+	if err != nil {
+		return err
+	}
+	for _, datfile := range datfiles {
+		if err := RebuildStatTX(tx, datfile); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+//RebuildStats rebuilds statistics of all threads in db of my.
+func RebuildStats(my *myself.Myself) error {
+	err := my.DB.Update(RebuildStatsTX)
+	if err != nil {
+		log.Println(err)
+	}
+	return err
+}
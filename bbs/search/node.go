@@ -0,0 +1,396 @@
+/*
+ * Copyright (c) 2015, Shinya Yagyu
+ * All rights reserved.
+ * Redistribution and use in source and binary forms, with or without
+ * modification, are permitted provided that the following conditions are met:
+ *
+ * 1. Redistributions of source code must retain the above copyright notice,
+ *    this list of conditions and the following disclaimer.
+ * 2. Redistributions in binary form must reproduce the above copyright notice,
+ *    this list of conditions and the following disclaimer in the documentation
+ *    and/or other materials provided with the distribution.
+ * 3. Neither the name of the copyright holder nor the names of its
+ *    contributors may be used to endorse or promote products derived from this
+ *    software without specific prior written permission.
+ *
+ * THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+ * AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+ * IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+ * ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+ * LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+ * CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+ * SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+ * INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+ * CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+ * ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+ * POSSIBILITY OF SUCH DAMAGE.
+ */
+package search
+
+import (
+	"fmt"
+	"html"
+	"strings"
+
+	"bbs/db"
+	"bbs/myself"
+	"bbs/record"
+	"bbs/tag/suggest"
+	"bbs/tag/user"
+	"bbs/util"
+)
+
+//Node is a node of query AST.
+type Node interface {
+	//match returns true if the record or thread in d matches.
+	match(d *doc) bool
+	//candidates returns records (or threads if threads) which may match with scores,
+	//or nil if it cannot narrow down them.
+	candidates(tx db.Tx, threads bool) (hits, error)
+	//terms returns terms not in NOT for snippets.
+	terms() []string
+	String() string
+}
+
+//hits are records or threads keyed by record key or datfile.
+type hits map[string]*record.Hit
+
+//doc is a record, or a thread if rec is nil, to be matched.
+type doc struct {
+	tx      db.Tx
+	datfile string
+	rec     *record.Record
+	text    []string
+	title   []string
+}
+
+//newRecordDoc returns doc of record d.
+func newRecordDoc(my *myself.Myself, tx db.Tx, d *record.DB) (*doc, error) {
+	rec, err := d.Record(my)
+	if err != nil {
+		return nil, err
+	}
+	return &doc{
+		tx:      tx,
+		datfile: d.Datfile,
+		rec:     rec,
+		text:    util.Tokenize(d.Text()),
+	}, nil
+}
+
+//newThreadDoc returns doc of thread datfile.
+func newThreadDoc(tx db.Tx, datfile string) *doc {
+	return &doc{
+		tx:      tx,
+		datfile: datfile,
+	}
+}
+
+//titleTerms returns terms in the title of thread.
+func (d *doc) titleTerms() []string {
+	if d.title == nil {
+		d.title = util.Tokenize(util.FileDecode(d.datfile))
+	}
+	return d.title
+}
+
+//value returns unescaped value of key k in the record.
+func (d *doc) value(k string) string {
+	return html.UnescapeString(d.rec.GetBodyValue(k, ""))
+}
+
+//hasPhrase returns true if terms contains phrase in sequence.
+//a phrase of one CJK character matches bigrams which have it.
+func hasPhrase(terms, phrase []string) bool {
+	if len(phrase) == 1 && util.IsCJKChar(phrase[0]) {
+		for _, t := range terms {
+			if strings.Contains(t, phrase[0]) {
+				return true
+			}
+		}
+		return false
+	}
+	for i := 0; i+len(phrase) <= len(terms); i++ {
+		j := 0
+		for j < len(phrase) && terms[i+j] == phrase[j] {
+			j++
+		}
+		if j == len(phrase) {
+			return true
+		}
+	}
+	return false
+}
+
+//threadHits returns hits of threads.
+func threadHits(datfiles []string) hits {
+	h := make(hits)
+	for _, datfile := range datfiles {
+		h[datfile] = &record.Hit{
+			Head: &record.Head{Datfile: datfile},
+		}
+	}
+	return h
+}
+
+//recordHits returns hits of alive records in threads in h.
+func recordHits(tx db.Tx, h hits) hits {
+	r := make(hits)
+	for datfile := range h {
+		ds, err := record.GetFromDBs(tx, datfile)
+		if err != nil {
+			continue
+		}
+		for _, d := range ds {
+			if !d.Deleted {
+				r[string(d.Head.ToKey())] = &record.Hit{Head: d.Head}
+			}
+		}
+	}
+	return r
+}
+
+//intersect returns hits in both a and b with summed scores.
+//nil means all.
+func intersect(a, b hits) hits {
+	if a == nil {
+		return b
+	}
+	if b == nil {
+		return a
+	}
+	r := make(hits)
+	for k, h := range a {
+		if hb, ok := b[k]; ok {
+			r[k] = &record.Hit{
+				Head:  h.Head,
+				Score: h.Score + hb.Score,
+			}
+		}
+	}
+	return r
+}
+
+//union returns hits in a or b with summed scores.
+//nil means all.
+func union(a, b hits) hits {
+	if a == nil || b == nil {
+		return nil
+	}
+	r := make(hits)
+	for k, h := range a {
+		r[k] = h
+	}
+	for k, h := range b {
+		if ha, ok := r[k]; ok {
+			r[k] = &record.Hit{
+				Head:  h.Head,
+				Score: h.Score + ha.Score,
+			}
+			continue
+		}
+		r[k] = h
+	}
+	return r
+}
+
+//andNode matches if all nodes match.
+type andNode []Node
+
+func (n andNode) match(d *doc) bool {
+	for _, nn := range n {
+		if !nn.match(d) {
+			return false
+		}
+	}
+	return true
+}
+
+func (n andNode) candidates(tx db.Tx, threads bool) (hits, error) {
+	var r hits
+	for _, nn := range n {
+		h, err := nn.candidates(tx, threads)
+		if err != nil {
+			return nil, err
+		}
+		r = intersect(r, h)
+	}
+	return r, nil
+}
+
+func (n andNode) terms() []string {
+	var r []string
+	for _, nn := range n {
+		r = append(r, nn.terms()...)
+	}
+	return r
+}
+
+func (n andNode) String() string {
+	s := make([]string, len(n))
+	for i, nn := range n {
+		s[i] = nn.String()
+	}
+	return "(AND " + strings.Join(s, " ") + ")"
+}
+
+//orNode matches if one of nodes matches.
+type orNode []Node
+
+func (n orNode) match(d *doc) bool {
+	for _, nn := range n {
+		if nn.match(d) {
+			return true
+		}
+	}
+	return false
+}
+
+func (n orNode) candidates(tx db.Tx, threads bool) (hits, error) {
+	r := make(hits)
+	for _, nn := range n {
+		h, err := nn.candidates(tx, threads)
+		if err != nil {
+			return nil, err
+		}
+		if r = union(r, h); r == nil {
+			return nil, nil
+		}
+	}
+	return r, nil
+}
+
+func (n orNode) terms() []string {
+	return andNode(n).terms()
+}
+
+func (n orNode) String() string {
+	s := make([]string, len(n))
+	for i, nn := range n {
+		s[i] = nn.String()
+	}
+	return "(OR " + strings.Join(s, " ") + ")"
+}
+
+//notNode matches if node doesn't match.
+type notNode struct {
+	node Node
+}
+
+func (n *notNode) match(d *doc) bool {
+	return !n.node.match(d)
+}
+
+func (n *notNode) candidates(tx db.Tx, threads bool) (hits, error) {
+	return nil, nil
+}
+
+func (n *notNode) terms() []string {
+	return nil
+}
+
+func (n *notNode) String() string {
+	return "(NOT " + n.node.String() + ")"
+}
+
+//textNode matches records which have str as a phrase, or threads whose title has it.
+type textNode struct {
+	str   string
+	words []string
+}
+
+func (n *textNode) match(d *doc) bool {
+	if d.rec == nil {
+		return hasPhrase(d.titleTerms(), n.words)
+	}
+	return hasPhrase(d.text, n.words)
+}
+
+func (n *textNode) candidates(tx db.Tx, threads bool) (hits, error) {
+	var r hits
+	for _, w := range n.words {
+		var h hits
+		if threads {
+			h = threadHits(record.TitleThreadsTX(tx, w))
+		} else {
+			var err error
+			if h, err = record.TermHitsTX(tx, w); err != nil {
+				return nil, err
+			}
+		}
+		r = intersect(r, h)
+	}
+	return r, nil
+}
+
+func (n *textNode) terms() []string {
+	return n.words
+}
+
+func (n *textNode) String() string {
+	return fmt.Sprintf("%q", n.str)
+}
+
+//fieldNode matches records or threads by field filter.
+type fieldNode struct {
+	field string
+	value string
+	words []string
+	stamp int64
+}
+
+func (n *fieldNode) match(d *doc) bool {
+	switch n.field {
+	case "tag":
+		return user.HasTX(d.tx, d.datfile, n.value) || suggest.HasTagstrTX(d.tx, d.datfile, n.value)
+	case "thread":
+		return hasPhrase(d.titleTerms(), n.words)
+	}
+	if d.rec == nil {
+		return false
+	}
+	switch n.field {
+	case "name":
+		return strings.Contains(strings.ToLower(d.value("name")), n.value)
+	case "pubkey":
+		return d.rec.Pubkey() != "" && (strings.HasPrefix(d.rec.Pubkey(), n.value) || d.rec.ShortPubkey() == n.value)
+	case "since":
+		return d.rec.Stamp >= n.stamp
+	case "until":
+		return d.rec.Stamp <= n.stamp
+	case "has":
+		return d.rec.HasBodyValue("attach")
+	case "suffix":
+		return strings.ToLower(d.value("suffix")) == n.value
+	}
+	return false
+}
+
+func (n *fieldNode) candidates(tx db.Tx, threads bool) (hits, error) {
+	var h hits
+	switch n.field {
+	case "tag":
+		h = threadHits(append(user.ThreadsTX(tx, n.value), suggest.ThreadsTX(tx, n.value)...))
+	case "thread":
+		for _, w := range n.words {
+			h = intersect(h, threadHits(record.TitleThreadsTX(tx, w)))
+		}
+	default:
+		if threads {
+			return hits{}, nil
+		}
+		return nil, nil
+	}
+	if threads {
+		return h, nil
+	}
+	return recordHits(tx, h), nil
+}
+
+func (n *fieldNode) terms() []string {
+	return nil
+}
+
+func (n *fieldNode) String() string {
+	return fmt.Sprintf("%s:%q", n.field, n.value)
+}
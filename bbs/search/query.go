@@ -0,0 +1,374 @@
+/*
+ * Copyright (c) 2015, Shinya Yagyu
+ * All rights reserved.
+ * Redistribution and use in source and binary forms, with or without
+ * modification, are permitted provided that the following conditions are met:
+ *
+ * 1. Redistributions of source code must retain the above copyright notice,
+ *    this list of conditions and the following disclaimer.
+ * 2. Redistributions in binary form must reproduce the above copyright notice,
+ *    this list of conditions and the following disclaimer in the documentation
+ *    and/or other materials provided with the distribution.
+ * 3. Neither the name of the copyright holder nor the names of its
+ *    contributors may be used to endorse or promote products derived from this
+ *    software without specific prior written permission.
+ *
+ * THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+ * AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+ * IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+ * ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+ * LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+ * CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+ * SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+ * INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+ * CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+ * ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+ * POSSIBILITY OF SUCH DAMAGE.
+ */
+package search
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"bbs/util"
+)
+
+/*
+query syntax
+
+query   := or
+or      := and ("OR" and)*
+and     := not (["AND"] not)*
+not     := ("NOT" | "-") not | primary
+primary := "(" or ")" | field ":" value | "phrase" | word
+
+fields:
+name:    substring of name
+pubkey:  prefix of pubkey, or short pubkey
+tag:     user or suggested tag of the thread
+thread:  words in title of the thread
+since:   records posted at/after the date (2006-01-02 or unix time)
+until:   records posted at/before the date
+has:     attach
+suffix:  suffix of the attached file
+*/
+
+const (
+	maxQuery = 256 //max # of runes in query
+	maxDepth = 32  //max nesting level of parens and NOTs
+)
+
+//fields are names of field filters.
+var fields = []string{"name", "pubkey", "tag", "thread", "since", "until", "has", "suffix"}
+
+//token is a lexical unit of query.
+type token struct {
+	kind  int
+	str   string
+	field string
+}
+
+//kinds of tokens.
+const (
+	tWord = iota
+	tPhrase
+	tField
+	tLParen
+	tRParen
+	tOr
+	tAnd
+	tNot
+)
+
+//readValue reads a word or a quoted phrase from rs[i:], and returns it and the next index.
+func readValue(rs []rune, i int) (string, bool, int, error) {
+	if i < len(rs) && rs[i] == '"' {
+		j := i + 1
+		for j < len(rs) && rs[j] != '"' {
+			j++
+		}
+		if j == len(rs) {
+			return "", false, j, errors.New("unterminated quote")
+		}
+		return string(rs[i+1 : j]), true, j + 1, nil
+	}
+	j := i
+	for j < len(rs) && !unicode.IsSpace(rs[j]) && rs[j] != '(' && rs[j] != ')' {
+		j++
+	}
+	return string(rs[i:j]), false, j, nil
+}
+
+//readField returns the field name and the index after ":" if rs[i:] starts with "field:".
+func readField(rs []rune, i int) (string, int) {
+	j := i
+	for j < len(rs) && unicode.IsLetter(rs[j]) {
+		j++
+	}
+	if j == len(rs) || rs[j] != ':' {
+		return "", i
+	}
+	f := strings.ToLower(string(rs[i:j]))
+	if !util.HasString(fields, f) {
+		return "", i
+	}
+	return f, j + 1
+}
+
+//lex splits query into tokens.
+func lex(query string) ([]*token, error) {
+	var ts []*token
+	rs := []rune(query)
+	for i := 0; i < len(rs); {
+		switch {
+		case unicode.IsSpace(rs[i]):
+			i++
+			continue
+		case rs[i] == '(':
+			ts = append(ts, &token{kind: tLParen})
+			i++
+			continue
+		case rs[i] == ')':
+			ts = append(ts, &token{kind: tRParen})
+			i++
+			continue
+		case rs[i] == '-':
+			ts = append(ts, &token{kind: tNot})
+			i++
+			continue
+		}
+		if f, j := readField(rs, i); f != "" {
+			for j < len(rs) && unicode.IsSpace(rs[j]) {
+				j++
+			}
+			v, _, j, err := readValue(rs, j)
+			if err != nil {
+				return nil, err
+			}
+			if v == "" {
+				return nil, fmt.Errorf("no value for %s:", f)
+			}
+			ts = append(ts, &token{kind: tField, field: f, str: v})
+			i = j
+			continue
+		}
+		v, quoted, j, err := readValue(rs, i)
+		if err != nil {
+			return nil, err
+		}
+		i = j
+		switch {
+		case quoted:
+			ts = append(ts, &token{kind: tPhrase, str: v})
+		case v == "OR":
+			ts = append(ts, &token{kind: tOr})
+		case v == "AND":
+			ts = append(ts, &token{kind: tAnd})
+		case v == "NOT":
+			ts = append(ts, &token{kind: tNot})
+		default:
+			ts = append(ts, &token{kind: tWord, str: v})
+		}
+	}
+	return ts, nil
+}
+
+//parser parses tokens into Node by recursive descent.
+type parser struct {
+	ts    []*token
+	pos   int
+	depth int
+}
+
+//peek returns the current token or nil if no tokens remain.
+func (p *parser) peek() *token {
+	if p.pos >= len(p.ts) {
+		return nil
+	}
+	return p.ts[p.pos]
+}
+
+//Parse parses query and returns its AST.
+func Parse(query string) (Node, error) {
+	if len([]rune(query)) > maxQuery {
+		return nil, fmt.Errorf("query is longer than %d characters", maxQuery)
+	}
+	ts, err := lex(query)
+	if err != nil {
+		return nil, err
+	}
+	if len(ts) == 0 {
+		return nil, errors.New("empty query")
+	}
+	p := &parser{ts: ts}
+	n, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t != nil {
+		return nil, errors.New("unexpected ')'")
+	}
+	return n, nil
+}
+
+//enter increments the nesting level and returns an error if it is too deep.
+func (p *parser) enter() error {
+	p.depth++
+	if p.depth > maxDepth {
+		return errors.New("query is nested too deeply")
+	}
+	return nil
+}
+
+//leave decrements the nesting level.
+func (p *parser) leave() {
+	p.depth--
+}
+
+func (p *parser) parseOr() (Node, error) {
+	n, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	or := orNode{n}
+	for t := p.peek(); t != nil && t.kind == tOr; t = p.peek() {
+		p.pos++
+		if n, err = p.parseAnd(); err != nil {
+			return nil, err
+		}
+		or = append(or, n)
+	}
+	if len(or) == 1 {
+		return or[0], nil
+	}
+	return or, nil
+}
+
+func (p *parser) parseAnd() (Node, error) {
+	n, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	and := andNode{n}
+	for t := p.peek(); t != nil && t.kind != tOr && t.kind != tRParen; t = p.peek() {
+		if t.kind == tAnd {
+			p.pos++
+		}
+		if n, err = p.parseNot(); err != nil {
+			return nil, err
+		}
+		and = append(and, n)
+	}
+	if len(and) == 1 {
+		return and[0], nil
+	}
+	return and, nil
+}
+
+func (p *parser) parseNot() (Node, error) {
+	t := p.peek()
+	if t != nil && t.kind == tNot {
+		p.pos++
+		if err := p.enter(); err != nil {
+			return nil, err
+		}
+		defer p.leave()
+		n, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return &notNode{n}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *parser) parsePrimary() (Node, error) {
+	t := p.peek()
+	if t == nil {
+		return nil, errors.New("unexpected end of query")
+	}
+	p.pos++
+	switch t.kind {
+	case tLParen:
+		if err := p.enter(); err != nil {
+			return nil, err
+		}
+		defer p.leave()
+		n, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if t := p.peek(); t == nil || t.kind != tRParen {
+			return nil, errors.New("missing ')'")
+		}
+		p.pos++
+		return n, nil
+	case tWord, tPhrase:
+		return newTextNode(t.str)
+	case tField:
+		return newFieldNode(t.field, t.str)
+	case tRParen:
+		return nil, errors.New("unexpected ')'")
+	}
+	return nil, errors.New("unexpected operator")
+}
+
+//newTextNode returns a node which matches records having str as a phrase.
+func newTextNode(str string) (Node, error) {
+	terms := util.Tokenize(str)
+	if len(terms) == 0 {
+		return nil, fmt.Errorf("no words in %q", str)
+	}
+	return &textNode{str: str, words: terms}, nil
+}
+
+//newFieldNode returns a node which matches records or threads by field f.
+func newFieldNode(f, v string) (Node, error) {
+	n := &fieldNode{field: f, value: strings.ToLower(v)}
+	var err error
+	switch f {
+	case "pubkey", "tag":
+		n.value = v
+	case "thread":
+		n.words = util.Tokenize(v)
+		if len(n.words) == 0 {
+			return nil, fmt.Errorf("no words in %q", v)
+		}
+	case "since":
+		n.stamp, err = parseDate(v, false)
+	case "until":
+		n.stamp, err = parseDate(v, true)
+	case "has":
+		if n.value != "attach" {
+			err = fmt.Errorf("unknown value has:%s", v)
+		}
+	}
+	return n, err
+}
+
+//parseDate parses v as date (2006-01-02 or 2006-01-02T15:04) in local time or unix time.
+//if end, returns the end of the date.
+func parseDate(v string, end bool) (int64, error) {
+	if stamp, err := strconv.ParseInt(v, 10, 64); err == nil {
+		return stamp, nil
+	}
+	if t, err := time.ParseInLocation("2006-01-02T15:04", v, time.Local); err == nil {
+		if end {
+			t = t.Add(time.Minute - time.Second)
+		}
+		return t.Unix(), nil
+	}
+	t, err := time.ParseInLocation("2006-01-02", v, time.Local)
+	if err != nil {
+		return 0, fmt.Errorf("bad date %q", v)
+	}
+	if end {
+		t = t.AddDate(0, 0, 1).Add(-time.Second)
+	}
+	return t.Unix(), nil
+}
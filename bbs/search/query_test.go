@@ -0,0 +1,97 @@
+/*
+ * Copyright (c) 2015, Shinya Yagyu
+ * All rights reserved.
+ * Redistribution and use in source and binary forms, with or without
+ * modification, are permitted provided that the following conditions are met:
+ *
+ * 1. Redistributions of source code must retain the above copyright notice,
+ *    this list of conditions and the following disclaimer.
+ * 2. Redistributions in binary form must reproduce the above copyright notice,
+ *    this list of conditions and the following disclaimer in the documentation
+ *    and/or other materials provided with the distribution.
+ * 3. Neither the name of the copyright holder nor the names of its
+ *    contributors may be used to endorse or promote products derived from this
+ *    software without specific prior written permission.
+ *
+ * THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+ * AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+ * IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+ * ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+ * LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+ * CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+ * SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+ * INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+ * CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+ * ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+ * POSSIBILITY OF SUCH DAMAGE.
+ */
+package search
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := map[string]string{
+		`foo bar`:                   `(AND "foo" "bar")`,
+		`foo AND bar OR baz`:        `(OR (AND "foo" "bar") "baz")`,
+		`"foo bar" -baz`:            `(AND "foo bar" (NOT "baz"))`,
+		`NOT (a OR b) name:Gou`:     `(AND (NOT (OR "a" "b")) name:"gou")`,
+		`tag:News thread:"東京 話"`:    `(AND tag:"News" thread:"東京 話")`,
+		`has:attach suffix:PNG`:     `(AND has:"attach" suffix:"png")`,
+		`since:2015-01-02 until: 0`: `(AND since:"2015-01-02" until:"0")`,
+		`url:foo`:                   `"url:foo"`,
+	}
+	for q, ast := range tests {
+		n, err := Parse(q)
+		if err != nil {
+			t.Fatal(q, err)
+		}
+		if n.String() != ast {
+			t.Fatal(q, "parsed to", n.String())
+		}
+	}
+	for _, q := range []string{"", "(foo", "foo)", `"foo`, "name:", "has:sign", "since:yesterday", "OR", "---"} {
+		if _, err := Parse(q); err == nil {
+			t.Fatal(q, "should be error")
+		}
+	}
+	for _, q := range []string{
+		strings.Repeat("a ", maxQuery/2) + "b",
+		strings.Repeat("(", 5000000) + "x",
+		strings.Repeat("(", maxDepth+1) + "x" + strings.Repeat(")", maxDepth+1),
+		strings.Repeat("-", maxDepth+1) + "x",
+	} {
+		if _, err := Parse(q); err == nil {
+			t.Fatal(len(q), "runes should be error")
+		}
+	}
+	for _, q := range []string{
+		strings.Repeat("(", maxDepth) + "x" + strings.Repeat(")", maxDepth),
+		strings.Repeat("-", maxDepth) + "x",
+	} {
+		if _, err := Parse(q); err != nil {
+			t.Fatal(q, err)
+		}
+	}
+}
+
+func TestHasPhrase(t *testing.T) {
+	terms := []string{"東京", "京都", "gou"}
+	tests := []struct {
+		phrase []string
+		has    bool
+	}{
+		{[]string{"東京", "京都"}, true},
+		{[]string{"京都", "東京"}, false},
+		{[]string{"京"}, true},
+		{[]string{"大"}, false},
+		{[]string{"g"}, false},
+	}
+	for _, test := range tests {
+		if hasPhrase(terms, test.phrase) != test.has {
+			t.Fatal(test.phrase, "should be", test.has)
+		}
+	}
+}
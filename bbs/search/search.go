@@ -0,0 +1,189 @@
+/*
+ * Copyright (c) 2015, Shinya Yagyu
+ * All rights reserved.
+ * Redistribution and use in source and binary forms, with or without
+ * modification, are permitted provided that the following conditions are met:
+ *
+ * 1. Redistributions of source code must retain the above copyright notice,
+ *    this list of conditions and the following disclaimer.
+ * 2. Redistributions in binary form must reproduce the above copyright notice,
+ *    this list of conditions and the following disclaimer in the documentation
+ *    and/or other materials provided with the distribution.
+ * 3. Neither the name of the copyright holder nor the names of its
+ *    contributors may be used to endorse or promote products derived from this
+ *    software without specific prior written permission.
+ *
+ * THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+ * AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+ * IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+ * ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+ * LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+ * CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+ * SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+ * INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+ * CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+ * ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+ * POSSIBILITY OF SUCH DAMAGE.
+ */
+package search
+
+import (
+	"errors"
+	"log"
+	"sort"
+
+	"bbs/db"
+	"bbs/myself"
+	"bbs/record"
+	"bbs/thread"
+	"bbs/util"
+)
+
+//maxRecords is the max # of records in search results.
+const maxRecords = 500
+
+//ErrNoTerm is returned if the query has no words, tags or titles to be looked up in the index
+//and scanning all records is not allowed.
+var ErrNoTerm = errors.New("query needs a word, tag: or thread: which is not negated")
+
+//Result is a thread which matches a search query.
+type Result struct {
+	*thread.Cache
+	Title         string
+	Score         float64
+	ThreadMatched bool
+	Hits          []*record.Hit
+}
+
+//matchRecords returns alive records which match n, ordered by score.
+//all records are checked if n cannot be looked up in the index and scan,
+//or ErrNoTerm is returned.
+func matchRecords(my *myself.Myself, tx db.Tx, n Node, scan bool) ([]*record.Hit, error) {
+	cands, err := n.candidates(tx, false)
+	if err != nil {
+		return nil, err
+	}
+	if cands == nil && !scan {
+		return nil, ErrNoTerm
+	}
+	var result []*record.Hit
+	check := func(d *record.DB, h *record.Hit) {
+		if d.Deleted {
+			return
+		}
+		dc, err := newRecordDoc(my, tx, d)
+		if err != nil {
+			log.Println(err)
+			return
+		}
+		if n.match(dc) {
+			result = append(result, h)
+		}
+	}
+	if cands == nil {
+		if !tx.HasBucket("record") {
+			return nil, nil
+		}
+		err = record.ForEach(tx, func(d *record.DB) error {
+			//d is reused in ForEach.
+			h := *d.Head
+			check(d, &record.Hit{Head: &h})
+			return nil
+		})
+	}
+	for _, h := range cands {
+		d, errr := record.GetFromDB(tx, h.Head)
+		if errr != nil {
+			log.Println(errr)
+			continue
+		}
+		check(d, h)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Score != result[j].Score {
+			return result[i].Score > result[j].Score
+		}
+		return result[i].Stamp > result[j].Stamp
+	})
+	return result, err
+}
+
+//matchThreads returns threads which match n by their titles and tags.
+//threads are not matched if n cannot be looked up in titles and tags, e.g. by negations
+//or fields of records only.
+func matchThreads(tx db.Tx, n Node) ([]string, error) {
+	cands, err := n.candidates(tx, true)
+	if err != nil {
+		return nil, err
+	}
+	var result []string
+	for datfile := range cands {
+		if n.match(newThreadDoc(tx, datfile)) {
+			result = append(result, datfile)
+		}
+	}
+	return result, nil
+}
+
+//Run parses query and returns threads which match it by records, titles or tags,
+//ordered by score with matched records and their snippets.
+//threads which match by titles or tags come first.
+//all records are scanned for queries which cannot be looked up in the index only if scan.
+func Run(my *myself.Myself, query string, scan bool) ([]*Result, error) {
+	n, err := Parse(query)
+	if err != nil {
+		return nil, err
+	}
+	var hits []*record.Hit
+	var datfiles []string
+	err = my.DB.View(func(tx db.Tx) error {
+		var err error
+		if hits, err = matchRecords(my, tx, n, scan); err != nil {
+			return err
+		}
+		if len(hits) > maxRecords {
+			hits = hits[:maxRecords]
+		}
+		terms := n.terms()
+		for _, h := range hits {
+			if err := h.Load(tx, terms); err != nil {
+				log.Println(err)
+			}
+		}
+		datfiles, err = matchThreads(tx, n)
+		return err
+	})
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+	var result []*Result
+	threads := make(map[string]*Result)
+	get := func(datfile string) *Result {
+		r, ok := threads[datfile]
+		if !ok {
+			r = &Result{
+				Cache: thread.NewCache(my, datfile),
+				Title: util.FileDecode(datfile),
+			}
+			threads[datfile] = r
+			result = append(result, r)
+		}
+		return r
+	}
+	for _, h := range hits {
+		r := get(h.Datfile)
+		r.Score += h.Score
+		r.Hits = append(r.Hits, h)
+	}
+	for _, datfile := range datfiles {
+		get(datfile).ThreadMatched = true
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		if result[i].ThreadMatched != result[j].ThreadMatched {
+			return result[i].ThreadMatched
+		}
+		return result[i].Score > result[j].Score
+	})
+	return result, nil
+}
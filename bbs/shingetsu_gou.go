@@ -34,44 +34,43 @@ import (
 	"log"
 	"time"
 
-	"net"
-
 	"bbs/cfg"
 	"bbs/db"
 	"bbs/gou"
 )
 
-var listener net.Listener
+var config *cfg.Config
+var instance *gou.Instance
 var ch chan error
 
 //ExpandFiles expands files in files dir.
 func ExpandFiles(rpath string,location string,timeoffset int) {
 	time.Local = time.FixedZone(location, timeoffset)
 	cfg.SetAndroid(rpath)
-	cfg.Parse()
-	gou.SetupDirectories()
-	gou.SetLogger(true, false)
+	config = cfg.Parse()
+	gou.SetupDirectories(config)
+	gou.SetLogger(config, true, false)
 	log.Println("********************starting Gou", cfg.Version, "...******************")
-	gou.ExpandAssets()
+	gou.ExpandAssets(config)
 }
 
 //Port returns port number.
 func Port() int {
-	return cfg.DefaultPort
+	return config.DefaultPort
 }
 
 //Run setups params and start daemon for android.
 //You must call ExpandFiles beforehand.
 func Run() {
-	db.Setup()
-	listener, ch = gou.StartDaemon()
+	instance, ch = gou.StartDaemon(config, db.Setup(config))
 }
 
 //Stop stops the http server.
 func Stop() {
-	if listener != nil {
-		listener.Close()
-		db.DB.Close()
+	if instance != nil {
+		if err := instance.Close(); err != nil {
+			log.Println(err)
+		}
 		log.Println(<-ch)
 	}
 }
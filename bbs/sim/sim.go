@@ -0,0 +1,348 @@
+/*
+ * Copyright (c) 2015, Shinya Yagyu
+ * All rights reserved.
+ * Redistribution and use in source and binary forms, with or without
+ * modification, are permitted provided that the following conditions are met:
+ *
+ * 1. Redistributions of source code must retain the above copyright notice,
+ *    this list of conditions and the following disclaimer.
+ * 2. Redistributions in binary form must reproduce the above copyright notice,
+ *    this list of conditions and the following disclaimer in the documentation
+ *    and/or other materials provided with the distribution.
+ * 3. Neither the name of the copyright holder nor the names of its
+ *    contributors may be used to endorse or promote products derived from this
+ *    software without specific prior written permission.
+ *
+ * THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+ * AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+ * IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+ * ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+ * LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+ * CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+ * SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+ * INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+ * CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+ * ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+ * POSSIBILITY OF SUCH DAMAGE.
+ */
+
+//Package sim runs a network of nodes in one process for testing how they talk with each other.
+//nodes listen on loopback and can talk only with nodes in the same network and partition.
+package sim
+
+import (
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"gopkg.in/ini.v1"
+	"bbs/cfg"
+	"bbs/db"
+	"bbs/gou"
+	"bbs/node"
+	"bbs/node/manager"
+	"bbs/record"
+	"bbs/thread"
+	"bbs/thread/download"
+)
+
+//errPartitioned is returned when dialing a node which cannot be talked with,
+//as if the node refused the connection.
+var errPartitioned = &net.OpError{Op: "dial", Net: "tcp", Err: os.NewSyscallError("connect", syscall.ECONNREFUSED)}
+
+//Network is a set of nodes which run in one process and talk with each other on loopback.
+type Network struct {
+	Nodes []*Node
+
+	dir   string
+	mutex sync.RWMutex
+	group map[string]int //partition group of each addr of nodes. nil if not partitioned
+}
+
+//Node is a gou instance in Network.
+type Node struct {
+	*gou.Instance
+	Addr string //host:port which the node listens on
+
+	nw *Network
+}
+
+//New starts a network of n nodes whose files are in dir.
+//init nodes of each node are all other nodes, but they don't join each other until Join or Bootstrap.
+func New(dir string, n int) (*Network, error) {
+	nw := &Network{
+		dir: dir,
+	}
+	ls := make([]net.Listener, n)
+	for i := range ls {
+		l, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			nw.Close()
+			return nil, err
+		}
+		ls[i] = l
+	}
+	for i, l := range ls {
+		var inits []string
+		for j, ll := range ls {
+			if j != i {
+				inits = append(inits, ll.Addr().String()+cfg.ServerURL)
+			}
+		}
+		if _, err := nw.start(l, inits); err != nil {
+			for _, ll := range ls[i:] {
+				ll.Close()
+			}
+			nw.Close()
+			return nil, err
+		}
+	}
+	return nw, nil
+}
+
+//Add starts a new node in nw whose init nodes are all nodes in nw.
+func (nw *Network) Add() (*Node, error) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, err
+	}
+	var inits []string
+	for _, n := range nw.Nodes {
+		inits = append(inits, n.Node().Nodestr)
+	}
+	return nw.start(l, inits)
+}
+
+//start makes a node which listens on l, with init nodes inits and a memory db, and serves it.
+func (nw *Network) start(l net.Listener, inits []string) (*Node, error) {
+	host, port, err := net.SplitHostPort(l.Addr().String())
+	if err != nil {
+		l.Close()
+		return nil, err
+	}
+	d := filepath.Join(nw.dir, strconv.Itoa(len(nw.Nodes)))
+	for _, sub := range []string{"www", "file", "run"} {
+		if err := os.MkdirAll(filepath.Join(d, sub), 0755); err != nil {
+			l.Close()
+			return nil, err
+		}
+	}
+	initnode := []byte(strings.Join(inits, "\n"))
+	if err := ioutil.WriteFile(filepath.Join(d, "file", "initnode.txt"), initnode, 0644); err != nil {
+		l.Close()
+		return nil, err
+	}
+	i := ini.Empty()
+	i.Section("Network").Key("port").SetValue(port)
+	i.Section("Path").Key("docroot").SetValue(filepath.Join(d, "www"))
+	i.Section("Gateway").Key("server_name").SetValue(host)
+
+	n := &Node{
+		Instance: gou.NewInstance(cfg.New(i), db.NewMemory()),
+		Addr:     l.Addr().String(),
+		nw:       nw,
+	}
+	n.My.Dial = func(network, addr string, timeout time.Duration) (net.Conn, error) {
+		if !nw.canTalk(n.Addr, addr) {
+			return nil, errPartitioned
+		}
+		return net.DialTimeout(network, addr, timeout)
+	}
+	go func() {
+		if err := n.Serve(l); err != nil {
+			log.Println(n.Addr, err)
+		}
+	}()
+	nw.mutex.Lock()
+	nw.Nodes = append(nw.Nodes, n)
+	nw.mutex.Unlock()
+	return n, nil
+}
+
+//Close stops all nodes in nw.
+func (nw *Network) Close() {
+	for _, n := range nw.Nodes {
+		if err := n.Close(); err != nil {
+			log.Println(err)
+		}
+	}
+}
+
+//canTalk returns true if node whose addr is from can dial addr to.
+//to must be a node in nw, and in the same group as from if partitioned.
+func (nw *Network) canTalk(from, to string) bool {
+	nw.mutex.RLock()
+	defer nw.mutex.RUnlock()
+	found := false
+	for _, n := range nw.Nodes {
+		if n.Addr == to {
+			found = true
+		}
+	}
+	if !found {
+		return false
+	}
+	if nw.group == nil {
+		return true
+	}
+	gf, okf := nw.group[from]
+	gt, okt := nw.group[to]
+	return okf && okt && gf == gt
+}
+
+//Partition splits nodes into groups. nodes in different groups cannot talk with each other,
+//and nodes which are not in any groups cannot talk with any nodes.
+func (nw *Network) Partition(groups ...[]*Node) {
+	nw.mutex.Lock()
+	defer nw.mutex.Unlock()
+	nw.group = make(map[string]int)
+	for i, g := range groups {
+		for _, n := range g {
+			nw.group[n.Addr] = i
+		}
+	}
+}
+
+//Heal removes partitions.
+func (nw *Network) Heal() {
+	nw.mutex.Lock()
+	nw.group = nil
+	nw.mutex.Unlock()
+}
+
+//Mesh makes all nodes join each other.
+func (nw *Network) Mesh() {
+	for _, n := range nw.Nodes {
+		for _, nn := range nw.Nodes {
+			if n != nn {
+				n.Join(nn)
+			}
+		}
+	}
+}
+
+//Subscribe makes all nodes subscribe the thread datfile.
+func (nw *Network) Subscribe(datfile string) {
+	for _, n := range nw.Nodes {
+		n.Subscribe(datfile)
+	}
+}
+
+//Download makes all nodes download records of all threads they subscribe.
+func (nw *Network) Download() {
+	var wg sync.WaitGroup
+	for _, n := range nw.Nodes {
+		wg.Add(1)
+		go func(n *Node) {
+			defer wg.Done()
+			n.Download()
+		}(n)
+	}
+	wg.Wait()
+}
+
+//Converged returns true if all nodes have the same records in datfile, which are not removed.
+func (nw *Network) Converged(datfile string) bool {
+	var recs string
+	for i, n := range nw.Nodes {
+		r := fmt.Sprint(n.Records(datfile))
+		if i > 0 && r != recs {
+			return false
+		}
+		recs = r
+	}
+	return true
+}
+
+//Wait calls cond until it returns true or timeout, and returns the last result.
+func Wait(timeout time.Duration, cond func() bool) bool {
+	end := time.Now().Add(timeout)
+	for !cond() {
+		if time.Now().After(end) {
+			return false
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	return true
+}
+
+//Node returns node info of n which other nodes use.
+func (n *Node) Node() *node.Node {
+	return node.Me(n.My, true)
+}
+
+//Join makes n join nn, and returns true if welcomed.
+func (n *Node) Join(nn *Node) bool {
+	return manager.Join(n.My, nn.Node())
+}
+
+//Bootstrap makes n join its init nodes.
+func (n *Node) Bootstrap() {
+	manager.Initialize(n.My, node.NewSlice(n.My.Cfg.InitNode.GetData()))
+}
+
+//Bye makes n say bye to nn and removes nn from the nodelist of n,
+//and returns true if nn replied.
+func (n *Node) Bye(nn *Node) bool {
+	manager.RemoveFromList(n.My, nn.Node())
+	return nn.Node().Bye(n.My)
+}
+
+//Subscribe makes n subscribe the thread datfile.
+func (n *Node) Subscribe(datfile string) {
+	thread.NewCache(n.My, datfile).Subscribe()
+}
+
+//Post saves a record in datfile which has body, signed with passwd if not empty,
+//and tells it to other nodes in background as posted from thread.cgi.
+func (n *Node) Post(datfile string, body map[string]string, passwd string) *record.Record {
+	rec := record.New(n.My, datfile, "", 0)
+	rec.Build(time.Now().Unix(), body, passwd)
+	rec.Sync()
+	go n.Queue.UpdateNodes(rec, nil)
+	return rec
+}
+
+//Remove posts a record which requests to remove rec, signed with passwd.
+//rec is removed if passwd is same as one of rec.
+func (n *Node) Remove(rec *record.Record, passwd string) *record.Record {
+	return n.Post(rec.Datfile, map[string]string{
+		"remove_stamp": strconv.FormatInt(rec.Stamp, 10),
+		"remove_id":    rec.LegacyID(),
+	}, passwd)
+}
+
+//Download gets records of all threads n subscribes from other nodes.
+func (n *Node) Download() {
+	download.Getall(n.My)
+}
+
+//Records returns sorted idstrs of records in datfile which are not removed.
+func (n *Node) Records(datfile string) []string {
+	recs := thread.NewCache(n.My, datfile).LoadRecords(record.Alive)
+	ids := make([]string, 0, len(recs))
+	for _, r := range recs {
+		ids = append(ids, r.Idstr())
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+//Has returns true if n has rec which is not removed.
+func (n *Node) Has(rec *record.Record) bool {
+	for _, id := range n.Records(rec.Datfile) {
+		if id == rec.Idstr() {
+			return true
+		}
+	}
+	return false
+}
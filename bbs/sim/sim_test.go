@@ -0,0 +1,316 @@
+/*
+ * Copyright (c) 2015, Shinya Yagyu
+ * All rights reserved.
+ * Redistribution and use in source and binary forms, with or without
+ * modification, are permitted provided that the following conditions are met:
+ *
+ * 1. Redistributions of source code must retain the above copyright notice,
+ *    this list of conditions and the following disclaimer.
+ * 2. Redistributions in binary form must reproduce the above copyright notice,
+ *    this list of conditions and the following disclaimer in the documentation
+ *    and/or other materials provided with the distribution.
+ * 3. Neither the name of the copyright holder nor the names of its
+ *    contributors may be used to endorse or promote products derived from this
+ *    software without specific prior written permission.
+ *
+ * THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+ * AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+ * IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+ * ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+ * LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+ * CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+ * SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+ * INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+ * CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+ * ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+ * POSSIBILITY OF SUCH DAMAGE.
+ */
+
+package sim
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"bbs/cfg"
+	"bbs/node/manager"
+	"bbs/record"
+	"bbs/updateque"
+	"bbs/util"
+)
+
+const timeout = 30 * time.Second
+
+var datfile = util.FileEncode("thread", "sim")
+
+//newNetwork starts a network of n nodes, and returns it and a func which closes it.
+func newNetwork(t *testing.T, n int) (*Network, func()) {
+	dir, err := ioutil.TempDir("", "gou")
+	if err != nil {
+		t.Fatal(err)
+	}
+	nw, err := New(dir, n)
+	if err != nil {
+		os.RemoveAll(dir)
+		t.Fatal(err)
+	}
+	return nw, func() {
+		nw.Close()
+		os.RemoveAll(dir)
+	}
+}
+
+//talk requests path of server.cgi in n and returns lines of the response.
+func talk(t *testing.T, n *Node, path string) []string {
+	res, err := http.Get("http://" + n.Addr + cfg.ServerURL + path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+	b, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := strings.TrimSpace(string(b))
+	if s == "" {
+		return nil
+	}
+	return strings.Split(s, "\n")
+}
+
+//expect fails if lines are not same as expected.
+func expect(t *testing.T, path string, lines []string, expected ...string) {
+	t.Helper()
+	if fmt.Sprint(lines) != fmt.Sprint(expected) {
+		t.Fatalf("%s: %q, expected %q", path, lines, expected)
+	}
+}
+
+func TestProtocol(t *testing.T) {
+	nw, done := newNetwork(t, 2)
+	defer done()
+	a, b := nw.Nodes[0], nw.Nodes[1]
+	expect(t, "/ping", talk(t, b, "/ping"), "PONG", "127.0.0.1")
+	expect(t, "/node", talk(t, b, "/node"), a.Node().Nodestr)
+
+	join := "/join/" + a.Node().Toxstring()
+	expect(t, join, talk(t, b, join), "WELCOME")
+	if !util.HasString(manager.GetNodestrSliceInList(b.My), a.Node().Nodestr) {
+		t.Fatal("joined node is not in the nodelist")
+	}
+	expect(t, "/node", talk(t, b, "/node"), a.Node().Nodestr)
+
+	nw.Subscribe(datfile)
+	expect(t, "/have", talk(t, a, "/have/"+datfile), "NO")
+	rec := a.Post(datfile, map[string]string{"body": "hello"}, "")
+	expect(t, "/have", talk(t, a, "/have/"+datfile), "YES")
+	expect(t, "/have", talk(t, b, "/have/"+datfile), "NO")
+
+	head := fmt.Sprintf("%d<>%s", rec.Stamp, rec.ID)
+	expect(t, "/head", talk(t, a, "/head/"+datfile), head)
+	expect(t, "/head", talk(t, a, fmt.Sprintf("/head/%s/%d-", datfile, rec.Stamp+1)))
+	get := fmt.Sprintf("/get/%s/%d/%s", datfile, rec.Stamp, rec.ID)
+	expect(t, get, talk(t, a, get), rec.Recstr())
+	get = fmt.Sprintf("/get/%s/%d-%d", datfile, rec.Stamp, rec.Stamp)
+	expect(t, get, talk(t, a, get), rec.Recstr())
+
+	update := fmt.Sprintf("/update/%s/%d/%s/%s", datfile, rec.Stamp, rec.ID, a.Node().Toxstring())
+	expect(t, update, talk(t, b, update), "OK")
+	if !Wait(timeout, func() bool { return b.Has(rec) }) {
+		t.Fatal("updated record was not gotten")
+	}
+	if !util.HasString(manager.GetNodestrSliceInTable(b.My, datfile), a.Node().Nodestr) {
+		t.Fatal("updating node is not in the lookup table")
+	}
+	recent := rec.LegacyHead().Recstr()
+	if !Wait(timeout, func() bool { return util.HasString(talk(t, b, "/recent/0-"), recent) }) {
+		t.Fatal("/recent doesn't have", recent, talk(t, b, "/recent/0-"))
+	}
+	expect(t, "/recent", talk(t, b, fmt.Sprintf("/recent/%d-", rec.Stamp+1)))
+
+	bye := "/bye/" + a.Node().Toxstring()
+	expect(t, bye, talk(t, b, bye), "BYEBYE")
+	if util.HasString(manager.GetNodestrSliceInList(b.My), a.Node().Nodestr) {
+		t.Fatal("node which said bye is in the nodelist")
+	}
+}
+
+func TestConvergence(t *testing.T) {
+	nw, done := newNetwork(t, 4)
+	defer done()
+	for i := 0; i < len(nw.Nodes)-1; i++ {
+		if !nw.Nodes[i].Join(nw.Nodes[i+1]) {
+			t.Fatal("failed to join")
+		}
+	}
+	nw.Subscribe(datfile)
+	var recs []*record.Record
+	for i := 0; i < 3; i++ {
+		recs = append(recs, nw.Nodes[0].Post(datfile, map[string]string{"body": fmt.Sprint("post", i)}, ""))
+	}
+	ok := Wait(timeout, func() bool {
+		return nw.Converged(datfile) && len(nw.Nodes[3].Records(datfile)) == len(recs)
+	})
+	if !ok {
+		t.Fatal("not converged", nw.Nodes[3].Records(datfile))
+	}
+}
+
+func TestPartition(t *testing.T) {
+	nw, done := newNetwork(t, 4)
+	defer done()
+	nw.Mesh()
+	nw.Subscribe(datfile)
+	n := nw.Nodes
+	nw.Partition(n[:2], n[2:])
+	r1 := n[0].Post(datfile, map[string]string{"body": "r1"}, "")
+	r2 := n[2].Post(datfile, map[string]string{"body": "r2"}, "")
+	if !Wait(timeout, func() bool { return n[1].Has(r1) && n[3].Has(r2) }) {
+		t.Fatal("records were not told in the partitions")
+	}
+	if n[2].Has(r1) || n[3].Has(r1) || n[0].Has(r2) || n[1].Has(r2) {
+		t.Fatal("records were told across the partition")
+	}
+
+	nw.Heal()
+	nw.Download()
+	for _, nn := range n {
+		if !nn.Has(r1) || !nn.Has(r2) {
+			t.Fatal(nn.Addr, "doesn't have records after healed", nn.Records(datfile))
+		}
+	}
+	if !nw.Converged(datfile) {
+		t.Fatal("not converged")
+	}
+}
+
+func TestRemoval(t *testing.T) {
+	const passwd = "secret"
+	nw, done := newNetwork(t, 3)
+	defer done()
+	nw.Mesh()
+	nw.Subscribe(datfile)
+	n := nw.Nodes
+	r := n[0].Post(datfile, map[string]string{"body": "to be removed"}, passwd)
+	if !Wait(timeout, func() bool { return n[1].Has(r) && n[2].Has(r) }) {
+		t.Fatal("record was not told")
+	}
+
+	late, err := nw.Add()
+	if err != nil {
+		t.Fatal(err)
+	}
+	nw.Partition([]*Node{n[0], n[1], late}, []*Node{n[2]})
+	rm := n[0].Remove(r, passwd)
+	if n[0].Has(r) || !n[0].Has(rm) {
+		t.Fatal("record was not removed")
+	}
+	if !Wait(timeout, func() bool { return !n[1].Has(r) && n[1].Has(rm) }) {
+		t.Fatal("removal was not told")
+	}
+	if !n[2].Has(r) {
+		t.Fatal("record was removed across the partition")
+	}
+	late.Join(n[0])
+	late.Subscribe(datfile)
+	late.Download()
+	if !late.Has(rm) || late.Has(r) {
+		t.Fatal("failed to download the removal", late.Records(datfile))
+	}
+
+	//late gets the removed record from n[2] after the removal,
+	//and n[2] gets the removal after the record.
+	nw.Heal()
+	late.Join(n[2])
+	late.Download()
+	nw.Download()
+	for _, nn := range nw.Nodes {
+		if nn.Has(r) || !nn.Has(rm) {
+			t.Fatal(nn.Addr, "doesn't keep the record removed", nn.Records(datfile))
+		}
+	}
+}
+
+func TestQueue(t *testing.T) {
+	nw, done := newNetwork(t, 2)
+	defer done()
+	nw.Mesh()
+	nw.Subscribe(datfile)
+	a, b := nw.Nodes[0], nw.Nodes[1]
+	nw.Partition([]*Node{a}, []*Node{b})
+	r1 := a.Post(datfile, map[string]string{"body": "r1"}, "")
+	if !Wait(timeout, func() bool { return len(a.Queue.Items()) == 1 }) {
+		t.Fatal("update was not queued")
+	}
+	it := a.Queue.Items()[0]
+	if it.Kind != updateque.Tell || it.Tries != 1 || it.Next <= time.Now().Unix() {
+		t.Fatal("illegal item", it)
+	}
+	a.Queue.RetryDue()
+	if its := a.Queue.Items(); len(its) != 1 || its[0].Tries != 1 {
+		t.Fatal("item was retried before the next try")
+	}
+	stale := *it
+	a.Queue.Retry(it)
+	a.Queue.Retry(&stale)
+	if its := a.Queue.Items(); len(its) != 1 || its[0].Tries != 2 {
+		t.Fatal("item which was read before the last try was retried", its)
+	}
+	nw.Heal()
+	a.Queue.Retry(it)
+	if !Wait(timeout, func() bool { return b.Has(r1) && len(a.Queue.Items()) == 0 }) {
+		t.Fatal("update was not told by retrying", a.Queue.Items())
+	}
+
+	nw.Partition([]*Node{a}, []*Node{b})
+	r2 := a.Post(datfile, map[string]string{"body": "r2"}, "")
+	update := fmt.Sprintf("/update/%s/%d/%s/%s", datfile, r2.Stamp, r2.ID, a.Node().Toxstring())
+	expect(t, update, talk(t, b, update), "OK")
+	if !Wait(timeout, func() bool {
+		its := b.Queue.Items()
+		return len(its) == 1 && its[0].Kind == updateque.Fetch && its[0].Err != ""
+	}) {
+		t.Fatal("failed fetch was not queued", b.Queue.Items())
+	}
+	nw.Heal()
+	b.Queue.Retry(b.Queue.Items()[0])
+	if !b.Has(r2) || len(b.Queue.Items()) != 0 {
+		t.Fatal("record was not gotten by retrying")
+	}
+	if !Wait(timeout, func() bool { return len(a.Queue.Items()) == 0 }) {
+		t.Fatal("gotten update remains in the queue", a.Queue.Items())
+	}
+}
+
+func TestDelivery(t *testing.T) {
+	nw, done := newNetwork(t, 3)
+	defer done()
+	nw.Mesh()
+	nw.Subscribe(datfile)
+	n := nw.Nodes
+	nw.Partition(n[:2], n[2:])
+	r := n[0].Post(datfile, map[string]string{"body": "r"}, "")
+	results := func() map[string]string {
+		m := make(map[string]string)
+		for _, d := range manager.Deliveries(n[0].My, datfile, r.Stamp, r.LegacyID()) {
+			m[d.Node] = d.Result
+		}
+		return m
+	}
+	ok, ng := n[1].Node().Nodestr, n[2].Node().Nodestr
+	if !Wait(timeout, func() bool { return len(results()) == 2 }) {
+		t.Fatal("deliveries were not saved", results())
+	}
+	if m := results(); m[ok] != manager.DeliveryOK || m[ng] != manager.DeliveryRefused {
+		t.Fatal("illegal deliveries", m)
+	}
+	s := manager.Scores(n[0].My, []string{ok, ng})
+	if s[ok].OK == 0 || s[ng].Refused == 0 || s[ok].Rate <= s[ng].Rate {
+		t.Fatal("illegal scores", s[ok], s[ng])
+	}
+}
@@ -31,18 +31,17 @@ package suggest
 import (
 	"log"
 
-	"github.com/boltdb/bolt"
-	"bbs/cfg"
 	"bbs/db"
+	"bbs/myself"
 	"bbs/record"
 	"bbs/tag"
 	"bbs/util"
 )
 
 //Get returns copy of Slice associated with datfile or returns def if not exists.
-func Get(datfile string, def tag.Slice) tag.Slice {
+func Get(my *myself.Myself, datfile string, def tag.Slice) tag.Slice {
 	var r []string
-	err := db.DB.View(func(tx *bolt.Tx) error {
+	err := my.DB.View(func(tx db.Tx) error {
 		var err error
 		r, err = db.MapKeys(tx, "sugtag", []byte(datfile))
 		return err
@@ -57,16 +56,16 @@ func Get(datfile string, def tag.Slice) tag.Slice {
 			Tagstr: rr,
 		}
 	}
-	if len(tags) > cfg.TagSize {
-		tags = tags[:cfg.TagSize]
+	if len(tags) > my.Cfg.TagSize {
+		tags = tags[:my.Cfg.TagSize]
 	}
 	return tag.Slice(tags)
 }
 
 //keys return datfile names of Sugtaglist.
-func keys() []string {
+func keys(s db.Store) []string {
 	var r []string
-	err := db.DB.View(func(tx *bolt.Tx) error {
+	err := s.View(func(tx db.Tx) error {
 		var err error
 		r, err = db.KeyStrings(tx, "sugtag")
 		return err
@@ -79,7 +78,7 @@ func keys() []string {
 }
 
 //AddString adds tags to datfile from tagstrings.
-func AddString(tx *bolt.Tx, datfile string, vals []string) {
+func AddString(tx db.Tx, datfile string, vals []string) {
 	for _, v := range vals {
 		if !tag.IsOK(v) {
 			continue
@@ -91,10 +90,10 @@ func AddString(tx *bolt.Tx, datfile string, vals []string) {
 }
 
 //HasTagstr return true if one of tags has tagstr
-func HasTagstr(datfile string, tagstr string) bool {
+func HasTagstr(my *myself.Myself, datfile string, tagstr string) bool {
 	var r bool
-	err := db.DB.View(func(tx *bolt.Tx) error {
-		r = db.HasVal(tx, "sugtag", []byte(datfile), tagstr)
+	err := my.DB.View(func(tx db.Tx) error {
+		r = HasTagstrTX(tx, datfile, tagstr)
 		return nil
 	})
 	if err != nil {
@@ -103,9 +102,33 @@ func HasTagstr(datfile string, tagstr string) bool {
 	return r
 }
 
+//HasTagstrTX return true if one of tags has tagstr
+func HasTagstrTX(tx db.Tx, datfile string, tagstr string) bool {
+	return db.HasVal(tx, "sugtag", []byte(datfile), tagstr)
+}
+
+//ThreadsTX returns threads which have tagstr in suggested tags.
+func ThreadsTX(tx db.Tx, tagstr string) []string {
+	if !tx.HasBucket("sugtag") {
+		return nil
+	}
+	datfiles, err := db.KeyStrings(tx, "sugtag")
+	if err != nil {
+		log.Println(err)
+		return nil
+	}
+	var r []string
+	for _, datfile := range datfiles {
+		if HasTagstrTX(tx, datfile, tagstr) {
+			r = append(r, datfile)
+		}
+	}
+	return r
+}
+
 //String return tagstr string of datfile.
-func String(datfile string) string {
-	ts := Get(datfile, nil)
+func String(my *myself.Myself, datfile string) string {
+	ts := Get(my, datfile, nil)
 	if ts == nil {
 		return ""
 	}
@@ -114,14 +137,14 @@ func String(datfile string) string {
 
 //Prune removes Sugtaglists which are not listed in recs,
 //or truncates its size to tagsize if listed.
-func Prune(recs []*record.Head) {
-	tmp := keys()
+func Prune(my *myself.Myself, recs []*record.Head) {
+	tmp := keys(my.DB)
 	for _, r := range recs {
 		if l := util.FindString(tmp, r.Datfile); l != -1 {
 			tmp = append(tmp[:l], tmp[l+1:]...)
 		}
 	}
-	err := db.DB.Update(func(tx *bolt.Tx) error {
+	err := my.DB.Update(func(tx db.Tx) error {
 		for _, datfile := range tmp {
 			err := db.Del(tx, "sugtag", []byte(datfile))
 			if err != nil {
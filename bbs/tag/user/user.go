@@ -31,21 +31,20 @@ package user
 import (
 	"log"
 
-	"github.com/boltdb/bolt"
 	"bbs/db"
 	"bbs/tag"
 )
 
 //String  returns string form of usertags.
-func String(thread string) string {
-	tags := GetByThread(thread)
+func String(s db.Store, thread string) string {
+	tags := GetByThread(s, thread)
 	return tags.String()
 }
 
 //Len  returns # of usertags.
-func Len(thread string) int {
+func Len(s db.Store, thread string) int {
 	var r map[string]struct{}
-	err := db.DB.View(func(tx *bolt.Tx) error {
+	err := s.View(func(tx db.Tx) error {
 		var errr error
 		r, errr = db.GetMap(tx, "usertag", []byte(thread))
 		return errr
@@ -57,15 +56,10 @@ func Len(thread string) int {
 }
 
 //Has returns true if thread has the tag.
-func Has(thread string, tag ...string) bool {
+func Has(s db.Store, thread string, tag ...string) bool {
 	rr := false
-	err := db.DB.View(func(tx *bolt.Tx) error {
-		for _, t := range tag {
-			if db.HasVal(tx, "usertag", []byte(thread), t) {
-				rr = true
-				return nil
-			}
-		}
+	err := s.View(func(tx db.Tx) error {
+		rr = HasTX(tx, thread, tag...)
 		return nil
 	})
 	if err != nil {
@@ -74,10 +68,29 @@ func Has(thread string, tag ...string) bool {
 	return rr
 }
 
+//HasTX returns true if thread has the tag.
+func HasTX(tx db.Tx, thread string, tag ...string) bool {
+	for _, t := range tag {
+		if db.HasVal(tx, "usertag", []byte(thread), t) {
+			return true
+		}
+	}
+	return false
+}
+
+//ThreadsTX returns threads which have the tag.
+func ThreadsTX(tx db.Tx, tag string) []string {
+	r, err := db.MapKeys(tx, "usertagTag", []byte(tag))
+	if err != nil {
+		return nil
+	}
+	return r
+}
+
 //Get tags from the disk and returns Slice.
-func Get() tag.Slice {
+func Get(s db.Store) tag.Slice {
 	var r []string
-	err := db.DB.View(func(tx *bolt.Tx) error {
+	err := s.View(func(tx db.Tx) error {
 		var err error
 		r, err = db.KeyStrings(tx, "usertagTag")
 		return err
@@ -89,12 +102,11 @@ func Get() tag.Slice {
 }
 
 //GetStrings gets thread tags from the disk
-func GetStrings(thread string) []string {
+func GetStrings(s db.Store, thread string) []string {
 	var r []string
-	err := db.DB.View(func(tx *bolt.Tx) error {
-		var err error
-		r, err = db.MapKeys(tx, "usertag", []byte(thread))
-		return err
+	err := s.View(func(tx db.Tx) error {
+		r = GetStringsTX(tx, thread)
+		return nil
 	})
 	if err != nil {
 		return nil
@@ -102,15 +114,24 @@ func GetStrings(thread string) []string {
 	return r
 }
 
+//GetStringsTX gets thread tags from the disk
+func GetStringsTX(tx db.Tx, thread string) []string {
+	r, err := db.MapKeys(tx, "usertag", []byte(thread))
+	if err != nil {
+		return nil
+	}
+	return r
+}
+
 //GetByThread gets thread tags from the disk
-func GetByThread(thread string) tag.Slice {
-	r := GetStrings(thread)
+func GetByThread(s db.Store, thread string) tag.Slice {
+	r := GetStrings(s, thread)
 	return tag.NewSlice(r)
 }
 
 //Add saves tag strings.
-func Add(thread string, tag []string) {
-	err := db.DB.Update(func(tx *bolt.Tx) error {
+func Add(s db.Store, thread string, tag []string) {
+	err := s.Update(func(tx db.Tx) error {
 		return AddTX(tx, thread, tag)
 	})
 	if err != nil {
@@ -119,7 +140,7 @@ func Add(thread string, tag []string) {
 }
 
 //AddTX saves tag strings.
-func AddTX(tx *bolt.Tx, thread string, tag []string) error {
+func AddTX(tx db.Tx, thread string, tag []string) error {
 	for _, t := range tag {
 		if err := db.PutMap(tx, "usertag", []byte(thread), t); err != nil {
 			return err
@@ -132,8 +153,8 @@ func AddTX(tx *bolt.Tx, thread string, tag []string) error {
 }
 
 //Set remove all tags and saves tag strings.
-func Set(thread string, tag []string) {
-	err := db.DB.Update(func(tx *bolt.Tx) error {
+func Set(s db.Store, thread string, tag []string) {
+	err := s.Update(func(tx db.Tx) error {
 		ts, err := db.GetMap(tx, "usertag", []byte(thread))
 		if err != nil {
 			log.Println(err)
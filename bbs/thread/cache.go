@@ -31,11 +31,10 @@ package thread
 import (
 	"log"
 	"strings"
-	"time"
 
-	"github.com/boltdb/bolt"
 	"bbs/cfg"
 	"bbs/db"
+	"bbs/myself"
 	"bbs/recentlist"
 	"bbs/record"
 	"bbs/util"
@@ -44,100 +43,43 @@ import (
 //Cache represents cache of one file.
 type Cache struct {
 	Datfile string
+	my      *myself.Myself
 }
 
 //NewCache read tag files to set and returns cache obj.
 //it uses sync.pool to ensure that only one cache obj exists for one datfile.
 //and garbage collected when not used.
-func NewCache(datfile string) *Cache {
+func NewCache(my *myself.Myself, datfile string) *Cache {
 	c := &Cache{
 		Datfile: datfile,
+		my:      my,
 	}
 	return c
 }
 
 //Stamp returns latest stampl of records in the cache.
 func (c *Cache) Stamp() int64 {
-	var r []*record.DB
-	err := db.DB.View(func(tx *bolt.Tx) error {
-		var err error
-		r, err = record.GetFromDBs(tx, c.Datfile)
-		return err
-	})
-	if err != nil {
-		log.Print(err)
-		return 0
-	}
-	if len(r) == 0 {
-		return 0
-	}
-	var stamp int64
-	for i := len(r) - 1; i >= 0; i-- {
-		if !r[i].Deleted {
-			stamp = r[i].Stamp
-			break
-		}
-	}
-	return stamp
+	return record.Stats(c.my, c.Datfile).LastAlive
 }
 
 //Len returns # of records in the cache.
 func (c *Cache) Len(kind int) int {
-	m, err := record.FromRecordDB(c.Datfile, kind)
-	if err != nil {
-		log.Print(err)
-		return 0
-	}
-	return len(m)
+	return record.Stats(c.my, c.Datfile).Len(kind)
 }
 
-//Velocity returns number of records in one days in the cache.
+//Velocity returns number of records in recent 7 days in the cache.
 func (c *Cache) Velocity() int {
-	var r []*record.DB
-	err := db.DB.View(func(tx *bolt.Tx) error {
-		var err error
-		r, err = record.GetFromDBs(tx, c.Datfile)
-		return err
-	})
-	if err != nil {
-		log.Print(err)
-		return 0
-	}
-	cnt := 0
-	t := int64(time.Now().Add(-7 * 24 * time.Hour).Second())
-	for _, rr := range r {
-		if rr.Stamp > t {
-			cnt++
-		}
-	}
-	return cnt
+	return record.Stats(c.my, c.Datfile).Velocity()
 }
 
 //Size returns sum of body char length of records in the cache.
 func (c *Cache) Size() int64 {
-	if c.Len(record.All) == 0 {
-		return 0
-	}
-	var r []*record.DB
-	err := db.DB.View(func(tx *bolt.Tx) error {
-		var err error
-		r, err = record.GetFromDBs(tx, c.Datfile)
-		return err
-	})
-	if err != nil {
-		log.Print(err)
-		return 0
-	}
-	var cnt int64
-	for _, rr := range r {
-		cnt += int64(len(rr.Body))
-	}
-	return cnt
+	return record.Stats(c.my, c.Datfile).Size
 }
 
 //LoadRecords loads and returns record maps from the disk..
 func (c *Cache) LoadRecords(kind int) record.Map {
-	m, err := record.FromRecordDB(c.Datfile, kind)
+	m, err := record.FromRecordDB(c.my, c.Datfile, kind)
 	if err != nil {
 		log.Print(err)
 		return nil
@@ -145,18 +87,21 @@ func (c *Cache) LoadRecords(kind int) record.Map {
 	return m
 }
 
-//subscribe add the thread to thread db.
-func (c *Cache) subscribe(tx *bolt.Tx) {
+//SubscribeTX add the thread to thread db.
+func (c *Cache) SubscribeTX(tx db.Tx) {
 	err := db.Put(tx, "thread", []byte(c.Datfile), []byte(""))
 	if err != nil {
 		log.Print(err)
 	}
+	if err := record.IndexTitleTX(tx, c.Datfile); err != nil {
+		log.Print(err)
+	}
 }
 
 //Subscribe add the thread to thread db.
 func (c *Cache) Subscribe() {
-	err := db.DB.Update(func(tx *bolt.Tx) error {
-		c.subscribe(tx)
+	err := c.my.DB.Update(func(tx db.Tx) error {
+		c.SubscribeTX(tx)
 		return nil
 	})
 	if err != nil {
@@ -166,11 +111,11 @@ func (c *Cache) Subscribe() {
 
 //CheckData makes a record from res and checks its records meets condisions of args.
 //adds the rec to cache if meets conditions.
-//if spam or big data, remove the rec from disk.
-//returns spam/getting error.
-func (c *Cache) CheckData(tx *bolt.Tx, res string, stamp int64,
+//if spam, big data or forged sign, remove the rec from disk.
+//returns spam/sign/getting error.
+func (c *Cache) CheckData(tx db.Tx, res string, stamp int64,
 	id string, begin, end int64) error {
-	r := record.New(c.Datfile, "", 0)
+	r := record.New(c.my, c.Datfile, "", 0)
 	if errr := r.Parse(res); errr != nil {
 		return cfg.ErrGet
 	}
@@ -184,30 +129,30 @@ func (c *Cache) CheckData(tx *bolt.Tx, res string, stamp int64,
 	if !r.Meets(begin, end) {
 		return cfg.ErrGet
 	}
-	deleted := false
-	if len(r.Recstr()) > cfg.RecordLimit<<10 || r.IsSpam() {
+	var errr error
+	if len(r.Recstr()) > c.my.Cfg.RecordLimit<<10 || r.IsSpam() {
 		log.Printf("warning:%s/%s:too large or spam record", r.Datfile, r.Idstr())
-		deleted = true
+		errr = cfg.ErrSpam
+	}
+	if r.IsForged() {
+		log.Printf("warning:%s/%s:forged sign", r.Datfile, r.Idstr())
+		errr = cfg.ErrSign
 	}
-	err = r.SyncTX(tx, deleted)
+	err = r.SyncTX(tx, errr != nil)
 	if err != nil {
 		return err
 	}
-	if deleted {
-		return cfg.ErrSpam
-	}
-	return nil
+	return errr
 }
 
 //Remove Remove all files and dirs of cache.
 func (c *Cache) Remove() {
-	err := db.DB.Update(func(tx *bolt.Tx) error {
-		r, err := record.GetFromDBs(tx, c.Datfile)
-		if err != nil {
+	err := c.my.DB.Update(func(tx db.Tx) error {
+		if err := record.DelDBs(tx, c.Datfile); err != nil {
 			return err
 		}
-		for _, rr := range r {
-			rr.Del(tx)
+		if err := record.UnindexTitleTX(tx, c.Datfile); err != nil {
+			return err
 		}
 		return db.Del(tx, "thread", []byte(c.Datfile))
 	})
@@ -218,28 +163,13 @@ func (c *Cache) Remove() {
 
 //HasRecord return true if  cache has more than one records or removed records.
 func (c *Cache) HasRecord() bool {
-	var r []*record.DB
-	err := db.DB.View(func(tx *bolt.Tx) error {
-		var err error
-		r, err = record.GetFromDBs(tx, c.Datfile)
-		return err
-	})
-	if err != nil {
-		log.Print(err)
-		return false
-	}
-	for _, rr := range r {
-		if !rr.Deleted {
-			return true
-		}
-	}
-	return false
+	return record.Stats(c.my, c.Datfile).Alive > 0
 }
 
 //Exists return true is datapath exists.
 func (c *Cache) Exists() bool {
 	var cnt bool
-	err := db.DB.View(func(tx *bolt.Tx) error {
+	err := c.my.DB.View(func(tx db.Tx) error {
 		var err error
 		cnt, err = db.HasKey(tx, "thread", []byte(c.Datfile))
 		return err
@@ -264,7 +194,7 @@ func (c *Cache) Gettitle() string {
 //len(recstrs) is <=2.
 //used in templates
 func (c *Cache) GetContents() []string {
-	m, err := record.FromRecordDB(c.Datfile, record.Alive)
+	m, err := record.FromRecordDB(c.my, c.Datfile, record.Alive)
 	if err != nil {
 		log.Print(err)
 		return nil
@@ -281,13 +211,13 @@ func (c *Cache) GetContents() []string {
 
 //CreateAllCachedirs creates all dirs in recentlist to be retrived when called recentlist.getall.
 //(heavymoon)
-func CreateAllCachedirs() {
-	recs := recentlist.GetRecords()
-	err := db.DB.Update(func(tx *bolt.Tx) error {
+func CreateAllCachedirs(my *myself.Myself) {
+	recs := recentlist.GetRecords(my)
+	err := my.DB.Update(func(tx db.Tx) error {
 		for _, rh := range recs {
-			ca := NewCache(rh.Datfile)
+			ca := NewCache(my, rh.Datfile)
 			if !ca.Exists() {
-				ca.subscribe(tx)
+				ca.SubscribeTX(tx)
 			}
 		}
 		return nil
@@ -299,7 +229,7 @@ func CreateAllCachedirs() {
 
 //RecentStamp  returns time of getting by /recent.
 func (c *Cache) RecentStamp() int64 {
-	n, err := recentlist.Newest(c.Datfile)
+	n, err := recentlist.Newest(c.my, c.Datfile)
 	s := c.Stamp()
 	if err != nil || n.Stamp < s {
 		return s
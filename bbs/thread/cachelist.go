@@ -32,18 +32,15 @@ import (
 	"log"
 	"time"
 
-	"regexp"
-
-	"github.com/boltdb/bolt"
-	"bbs/cfg"
 	"bbs/db"
+	"bbs/myself"
 	"bbs/record"
 )
 
 //AllCaches returns all  thread names
-func AllCaches() Caches {
+func AllCaches(my *myself.Myself) Caches {
 	var r []string
-	err := db.DB.View(func(tx *bolt.Tx) error {
+	err := my.DB.View(func(tx db.Tx) error {
 		var err error
 		r, err = db.KeyStrings(tx, "thread")
 		return err
@@ -54,15 +51,15 @@ func AllCaches() Caches {
 	}
 	ca := make(Caches, len(r))
 	for i, t := range r {
-		ca[i] = NewCache(t)
+		ca[i] = NewCache(my, t)
 	}
 	return ca
 }
 
 //Len returns # of Caches
-func Len() int {
+func Len(my *myself.Myself) int {
 	var r []string
-	err := db.DB.View(func(tx *bolt.Tx) error {
+	err := my.DB.View(func(tx db.Tx) error {
 		var err error
 		r, err = db.GetPrefixs(tx, "record")
 		return err
@@ -75,43 +72,14 @@ func Len() int {
 	return len(r)
 }
 
-//Search reloads records in Caches in cachelist
-//and returns slice of cache which matches query.
-func Search(q string) Caches {
-	reg, err := regexp.Compile(q)
-	if err != nil {
-		log.Println(err)
-		return nil
-	}
-	var cnt []string
-	err = db.DB.View(func(tx *bolt.Tx) error {
-		return record.ForEach(tx,
-			func(d *record.DB) error {
-				if reg.Match([]byte(d.Body)) {
-					cnt = append(cnt, d.Datfile)
-				}
-				return nil
-			})
-	})
-	if err != nil {
-		log.Println(err)
-	}
-	result := make([]*Cache, len(cnt))
-
-	for i, rr := range cnt {
-		result[i] = NewCache(rr)
-	}
-	return result
-}
-
 //CleanRecords remove old or duplicates records for each Caches.
-func CleanRecords() {
-	if cfg.SaveRecord <= 0 {
+func CleanRecords(my *myself.Myself) {
+	if my.Cfg.SaveRecord <= 0 {
 		return
 	}
-	err := db.DB.Update(func(tx *bolt.Tx) error {
+	err := my.DB.Update(func(tx db.Tx) error {
 		return record.ForEach(tx, func(rec *record.DB) error {
-			if rec.Head.Stamp < time.Now().Unix()-cfg.SaveRecord {
+			if rec.Head.Stamp < time.Now().Unix()-my.Cfg.SaveRecord {
 				rec.Del(tx)
 			}
 			return nil
@@ -123,14 +91,14 @@ func CleanRecords() {
 }
 
 //RemoveRemoved removes files in removed dir if old.
-func RemoveRemoved() {
-	if cfg.SaveRemoved <= 0 {
+func RemoveRemoved(my *myself.Myself) {
+	if my.Cfg.SaveRemoved <= 0 {
 		return
 	}
-	err := db.DB.Update(func(tx *bolt.Tx) error {
+	err := my.DB.Update(func(tx db.Tx) error {
 		return record.ForEach(tx,
 			func(rec *record.DB) error {
-				if rec.Deleted && rec.Head.Stamp < time.Now().Unix()-cfg.SaveRemoved {
+				if rec.Deleted && rec.Head.Stamp < time.Now().Unix()-my.Cfg.SaveRemoved {
 					rec.Del(tx)
 				}
 				return nil
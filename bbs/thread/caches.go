@@ -30,7 +30,9 @@ package thread
 
 import (
 	"sort"
+	"time"
 
+	"bbs/myself"
 	"bbs/recentlist"
 )
 
@@ -124,10 +126,15 @@ func (c *SortByVelocity) Swap(i, j int) {
 
 //MakeRecentCachelist returns sorted cachelist copied from Recentlist.
 //which doens't contain duplicate Caches.
-func MakeRecentCachelist() Caches {
+//only records in my.Cfg.RecentRange are read if RecentRange>0.
+func MakeRecentCachelist(my *myself.Myself) Caches {
+	var begin int64
+	if my.Cfg.RecentRange > 0 {
+		begin = time.Now().Unix() - my.Cfg.RecentRange
+	}
 	var cl Caches
-	for _, datfile := range recentlist.Datfiles() {
-		ca := NewCache(datfile)
+	for _, datfile := range recentlist.Datfiles(my, begin) {
+		ca := NewCache(my, datfile)
 		cl = append(cl, ca)
 	}
 	sort.Sort(sort.Reverse(NewSortByStamp(cl, true)))
@@ -35,9 +35,8 @@ import (
 	"sync"
 	"time"
 
-	"github.com/boltdb/bolt"
-	"bbs/cfg"
 	"bbs/db"
+	"bbs/myself"
 	"bbs/node"
 	"bbs/node/manager"
 	"bbs/recentlist"
@@ -72,28 +71,28 @@ func (t TargetRecSlice) Less(i, j int) bool {
 	return t[i].stamp < t[j].stamp
 }
 
-var managers = make(map[string]*Manager)
-
 //Manager manages download range of records.
 type Manager struct {
+	my      *myself.Myself
 	datfile string
 	recs    map[string]*targetRec
 	mutex   sync.RWMutex
 }
 
 //NewManger sets recs as finished recs and returns DownloadManager obj.
-func NewManger(ca *thread.Cache) *Manager {
-	if d, exist := managers[ca.Datfile]; exist {
+func NewManger(my *myself.Myself, ca *thread.Cache) *Manager {
+	if d, exist := my.Downloads.Load(ca.Datfile); exist {
 		log.Println(ca.Datfile, "is downloading")
-		return d
+		return d.(*Manager)
 	}
 	recs := ca.LoadRecords(record.All)
 	dm := &Manager{
+		my:      my,
 		datfile: ca.Datfile,
 		recs:    make(map[string]*targetRec),
 	}
-	for k := range recs {
-		dm.recs[k] = &targetRec{
+	for _, rec := range recs {
+		dm.recs[rec.LegacyHead().Idstr()] = &targetRec{
 			finished: true,
 		}
 	}
@@ -110,7 +109,7 @@ func (dm *Manager) Set(res []string, n *node.Node) {
 				rec.node = append(rec.node, n)
 			}
 		} else {
-			dm.recs[r.Recstr()] = &targetRec{
+			dm.recs[r.Idstr()] = &targetRec{
 				node:  []*node.Node{n},
 				stamp: r.Stamp,
 			}
@@ -133,7 +132,7 @@ func (dm *Manager) Get(n *node.Node) (int64, int64) {
 		dm.checkFinished()
 		return -1, -1
 	}
-	managers[dm.datfile] = dm
+	dm.my.Downloads.Store(dm.datfile, dm)
 	sort.Sort(sort.Reverse(s))
 	begin := len(s) - 1
 	if len(s) > 5 {
@@ -146,7 +145,7 @@ func (dm *Manager) Get(n *node.Node) (int64, int64) {
 }
 
 func (dm *Manager) checkFinished() {
-	if _, exist := managers[dm.datfile]; !exist {
+	if _, exist := dm.my.Downloads.Load(dm.datfile); !exist {
 		return
 	}
 	finished := true
@@ -160,8 +159,7 @@ func (dm *Manager) checkFinished() {
 	}
 	if finished {
 		log.Println(dm.datfile, ":finished downloading")
-		managers[dm.datfile] = nil
-		delete(managers, dm.datfile)
+		dm.my.Downloads.Delete(dm.datfile)
 	}
 }
 
@@ -184,27 +182,27 @@ func (dm *Manager) Finished(n *node.Node, success bool) {
 
 //headWithRange checks node n has records with range and adds records which should be downloaded to downloadmanager.
 func headWithRange(n *node.Node, c *thread.Cache, dm *Manager) bool {
-	begin := time.Now().Unix() - cfg.GetRange
-	if rec, err := recentlist.Newest(c.Datfile); err == nil {
-		begin = rec.Stamp - cfg.GetRange
+	begin := time.Now().Unix() - dm.my.Cfg.GetRange
+	if rec, err := recentlist.Newest(dm.my, c.Datfile); err == nil {
+		begin = rec.Stamp - dm.my.Cfg.GetRange
 	}
-	if cfg.GetRange == 0 || begin < 0 {
+	if dm.my.Cfg.GetRange == 0 || begin < 0 {
 		begin = 0
 	}
-	res, err := n.Talk(fmt.Sprintf("/head/%s/%d-", c.Datfile, begin), nil)
+	res, err := n.Talk(dm.my, fmt.Sprintf("/head/%s/%d-", c.Datfile, begin), nil)
 	if err != nil {
 		return false
 	}
 	if len(res) == 0 {
-		ress, errr := n.Talk(fmt.Sprintf("/have/%s", c.Datfile), nil)
+		ress, errr := n.Talk(dm.my, fmt.Sprintf("/have/%s", c.Datfile), nil)
 		if errr != nil || len(ress) == 0 || ress[0] != "YES" {
-			manager.RemoveFromTable(c.Datfile, n)
+			manager.RemoveFromTable(dm.my, c.Datfile, n)
 		} else {
-			manager.AppendToTable(c.Datfile, n)
+			manager.AppendToTable(dm.my, c.Datfile, n)
 		}
 		return false
 	}
-	manager.AppendToTable(c.Datfile, n)
+	manager.AppendToTable(dm.my, c.Datfile, n)
 	dm.Set(res, n)
 	return true
 }
@@ -221,12 +219,12 @@ func getWithRange(n *node.Node, c *thread.Cache, dm *Manager) bool {
 		}
 
 		var okcount int
-		ress, err := n.Talk(fmt.Sprintf("/get/%s/%d-%d", c.Datfile, from, to), nil)
+		ress, err := n.Talk(dm.my, fmt.Sprintf("/get/%s/%d-%d", c.Datfile, from, to), nil)
 		if err != nil {
 			dm.Finished(n, false)
 			return false
 		}
-		err = db.DB.Update(func(tx *bolt.Tx) error {
+		err = dm.my.DB.Update(func(tx db.Tx) error {
 			for _, res := range ress {
 				errf := c.CheckData(tx, res, -1, "", from, to)
 				if errf == nil {
@@ -246,13 +244,13 @@ func getWithRange(n *node.Node, c *thread.Cache, dm *Manager) bool {
 
 //GetCache checks  nodes in lookuptable have the cache.
 //if found gets records.
-func GetCache(background bool, c *thread.Cache) bool {
+func GetCache(my *myself.Myself, background bool, c *thread.Cache) bool {
 	const searchDepth = 100 // Search node size
-	ns := manager.NodesForGet(c.Datfile, searchDepth)
+	ns := manager.NodesForGet(my, c.Datfile, searchDepth)
 	found := false
 	var wg sync.WaitGroup
 	var mutex sync.RWMutex
-	dm := NewManger(c)
+	dm := NewManger(my, c)
 	for _, n := range ns {
 		wg.Add(1)
 		go func(n *node.Node) {
@@ -269,7 +267,7 @@ func GetCache(background bool, c *thread.Cache) bool {
 		}(n)
 	}
 	if background {
-		bg(c, &wg)
+		bg(my, c, &wg)
 	} else {
 		wg.Wait()
 	}
@@ -279,9 +277,9 @@ func GetCache(background bool, c *thread.Cache) bool {
 }
 
 //bg waits for at least one record in the cache.
-func bg(c *thread.Cache, wg *sync.WaitGroup) {
+func bg(my *myself.Myself, c *thread.Cache, wg *sync.WaitGroup) {
 	w := 2 * time.Second
-	newest, err := recentlist.Newest(c.Datfile)
+	newest, err := recentlist.Newest(my, c.Datfile)
 	var done chan struct{}
 	go func() {
 		wg.Wait()
@@ -304,10 +302,10 @@ func bg(c *thread.Cache, wg *sync.WaitGroup) {
 }
 
 //Getall reload all records in cache in cachelist from network.
-func Getall() {
-	for _, ca := range thread.AllCaches() {
+func Getall(my *myself.Myself) {
+	for _, ca := range thread.AllCaches(my) {
 		log.Println(ca.Datfile, "is downloading...")
-		GetCache(false, ca)
+		GetCache(my, false, ca)
 		log.Println(ca.Datfile, "end")
 	}
 }
@@ -0,0 +1,103 @@
+/*
+ * Copyright (c) 2015, Shinya Yagyu
+ * All rights reserved.
+ * Redistribution and use in source and binary forms, with or without
+ * modification, are permitted provided that the following conditions are met:
+ *
+ * 1. Redistributions of source code must retain the above copyright notice,
+ *    this list of conditions and the following disclaimer.
+ * 2. Redistributions in binary form must reproduce the above copyright notice,
+ *    this list of conditions and the following disclaimer in the documentation
+ *    and/or other materials provided with the distribution.
+ * 3. Neither the name of the copyright holder nor the names of its
+ *    contributors may be used to endorse or promote products derived from this
+ *    software without specific prior written permission.
+ *
+ * THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+ * AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+ * IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+ * ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+ * LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+ * CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+ * SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+ * INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+ * CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+ * ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+ * POSSIBILITY OF SUCH DAMAGE.
+ */
+
+package download
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"bbs/myself"
+	"bbs/node"
+	"bbs/record"
+	"bbs/thread"
+	"bbs/util"
+)
+
+func TestManager(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gou")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	my := myself.NewMemory(dir)
+
+	ca := thread.NewCache(my, util.FileEncode("thread", "download"))
+	ca.Subscribe()
+	rec := record.New(my, ca.Datfile, "", 0)
+	rec.Build(1500000000, map[string]string{"body": "saved"}, "")
+	rec.Sync()
+
+	n1, err := node.New("127.0.0.1:8001/server.cgi")
+	if err != nil {
+		t.Fatal(err)
+	}
+	n2, err := node.New("127.0.0.1:8002/server.cgi")
+	if err != nil {
+		t.Fatal(err)
+	}
+	head := func(stamp int64, id string) string {
+		return fmt.Sprintf("%d<>%s", stamp, id)
+	}
+	saved := head(rec.Stamp, rec.LegacyID())
+	id2, id3 := util.MD5digest("2"), util.MD5digest("3")
+
+	dm := NewManger(my, ca)
+	dm.Set([]string{saved, head(1500000020, id2), head(1500000030, id3)}, n1)
+	dm.Set([]string{saved, head(1500000020, id2)}, n2)
+
+	//n1 gets both records which are not saved.
+	if b, e := dm.Get(n1); b != 1500000020 || e != 1500000030 {
+		t.Fatal("n1 gets", b, e)
+	}
+	if NewManger(my, ca) != dm {
+		t.Fatal("another manager is made while downloading")
+	}
+	//records which n1 is downloading are not gotten by n2.
+	if b, e := dm.Get(n2); b != -1 || e != -1 {
+		t.Fatal("n2 gets", b, e)
+	}
+	//failed records are gotten again.
+	dm.Finished(n1, false)
+	if b, e := dm.Get(n2); b != 1500000020 || e != 1500000020 {
+		t.Fatal("n2 gets", b, e)
+	}
+	dm.Finished(n2, true)
+	if b, e := dm.Get(n1); b != 1500000030 || e != 1500000030 {
+		t.Fatal("n1 gets", b, e)
+	}
+	dm.Finished(n1, true)
+	if b, e := dm.Get(n1); b != -1 || e != -1 {
+		t.Fatal("n1 gets", b, e)
+	}
+	if NewManger(my, ca) == dm {
+		t.Fatal("manager is not removed after finished")
+	}
+}
@@ -0,0 +1,85 @@
+/*
+ * Copyright (c) 2015, Shinya Yagyu
+ * All rights reserved.
+ * Redistribution and use in source and binary forms, with or without
+ * modification, are permitted provided that the following conditions are met:
+ *
+ * 1. Redistributions of source code must retain the above copyright notice,
+ *    this list of conditions and the following disclaimer.
+ * 2. Redistributions in binary form must reproduce the above copyright notice,
+ *    this list of conditions and the following disclaimer in the documentation
+ *    and/or other materials provided with the distribution.
+ * 3. Neither the name of the copyright holder nor the names of its
+ *    contributors may be used to endorse or promote products derived from this
+ *    software without specific prior written permission.
+ *
+ * THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+ * AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+ * IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+ * ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+ * LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+ * CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+ * SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+ * INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+ * CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+ * ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+ * POSSIBILITY OF SUCH DAMAGE.
+ */
+package thread
+
+import (
+	"regexp"
+
+	"bbs/db"
+	"bbs/myself"
+	"bbs/record"
+)
+
+var anchorReg = regexp.MustCompile("&gt;&gt;([0-9a-f]{8})")
+
+//Page is records in a page of a thread and records which are referred by anchors
+//in them, which are loaded in one transaction.
+type Page struct {
+	Records []*record.Record
+	Anchors []*record.Record //alive records referred by >>id[:8] in Records and not in Records.
+}
+
+//LoadPage loads records in rg, and also records referred by them if anchors,
+//in one read transaction. referred records are looked up by the id index.
+func LoadPage(my *myself.Myself, rg *record.Range, anchors bool) (*Page, error) {
+	p := &Page{}
+	err := my.DB.View(func(tx db.Tx) error {
+		err := rg.EachTX(my, tx, func(r *record.Record) error {
+			p.Records = append(p.Records, r)
+			return nil
+		})
+		if err != nil || !anchors {
+			return err
+		}
+		ids := p.anchorIDs(tx)
+		if len(ids) == 0 {
+			return nil
+		}
+		p.Anchors, err = record.AnchorsTX(my, tx, rg.Datfile, ids)
+		return err
+	})
+	return p, err
+}
+
+//anchorIDs returns ids in anchors of p.Records which don't refer to p.Records.
+func (p *Page) anchorIDs(tx db.Tx) []string {
+	has := make(map[string]bool)
+	for _, r := range p.Records {
+		has[r.LegacyIDTX(tx)[:8]] = true
+	}
+	var ids []string
+	for _, r := range p.Records {
+		for _, m := range anchorReg.FindAllStringSubmatch(r.GetBodyValue("body", ""), -1) {
+			if !has[m[1]] {
+				has[m[1]] = true
+				ids = append(ids, m[1])
+			}
+		}
+	}
+	return ids
+}
@@ -6,7 +6,7 @@ import (
 	"log"
 	"os"
 
-	"github.com/boltdb/bolt"
+	bolt "go.etcd.io/bbolt"
 )
 
 func main() {
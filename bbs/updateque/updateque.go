@@ -29,11 +29,16 @@
 package updateque
 
 import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
 	"log"
-	"sync"
+	"math/rand"
 	"time"
 
 	"bbs/cfg"
+	"bbs/db"
+	"bbs/myself"
 	"bbs/node"
 	"bbs/node/manager"
 	"bbs/recentlist"
@@ -41,124 +46,397 @@ import (
 	"bbs/thread"
 )
 
-//UpdateQue is for telling updates of records.
-//it records hash of updated records for 1 hour not to tell again.
-var mutex sync.Mutex
-var updated = make(map[[16]byte]time.Time)
+//kinds of items in the queue.
+const (
+	//Tell is an update of my record, which is told to other nodes until one of them accepts it.
+	Tell = iota
+	//Fetch is an update told by other node, whose record is gotten from the node.
+	Fetch
+)
+
+const (
+	retryMin   = time.Minute //wait before the first retry
+	retryMax   = time.Hour   //max wait between retries
+	oldUpdated = time.Hour   //updates are not handled again in this duration
+)
+
+//Item is an update waiting to be told or gotten, saved in "updateque" bucket.
+type Item struct {
+	*record.Head        //head with legacy id
+	Kind         int    //Tell or Fetch
+	Node         string //nodestr of the node which has the record if Fetch
+	Added        int64  //unixtime when queued
+	Tries        int    //# of tries
+	Next         int64  //unixtime of the next try
+	Err          string //error of the last try
+}
+
+//key returns key of "updateque" bucket.
+func (it *Item) key() []byte {
+	return db.ToKey(it.Datfile, it.Stamp, it.ID, it.Node)
+}
+
+//Key returns the string which identifies it in the queue.
+//used in templates
+func (it *Item) Key() string {
+	return it.Datfile + "/" + it.Idstr() + "/" + it.Node
+}
+
+//Expired returns true if the stamp of the record is out of the update range,
+//because other nodes ignore the update.
+func (it *Item) Expired() bool {
+	return !recentlist.IsInUpdateRange(it.Stamp)
+}
+
+//backoff returns the wait before the next try after tries,
+//which doubles from retryMin to retryMax with random jitter of half of it.
+func backoff(tries int) time.Duration {
+	d := retryMin
+	for i := 1; i < tries && d < retryMax; i++ {
+		d *= 2
+	}
+	if d > retryMax {
+		d = retryMax
+	}
+	return d/2 + time.Duration(rand.Int63n(int64(d/2)+1))
+}
+
+//Queue tells updates of records to other nodes, and gets records whose updates are told.
+//updates are saved in db until they succeed or expire, and failed ones are retried with backoff.
+//it records hash of updated records for 1 hour not to handle them again,
+//and old hashes are removed by RetryDue.
+type Queue struct {
+	my *myself.Myself
+}
+
+//New returns Queue which tells updates of records from my.
+func New(my *myself.Myself) *Queue {
+	return &Queue{
+		my: my,
+	}
+}
 
-//UpdateNodes do doUpdateNode for each records using related nodes.
-//if success to doUpdateNode, add node to updatelist and recentlist and
-//removes the record from queue.
-func UpdateNodes(rec *record.Record, n *node.Node) {
-	if !doUpdateNode(rec, n) {
+//UpdateNodes handles the update of rec told by n, or by myself if n is nil.
+//my update is queued to be told, and the record told by n is queued to be gotten from n
+//if I have the thread, or the update is relayed to other nodes if not.
+//the update is added to recentlist if it is told or gotten.
+func (q *Queue) UpdateNodes(rec *record.Record, n *node.Node) {
+	h := rec.LegacyHead()
+	if !q.markUpdated(h) {
+		log.Println("already broadcasted", rec.ID)
+		q.done(h)
 		return
 	}
-	recentlist.Append(rec.Head)
-	if !cfg.HeavyMoon {
+	ca := thread.NewCache(q.my, rec.Datfile)
+	switch {
+	case n == nil:
+		log.Println("updates by myself, broadcast updates.")
+		q.push(&Item{Head: h, Kind: Tell})
+	case ca.Exists():
+		log.Println("cache exists. get record from node n.")
+		q.push(&Item{Head: h, Kind: Fetch, Node: n.Nodestr})
 		return
+	default:
+		log.Println("no cache, broadcast updates.")
+		manager.TellUpdate(q.my, ca.Datfile, h.Stamp, h.ID, n)
 	}
-	if ca := thread.NewCache(rec.Datfile); !ca.Exists() {
-		ca.Subscribe()
+	q.done(h)
+}
+
+//Add queues my update of rec to be told by RetryDue, without telling it now.
+//it is used when the node may not be running, e.g. importing records from command line.
+func (q *Queue) Add(rec *record.Record) {
+	h := rec.LegacyHead()
+	if q.markUpdated(h) {
+		now := time.Now().Unix()
+		it := &Item{Head: h, Kind: Tell, Added: now, Next: now}
+		err := q.my.DB.Update(func(tx db.Tx) error {
+			return db.Put(tx, "updateque", it.key(), it)
+		})
+		if err != nil {
+			log.Println(err)
+		}
 	}
+	q.done(h)
 }
 
-//deleteOldUpdated removes old updated records from updated map.
-func deleteOldUpdated() {
-	const oldUpdated = time.Hour
+//done adds h to recentlist, and subscribes the thread if heavymoon.
+func (q *Queue) done(h *record.Head) {
+	recentlist.Append(q.my, h)
+	if !q.my.Cfg.HeavyMoon {
+		return
+	}
+	if ca := thread.NewCache(q.my, h.Datfile); !ca.Exists() {
+		ca.Subscribe()
+	}
+}
 
-	for k, v := range updated {
-		if time.Now().After(v.Add(oldUpdated)) {
-			delete(updated, k)
+//markUpdated saves hash of h and returns true if h was not updated in oldUpdated.
+func (q *Queue) markUpdated(h *record.Head) bool {
+	hash := []byte(h.Hash(q.my.Cfg))
+	now := time.Now()
+	fresh := false
+	err := q.my.DB.Update(func(tx db.Tx) error {
+		if v := tx.Get("updated", hash); v != nil && !isOld(v, now) {
+			return nil
 		}
+		fresh = true
+		return db.Put(tx, "updated", hash, now.Unix())
+	})
+	if err != nil {
+		log.Println(err)
 	}
+	return fresh
 }
 
-//RecordChannel is for informing record was gotten.
-type RecordChannel struct {
-	*record.Head
-	Ch    chan struct{}
-	mutex sync.RWMutex
+//isOld returns true if v, unixtime in "updated" bucket, is before oldUpdated from now.
+func isOld(v []byte, now time.Time) bool {
+	t := time.Unix(int64(binary.BigEndian.Uint64(v)), 0)
+	return now.After(t.Add(oldUpdated))
 }
 
-//regist registers record to RecordChannel.
-func (r *RecordChannel) register(h *record.Head) {
-	r.mutex.Lock()
-	defer r.mutex.Unlock()
-	if r.Ch == nil {
-		r.Ch = make(chan struct{})
+//removeOldUpdated removes hashes which were updated before oldUpdated.
+func (q *Queue) removeOldUpdated() {
+	now := time.Now()
+	err := q.my.DB.Update(func(tx db.Tx) error {
+		var olds [][]byte
+		err := db.ForEach(tx, "updated", func(k, v []byte) error {
+			if isOld(v, now) {
+				olds = append(olds, append([]byte{}, k...))
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		for _, k := range olds {
+			if err := db.Del(tx, "updated", k); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		log.Println(err)
 	}
-	r.Head = h
 }
 
-//Inform call channel if specifiedd condition is met.
-func (r *RecordChannel) Inform(datfile, id string, begin, end int64) {
-	r.mutex.Lock()
-	if r.Head != nil && r.Datfile == datfile && begin <= r.Stamp && r.Stamp <= end && r.ID == id {
-		r.Head = nil
-		r.mutex.Unlock()
-		r.Ch <- struct{}{}
+//push saves it to the queue as tried once and tries it.
+func (q *Queue) push(it *Item) {
+	now := time.Now()
+	it.Added = now.Unix()
+	it.Tries = 1
+	it.Next = now.Add(backoff(it.Tries)).Unix()
+	err := q.my.DB.Update(func(tx db.Tx) error {
+		return db.Put(tx, "updateque", it.key(), it)
+	})
+	if err != nil {
+		log.Println(err)
 		return
 	}
-	r.mutex.Unlock()
+	q.run(it)
 }
 
-//wait waits for channel or 1 minutex.
-func (r *RecordChannel) wait() bool {
-	select {
-	case <-r.Ch:
-		return true
-	case <-time.After(time.Minute):
-		return false
-	}
+//claim re-reads it in the queue, counts up its tries and schedules the next try with backoff
+//in one transaction, so that it is tried only once by concurrent retries.
+//it returns false if it was removed or tried by others since it was read,
+//or if due and its next try has not come yet.
+func (q *Queue) claim(it *Item, due bool) (bool, error) {
+	now := time.Now()
+	claimed := false
+	err := q.my.DB.Update(func(tx db.Tx) error {
+		var cur Item
+		if _, err := db.Get(tx, "updateque", it.key(), &cur); err != nil {
+			return nil
+		}
+		if cur.Tries != it.Tries || due && cur.Next > now.Unix() {
+			return nil
+		}
+		cur.Tries++
+		cur.Next = now.Add(backoff(cur.Tries)).Unix()
+		*it = cur
+		claimed = true
+		return db.Put(tx, "updateque", it.key(), it)
+	})
+	return claimed, err
 }
 
-//UpdatedRecord is concerned record.
-var UpdatedRecord RecordChannel
-
-//doUpdateNode broadcast and get data for each new records.
-//if can get data (even if spam) return true, if fails to get, return false.
-//if no fail, broadcast updates to node in cache and added n to nodelist and searchlist.
-func doUpdateNode(rec *record.Record, n *node.Node) bool {
-	mutex.Lock()
-	deleteOldUpdated()
-	if _, exist := updated[rec.Hash()]; exist {
-		log.Println("already broadcasted", rec.ID)
-		mutex.Unlock()
-		return true
-	}
-	updated[rec.Hash()] = time.Now()
-	mutex.Unlock()
-
-	ca := thread.NewCache(rec.Datfile)
-	var err error
-	if !ca.Exists() || n == nil {
-		log.Println("no cache or updates by myself, broadcast updates.")
-		UpdatedRecord.register(rec.Head)
-		manager.TellUpdate(ca.Datfile, rec.Stamp, rec.ID, n)
-		if UpdatedRecord.wait() || n != nil {
-			log.Println(rec.ID, "was gotten or don't have the record")
-		} else {
-			log.Println(rec.ID, "was NOT gotten, will call updates later")
-			go func() {
-				time.Sleep(10 * time.Minute)
-				UpdateNodes(rec, n)
-			}()
+//run tries it once. Tell is removed from the queue when one of nodes accepts the update,
+//or when all nodes responded but rejected it. Fetch is removed when the record is gotten
+//or it turns out to be spam. only rejections are not retried.
+func (q *Queue) run(it *Item) {
+	switch it.Kind {
+	case Tell:
+		ds := manager.TellUpdate(q.my, it.Datfile, it.Stamp, it.ID, nil)
+		retry := len(ds) == 0
+		for _, d := range ds {
+			switch d.Result {
+			case manager.DeliveryOK:
+				q.Remove(it)
+				return
+			case manager.DeliveryTimeout, manager.DeliveryRefused, manager.DeliveryError, manager.DeliverySkipped:
+				retry = true
+			}
 		}
-		return true
+		if !retry {
+			log.Println("all nodes rejected the update", it.Idstr())
+			q.Remove(it)
+			return
+		}
+		q.fail(it, errors.New("no nodes accepted the update"))
+	case Fetch:
+		q.fetch(it)
+	}
+}
+
+//fetch gets the record of it from it.Node, and tells the update to other nodes if gotten.
+func (q *Queue) fetch(it *Item) {
+	n, err := node.New(it.Node)
+	if err != nil {
+		log.Println(err)
+		q.Remove(it)
+		return
 	}
-	log.Println("cache exists. get record from node n.")
-	err = rec.GetData(n)
-	switch err {
+	rec := record.New(q.my, it.Datfile, it.ID, it.Stamp)
+	switch err := rec.GetData(n); err {
 	case cfg.ErrGet:
-		log.Println("could not get")
-		return false
+		log.Println("could not get, will retry")
+		q.fail(it, err)
+		return
 	case cfg.ErrSpam:
 		log.Println("marked spam")
-		return true
+	case cfg.ErrSign:
+		log.Println("marked forged sign")
 	default:
 		log.Println("telling update")
-		manager.TellUpdate(ca.Datfile, rec.Stamp, rec.ID, nil)
-		manager.Join(n)
-		return true
+		manager.TellUpdate(q.my, it.Datfile, it.Stamp, it.ID, nil)
+		manager.Join(q.my, n)
+	}
+	q.Remove(it)
+	q.done(it.Head)
+}
+
+//fail saves err as the last error of it if it is still in the queue and not tried by others.
+func (q *Queue) fail(it *Item, err error) {
+	it.Err = err.Error()
+	errr := q.my.DB.Update(func(tx db.Tx) error {
+		var cur Item
+		if _, err := db.Get(tx, "updateque", it.key(), &cur); err != nil || cur.Tries != it.Tries {
+			return nil
+		}
+		cur.Err = it.Err
+		return db.Put(tx, "updateque", it.key(), &cur)
+	})
+	if errr != nil {
+		log.Println(errr)
+	}
+}
+
+//Inform removes my updates in datfile from the queue which are in begin~end
+//and whose id is id if not empty, because other node got them.
+func (q *Queue) Inform(datfile, id string, begin, end int64) {
+	var keys [][]byte
+	err := q.my.DB.View(func(tx db.Tx) error {
+		return db.ForEachPrefix(tx, "updateque", db.ToKey(datfile), func(k, v []byte) error {
+			var it Item
+			if err := json.Unmarshal(v, &it); err != nil {
+				return err
+			}
+			if it.Kind == Tell && begin <= it.Stamp && it.Stamp <= end && (id == "" || it.ID == id) {
+				keys = append(keys, append([]byte(nil), k...))
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		log.Println(err)
+	}
+	if len(keys) == 0 {
+		return
+	}
+	err = q.my.DB.Update(func(tx db.Tx) error {
+		for _, k := range keys {
+			if err := db.Del(tx, "updateque", k); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		log.Println(err)
+	}
+	log.Println(len(keys), "updates in", datfile, "were gotten")
+}
+
+//Items returns all updates in the queue.
+func (q *Queue) Items() []*Item {
+	var its []*Item
+	err := q.my.DB.View(func(tx db.Tx) error {
+		return db.ForEach(tx, "updateque", func(k, v []byte) error {
+			var it Item
+			if err := json.Unmarshal(v, &it); err != nil {
+				return err
+			}
+			its = append(its, &it)
+			return nil
+		})
+	})
+	if err != nil {
+		log.Println(err)
+	}
+	return its
+}
+
+//Find returns the update in the queue whose Key is key.
+func (q *Queue) Find(key string) (*Item, error) {
+	for _, it := range q.Items() {
+		if it.Key() == key {
+			return it, nil
+		}
+	}
+	return nil, errors.New("not found in the queue")
+}
+
+//Remove removes it from the queue.
+func (q *Queue) Remove(it *Item) error {
+	err := q.my.DB.Update(func(tx db.Tx) error {
+		return db.Del(tx, "updateque", it.key())
+	})
+	if err != nil {
+		log.Println(err)
+	}
+	return err
+}
+
+//Retry tries it again now, unless it was removed or tried by others since it was read.
+func (q *Queue) Retry(it *Item) {
+	q.retry(it, false)
+}
+
+//retry claims it and tries it if claimed. it is not claimed before its next try if due.
+func (q *Queue) retry(it *Item, due bool) {
+	ok, err := q.claim(it, due)
+	if err != nil {
+		log.Println(err)
+		return
+	}
+	if ok {
+		q.run(it)
+	}
+}
+
+//RetryDue removes expired updates and old hashes of updated records,
+//and tries updates whose next try has come.
+func (q *Queue) RetryDue() {
+	q.removeOldUpdated()
+	now := time.Now().Unix()
+	for _, it := range q.Items() {
+		switch {
+		case it.Expired():
+			log.Println(it.Datfile, it.Idstr(), "in the update queue expired")
+			q.Remove(it)
+		case it.Next <= now:
+			q.retry(it, true)
+		}
 	}
 }
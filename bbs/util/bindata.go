@@ -71,6 +71,15 @@
 // gou_template/thread_tags.txt
 // gou_template/thread_top.txt
 // gou_template/top.txt
+// gou_template/moderation.txt
+// file/moderator.txt
+// gou_template/search_result.txt
+// gou_template/fsck.txt
+// gou_template/bundle.txt
+// gou_template/export_form.txt
+// gou_template/export_html.txt
+// gou_template/delivery.txt
+// gou_template/updateque.txt
 // DO NOT EDIT!
 
 package util
@@ -178,7 +187,7 @@ func www00initializeJs() (*asset, error) {
 	return a, nil
 }
 
-var _www20jumpJs = []byte("\x1f\x8b\x08\x00\x00\x09\x6e\x88\x00\xff\x84\x55\x5d\x4f\xeb\x38\x10\x7d\xef\xaf\x18\x42\xa5\xda\x2d\x75\x52\x84\x58\x2d\xdd\xb0\x0f\x5d\x84\xb4\xd2\x22\xb4\xf4\x8d\x0f\xc9\xc4\x6e\x63\x36\xb1\xa3\x78\x42\x61\x51\xff\xfb\x95\xdb\xa4\xe4\xeb\xde\x5a\x7d\xa8\xc6\xc7\xc7\x33\x67\xce\x38\xfe\x78\x00\x63\xf8\xbb\x48\x33\xb8\x93\x1b\xb8\x37\x16\x2d\x73\xa1\x85\xc9\x3e\x73\xb5\x8e\x11\xc8\x82\xc2\x79\x10\x5c\x4e\xcf\x83\xd9\x05\xd8\x58\xe9\xdb\x9b\xa5\x2d\xe0\x3e\x37\x6f\x32\x42\x87\xf6\x07\x03\x17\x5f\x4b\xb4\x05\x53\x5a\xa1\xe2\x89\xfa\x5f\x92\x55\xa1\x23\x54\x46\x03\xa1\xf0\x35\x00\x00\x78\xe7\x39\x24\xdc\x62\xc6\xd7\x12\x42\x48\x4c\xc4\x1d\x80\x65\x1c\x63\xcd\x53\xc9\xac\xe4\x79\x14\x13\xff\xe5\xc9\xff\x13\xe3\x5c\x72\xc1\xa2\xb5\x7a\xf2\x1f\x5f\x9e\xfc\xe7\xc9\xd0\xa7\x10\x86\x10\xcc\x07\x3b\xb6\x03\x7f\x2e\x53\xf3\x2e\x17\xc6\xfc\xa7\xe4\xe1\xae\xea\x3e\xc1\x3f\x21\x04\x2d\x37\xf0\x17\x47\x49\xe8\xbc\xb1\xcb\xa3\x48\x5a\x0b\xa1\x83\xb1\xb5\xc4\xa5\x4a\x1b\x18\x17\xb6\x65\xb8\xc4\x4e\xe1\xb7\xf1\xf9\xc5\xf8\x32\x70\xbf\x59\x10\x04\x75\xb8\x89\x8a\x54\x6a\x64\xd1\x2e\x19\x08\x61\x84\x69\xb6\x3f\x18\x06\x73\x18\xc1\xe4\x80\xed\xae\x91\xd3\x21\xf4\x8f\xc2\xe4\x47\xa6\x72\x69\xc3\x11\x4c\x76\x09\xa2\xb9\xfd\x67\xf9\x80\xb9\xd2\xeb\x2a\xf7\x6d\x47\x22\x2e\x7a\x04\x52\x2b\x20\xad\x9c\x0f\x3d\xf8\x4e\x9c\x3c\x06\xd3\xdf\x9f\xc7\xd4\xa7\x70\x1d\x42\x50\x27\x70\x2b\x97\x58\xe4\x1a\xfe\x95\xeb\x9b\x8f\x8c\x0d\x67\xdf\x72\x6c\x41\x26\x56\xf6\xc3\x75\x91\x24\x35\x64\x6f\xd6\x6f\x45\x9a\xa1\x21\x4a\xb4\x9b\x6a\xcb\x96\x96\x45\x6f\x94\x16\x66\xc3\x2a\x3f\xd5\x1a\xe2\x0a\xb4\x55\x49\xde\xa9\x47\xe1\x8f\x6e\x01\x43\xe2\xc5\x98\x26\x67\xaf\x46\x7c\x7a\x94\x71\xad\x52\x67\x95\xaf\x4e\x0f\x6c\x94\x9b\x24\x59\x9a\xec\xca\x9d\x39\xcd\x3d\x98\x80\x12\x94\x99\xd5\xca\x4a\x24\x94\xa1\xc9\x1a\x87\xb6\x67\xd0\x65\x11\x45\xbe\x4b\xf3\xca\x0d\x56\x13\x4e\x8f\x29\x62\x25\xde\xc9\x8d\x1b\x54\x22\xb0\xdd\xc9\x6a\xb4\xba\xf5\x09\xa4\x8c\x0b\xb1\x48\xb8\xb5\xc4\xd3\x72\x93\x19\x8b\xde\xd1\xdb\xf4\xfe\x2a\x5b\x5a\xbf\x7d\x5f\x37\x5a\xf5\xc7\xd9\x0d\xc2\x72\xba\x7e\x6d\x87\x1a\x3c\x68\xe7\x53\xed\x6b\xb9\x51\x0e\xe0\x79\xcd\xe9\x75\x05\x3f\x20\x4f\x33\x67\x87\x86\x9f\x86\xc4\x13\x08\x36\xe3\x9a\x59\x07\x78\x14\x1c\xf9\x74\xf7\xf7\xd9\xa3\x4c\xf2\x28\xee\x79\x9f\xea\xdc\xb6\xe4\x1d\x12\x8c\x95\xa5\x8c\x23\xe6\x64\xf4\x4d\x33\xaa\xa9\x57\x09\xb2\x3f\x73\xbd\xab\xa7\xcd\x59\xf1\x0a\xac\x91\x46\x89\xb1\xd2\xa2\x4b\xd6\xa3\xee\xfd\x21\x41\x8b\xb6\xa2\x3e\xd9\x69\xd0\x47\xea\x56\x25\x90\x40\xa6\x04\xb3\xc5\xab\xdd\x0f\xc6\xac\x87\x6d\xdb\xb5\x75\xc3\x54\xcd\x13\x4d\x74\x5d\x70\x57\x41\xad\x61\xad\xa1\x3b\x81\xae\x3b\xca\xfe\xbb\xed\xde\x6a\xca\x71\xdf\xef\x1d\x79\x45\x4a\xec\x90\x1c\x52\xea\x57\xb3\xad\xc7\xcf\x3c\xdf\xf8\x18\xd4\x5f\xcb\x3d\xbe\x3d\x09\xfb\x68\xf3\xc3\x33\x1f\x38\x11\x7e\x04\x00\x00\xff\xff\x74\x25\x9d\x47\x54\x07\x00\x00")
+var _www20jumpJs = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\x85\x55\x5b\x4f\xdb\x30\x18\x7d\xef\xaf\x30\xa1\x52\x9d\x42\x9d\x14\x21\xa6\xd1\x85\x3d\x74\x08\x09\x69\x13\x1a\x7d\x03\x26\x99\xd8\x6d\xcc\x92\x38\xb2\x1d\x2e\x43\xfd\xef\xfb\x9c\x4b\xc9\x6d\xab\xd5\x87\xca\x3e\xfe\x2e\xe7\x9c\xcf\xf1\xa6\x23\x34\x45\xd7\x79\x92\xa1\x1f\xfc\x05\xdd\x48\x6d\x34\xb1\x5b\x4b\x99\xbd\x29\xb1\x89\x0c\xc2\x4b\x17\x9d\xf8\xfe\xd9\xec\xc4\x9f\x9f\x22\x1d\x89\xf4\xea\x72\xa5\x73\x74\xa3\xe4\x13\x0f\x8d\x45\x7b\xa3\x91\xdd\xdf\x70\xa3\x73\x22\x52\x61\x04\x8d\xc5\x1f\x8e\xd7\x79\x1a\x1a\x21\x53\x84\x5d\xf4\x3e\x42\xb0\x9e\xa9\x42\x31\xd5\x26\xa3\x1b\x8e\x02\x14\xcb\x90\x5a\x00\xc9\xa8\x89\x52\x9a\x70\xa2\x39\x55\x61\x84\xbd\x5f\xf7\xde\x57\x13\x29\x4e\x19\x09\x37\xe2\xde\xbb\x83\x8d\x87\xa3\xb1\xe7\xa2\x20\x40\xfe\x62\x54\x44\xdb\xc5\x57\x3c\x91\xcf\x7c\x29\xe5\x6f\xc1\x77\xb9\xea\x7c\x8c\xbe\x41\xaa\x14\xda\xfb\x46\x0d\x1c\x2f\x5a\xa7\x34\x0c\xb9\xd6\x00\x00\x18\x81\x0e\x56\x22\x69\x61\xec\xb6\xae\xb6\x2b\xec\x0c\x7d\x9a\x9e\x9c\x4e\xcf\x7c\xfb\x9b\xfb\xbe\xdf\x84\xcb\x30\x4f\x78\x6a\x48\x58\x14\x03\x71\x27\x26\xc9\xca\x8b\x81\xbf\x40\x13\x74\xb4\xc3\xf6\xd7\xc4\xf2\x10\x78\x7b\x61\xfc\x35\x13\x8a\xeb\x00\x60\x45\x81\x46\x5e\x7d\x5f\xdd\x1a\x05\x1a\xd4\xb5\x6f\x7b\x14\x51\x36\x40\x90\x58\x23\xdc\xa9\x79\xa7\xc1\x47\xe1\xf8\xce\x9f\x7d\x7e\x98\xba\x40\xff\x05\xd0\xdf\x0c\x60\x97\xe2\x26\x57\x29\xfa\xc9\x37\x97\xaf\x19\x19\xcf\x3f\xe8\xd8\x22\x1e\x6b\x3e\x0c\x4f\xf3\x38\x6e\x20\x07\xab\x7e\x02\x67\x1a\x89\x05\xeb\x8a\xaa\x2b\x49\xab\xa6\x5f\x44\xca\xe4\x0b\xa9\xfd\xd4\x10\xc4\x36\xa8\xeb\x96\x9c\x43\xc7\x45\x5f\xfa\x0d\x8c\xb1\x13\x99\x24\x3e\x7e\x94\xec\xcd\x71\x09\x4d\x45\x62\xad\xf2\xde\xd3\x40\x87\x4a\xc6\xf1\x4a\x66\xe7\xf6\xce\xa1\x72\x40\x00\x28\x8e\xc8\xf5\x1a\x4c\x82\x5d\x50\x22\x6b\x5d\xda\x1e\xa3\x7e\x14\x96\xab\xa2\xcc\x73\x3b\x58\x6d\xb8\xbb\x8f\x11\x48\x03\x93\x6a\x07\x15\x33\xd3\x55\xb2\x1e\xad\x7e\x7f\x80\x25\x94\xb1\x25\x20\x34\x76\x80\xb9\x0c\x22\x38\x7b\xb3\xa5\x65\x2a\x5d\x59\xbf\x9b\xaf\xbf\x5b\xeb\x63\xed\x06\x12\x95\x80\xff\xdb\xa1\x01\xf7\xbb\xf5\xd4\xe7\x50\x87\xb0\x00\xc7\x69\x4f\xaf\x6d\xf8\xd6\x50\x78\xbe\x82\x8e\x9f\x0a\x79\x78\x28\x15\xd3\x88\x19\xa4\x33\x9a\x12\x6d\x91\x77\x8c\x1a\x3a\x2b\xfe\x3e\x80\xd6\x9c\x82\x2f\xfa\x0f\x55\xcb\x6b\x55\x82\x31\x36\x91\xd0\xc0\xa3\x31\x0a\x4f\x3e\xc2\x4c\x1a\x34\xee\x2c\x57\xdc\xb9\x28\x1a\x73\x07\x2c\x50\x3c\x4c\xa6\x11\x34\x8c\xa5\xe6\x20\xaa\xc3\x40\x16\xfb\x10\x61\xbf\x13\xb6\x0e\x7d\x50\x90\x31\x14\xd4\xae\x9a\x29\x66\x88\x60\x44\xe7\x8f\xba\x9c\x90\xf9\x40\xb4\x6d\xdf\xdf\x2d\x77\xb5\x6f\xb4\xd1\x4d\xe6\x6d\x07\x0d\xe5\x3a\xd3\x77\x80\xfa\x36\xa9\x8c\x60\x8f\x07\xbb\xa9\xe6\xbe\x3c\xdb\xf3\x9c\x54\xd8\x31\xde\x95\x34\xcc\x66\x97\x8f\x7f\x99\xbf\xf5\x55\x68\x3e\x9b\x25\xbe\x3b\x12\xe5\x6e\xfb\x0b\xb4\x18\x59\x12\xfe\x02\xd9\x1f\xd6\xb5\x5d\x07\x00\x00")
 
 func www20jumpJsBytes() ([]byte, error) {
 	return bindataRead(
@@ -193,7 +202,7 @@ func www20jumpJs() (*asset, error) {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "www/20jump.js", size: 1876, mode: os.FileMode(420), modTime: time.Unix(1448763309, 0)}
+	info := bindataFileInfo{name: "www/20jump.js", size: 1885, mode: os.FileMode(420), modTime: time.Unix(1792207460, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
@@ -978,7 +987,7 @@ func fileInitnodeTxt() (*asset, error) {
 	return a, nil
 }
 
-var _fileMessageEnTxt = []byte("\x1f\x8b\x08\x00\x00\x09\x6e\x88\x00\xff\x9c\x56\x51\x6f\xe4\xb8\x0d\x7e\xd7\xaf\x20\x36\xe8\x36\x0b\x74\x9d\x34\xdd\x7b\xe9\xaa\x2a\x32\x59\xef\x6e\x70\xb9\x49\x30\x9e\xc3\x76\x51\x14\x86\xc6\xa6\x6d\x35\xb2\xe8\x93\xe4\x38\xbe\x5f\x5f\x50\xf6\x4c\x82\x3b\xe0\x1e\xfa\x90\x50\x22\x29\x91\x32\x3f\x7e\x9c\x33\x71\x06\x3f\x61\x08\xba\x45\x68\x8c\x45\x68\xc8\x43\xee\x5a\x6b\x42\x27\xce\xe0\x86\x86\xd9\x9b\xb6\x8b\x70\x5e\xbd\x83\xab\xcb\xcb\x1f\xde\x5f\x5d\xfe\xf5\x07\x08\x9d\x71\x5f\xf2\x7d\x18\xe1\xc1\xd3\x7f\xb1\x8a\x99\x38\x13\xc2\x6a\xd7\x4a\x85\x4e\x88\x33\xe8\xd1\x8d\x70\xd0\x5e\x44\x1a\xa4\xda\xdf\x3f\x08\x87\x93\x54\xdb\xfc\x9b\x30\xae\xc6\x67\xa9\x6e\xb7\x9f\xf2\x7f\x89\xaa\xd3\xae\xc5\x20\xd5\xcd\xd7\xeb\xed\x97\xbc\x10\x1e\x2b\x74\x51\xaa\x5d\x7e\x93\x6f\xf7\x22\xa0\xf6\x55\x27\x55\x91\x5f\xef\x6e\xbe\x8a\x9e\xd7\x57\x37\x5f\xdf\x6f\x76\xf7\xdf\x8a\x7c\x27\x7c\x08\x52\xed\x8a\x82\x63\xd6\x18\x2a\x6f\x86\x68\xc8\x09\x5e\x97\xc7\x48\x2c\x80\x1a\xd0\x55\x87\x35\x6c\x36\x05\x9c\x07\xf2\x11\x6b\x38\xcc\xf0\x84\x96\x2a\x13\xe7\x77\xd9\x72\xe8\x94\xd1\x1f\x1f\x8b\xa6\xc7\x10\x75\x3f\x1c\xcf\x9d\x12\x4f\xd2\xce\x30\x0e\xb5\x8e\xcb\xc1\xd5\xe5\xf4\x98\x24\xa1\xf1\xd4\x43\x75\xba\x7d\x75\x42\xef\xc9\x4b\xb5\x27\x08\xfa\x09\x41\x3b\x72\x73\x6f\xe2\x9c\xc1\x7e\xf4\x0e\xa8\x69\x52\x91\x2a\x72\x01\xab\x31\x9a\x27\x84\x81\x42\x3c\x66\x4f\x7d\xbf\xa6\xa1\x03\x39\x88\x04\x1e\x7b\x7a\x42\x38\x37\x0d\xcc\x34\x42\x40\x57\xb3\x9a\x62\x87\x1e\x1c\xd5\x18\xde\x9d\xf2\x73\xb5\x54\x2f\x61\x8c\x0f\x31\x5d\x9e\x22\x3a\x9c\xd2\x47\x98\x3a\x74\xe9\xa6\x49\xbb\xc8\x37\xa5\x3c\x67\x1a\xfd\xab\x64\xb9\x1e\x91\x06\x18\x74\x8b\xc2\x52\x4b\x52\x9d\x40\x23\x5e\x15\x4a\xaa\x87\xab\x87\xf5\x1c\x8d\x81\x03\x88\x60\x22\x4a\x75\xdf\x34\xa6\x32\xda\x42\x61\x22\x8a\x10\x75\x1c\x83\x54\x45\x92\x42\xb7\x1e\x71\x79\xe8\xf5\x71\x29\xa2\x89\x16\xa5\xda\xb3\x58\x71\xf4\x52\xcd\xa5\x2c\x70\xb3\xec\x85\xb6\x56\xaa\x6b\x6b\x19\x51\x65\xa5\x23\xb6\xe4\x4d\xc2\xe1\x69\x9d\x1e\x7d\x55\x75\x30\x06\xf4\xa0\x5b\x74\x31\xf0\xb3\x12\xaa\x44\x63\x6c\x44\x2f\xd5\xe7\x24\x85\xc7\x16\x9f\x07\x0e\xd3\xe6\xcf\x83\x88\xba\x95\x6a\xaf\x5b\x11\xa2\x37\xdc\x15\x45\x92\xac\x2f\xf9\xf5\x8c\xae\x61\x8c\x10\x75\x1b\x20\x0c\xd6\xc4\x68\x5c\xcb\xb8\x0a\x83\xae\x30\x83\x4f\x04\x8e\x22\x87\x86\xb7\x36\x7e\xfc\x0b\xbc\x6d\xf9\xbf\x76\x35\xbc\xd5\xfd\xf0\x31\x13\xa1\xa3\x89\x3f\x2a\x4d\x9c\x14\x57\x49\x2c\xf5\x2b\x7e\x5f\x60\xe1\x74\x8f\x52\x6d\x75\x8f\xa2\xd7\xc6\x4a\x95\xbf\x67\x29\x82\x69\x9d\x8e\xa3\x47\xa9\x8a\xe3\x52\xe8\x18\x35\xc3\xf4\x3a\x49\x11\xc6\xa6\x31\xcf\x52\x15\x49\x8a\x15\x9f\x39\x0b\x30\xee\xa5\x11\xc4\x09\x7b\x37\xcb\x42\x70\x52\x52\x3d\xdc\x17\xfb\xb4\x2c\x0f\x54\xcf\x52\x3d\x30\xa0\x22\x3e\x47\xce\x5b\xd7\xbd\xe1\x6e\xb5\x25\xd3\x8f\x54\x9f\xf2\xbb\x7c\x9f\x27\x18\xb0\xd2\x63\x45\xbe\x3e\xa9\xaf\x77\xfb\xdb\x9b\xbb\x5c\x2c\x90\x96\x6a\x91\xa2\xd2\xae\x42\x2b\xd5\x22\x57\xce\x28\x1d\x4e\xeb\xa5\x6b\xbf\x25\xe0\xf6\xfa\x11\x8f\x50\x16\x95\x47\xcd\x58\x5b\x64\xc2\x6c\xe7\x51\xd7\xe2\x04\x48\xa9\x4e\x4b\x61\x75\x88\xa5\xf6\xd1\x54\x7c\xe9\x17\xe2\x8f\x1c\x3b\x04\xd6\xc3\xaa\xcf\x98\xf0\x4a\x6a\x4a\x06\x3e\x77\xf1\xc0\x0c\x12\x3b\x13\x52\x2b\x64\xe2\x40\x31\x52\xff\xe2\xb1\x49\xfb\xdf\x38\xa5\x48\x8b\x9d\xab\xcf\x7f\xac\x62\x0e\xfd\x8d\xda\xe1\x24\xc8\xd6\xab\x96\x6c\xcd\x38\xe1\x3f\x81\xb5\x89\x65\xc2\x61\x5e\x9b\x05\x69\xc2\x2f\x9d\x10\x44\x98\x5d\x55\x32\xff\x94\x0e\xe3\x44\xfe\x51\xaa\x62\x76\xd5\xf1\x15\x61\xe1\xa6\xd5\x26\x9e\x4c\x8d\xc4\xc4\x24\xd5\x77\x6e\xf3\x83\xa7\x89\x7b\xa2\x26\x0c\x09\xa6\x61\x1c\x06\xf2\x31\x7d\x8d\xe4\xcc\xe1\xb2\x85\x93\x2d\x46\x7c\x55\xcb\xf2\x17\xa9\x3e\x51\xe2\x8f\xc5\x06\x0d\x59\x4b\x13\xc3\x7f\x8d\x7e\x1e\xde\xfd\xf3\x04\x89\x3f\xf2\xdf\x6c\x8a\x73\x64\xe7\x99\xdf\xf5\x3d\x4f\x53\x20\xe1\x53\x38\x3a\x61\xc7\x11\x84\xb1\xea\x8e\xb7\xb3\x69\x81\xc5\xd1\xc0\x48\x70\xa3\xb5\x2f\xb5\xdd\x8e\xd6\xc2\xf5\xd1\x9f\x4d\x2b\xb7\x24\xc3\x42\x30\x07\x5d\x1f\xb5\x1b\x5d\x2f\xca\x0c\xbe\xd3\x08\x95\x76\x7f\x5e\x5a\xf7\xcd\xc5\xbf\xff\x93\x2a\xd5\xc6\x8f\x6f\x12\x9f\x68\x48\x67\xb2\xf5\xd6\x79\x38\x5d\x3a\x0f\x28\x0e\xa6\x5d\x73\xdb\x13\xc1\xc1\xb4\x69\x28\x8b\x0f\x97\x7f\x93\xea\x33\xf9\x83\xa9\x6b\x74\xbc\x5d\x5b\x89\xa3\xd5\xc4\xd1\x3a\xe6\xe0\x01\x7d\x6f\x42\x30\x0b\xef\xeb\xaa\xc2\x10\x16\x58\xfd\xbc\xbb\xcd\xe0\xd6\x85\xa8\xad\x05\xa9\xa1\xf3\xd8\xfc\xe3\x4d\x17\xe3\xf0\xf7\x8b\x8b\x69\x9a\x32\x26\xe7\x16\x63\x18\x33\xe3\x1a\xba\x78\xf3\xc2\xd6\xf2\x42\xab\x4c\x7c\xb8\xfc\x20\xd5\x96\x22\x7c\xa6\xd1\xd5\xbc\x5d\x53\xd8\x77\x08\x1e\x7f\x19\x31\xf0\xac\xfb\x79\x77\x0b\x93\x5e\x40\xd1\xb0\x27\x70\x2e\x9c\x41\x40\xff\x84\x3e\x83\xbd\x9f\xc1\xea\x88\x1e\x12\x7d\xfc\xff\x19\x39\x2a\x6b\x1d\xf5\xf2\xf5\xb5\x6f\x47\xa6\x9c\xc0\xb7\x6e\x09\xd8\x92\x89\x30\xe8\x7e\x85\x6c\x9a\x62\x96\xe8\x31\x80\x35\x8f\x08\x9a\x89\xb6\xcf\x56\xde\x3e\x0e\xdd\x1d\xb6\xa3\xd5\x1e\xf0\x79\xf0\x98\x3e\x64\x58\xf0\x94\x09\xec\x87\x38\x97\xd6\x30\xa3\x6d\x89\x31\x83\x01\x66\x8c\x19\x7c\xd3\x26\x82\x86\x06\x27\xe8\x8d\x1b\x23\x86\x44\xd3\x95\x35\xd5\x23\xfc\x29\xa4\x36\x58\xc6\x97\xb0\xc6\x3d\x62\x5d\x26\x4e\x96\xea\x2e\xed\x60\x9b\x18\xfa\xd1\xd1\xe4\x8e\x96\x1f\x79\xb3\x1a\x18\x01\x41\xaa\x14\x50\x2c\x98\x0e\x52\xad\xe0\x0c\x22\xfd\x7e\x28\x83\xf9\x15\x79\x76\x55\x1d\x42\x61\x7e\x45\x11\xd0\x36\xe9\x36\x66\x3f\xdb\xa4\x31\x90\x7e\x97\x99\x50\x89\x96\xa8\x4d\x14\x76\x7f\xff\xe5\x2e\x17\xd6\xf4\x26\x4a\x95\x84\xe8\x0f\x52\xfd\xb4\x11\x8f\x07\xa9\x7e\xdc\xf0\x98\xa4\xaa\xec\xb1\x97\x2a\x2d\xd3\x0f\x9a\x1e\x7b\xf2\xb3\x88\x14\xb5\x7d\xe5\x90\xf6\xf0\x3b\xb7\x8a\x9c\xc3\x8a\x67\x7d\x79\x1c\xe2\x2f\x2a\xc1\xb4\x71\x99\x98\x9b\x21\x93\xaa\x54\x8f\x98\xc6\x97\xc3\xf7\x93\x9e\xe1\x95\xb3\x47\xab\x67\xac\xa5\x1a\x03\xb7\x7f\xda\xae\xc0\x12\x34\xa0\x63\x53\xc3\xcd\xf4\xea\x4c\x6d\xc2\xba\x63\xeb\xeb\x9d\xf8\x5f\x00\x00\x00\xff\xff\x8b\x55\xca\x25\xfd\x0a\x00\x00")
+var _fileMessageEnTxt = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\x9d\x58\x6d\x73\xdb\xb8\x11\xfe\x8e\x5f\x81\x71\xa6\x57\x7b\xc6\x66\x7c\xee\x5d\x3f\xf8\x54\x75\xfc\xa2\x4b\xd2\x24\x76\x46\xd2\x35\xbd\xe9\x74\x34\x10\x09\x49\x38\x91\x00\x8f\x00\x23\x33\xbf\xbe\xcf\x2e\x40\x8a\xb1\x3b\xf7\xa1\x1f\x2c\x00\x8b\xb7\xc5\xee\xb3\xcf\x2e\xfd\x4a\xbc\x92\x1f\xb5\xf7\x6a\xab\xe5\xc6\x94\xf8\x71\x8d\x9c\xd9\x6d\x69\xfc\x0e\x53\x77\xae\xee\x1a\xb3\xdd\x05\x79\x9a\x9f\xc9\xab\xcb\xcb\x1f\x2f\xae\x2e\xbf\xff\x51\xfa\x9d\xb1\x6f\x66\x4b\xdf\xca\x4f\x8d\xfb\x4d\xe7\x21\x13\xaf\x84\x28\x95\xdd\x4e\xa6\xda\x0a\xec\xac\xb4\x6d\xe5\x5a\x35\x22\xb8\x7a\x32\x5d\x3e\x7e\x12\x56\x1f\x26\xd3\x87\xd9\x67\x61\x6c\xa1\x9f\x26\xd3\x77\x0f\xf7\xb3\x7f\x89\x7c\x87\x4d\xda\x4f\xa6\x77\x6f\x6f\x1e\xde\xcc\x16\xa2\xd1\xb9\xb6\x61\x32\x9d\xcf\xee\x66\x0f\x4b\xe1\xb5\x6a\xf2\xdd\x64\xba\x98\xdd\xcc\xef\xde\x8a\x8a\xfa\x57\x77\x6f\x2f\x6e\xe7\x8f\x9f\x17\xb3\xb9\x68\x3c\xf6\xce\x17\x0b\xba\xb3\xd0\x3e\x6f\x4c\x1d\x8c\xb3\x82\xfa\xab\xfe\x26\x6a\xa4\xdb\x48\x95\xef\x74\x21\x6f\x6f\x17\xf2\xd4\xbb\x26\xa0\xbf\xee\xe4\x17\x5d\xba\xdc\x84\xee\x2c\x8b\x9b\x06\x8d\xfe\x78\x5b\x30\x95\xf6\x41\x55\x75\xbf\x6f\x50\x9c\xdb\xb2\x93\x6d\x5d\xa8\x10\x37\xa6\x25\xc3\x63\xb8\x95\x9b\xc6\x55\x32\x1f\x4e\x4f\x8b\x74\xd3\xb8\x06\x26\x73\xd2\xab\x2f\x5a\x2a\xeb\x6c\x57\x41\xbf\x4c\x2e\xdb\xc6\x42\x9f\x0d\x3b\x29\x77\xd6\xeb\xbc\x0d\x06\x6b\x6a\xe7\x43\xaf\xbd\xab\xaa\xa4\x86\xf2\xce\xca\xe0\x64\xa3\x2b\x87\x45\xa7\x66\x23\x3b\xd7\x4a\xaf\x6d\x41\x62\x17\x76\xba\x91\xd6\x61\xdb\xd9\xa0\x9f\x2d\x70\xf3\x70\x8d\x69\x7c\xe0\xc3\xf9\x46\x38\x90\x8d\x70\xd8\x69\xcb\x27\x1d\x94\x0d\x74\x12\xeb\x09\x41\x33\x52\x96\xfc\x01\xd7\xcb\x1a\xc8\x12\xa5\xdb\xba\xc9\x74\x00\x8d\x18\x39\x6a\x32\xfd\x74\xf5\x29\xed\x73\xad\xa7\x0b\x84\x37\x41\x4f\xa6\x8f\x9b\x8d\xc9\x8d\x2a\xe5\x02\x43\x01\x53\x87\x16\x4e\x59\x70\x2b\xd4\xb6\xd1\x3a\x3e\xf4\xa6\xef\x8a\x60\x42\x89\x8d\x4b\x6a\x12\x8e\x8e\xde\x8c\x6e\x91\x77\x71\x2c\x54\x59\x62\x6b\x59\x12\xa2\x56\x39\xfc\xb4\x75\x8d\x61\x1c\x0e\x7d\x7e\xf4\x15\xfc\xd4\x7a\x18\x0a\xef\xb0\xc1\xd3\xb3\x18\x55\x02\xd1\x12\x34\xfc\xf4\x33\xb7\xb8\x6e\xab\x9f\x6a\xba\x66\x3b\x7b\xaa\x45\x50\x88\x84\xa5\xda\x42\xef\xc6\x50\x54\x2c\xb8\x25\xf9\x8a\x5e\x4f\xe8\xaa\x5b\x58\x4f\x6d\xbd\xf4\x75\x69\x42\xc0\x34\xe1\xca\xd7\x2a\xd7\x99\xbc\x77\x70\x4d\xa0\xab\xe5\x77\x65\xf8\xe9\x5c\x7e\xb7\xa5\x5f\x05\xdf\x7d\x07\xd0\xfd\x94\x09\xbf\x73\x07\x32\xaa\x3b\x90\x52\xe4\x25\x11\xfd\xb7\x78\xe9\x60\x61\x55\x05\xcb\x3c\xe0\x57\x54\xca\xe0\xe9\xb3\x0b\x6a\x61\xea\xad\x85\x41\x1b\x4c\x2e\xfa\xae\xf8\xa2\x1b\xb3\x31\xba\x58\xd1\xec\x64\xfa\xcf\x34\x94\xc3\x62\xd1\xda\x67\x6b\x7e\x19\x04\xa3\x55\x2a\x04\x45\x70\xbf\xe1\x56\xf8\x16\x1e\x45\x38\x2e\xb8\x15\x09\xe7\x33\x6a\x60\xd3\x63\x40\x89\x01\xc3\x77\xb1\x23\xe8\x71\x00\xca\xe3\x62\xc9\xdd\xd5\xda\x15\x1d\xc6\x04\xcc\xa0\x9f\x02\xbd\x5f\x15\x95\xa1\xa8\x2f\x57\x44\x63\x93\xe9\xfd\xec\xc3\x6c\x39\x63\x38\x91\x10\x68\x70\x4d\x31\x88\x6f\xe6\xcb\x77\x77\x1f\x66\x22\x86\xc6\x64\x1a\xdb\x34\x5c\xa9\x1a\x0e\xd1\x58\x9d\x3a\xa2\x82\x0d\x1b\x15\xe1\xfa\x71\xe8\xc7\x98\x19\xcf\xdd\x34\xc1\xe4\x25\x90\x13\x0f\x8a\x3c\xd1\xb4\x9e\x58\x20\xad\x73\x8d\xcf\xc4\xd0\x1f\x8e\x73\x8d\x20\xb2\x80\x82\xf8\x15\x2a\x9e\x33\x1c\x98\x14\x2b\x7a\x45\x0b\x70\x28\x9e\xf4\xc5\x10\xa5\xc2\x74\xb1\x2b\x0a\xe3\x2b\x43\x7c\x98\x3a\xc2\xba\x15\x6f\x50\x70\xf8\x83\x93\xaa\x57\xef\xa0\x1b\x3d\xd6\x71\xac\x5b\xae\x6c\xae\xb1\x3e\xb6\x89\x7f\x57\x88\xfd\x64\xd8\xc4\x5d\x4c\x02\x95\xda\xeb\x9e\x16\x44\xde\x68\x7e\x41\x6c\x39\xfe\x77\xe8\x16\x62\x08\x6e\x18\xb4\xef\x22\x57\xc0\x8f\xc3\x3b\xdf\x38\x02\x2c\xf0\x2a\x49\xde\x2b\x9a\x51\xf2\x58\xb9\xcd\x8a\x48\x84\x18\xb1\x26\x36\x0e\x3b\xe3\x99\x56\x32\xb1\x76\x21\xb8\xea\xb8\xe2\x96\xc7\xcf\x16\xf1\x4d\x71\x9e\x22\x89\xfe\x48\x44\xf9\xe8\x99\x18\x12\xe1\xca\x22\x49\xd1\xa3\x98\xa3\x3f\xa1\x0b\x13\x56\x1c\xd3\x33\xf4\x38\x6a\xe1\x13\x66\x15\x2f\x7c\x67\xf3\x15\x71\x39\xac\x14\x0e\xae\xd9\xc3\x48\x10\x1d\xcd\xcd\x3c\x9f\xe6\xc4\x17\x53\x68\x47\x24\x3f\x99\xfe\x4a\x94\xb9\x6e\xdc\x81\xf8\xa5\x70\x58\x49\x21\xef\xdb\xba\x46\x96\x61\x6b\xf0\x62\xba\x2e\x8b\xf9\xad\xd4\xb0\xec\x11\xcf\xab\xdf\x01\x18\xc7\x5c\x1c\xe7\x40\x59\x65\xe9\x0e\x44\x25\xe9\xf6\x53\x7f\xf6\xf7\x21\x2c\xfe\x68\x3d\x5c\x78\xaa\x69\x71\x47\xef\xfa\x75\xc6\x19\x95\x63\x34\xc2\x28\xc6\x8f\x05\xdb\xb7\x70\x7f\x3a\x9d\xa6\x22\x2c\xfa\x09\x42\x82\x6d\xcb\xf2\xe8\xdb\x07\x8c\x64\x0f\x64\x9e\x4a\x3c\xcd\x13\x91\xac\xd7\xaa\xe8\xa5\xb7\xaa\x88\xc2\x4c\xc2\x3e\x48\x8f\xf6\xcf\x91\x06\x4f\x5e\xff\xfb\x3f\xec\x29\x38\xe4\x84\xb9\x59\x49\xde\x93\xa5\x53\xbb\x7a\x38\x14\x5d\xb1\x36\xdb\xa4\xdb\xd2\x39\x89\x11\x17\x38\xe2\x87\xcb\xbf\x80\xb4\x5d\xb3\x36\x45\x81\x52\x05\xc3\x44\x27\x74\x5b\xe1\xe8\xb6\x1d\xe5\xb3\x5a\x37\x14\x43\x26\xe6\x50\x95\xe7\xa8\x92\x22\xac\x7e\x99\xbf\xcb\xe4\x3b\x0b\xae\xc2\x55\x13\x25\x81\xf2\xcd\xdf\x4e\x76\x21\xd4\xd7\xaf\x5f\x1f\x0e\x87\x8c\x12\xdd\x56\x07\xdf\x66\xc6\x6e\xdc\xeb\x93\x63\xe6\x9b\xbc\x56\xd3\x0c\x77\xfe\x40\xf1\x18\xe4\xcf\xae\xb5\x05\x0d\x93\x0a\xcb\x1d\x85\xe5\xef\xad\x66\xc6\xc0\x3d\x48\xb1\x11\x14\x1b\x5a\x29\x49\x17\xd2\x00\x78\x01\xdd\xa2\x20\x68\x3a\x04\x0c\xd2\x8f\x64\x0a\xfd\xff\x35\x82\x1b\x41\x3f\x2a\x5a\x5f\x35\xdb\x96\x68\xd7\xd3\xa9\xa0\x0d\x9a\x41\xba\xa9\x55\x95\x20\xcb\x15\x41\xe9\xdc\xde\xcb\xd2\x80\x01\x14\x25\xad\x2a\x4b\x39\xb0\x2f\x60\x90\x09\xdb\x52\x35\x12\x22\x84\x0a\x19\xd2\x47\x3c\x65\x42\x57\x75\xe8\x56\xa8\x31\x03\xf3\x12\x30\x03\xec\x77\x3a\x64\xf2\xb3\x42\x78\x29\xb9\x01\xa7\x80\xcf\xdb\x00\x39\xa5\xbc\xbc\x34\xf9\x5e\xfe\xc9\x73\x18\xc4\x52\x40\x94\xc6\xee\x91\x7f\x38\xbf\x4d\xa6\x1f\x78\x04\x75\x29\xdb\xed\xad\x3b\xd8\x7e\xe6\x3d\x0d\xd2\x04\x21\x00\x22\xbe\x50\x44\x4c\xfb\x23\x6d\x0b\xae\xc5\x90\xd1\xbe\x6a\xaa\x03\xd0\x47\xed\xf1\x15\xb5\x87\x2e\x37\x7c\x1a\xb1\x5f\xb9\xe1\x94\xca\x35\xae\xf1\xb9\xd8\x3a\xb7\x65\x0a\x7b\x7c\x7c\x83\x8c\x52\x1a\x94\x3f\x93\x29\x37\xa2\x5a\x83\xe0\x6f\xc5\x1e\xcd\xfb\x5b\x2a\x39\x1c\xf2\x85\x86\x19\xb9\xcb\xc5\x21\x86\xae\xe9\x40\x74\xf0\xdd\x68\x01\x8f\xe5\x8b\x65\x28\xfa\x2c\xaa\x6d\xd8\x72\xd5\x17\x44\x47\x91\x20\xda\xb8\x64\xe6\x26\xc8\xb0\x97\x8a\x56\x73\x29\x60\xf5\xc5\x41\x75\x72\xb4\xb8\xd1\xa5\xea\x28\xa5\xb4\x9e\xc2\x9f\x87\x09\x58\xc2\xd5\xda\xd2\xd4\x86\x82\x69\xb4\x07\x79\x25\x8d\x68\x76\x3c\x12\x7b\xdd\x1d\x98\x21\xde\xc7\x4e\x4a\x3e\xbe\x2d\xa3\x8f\x2b\x15\x60\x4f\x1f\x81\x0c\x62\xe7\xd4\xb0\x8a\x52\xec\x8a\xe3\xb4\xaa\x10\x08\x81\xa6\xeb\x81\x44\x98\x64\x41\x4c\xb8\xdc\x9d\x4c\x3f\xd3\x25\x0c\x8e\x93\x7a\xd7\x28\xaf\xfd\x09\x90\xab\xe5\xcd\xc3\xbd\x2e\x32\xf9\x38\x3f\x97\x0f\x8f\x4b\x79\x7a\x41\xda\x9c\xf1\xc2\xd3\x2c\xcb\xce\x88\x51\xe4\x5a\x13\xa5\x60\x59\xac\xde\xfc\xb5\xa4\x02\xe9\x5a\xd6\xed\x1a\x0f\xb9\x26\xc6\xbd\x4e\xe9\xeb\x1a\x05\x0d\x32\xe1\xb5\x6c\x6d\x30\xe5\xb5\x3c\xc5\x57\xd0\x5f\x2f\x2e\xbf\xbf\xb8\xbc\x3a\x03\x53\xf8\xeb\x58\xe5\xc8\x58\xdd\x5c\xd7\x28\xf2\xd6\x2a\xdf\xb7\x35\x29\x4e\x6d\xd4\xba\x97\xdd\x03\x8c\xa5\xa3\x28\x93\xde\xaa\x1a\xe5\x5b\x88\xf9\x4a\x73\x9c\xad\xf1\x90\x4c\x6c\x7c\x8e\x24\x72\xb7\xd3\x00\xfd\xfd\x6d\x3c\x60\x2c\xa3\xda\x1f\xc1\xa3\x6d\xde\x3d\xdf\x1c\x4b\xc4\x46\xd7\xca\x80\x12\x42\x3c\x6b\x95\x23\xe3\x51\x6d\x4b\x4d\x94\xd4\x8d\x5b\x97\xba\x82\xf0\x53\xea\x45\x39\x9e\xcf\x3e\x8c\xa3\x42\x07\xae\x16\xef\xb9\x8d\xb2\x78\x36\xc5\x37\xb5\x63\x19\x39\xb2\x3f\x4d\xaa\x35\x7d\x74\xa4\x4a\x23\xce\x26\x65\x00\x8c\x74\x3b\x23\xa3\xd7\xa4\x87\xc6\x1a\xbf\x9c\x08\xb8\x4d\xd6\x4b\x32\x94\xd6\x94\x1a\x53\xe4\x32\x31\xcb\x38\xc7\xe4\x1e\x1f\x6f\x2a\x5e\x04\x22\x01\x5f\x32\x09\xa0\x5a\x31\x70\x51\x0a\x8c\x04\xda\xbe\xe2\x48\x89\x19\xac\xc4\x87\x3f\xbb\x4b\xc4\xd3\x06\xe9\x3b\x1e\x8e\xb5\x5a\x45\x9c\xe0\x83\xc5\xea\x98\x90\xa8\x9a\x45\xc4\x81\x1c\x2d\xb2\x18\x3e\x32\x12\x94\x3c\x03\x34\x5e\x83\x98\xc6\xf7\x18\x0a\x98\x5d\xdc\x13\xa1\x0c\xdc\xc5\x35\x44\x90\xbd\x29\x56\x0c\x40\x2a\xd0\xd1\x88\x6f\x2e\xf5\x94\x33\xb8\xd3\xcb\x07\x52\x7b\x00\x85\xa6\x41\x3f\xa7\x9f\x00\x1a\x4f\x2f\x43\x1b\x83\xfe\x9b\xf9\xa1\xca\x9c\xa7\xd2\xf0\xd9\x7c\x4c\x01\x0b\xfc\xbe\xdc\xf9\x5b\x22\x85\x79\xea\x3d\x5f\x81\xa8\x6d\xe3\x7c\xef\xba\xf2\xc5\x47\x4a\xb4\xcc\x60\xf9\xf8\x35\xfc\x8d\x8c\x52\x3e\xbf\xf7\x9c\x72\x53\x6f\xd5\x83\x81\x15\x15\x19\x0f\x00\xf0\xf2\x1f\x8b\xc7\x87\x73\xf9\x76\xf9\xf1\x83\xa4\x52\x15\x1f\xc8\xe7\xfc\x19\x87\x10\xa1\x5d\xd5\xda\x3d\x65\xe3\xc3\x63\x61\xb7\x1c\x1f\x46\xb8\x18\x7c\xd1\xfb\x0b\x47\x2b\xf9\xd5\xd4\x0c\xb5\x01\x2e\x28\x44\xc0\x5a\x5c\x52\xa0\x15\xf1\xa3\x1f\xaf\xc5\x37\x11\x77\x25\xbf\x3c\xde\xf7\x62\xd2\x53\xf8\xf6\x68\x3e\x20\xf7\x91\x57\x60\x95\xb5\xfe\x5f\x06\xe2\x37\xf3\xe4\x16\x85\x2e\xea\x6f\x2e\x2c\x31\x5f\xa1\x14\xd8\xe9\x8e\xd5\x6d\x34\xbe\x34\xa1\x2d\xd3\x15\x4d\x76\xf4\x02\x84\x5f\x76\xd4\x6d\xb5\x37\xf4\xa1\xf8\x1e\xbf\x23\x61\xd0\xf4\x4d\xbc\xc4\xef\x48\xb8\xd1\x81\x3e\xe1\xde\xe8\xd1\xd3\x52\x2e\xa4\x94\x3a\xde\x1e\xbf\x9f\x97\xd4\x8c\xd7\xe2\x03\x8d\xd0\xf8\x84\x80\x03\x85\x1f\x27\x12\xbb\x7f\xa0\x82\x3f\xd6\x99\xa3\x39\xd6\xb8\x60\xb7\x53\x67\x34\x45\xef\xeb\x08\x47\x68\x60\x96\xc3\x68\x2a\xd6\xb4\xc4\x56\x5c\x27\x8f\x8e\xa3\x70\x62\xb2\x69\x93\xd9\xc9\x52\x11\x92\x04\x85\x12\x18\xa1\x43\xef\x53\x2f\x7a\xeb\x28\x9f\x73\x1e\x63\x67\x91\x91\xd8\x49\x40\x48\x3c\xac\x67\xe0\xe8\xc6\x67\x2e\x3b\x8f\x78\xa2\x64\x4d\x31\x97\xf3\x19\x9a\xf2\x05\x4d\x1f\xef\x5e\x51\x81\x59\x73\x04\xdd\xa4\xde\x71\x6e\x64\xee\x41\xd6\xa7\xd6\xa8\xda\x51\x8e\x94\xcc\x1f\xf3\xd4\x1c\xa5\x0e\xa9\xe3\xf1\xfd\xe8\xc4\x6d\x2c\x40\xd5\x8b\xbb\xe8\x2b\xdc\xb5\x81\xfe\x8b\xc2\x9d\xf1\x8d\x1b\xca\x99\x74\x25\x77\x8e\x33\xe3\x2f\xf9\x91\x26\x7b\x53\xd7\xb4\x7e\x11\x3b\xa3\xf5\xd1\x1d\xcb\xa3\x09\x77\xa9\xcc\x5d\x6b\x6d\x07\xe4\x2b\xdb\x45\x23\x66\xe2\xbf\x81\x15\x05\xae\xa7\x14\x00\x00")
 
 func fileMessageEnTxtBytes() ([]byte, error) {
 	return bindataRead(
@@ -993,12 +1002,12 @@ func fileMessageEnTxt() (*asset, error) {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "file/message-en.txt", size: 2813, mode: os.FileMode(420), modTime: time.Unix(1491401929, 0)}
+	info := bindataFileInfo{name: "file/message-en.txt", size: 5287, mode: os.FileMode(420), modTime: time.Unix(1792211513, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
 
-var _fileMessageJaTxt = []byte("\x1f\x8b\x08\x00\x00\x09\x6e\x88\x00\xff\xb4\x57\x4b\x73\xe3\xc6\xf1\xbf\xcf\xa7\x60\x59\xf5\xdf\xda\x3d\x78\x57\xff\xcd\xfa\x92\x45\x70\x70\xca\xe5\xaa\xa4\x5c\xb5\x15\xe7\x96\x4a\xa1\x20\x70\x44\xc1\x0b\x02\x0c\x00\x59\xab\x9c\x38\x03\x3d\xa8\x07\x45\xae\x56\x0f\xeb\x15\x49\x2b\x8a\xa2\x44\x8b\x94\xed\x7d\xe8\xb9\xfc\x30\x4d\x00\xe4\x29\x5f\x21\xd5\x03\x90\x22\x25\x96\x7d\x49\x4e\x7c\xcc\x4c\xf7\xaf\x7b\x7e\xfd\xeb\x9e\x21\x32\x94\xf8\x86\x3a\x8e\x9a\xa2\x89\x51\xdd\xa0\x89\x51\xcb\x4e\xfc\x49\xcd\xa8\x26\x75\x28\x19\x4a\xfc\xd1\xca\x4c\xda\x7a\x6a\xcc\x4d\x3c\xd4\x1e\x25\x9e\x0e\x0f\x7f\xf1\xf9\xd3\xe1\xff\xff\x22\xe1\x8c\xe9\xe6\xd7\x5f\xfd\xd5\x19\x4f\xbc\xb0\xad\xef\xa8\xe6\x3e\x26\x43\x84\x18\xaa\x99\x92\xe4\xef\x54\x42\x86\x12\x69\x6a\x8e\x27\x46\x54\x9b\xb8\x56\x46\x92\xc1\xcb\x81\xe7\x81\xb7\x4e\x4c\x3a\x21\xc9\xc1\xda\x59\xab\x5c\x68\xde\x6c\x07\xb9\x22\xd1\xcd\x24\x7d\x25\xc9\xcd\xf3\x6c\xab\x7c\x44\xb4\x31\xd5\x4c\x51\x47\x92\x83\xed\x6c\xf8\x9e\x07\x5b\xef\x82\xb5\x33\x62\x53\x8d\x9a\xae\x38\x18\xee\x64\x03\x6f\xda\xdf\xfb\x89\x38\x54\xb5\xb5\x31\x49\x0e\x4a\xdb\xe1\xbb\xb7\x24\x8d\xdf\x9f\x6a\x63\xe0\xad\x81\x77\x0c\xbc\x0c\xfc\x03\xb1\x1d\x47\x92\xff\xf2\xed\xb7\x08\xc9\xb5\x32\x89\x8c\x9a\xa2\xc4\xb0\x52\x96\xb0\x15\x6c\xe7\x48\x92\x3a\x9a\xad\x67\x5c\xdd\x32\x25\xf9\xc5\xd3\x17\xfe\x62\xc3\x2f\xe6\x83\xa5\x9f\xc3\xd2\x65\xb0\xd3\x20\x8e\xee\x52\x49\xf6\xa7\x7f\xf4\xaf\x0b\xc0\xdf\x03\x2f\x81\x97\x23\x8e\xab\xba\xe3\x8e\x24\x87\xf3\x1f\x82\xe9\x05\xa2\xa6\x6c\x4a\xd3\x02\x22\x78\x79\x11\x6a\x0e\xbc\x3a\x78\xd7\xc0\xeb\x7e\xee\x38\x5c\xa9\xb4\xca\x85\xf0\xdd\x14\x71\x75\xd7\xa0\x92\x0c\xbc\x11\x59\x02\xaf\x1a\x47\xa7\xf4\x86\xde\x6a\xbc\x06\x56\x8b\xa3\x57\x0d\x03\x11\x54\xda\x5e\x05\xa3\x54\x34\xd5\xa5\x29\xcb\xd6\x71\xaf\x7f\xc6\xa3\x80\xc3\x95\x0a\xf0\x2a\x78\x33\xc0\xdf\x81\x77\x02\xde\x35\xc6\xdc\x13\x9d\x88\x54\x89\xb3\x0d\xde\x2c\xf0\x03\xe0\x17\xc0\xeb\xc0\xaa\xcd\xc6\x8e\x7f\xfa\x03\xb0\x55\xe0\x8b\x90\x65\xad\xd9\x13\x7f\x61\x35\xdc\x9c\x02\x56\x8d\x30\xc4\x4b\x7c\xa1\x9b\x18\x60\xb5\xe8\xca\x1e\xf6\xc0\x3d\x07\x96\x6f\x7d\xba\x06\xd6\x08\x56\xcf\xda\x7b\x33\x8f\x22\xa7\xdd\xc8\xfe\xab\x6e\xc5\x8e\x60\x83\xfb\xb9\xab\x5b\x57\x5d\xa6\x08\x50\xbd\x88\x80\xd5\x80\x71\x60\x07\xc0\x76\xef\x9b\x8b\x4e\x77\x28\xf5\x6b\x38\x59\x19\xd8\x54\x3f\xa4\x05\xe0\x73\x31\x0b\x85\x19\x6a\xdb\x96\x2d\xc9\x31\x95\xb2\x47\xe8\xa5\xb1\x13\x2c\x32\x81\x61\x17\x38\x7e\x09\x8e\x77\x5b\xde\x0d\x64\x79\x3b\x7b\x10\x7e\xd8\x0c\xe6\x57\xc3\x4a\x03\xd8\x06\xf0\x05\x60\x15\x60\x79\x60\xf5\x70\x6a\xdf\x9f\xbf\x00\x56\x05\xb6\x2e\x1c\x17\x80\xed\x21\x0e\x36\x15\x67\xd6\x4a\x47\xb4\x6b\x5e\xad\xa1\x71\x6f\x09\x39\xe7\xcd\xe1\x11\xce\xdb\xd9\xcd\x70\xf7\xb0\xdf\x66\x15\x58\xdd\x9f\x9b\x6f\x6f\x94\x80\xd5\xc2\xe2\x4c\xb8\xf2\x13\xf0\x65\x91\xa8\xa9\x81\x2e\x1c\x6a\x26\x25\xb9\x37\x63\xc1\x76\xd6\xcf\xed\xdc\xb9\x70\x60\x47\x90\x65\xc0\x4e\x80\xcd\x63\x46\x58\xe9\x36\x7c\xbe\xdc\x6c\xec\x00\xdb\xc7\xd8\xd1\x4b\x84\x64\x0f\xd8\xeb\x5f\x0b\x90\x0c\x25\x04\x5b\xc9\xa8\x6e\xb8\xd4\xc6\x5b\x59\xc5\x5b\xf1\xaa\xc0\x1b\xc4\xa6\x29\xfa\x2a\x23\xc9\xc1\xe9\x41\xab\x5c\x68\xed\x57\xc2\xc2\x27\xe2\xaa\xa9\xb8\xb6\xce\x88\xe3\xda\x3a\xea\x51\xb0\x36\xeb\x9f\xae\xfb\xb9\x75\x5c\x55\x30\x24\xdc\x72\x01\xde\xa6\x28\xcf\x0b\x60\x47\xfe\xe2\xa5\x9f\x9b\x15\xd4\x28\x47\xa7\x81\x2f\xfb\xd3\x87\xfe\xfc\xd6\x7d\x5c\x90\xe5\x0f\x0c\xf7\xf9\x83\x94\xfb\xfc\x81\x9a\xce\x3c\x07\x56\x6f\xde\x34\x80\xe5\x80\x7d\x02\xb6\x05\xfc\x0d\x64\x39\x71\xc6\xac\x09\x49\x46\x58\xa5\x4b\x8c\x24\x63\x39\x2e\x89\x52\xf9\x9b\x57\x45\x4c\x35\x8d\x9a\x53\xcc\xfb\x73\x79\x92\x56\x75\x43\x92\xbf\xfa\x1c\x3f\x89\xa3\xa7\x4c\xd5\x1d\xb7\xa9\x24\x87\x37\x3f\xfb\xc5\x3c\x51\x5d\x57\x15\x2a\xf8\xf1\xaa\x79\xf5\x83\x48\xd1\xbe\x90\x96\x2a\x71\xc6\x47\x47\xf5\x57\x92\x1c\x2c\xec\xfb\xd7\xef\xfd\xd3\x22\x89\x89\xd9\x57\xa8\xa2\x80\x80\x55\x5b\x27\x25\xff\x63\x8d\x74\x19\x05\xfc\x17\xf0\xf6\xc1\xfb\x05\xf5\x0e\xe1\x4b\x72\xc4\x51\xf1\x43\x19\xb1\x92\x93\x58\x66\x3f\x06\x6b\xb3\x18\xa0\x9a\x4c\xeb\xa8\x31\x86\x82\x8d\xa4\x9f\x30\x11\xdf\xc4\xa2\x4d\x35\xcb\x4e\xf6\x43\xb8\xdd\x61\xd3\xb4\xf5\x3d\x86\x1e\xfd\xd4\x54\x53\xa3\x06\x42\x39\x05\xef\x00\xa1\xf0\x2b\x11\x98\x28\x53\xc5\xa4\x13\x1d\x67\x28\x15\xeb\xc0\xa6\x6e\xbd\xf2\xe5\xe6\xcd\x76\x2f\xef\xa3\x02\x8d\x33\xac\xd9\x54\x75\xe9\x9d\x4e\x84\x3d\x62\xcc\xa6\x6a\x92\xa8\xa6\x65\x4e\xa6\x2d\x54\x78\xbf\x98\x0f\xa7\xf6\x85\xf5\x55\xe0\x6f\x88\xa1\x3a\xae\xa2\xda\xae\xae\x09\xc7\xdb\x59\xe1\xbb\xaf\x14\xb0\xe7\x29\xd6\xa8\x82\xcd\x06\x59\x1b\x11\xed\x1c\xc3\x9c\xce\xb5\xf7\x4e\xc9\x88\xe5\xba\x56\x7a\xf0\x96\xe6\xf9\x02\xb1\x51\x2b\x5b\x8d\x95\x66\x63\x9f\x38\x19\x44\x14\x5d\x62\xe9\x88\xd8\x34\x39\xae\xe1\xed\x9f\xd7\xfc\xb3\x42\x84\x26\x32\x22\x48\x69\xb8\xcf\x23\x48\xd8\x68\xef\x2e\xac\x9d\x11\xcb\x48\xc6\xff\xfa\x85\x92\xa0\x70\xca\x7d\x4e\x68\x52\x77\x95\x9e\xda\x01\xbe\x1c\x7e\xac\xb4\xb7\x66\xe2\x6c\x39\x93\xa6\xa6\x8c\xda\x56\x5a\x31\xa9\x3b\x61\xd9\x2f\x07\xb5\xb9\x48\x06\x51\x39\xf1\x27\x5e\x80\x5f\x5c\x0c\xb6\x77\x63\x1b\xdf\xeb\x49\x6a\xa1\x32\x4a\x32\x8a\xfc\xca\x15\x6e\x98\xc9\x87\x2b\xbb\x1d\x7d\x42\x65\x12\xbb\xba\x20\xb0\xdf\x7a\x3b\xa2\x4c\x72\xe2\x06\x76\x7b\x9b\x3b\xb0\x45\xbf\x31\xdd\x2a\x33\x94\x1e\xb6\x11\xb5\x3b\x83\xba\x94\x4c\x8a\x5e\xc3\xea\x91\x86\x75\x48\xa7\xfc\x43\xf0\xd5\xbf\x79\x23\x7c\xbd\x01\x56\x7b\x88\x1f\xd8\x60\xe6\x80\xd5\x1e\xf5\x71\x92\x2f\x77\x54\x72\x5d\x14\xf6\x06\xb0\x85\x7f\x5f\xef\x76\x19\xfe\xdb\xd6\x7a\xa8\x38\xd8\x14\x19\x4a\x88\x82\x24\xa6\xd5\xad\x0b\x60\x28\xac\xc0\x73\xc0\x66\x80\x9d\xf4\x97\x49\x1d\x1b\x18\x9f\xef\x13\x1a\xd3\x8a\x6b\xe0\xee\xc9\xdb\xfa\x1b\x78\x6c\xdc\x30\x7a\x68\x7c\xa7\x1a\x67\xa6\xfd\xda\x05\xb0\xc5\xf0\xf8\x32\x4a\x6e\xf7\xc8\x80\xf9\xe5\xee\xbe\x11\x35\x39\x78\x5b\x15\xb2\x8b\x4f\xfe\xf6\xf7\x8e\x7a\x42\x36\x2f\xfa\xe1\xb1\xe8\xaa\x0b\x78\x9d\xc5\x2a\x82\xec\x36\xd9\x28\x59\x59\xde\x93\xd7\xfa\x5d\x93\x03\xd5\x37\x82\x3a\x99\xc1\x42\xa9\xd4\xda\xfb\xff\xba\x87\x51\x4f\x75\xd2\xd6\xa3\x98\x08\xa1\x74\x24\xe4\x62\x03\xd8\x52\xd7\x3f\x79\x36\xfc\x3b\x49\x0e\xab\x0b\xfe\xf4\x61\x58\x66\xc1\xe9\xdb\xf8\xcf\x58\x05\xfb\x6c\xf0\xe5\x48\xf5\x23\x5a\x07\x95\xe3\xf6\x46\x11\xd8\xe2\xfd\x3b\x90\xd4\xc4\x98\x4d\x47\xff\xf0\xd9\x98\xeb\x66\x7e\xff\xe4\xc9\xc4\xc4\xc4\x63\x1c\xac\x53\xd4\x75\xc6\x1f\xeb\xe6\xa8\xf5\xe4\xb3\x78\x4a\x95\x9e\xa8\xb2\xa8\x87\x92\x10\xc1\x0b\x11\xfe\xb5\x40\x3c\xa0\x3d\x91\x67\xc3\xcf\xee\x05\x26\x6e\xb6\x24\x8a\xb4\x9f\x09\xcf\x86\x9f\x75\xc4\x7c\x83\xb7\xd7\xde\xa0\x1f\x6c\xe1\x38\x0d\xb4\x8e\xcb\x1d\x0f\x8d\xfb\x7e\x84\x99\x5d\x60\xf5\xff\x5d\x24\xa6\xa5\x24\x55\x57\x95\x64\xff\x7a\x35\x58\x3d\x03\xb6\x18\x9c\x1e\x88\xad\x05\x31\x6a\x4c\x61\x40\x59\x76\xab\x3a\x83\x12\x4d\x9c\x8c\x9a\x8e\x9b\xfe\x6b\xf0\xf6\x44\x4b\x68\x88\xf3\xd1\x44\xf7\x29\x16\x97\x2c\x8f\xc7\x8a\xce\x04\xd7\x3b\x5c\x88\xe2\xaa\xa0\xfa\x78\xd7\xb7\x44\xa2\xe9\x8c\x3b\xa9\x18\x3a\xb6\x47\x61\x68\xaf\xa7\xf0\x06\x60\x11\x9e\xce\x04\x95\x0b\xfe\xa7\xe9\x78\xe6\x10\xd2\xf9\x7f\x8e\x48\x4c\x5d\x4c\xef\x9e\x50\xd4\x41\x29\x21\x43\x89\xe8\xf5\x41\x0c\xdd\x7c\x49\x93\x8a\x69\x25\x51\xef\xda\x9b\x07\xc1\xd2\x61\x77\xac\x20\x2f\x4d\x6b\xc2\xec\x2c\x06\x4b\x6f\xb1\xf9\x75\x17\x91\xfb\xce\x9d\xa9\x6e\x55\xbc\xb3\x2c\x3b\xe9\xdc\x13\x04\x5c\xd3\x54\x6d\x8c\x2a\x8e\xfe\x4f\x7a\xdb\x90\x3d\xe0\x1f\xc1\x3b\x8c\xdf\x45\xfc\x92\x38\xd4\x18\x15\x3e\x25\x19\xa7\xf9\xdc\x4c\x6b\xf6\xa4\x75\x59\xed\x9d\x77\xc4\xeb\x50\x77\x34\x62\xe8\x69\x3d\x9a\xd4\xb1\xb1\xa5\x47\x24\xf9\x9b\x2f\xc9\xcb\x11\x49\xfe\xf3\x97\x24\x65\x59\x29\xac\xce\xaf\xc5\x27\xbe\x80\x2c\x4d\x49\xd3\xb4\x24\x37\x6f\x1a\xe1\x4a\xa5\x79\x7e\x2a\x86\x93\xb7\xe0\x9d\x10\xd7\x72\x55\xa3\x67\x4b\x64\x31\xda\x78\xbb\x4b\xb3\x4c\x93\x6a\xf8\x12\x52\x3a\xaf\xb7\x60\xe9\x30\xfc\xb0\x49\x32\x96\xed\x0e\x4b\x72\x38\x37\xeb\xb3\x77\xd1\x7f\xdd\x41\x3d\xd8\x3a\xc7\x2c\xe0\xc4\x1e\x5f\x22\xb1\x32\xd4\xa4\x49\x49\x0e\xb7\xce\x9b\x97\xcb\xb1\x8d\xa4\xee\xc4\x0e\x68\xb2\x63\x38\xd8\x3e\x21\xff\x09\x00\x00\xff\xff\xae\x3e\xf2\x90\x62\x0f\x00\x00")
+var _fileMessageJaTxt = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\xb5\x59\x5b\x53\x1b\x47\x16\x7e\x9f\x5f\xa1\x72\x6a\x53\x76\x95\x2f\x98\x4d\xf6\x81\xb0\x54\x6d\x36\xa9\x6c\x25\xeb\x38\x95\xe4\x6d\x6b\x4b\x35\x48\x8d\x98\x20\xcd\x28\x33\x23\x63\xf2\xa4\x99\xe1\x22\x23\x30\x8a\x6d\xc0\xd8\x60\x2e\xe6\x22\xc0\x08\x12\x63\x1b\x73\xd3\x8f\x69\xcd\x8c\xf4\xb4\x7f\x61\xcf\xe9\xee\x19\x8d\x2e\x49\x5e\x76\x1f\x0c\xf6\x74\xf7\xe9\x73\xfd\xce\x77\xda\x1f\x48\x1f\xc4\xee\x10\xc3\x90\x53\x24\x36\xa4\xa4\xe1\x87\xa6\xc7\xbe\x94\xb3\xb2\x4a\x0c\x02\x6b\x7f\xd7\xb2\x63\xba\x92\x1a\x36\x63\x57\x13\xd7\x62\xbd\x3d\x3d\x1f\xdf\xe8\xed\xb9\xfd\x71\xcc\x18\x56\xd4\x2f\x3e\xff\xde\xc8\xc5\xbe\xd1\xb5\x1f\x48\xc2\xbc\x29\x7d\x20\x49\x69\x59\x4d\xf5\x0f\xfc\x20\x4b\x70\x32\x43\xd4\x5c\x6c\x50\xd6\x25\x53\xcb\xf6\x0f\x50\xa7\x40\x1d\x87\x3a\x8b\x92\x4a\x46\xfb\x07\xbc\x85\xa3\xfa\xf6\x5c\xed\x62\xd9\x2b\x94\x24\x45\x4d\x92\xfb\xfd\x03\xb5\x93\x7c\x7d\x7b\x47\x4a\x0c\x83\x10\x62\xc0\x9e\xe5\xbc\xff\xc6\xf6\x9e\x1f\xc3\x66\x49\x27\x09\xa2\x9a\xec\xa0\xbf\x92\xf7\x9c\x09\x77\xed\x17\xc9\x20\xb2\x9e\x18\x86\x8f\x9b\xcb\xfe\xf1\x86\x94\xc1\xbf\xf7\x26\x86\xa9\xb3\x40\x9d\x5d\x6a\x6f\x53\xfb\xad\xa4\x1b\x20\xea\xdb\xef\xbe\x43\x95\x40\x93\x58\x16\x2c\x95\xd2\x5a\x4a\x63\xb2\xbc\xe5\x82\x94\x24\x46\x42\x57\xb2\xa6\xa2\xa9\xfd\x03\xdf\xf4\x7e\xe3\xce\x54\xdd\xd2\xac\xf7\xf0\x57\x7f\xf3\xd4\x5b\xa9\x4a\x86\x62\x92\xfe\x01\x77\xe2\x95\x7b\x3e\x47\xed\x37\xd4\xde\x04\x63\x24\xc3\x94\xcd\x1c\x88\xf6\xa7\xdf\x7a\x13\x45\x49\x4e\xe9\x84\x64\x98\x8a\xd4\x99\x65\xa6\x82\xc1\x87\xd4\x39\xa7\xf6\xa1\x5b\xd8\xf5\x9f\x94\xc1\x60\xff\x78\x5c\x32\x15\x33\x0d\xf2\xa8\x5d\xe5\x92\xa8\xb3\x2f\xac\x8b\x47\x4d\xaf\x57\x7f\xa6\x56\x45\x58\x2f\xa7\xd3\xa8\x41\xb9\xe1\x94\xd1\xca\x78\x42\x36\x49\x4a\xd3\x15\xdc\xeb\x1e\xd9\xdc\x60\xb8\x82\xda\xfb\xd4\x99\xa4\xf6\x31\x75\xf6\xe0\x6a\xb4\x39\x62\x1d\xb3\x34\x2e\xbc\x4d\x9d\x29\x6a\xbf\xa4\xf6\x7b\xd0\x8f\x5a\xfb\xb5\xea\x8a\x7b\xf0\x94\x5a\xf3\xd4\x9e\xa1\x79\xab\x3e\xb5\xe7\x16\xe7\xfd\x67\xe3\xb0\xc4\x75\x10\x4b\x76\x31\x74\x0c\xa8\xc7\x43\x76\x35\xa2\xee\x09\xb5\x66\xeb\x97\xe7\xd4\xaa\x7a\xf3\x47\x8d\xb5\xc9\x6b\xfc\xd2\xd0\xb2\xff\xe9\xb5\x6c\x87\xb7\x64\xbb\x85\xb3\xe6\x55\x61\xa6\x30\xa5\xa2\x1a\xc1\x49\x6a\xd9\xd4\x7a\x49\xad\xd5\x4e\x71\xfc\x74\x90\x52\xbf\xa7\xa7\xb5\x4d\xad\xf1\x56\x95\x8a\xd4\x7e\x20\xb2\x90\x89\x21\xba\xae\xe9\x10\x1b\x9e\x4a\xf9\x1d\xbc\xa5\xba\xe2\xcd\x58\x4c\x87\x55\x6a\xe3\x5f\xbc\xdd\xd5\xba\x73\x41\xf3\x76\x23\xff\xd2\x7f\xfb\xcc\x9b\x9e\xf7\xcb\x20\x6b\x09\x44\x53\xab\x0c\x6a\x53\xeb\xd0\x1f\x5f\x77\xa7\xdf\x83\x06\xd4\x5a\x64\x17\xcf\x51\x6b\x0d\xf5\xb0\xc6\x85\x67\xb5\x0c\x4f\xbb\xda\xd9\x02\x0a\x77\x1e\x62\xce\x39\x0f\xf0\x88\x0d\x92\x9f\xf9\xab\x5b\xad\x32\x41\xd4\xa1\xfb\x60\xba\xb1\xb4\x09\xfb\xfd\xd2\xa4\xff\xe4\x17\x6a\x3f\x62\x8e\x1a\xef\x7a\x85\x41\xd4\x24\xf8\x33\xe2\x31\xf0\xad\x5b\x58\x69\x0b\x38\xb5\x76\x20\x84\xd4\xda\xa3\xd6\x34\x7a\xc4\xda\x6c\x9a\x6f\x3f\x02\xf3\xa9\xb5\x8e\xb6\xe3\x2d\x5c\x13\xb8\xe5\xe7\xdf\x33\x10\xd2\x97\x65\xab\x04\xc8\x64\x12\x1d\xa3\x32\x8f\x51\x71\xc0\xb4\x2a\x14\x4d\x8a\xdc\x07\x68\xf1\x0e\x5e\x42\x69\xd5\xd7\xcb\xfe\xdc\xa5\x64\xca\x29\x51\x5b\x47\x50\xa2\xba\x82\x78\xe4\x2d\x4c\xb9\x07\x8b\x6e\x61\x11\x57\xe3\x68\x12\x6e\x79\x4f\x9d\x67\xac\x3c\xe1\xf2\x1d\x77\xe6\xd4\x2d\x4c\xb1\xd4\xd8\xe6\xa7\x41\x65\x77\x62\xcb\x9d\x7e\xde\xa9\x17\x44\xec\xc3\xb4\xf9\xc9\x87\x29\xf8\x23\x67\xb2\x9f\x80\x3f\x6b\x17\x60\x7e\x81\x5a\x97\xd4\x7a\x4e\xed\xc7\xb0\x43\x32\x86\x35\x00\x3a\x54\x6b\xf3\x14\x2d\xc9\x6a\x86\x29\x71\x57\xfe\x61\xa8\x24\x55\xce\x20\xe6\x94\x66\xdd\x07\xb3\x52\x46\x56\xa0\xfc\x3f\xbf\x81\xbf\x01\x8d\x52\x2a\x20\x8f\x0e\xcb\xfe\xc5\xaf\xb0\x43\xba\x47\x74\x65\x48\x21\xc9\x38\x2e\x31\x30\xac\x97\xcf\xbd\x93\x02\xcf\x78\xb1\x29\xa7\xb6\x6f\x5b\xde\xe3\x3b\x9b\x7b\x64\xd3\x94\x19\x9c\xbe\x3b\xab\x9d\x3d\x65\xbe\x5e\x67\x18\xb5\x2f\x19\xb9\xa1\x21\x05\x50\xc3\x2b\xae\xbb\xe7\x6f\xdc\x83\x92\x24\x32\xbc\xa5\xe2\x59\x25\x82\x35\xf5\xbd\x4d\xf7\x5d\x45\x0a\x53\x93\xda\xaf\xa9\xb3\x4e\x9d\xd7\x08\x9c\xe8\x07\x38\xc7\x92\x9d\xfd\x23\x3e\xa8\x25\xc7\x50\xa1\x57\x10\x28\xf4\x94\x9c\xcc\x28\x08\x56\xe9\x38\x76\xa4\xd6\xcc\xe3\x89\xcb\x16\xa1\xd0\x35\x3d\xd9\xaa\x42\x73\x87\x4e\x32\xda\x3d\xf4\x61\xf4\x9f\x71\x39\x9b\x4d\x83\x0f\xfa\x07\x1a\x16\xe2\x25\xf7\x92\x94\xd1\x92\x44\x97\x79\x03\xa0\xce\x06\xd6\xbe\xf3\x8a\xe5\xc6\x3b\xea\xec\x80\xde\xbc\x0e\xa2\xdb\x6a\xd5\xf5\xc6\xda\xb9\x28\xac\x96\x23\x55\xfc\x69\xcd\x04\x15\x06\xc9\xb3\x5a\x2f\x3f\xad\x9d\x16\x83\x6b\x34\xbd\xfd\x16\x3c\x22\x25\x65\x6c\x33\xde\xe2\x16\xb8\x51\x92\x75\x53\x49\xa0\xed\xe2\x28\x57\x3f\x19\x98\x23\xf4\x56\x54\x70\xc2\x3d\x05\x1b\xaa\xbf\x71\x5a\xdf\x9b\x75\x2f\x27\xa0\xc8\xa4\xa4\x62\x64\x14\xec\x7b\xee\xec\x71\xed\x04\xb2\x49\x8b\x33\x01\x72\xba\xdb\xcd\x5d\x95\x85\x9c\x46\xac\xb4\xa7\x5b\x72\x3a\x21\xab\x09\x82\x42\xec\x03\xea\xbc\xc4\x80\xda\x67\x2c\x3d\x18\x6a\xc6\xa1\xb5\x07\x21\x43\xe4\x06\x69\xe3\xcd\xd8\x01\x04\x5c\x2c\x47\x61\x88\xe3\xa5\x48\xf8\x84\x4e\xb8\xfd\x51\x62\x80\x2d\x7b\x18\x16\x92\x92\xac\x6a\xea\x58\x46\xc3\x86\x0b\x99\x0a\x98\xc1\xa4\x43\x82\x3e\x06\xce\x01\x29\x14\xfa\x0b\x90\x89\xdd\xdd\x82\x4c\x48\x41\xe2\xda\x50\x1c\x7b\x3f\x7a\x80\xd7\xfd\x09\x26\xcb\x44\xa1\xb1\x76\x20\x0d\x6a\xa6\xa9\x65\xba\x6f\x41\x07\xea\xd8\xba\xea\xd5\x27\x10\x75\xc9\xc8\xa2\x46\xbc\x14\x36\x77\x60\x29\x99\x4b\x60\x31\x9e\x54\xdc\xa3\x39\xae\x0d\x17\xc2\x30\x02\xfe\x70\x95\x90\xf7\xb4\x2f\xc0\x57\x2d\x9d\x14\x5f\xdd\xb9\x4d\x86\x28\xf0\x47\x22\x49\xc5\x8c\x47\xa0\x0c\x9c\xe7\xbf\x2b\x37\x9e\x4f\x0a\x6f\x19\x63\x6a\x22\x3e\xa4\x83\xca\x2a\x31\x47\x35\x7d\xa4\x1b\xeb\xe0\x5d\x89\x05\x9b\x45\x1a\x00\xad\x34\xe3\x2d\xaf\x0a\x19\xf7\x94\x24\xd1\xb0\x51\xc1\xd5\xd0\x73\x9f\x9c\xe1\x86\xc9\x59\xff\xc9\x6a\xd0\x2e\xb0\x51\xb0\x5d\xa1\x12\x48\x7f\x9c\x15\x86\x5a\x05\x9e\x2d\x51\xae\x85\x89\x54\x9d\xa8\x6f\x5b\xd8\x09\xac\x25\xce\x3e\xd2\xc4\x24\xd2\x18\x6b\xfd\x98\x52\xe3\x91\xd2\x8d\xff\xc8\xaa\xde\xbd\x78\xcc\xee\x82\x9f\x95\xab\xf8\x0b\xfb\x3d\x80\x62\xe5\x5a\x4b\x65\x83\x76\xcd\x2c\xbd\x44\x1d\xad\xe2\x7f\xce\x57\x43\x9c\xf8\x63\x69\x91\x54\xec\x2e\x0a\x14\x66\xb0\xc6\x0b\x86\xa3\x0b\xb5\xb0\xcf\x51\x1b\xec\x05\xef\xef\xb5\x82\x4d\xb7\x1a\x81\xb3\xbc\x06\xda\x4f\x36\x51\xac\xeb\xb1\x5c\x3a\x1d\x49\xe3\x36\x4c\x9b\x9c\x70\x2b\xd0\xa7\x66\xfc\xdd\x53\xee\xdc\xf0\x48\x17\x3a\xd9\xbe\x6f\x50\x4e\x76\xdf\xb6\x4f\xf3\x33\xb7\xfe\xf5\xef\xa0\x99\xd1\xfc\x2c\xa3\x27\xbb\x8c\xe4\x14\x31\x9c\xa5\x7d\x54\x32\xe4\x3c\xdc\x59\x79\x3b\xe2\xd7\xc3\x76\x91\x5d\x9b\x21\x57\x75\x2c\x8b\x85\x52\xae\x34\xd6\x5f\x74\xe8\xa8\xa4\x02\xb7\x45\xfa\x0e\xaa\xb0\xb9\xc3\xe0\x02\x62\xf4\x30\xbc\x5f\xfa\xa8\xe7\xcf\x20\x69\xbf\x08\x2d\xda\xdf\xb6\xbc\x83\x0d\xf1\x51\xf4\x92\x16\x19\xf6\x23\xde\x84\x79\x5a\x7b\xe5\xdd\xc6\x52\x09\x04\x77\xc6\xa0\x5f\x8e\x01\xda\x0c\xfd\xf5\xca\xb0\x69\x66\xfb\x6e\xdd\x1a\x1d\x1d\xbd\x89\x73\x4e\x8a\x98\x46\xee\xa6\xa2\x0e\x69\xb7\xae\x88\xa1\xa1\xff\x96\x3c\xc0\xea\x61\x93\x81\xe0\x7b\x66\xfe\x39\xd3\xb8\x0b\x5b\x00\xcd\x3e\xea\x30\x8c\x45\x76\x93\x15\x69\x6b\x26\xc0\xe6\xa0\x25\x2e\xd9\x8d\x85\xc7\x78\x0f\x32\x2a\x24\x67\xf5\xdd\xed\xe0\x86\x6a\xe7\x3d\x4c\x0c\x94\xef\xe1\xff\xcf\x12\xc8\x6e\x68\x54\x32\x60\xc6\xf9\x3c\x30\x7c\xb0\x03\xe8\x17\xdb\x3a\xc7\x98\xdf\x38\x1a\x04\x2c\x30\x44\x9d\x6e\x8e\x06\x0c\x95\x33\x82\x83\xfd\x4c\x9d\x35\xd6\x12\xaa\xec\x3c\x27\xd8\x97\x02\x5c\x60\x2b\x67\x79\x01\xa1\x8e\x72\x3d\x56\x5c\x65\x44\x1f\xec\x61\x41\x22\x91\x4c\xd6\x1c\x8b\xa7\x15\x24\x19\x4c\xd0\x5a\xa4\xf0\xba\xe8\xc2\x6e\x3a\x62\xa9\x3c\xc7\xda\x27\xa3\x80\x0c\x3a\xff\x64\x30\xc7\x1c\xb2\x61\xca\x61\x88\xda\xcd\x25\x80\x1a\x7c\x18\x94\xd2\x8a\x3a\x02\x04\x4b\x85\x3e\x0f\x78\xd7\x78\xf6\xd2\x7b\xb8\x15\xb2\x3c\x69\x44\xd5\x46\xd5\x60\xd1\x7b\xb8\x81\xcd\x2f\x5c\xc4\xdc\x37\xda\x48\xf6\x3c\x1b\x7b\x01\x85\x8c\x0e\x40\xc0\xb5\x04\x90\x35\x02\x6c\xee\x27\xd2\x6c\xc8\x0e\xa3\x2c\x5b\x62\x4c\xb5\x4f\xa1\x33\xa7\x87\xd8\x9d\xd0\xbf\x60\xb8\x2a\x4c\xc2\xcf\xfa\xe9\x7e\x94\x7e\xb2\x61\x5d\x31\x12\xa0\x7e\x46\xe1\x83\x13\x36\xb6\xcc\x60\xff\xc0\x9d\x4f\xa5\x11\xf8\xf5\xd5\xa7\x52\x4a\xd3\x52\x58\x9d\x5f\xb0\xdf\x38\x90\x6a\x40\x88\x08\x44\x11\xaa\x1d\x98\x54\xed\xe4\x80\x51\x3c\x30\x69\x0f\xda\xad\x29\xa7\x23\x5b\xb8\x44\xbe\xb1\xb9\x2b\xa1\xa9\x2a\x49\x20\x9d\x8a\x07\xc3\x34\xf8\x0b\x06\x21\x60\x85\xba\xd9\x03\xf5\xfd\x60\xca\xb5\x8e\xf9\xb7\x70\x6e\x02\x47\xa0\x17\x70\x80\x12\x41\x94\xb4\x2c\x51\x91\x1b\xf9\xcf\x4f\x6a\xa7\x8f\x84\x0c\xe0\x40\xe2\x02\x92\x0c\x04\x03\xe7\x95\x46\xc8\xd8\x28\x07\x76\x74\xd9\xb9\xe8\x96\xe0\x05\x86\xf9\x46\x2e\x0d\x1e\xa8\x6f\x17\xbb\x94\x26\xa6\x58\x90\x95\x9c\x96\xc4\x33\xb2\x09\x31\x48\x8a\x54\x7e\xc5\x1a\x30\xd2\x79\x9c\x28\xa7\x8e\xa5\x1f\x73\x44\x1f\x0b\x53\x97\xd1\x1d\x6f\x65\xbd\x76\xf6\x36\x9a\xba\x9c\x5b\xb2\xad\x48\xd8\x9e\xd6\xf7\x00\x1c\xcb\x57\x10\x2d\x04\x49\x3b\xbd\x02\x25\xfd\xb7\xaf\x3f\x0b\x08\xd3\x3e\x56\x8a\xd0\x0c\x73\xfe\xee\xb7\xd7\x63\x5f\xdf\xfd\x3e\x76\xf5\x06\x3f\x7e\xed\x7a\xec\xea\xcd\x9b\x37\xaf\xc1\x50\x11\x81\x62\xdc\xe9\xbf\x79\x01\x07\x79\x16\xf5\xc5\x70\xc8\xe8\x8b\x65\x73\x83\xe0\x93\xbe\x18\xd0\x8d\x3e\x41\xb7\xfa\x62\x86\x02\x44\xaf\x2f\x96\x53\x4d\x25\xdd\x17\xbb\xda\xdb\xd3\xf3\x97\x1b\x3d\xb7\x6f\xf4\xf4\x5e\x8b\x0d\xcb\x46\x1f\x9f\x14\x62\x7c\x2a\xe8\xcb\xaa\x29\xe8\x31\x89\x91\x1c\x7b\xe7\x29\x89\x5a\xb1\x37\xc4\x83\x0f\xb3\xaf\xb9\x1e\x00\x83\xb3\x14\x4c\x5f\x15\xe6\xbd\x69\xbe\x3d\xe0\xdc\x8c\xca\x40\xf9\x39\x79\x24\x17\x38\x3a\x1c\x04\xa5\x62\x24\x80\xef\x7c\xf6\x29\xb8\xc3\x5b\x3d\xe3\xe2\xf9\xb7\xae\xc2\xbd\xf9\x63\xb7\x54\xe0\x43\x28\x3f\x82\x61\xcc\x5b\xb5\x6a\xc5\xbd\xdc\x15\x64\x08\xcf\xc7\x13\x40\xde\x8c\xa0\x41\xf1\x4f\x59\x5d\x1b\x4c\x93\x0c\xd2\xce\xf9\xb9\xc6\xfa\x0c\xff\x0a\xfe\x0a\xf2\x87\x7f\x48\x12\x93\xcd\x68\xf5\xdd\xd7\xfe\xf1\x11\xff\xa6\x93\xac\xac\xe8\x38\x29\xe0\x3d\xd1\x6f\x98\x30\xb5\x93\x69\x20\xda\xd8\xda\x99\x5c\x36\x20\x73\x7d\x16\x43\xf8\xe3\x67\x20\x2f\x85\x16\x81\x12\x90\x0d\xbf\x9f\xa1\xd2\x60\x4e\x4d\xf2\x5e\x5a\x62\xae\x7b\x80\x24\x9d\xc7\x41\xac\x08\x96\x8f\x0e\x6e\x6e\x69\xed\x50\xfb\x1c\x73\xdc\xa9\x53\xee\xb0\x6e\x0c\x73\x3f\xa8\x4e\xde\xa4\x59\x03\x68\x8e\xb5\x3b\xee\xdc\x42\x98\x6f\x3c\x09\x25\x00\x73\xa8\xee\x50\x8f\xe8\x1d\x92\x92\x69\x59\x8b\x9e\x8e\x6a\x1f\xe7\x59\xda\x3f\x70\xbb\x8e\xce\xd8\xbf\xdd\x42\x41\x00\xd2\x5b\x18\x49\x59\xb0\x57\x41\x38\x2a\xee\x1a\xe6\x03\x23\x61\xc0\x29\xde\x33\x40\xaf\xb4\xd4\xaf\x78\x0c\x09\x2c\xe7\x7a\x8b\xab\x59\x61\x00\xb6\x2f\x14\xdd\x9d\x22\x0c\x6b\x52\x8b\x4a\x46\x2b\x12\x20\x4a\x8b\xf5\x26\x90\x07\x93\x91\x98\xec\xc4\x3a\xb9\x0f\x1d\x0b\x97\x17\x37\xd8\x43\x53\xa5\x75\xb9\xeb\xfc\xd7\xb1\xab\xa3\xad\x76\x48\xf9\x41\xe0\x61\xed\x64\x96\xf5\xed\xbd\xd6\x0d\x80\x42\x39\xd2\xf5\x81\x22\xfa\x3a\x21\xce\xf0\x48\xe2\x7d\x65\x56\xef\xef\xc3\xd1\x40\xbc\x81\x85\xeb\x51\x6c\x14\x0c\x25\x08\x4a\x05\x5f\x1a\x90\x35\xac\xb6\x85\xe0\xcb\xef\xee\x7e\x7d\x3d\xf6\x8f\xef\xef\xfc\x93\x21\xc9\x39\x3e\x6d\x62\x50\x17\xae\xc7\x7a\x01\x7a\x80\x84\x5c\x8f\x65\x06\xb5\xfb\x90\x65\x91\x68\x2d\x45\xaf\x6e\x99\xa0\x7e\xfb\xa6\x9f\x94\x6c\x6b\xe2\xef\x74\x09\xbf\x90\x38\xa4\xe9\x80\xf9\x10\x86\x8b\x0d\xf7\x7c\x4e\xca\x65\x71\x6a\x07\xaf\x61\x22\xb3\xe7\x4a\x04\x85\xad\x10\xd5\x23\xeb\x1d\x2e\xc5\xd9\x32\xf0\xaa\x70\x4a\xc7\x9e\x22\x1f\x5d\xa0\x10\xdc\xcb\x45\x1c\x5b\x9a\x04\x65\x3c\x0c\x44\xf8\x4c\x0c\xa9\x0f\xe3\x1d\xb0\x5b\x7c\xca\x42\x1a\xc5\x1b\x45\x91\xc9\xdf\x81\xd9\x0e\xd8\x23\xab\x98\xc0\xa8\x50\xbd\xf8\x88\x82\xef\x52\x02\xf9\x9a\x9f\x4d\x82\x0f\xcf\x5c\xcb\xc8\xe7\x21\x62\xe2\x3b\x11\x57\x2b\xf2\x9d\xf3\x8c\x26\xb1\x88\x08\xe2\x6f\xd5\x5c\x01\xf7\xf9\x0b\xac\x8c\xc8\x39\x72\x1f\x79\xc7\xab\x75\xcc\x58\xb6\x25\xb2\x18\xb4\x4f\xe0\x10\x97\x33\x2d\x8d\x33\xb2\xe7\x7e\x96\xc3\x6a\xd4\xfe\xc8\xba\x4e\x4c\xec\xae\xb5\xb3\x69\x56\xf4\xa5\xd0\x17\x91\x3d\x7c\x54\x0d\x1f\x8c\x22\xc2\x91\x51\x0a\xbc\xdf\x62\x5c\x33\x7c\x9b\x6e\x1f\xe4\x70\x18\x55\xee\xb1\x4e\xde\x98\x98\x6d\xe4\x2d\xfc\xdf\x81\xd7\x16\x4f\x86\xe6\x5a\x7b\xdc\x00\xfc\x3b\xb2\x23\x48\x0d\xc4\x73\xff\x4d\xc9\x7b\xb1\x8c\x50\x96\xb7\xdc\x52\x14\x61\x2b\xfe\x9b\x5f\xea\xe5\x42\x78\x6f\x5c\x4e\x24\x48\x96\x95\xb8\x3b\xb7\xe8\x97\x26\x9b\x2b\xed\xc1\x09\x17\x02\xd6\xc3\x6f\x69\x7e\x07\xfe\xa4\x13\x5e\xbe\x36\x4c\x06\x1b\xcd\x15\x0d\x7a\xed\xdd\xaf\x22\xa2\x53\x0c\x51\xda\x6f\x34\x95\x0c\xd1\x72\x66\x73\xea\x5c\xc3\x52\xc6\x6e\x5e\x88\xde\x3e\x94\x33\x22\x14\xad\xf8\xc8\x2d\x6d\x37\x97\x45\xf4\xa3\x6c\x29\x50\x6f\x44\xc9\x66\x79\xcc\xf7\x44\x86\x36\x4f\x89\x90\xb1\x57\x80\x48\xb0\x2e\x19\x71\xdf\xfd\xed\xc7\xd7\xf9\xd6\x59\x97\xc5\xf4\xbf\x84\x96\x26\x21\x23\x1b\x00\x00")
 
 func fileMessageJaTxtBytes() ([]byte, error) {
 	return bindataRead(
@@ -1013,7 +1022,7 @@ func fileMessageJaTxt() (*asset, error) {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "file/message-ja.txt", size: 3938, mode: os.FileMode(420), modTime: time.Unix(1502257508, 0)}
+	info := bindataFileInfo{name: "file/message-ja.txt", size: 6947, mode: os.FileMode(420), modTime: time.Unix(1792211513, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
@@ -1378,7 +1387,7 @@ func gou_templatePost_formTxt() (*asset, error) {
 	return a, nil
 }
 
-var _gou_templateRecordTxt = []byte("\x1f\x8b\x08\x00\x00\x09\x6e\x88\x00\xff\xb4\x55\xc1\x6e\xe3\x36\x10\xbd\xeb\x2b\x06\xc4\x16\xb0\x17\x88\xe4\x75\xb7\x97\xc0\x32\x90\xdd\x04\x59\xa3\x28\x10\xd4\x41\x2f\xc1\x22\xa0\x45\x4a\x62\x22\x91\x2a\x49\xb9\x71\x58\xfe\x7b\x31\x94\xec\x48\x8e\x9b\xa2\x0b\xe4\x26\xcf\x0c\xe7\xbd\x37\xf3\x48\x3b\x97\x7c\x8c\xe0\xab\x6a\x76\x5a\x14\xa5\x85\x49\x36\x85\xf9\x6c\xf6\xcb\xd9\x7c\xf6\xe9\x33\x98\x52\xc8\xeb\xab\x5b\xd3\xc2\x8d\x56\x0f\x3c\xb3\x71\x04\x1f\x13\xef\x23\xe7\x18\xcf\x85\xe4\x40\x34\xcf\x94\x66\xc4\xfb\x68\xc1\x2c\x08\x96\x12\xed\x5c\xbc\x16\xcc\x7b\x02\x8c\x5a\x7a\xd6\x55\x9c\x61\xea\x90\x59\x46\xce\x81\xc8\x21\x5e\x99\x0b\x56\x0b\x09\xde\x47\x00\x0b\x21\x9b\xd6\x82\xdd\x35\x3c\x25\x59\xc9\xb3\xc7\x8d\x7a\x22\x20\x69\xcd\xd3\x3d\x10\x6c\x69\xd5\xf2\xd0\xea\x77\x9e\x7d\xe3\x94\xc5\x6b\x4b\xeb\xc6\xfb\xfb\x41\x68\x75\x89\xf0\x49\x80\xe1\x92\x61\xfb\x05\x85\x52\xf3\x3c\x9c\xbc\x2d\x35\xa7\xec\xeb\xf5\xca\xfb\xc4\x39\x63\xf5\x95\xcc\x14\xe3\x10\xdf\x50\x5b\x86\xd8\x5e\x41\x56\x51\x63\x52\x22\x18\x09\xda\xc4\x4b\xa6\x63\xf5\x12\x58\xee\xbf\x16\x09\x45\xdc\x0f\x58\x70\x9e\x22\xa5\xf8\x9a\xdb\x2f\x8a\xed\xfe\x40\xea\x40\x30\x41\x80\x10\x08\x83\xc4\x31\x84\xda\x7e\x06\xa6\xa1\x72\x0f\x1b\x2a\x97\x7d\x2f\xec\x8c\xc9\x4e\x54\x65\xde\x3a\x10\xff\xc6\x8d\xa1\x05\x8f\xa9\x54\x72\x57\xab\xd6\x8c\x4f\x77\x23\x71\xee\x43\x4d\x45\x75\x92\x24\x26\x8e\x48\x62\x28\x40\xde\xf5\x07\xbd\xff\x3e\xee\xd6\xb4\x9b\x47\xbe\xeb\xfb\xad\x4b\xa5\xed\x4d\x88\x0c\x9a\x74\x25\xaf\x99\x1b\x51\x48\x02\x56\xd8\xaa\x5b\xee\x5e\x00\xc6\xa9\x6d\x35\xf7\xfe\xbc\x5b\xf0\x11\x51\x4b\x75\xc1\x2d\x52\xed\x96\x70\x40\x78\xad\x77\x0c\x88\xae\xe9\x2d\x1a\xbe\x4f\x79\x0a\x1b\x56\x2a\xa3\x95\x15\x35\x87\xe3\xec\x00\x01\xbd\x8c\xdc\xbe\x51\x33\xe0\x46\xad\xa5\x59\x49\x3a\xb5\xff\xea\xbf\xf8\x92\xda\x5c\x54\xbc\xfb\x31\xb4\x70\xf2\x9a\x51\x8c\x36\x6b\xf3\x5c\x3c\xf5\x9e\x7b\x23\x1f\x9c\x08\x30\x71\xce\xaa\x5f\xbf\xc0\xc4\xaa\x95\xb4\x10\x5f\x04\x56\x6b\xf1\xcc\xa7\x7f\x37\x5a\x48\x9b\x03\xf9\x29\x9e\xe5\xc4\xfb\xc1\xe0\x1f\x37\xde\x4f\x87\xc3\x4b\x98\x5d\x46\x0b\xc6\xc2\x45\xd8\x8c\x7c\x8f\x92\x0f\x2b\xa6\x92\xe1\x2c\x6a\xb5\xe5\xab\x4b\x98\x9c\x18\x8b\x0e\xc9\xfb\x6e\x03\xd3\xde\xc6\x1b\x0d\xc9\xf2\xee\x6e\xc0\xa0\x2b\xf3\xfe\xfb\x79\x04\x80\xd7\x54\x35\x57\x26\xa3\x8d\x90\x05\x02\x98\x0b\x99\x95\x4a\x07\xd2\x7b\xb8\x4e\xf3\xd8\x95\x61\x37\xb7\x65\x5b\x6f\xe4\xde\xc0\x1d\xd8\xbb\x6c\x24\x02\xc0\x87\xac\x2e\xc0\xe8\x2c\x25\xc9\x53\x5c\x88\xbc\xb7\x59\x45\x9f\x77\x98\xe9\x3c\x87\xe9\xff\x83\x6c\x4e\x43\x0f\x94\x8d\x98\x00\xad\x6c\x4a\xf0\x11\xec\x1f\xa4\xf0\x66\x04\xf9\xce\xf5\x6b\x9a\x9c\xb8\x4f\xbd\x67\x81\x90\x29\x4c\xf8\x9f\xd0\x77\x04\xf2\xd0\x14\x04\x48\x50\x43\x1a\x59\x8c\x17\xf7\x2e\x8a\xff\x63\xd6\x50\x72\xfc\xcf\x4a\xc9\xfc\xd3\xec\x94\xdc\x83\x01\xfa\xaf\x1f\x15\xfe\x17\xdf\xd4\x04\x48\xdd\x7c\x26\x40\x54\xb1\x3d\x52\xbe\x15\x8c\x2b\x78\x37\x71\x3f\xcf\x67\x04\x32\x25\xad\x56\x95\x01\xf4\xd7\xa2\x19\xbe\xf0\x01\xfe\x9e\x6b\x8d\xde\x6f\x96\x8b\x24\x04\x96\xe3\x9b\xcb\xf0\x37\x97\xcc\xfb\xe8\x9f\x00\x00\x00\xff\xff\x47\x39\xe3\x7d\xf2\x07\x00\x00")
+var _gou_templateRecordTxt = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\xb5\x56\x6d\x6f\xd3\x30\x10\xfe\xbe\x5f\x71\xb2\x00\xb5\x48\x4b\xca\x80\x2f\xa3\x2d\x1a\x6c\x1a\x15\x42\x9a\xd8\xb4\x2f\xd3\x54\xb9\xb1\x93\x98\x25\x76\xb0\xdd\xb2\x2e\xe4\xbf\xe3\xb7\x74\x69\x17\x5e\xc5\xbe\x54\xc9\xdd\xf9\xee\x79\xce\xf7\x5c\x5a\xd7\xf1\xf3\x3d\x78\x2f\xaa\xb5\x64\x59\xae\x61\x90\x0c\xe1\x60\x34\x7a\xbd\x7f\x30\x7a\xf1\x0a\x54\xce\xf8\xe9\xc9\x85\x5a\xc2\x99\x14\x5f\x68\xa2\xa3\x3d\x78\x1e\x37\xcd\x5e\x5d\x13\x9a\x32\x4e\x01\x49\x9a\x08\x49\x90\xb1\x8d\x89\x06\x46\x26\x48\xd6\x75\x74\xce\x48\xd3\x20\x20\x58\xe3\x7d\x1f\xb1\x6f\x5d\x1b\xcf\xd4\x64\x00\x96\x42\x34\x53\x47\xa4\x64\x1c\xcc\x79\x80\x31\xe3\xd5\x52\x83\x5e\x57\x74\x82\x92\x9c\x26\x37\x0b\x71\x8b\x80\xe3\xd2\xbc\x87\x42\xb0\xc2\xc5\x92\xba\x54\x9f\x69\xf2\x81\x62\x12\x9d\x6b\x5c\x56\x4d\x33\xef\x98\x66\xc7\xb6\x7c\xec\xca\x50\x4e\x6c\xfa\x31\x86\x5c\xd2\xd4\x9d\xbc\x30\x4f\x98\xbc\x3f\x9d\x35\x4d\x5c\xd7\x4a\xcb\x13\x9e\x08\x42\x21\x3a\xc3\x3a\x77\xb6\x96\x41\x52\x60\xa5\x26\x88\x99\xc2\x96\x00\xbb\xf7\x78\x54\xf7\x86\x69\xfb\x34\x8e\xb1\xad\xfb\xc4\x06\x1c\x4e\x2c\xa4\xe8\x94\xea\x77\x82\xac\x2f\x2d\x74\x40\xd6\x81\x00\x21\x70\x8d\xb4\x6d\x70\xb1\xa1\x07\xaa\xc2\xbc\x2d\xeb\x22\xa7\x21\x97\xcd\x6c\x9d\x9e\x54\xa1\x7e\x75\x20\xfa\x44\x95\xc2\x19\x8d\x30\x17\x7c\x5d\x8a\xa5\xda\x3e\xed\x5b\x62\x12\x97\x98\x15\xbd\x20\xad\x63\x07\xa4\x35\xb9\x92\x57\xe1\x60\xd3\x5c\x6f\x67\xab\x96\x8b\x1b\xba\x0e\xf9\xce\x73\x21\xf5\x99\xb3\x74\x92\xf8\x10\x97\x26\x8c\x80\x8d\x9d\xa9\x4b\x2a\x59\xca\x28\xe9\x21\xa5\x58\xc6\x11\x68\xa6\x0b\x7f\xef\x2d\xb7\x55\x38\x32\xb7\x01\x4d\x73\xe8\xef\x7f\x87\x87\xc6\x32\xa3\xda\x32\xf1\x77\xb4\x01\xd0\xb6\xc3\xe1\xf8\x49\x3b\x6d\x62\x58\xf2\xb6\x50\x1f\x88\x7b\xef\xff\x80\xd1\x76\x72\x33\xb4\x5b\x68\xec\x9c\x07\x51\xb9\xe7\x3e\x15\xd8\xe4\x85\x48\x70\xa1\x99\x19\xa9\x5d\x6f\x67\x06\x1e\xaa\xaf\x23\x10\x67\xf6\xfa\x20\xb4\x60\x86\xe2\xfa\x6d\xca\x0c\x75\xe3\x3b\xc6\xda\x3e\x36\xcd\x33\x93\xf2\x8d\x97\xe5\xe4\x4f\xe4\x18\x68\xb4\x09\xd1\xf4\xaa\xd3\xc7\xd6\x6a\x66\x2a\x08\xa8\xd3\x8d\x76\x4c\x3e\x60\xd5\xe9\x29\xd6\x1a\x27\x39\x7a\x00\x7e\x5b\xdd\xf7\x78\xe3\x1d\x44\xf1\x43\xd0\x91\x15\xf1\x32\x4d\xd9\x6d\x50\xf4\x2f\xfc\x0e\x26\xc0\xa0\xae\xb5\xf8\xf8\x0e\x06\x5a\xcc\xb8\x86\xe8\xc8\xa1\x3a\x67\x77\x74\xf8\xbd\x92\x8c\xeb\x14\xd0\xd3\x68\x94\x1a\x9c\x1d\xba\x37\x8b\xa6\x19\x76\x2f\x3a\x26\x7a\x6a\x56\x28\x71\x6b\x66\xb1\xb5\x55\x2c\xe5\x4d\x1f\xb0\x89\x37\xa8\x4a\xb1\xa2\xb3\x63\x18\xf4\xb4\x45\x3a\xe7\xdc\x4f\xcb\x30\xdc\xed\x42\x9a\x6d\x78\xd5\x6d\xb8\x0f\x33\xed\x3e\x74\xb3\xa7\xb4\xa8\x4e\x54\x82\x2b\xc6\x33\x5b\x40\x1d\xf1\xc4\x08\xd8\x81\x6e\xcb\x79\xce\xd7\xbb\xd2\xf5\xee\xa3\xaa\x2a\xbc\x7e\x07\x0f\xaa\xcc\xb1\x77\x1a\xce\x7d\x53\x1e\xb2\x5d\xe4\xcb\x72\xc1\xdb\x25\xe3\x21\x3f\xca\xbd\x9a\xec\xf6\x63\x53\x66\xa0\x64\x32\x41\xf1\x6d\x94\xb1\x34\x08\xab\xc0\x77\x6b\xeb\xf1\x2a\xb3\xee\xbf\xa9\xac\xfa\x4b\x77\x98\x6d\x21\x01\x23\xd3\x09\xb2\x1f\xaa\xcd\xcc\x9b\x45\x14\x96\x63\xb8\xec\x41\xcf\x36\x09\x93\x6f\xb6\xc9\x10\x06\xf4\x2b\x84\x8c\x80\xbe\x54\x99\xb1\x3a\x36\xa8\xe2\xd9\xf6\xf5\x3f\x0a\xe3\xdf\xf4\x1a\x72\x6a\xff\x57\x4c\xd0\xc1\x8b\x51\x1f\xdd\xdd\x51\xf8\x57\xe2\xdf\xe8\xa2\x34\xe6\xb2\x7a\x65\x7e\x45\xb6\xda\x61\xbe\x62\x84\x0a\x78\x34\x72\x2f\x0f\x0c\xb9\x44\x70\x2d\x45\xa1\xc0\xce\xd7\xb8\xea\x7e\x85\x5d\xf9\x39\x95\xd2\x2a\xa8\x32\xec\x9d\x61\xba\xad\x7f\x62\xdf\xcd\xab\x79\xfb\x01\xfc\x32\x7a\xc5\x96\x09\x00\x00")
 
 func gou_templateRecordTxtBytes() ([]byte, error) {
 	return bindataRead(
@@ -1393,7 +1402,7 @@ func gou_templateRecordTxt() (*asset, error) {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "gou_template/record.txt", size: 2034, mode: os.FileMode(420), modTime: time.Unix(1491401929, 0)}
+	info := bindataFileInfo{name: "gou_template/record.txt", size: 2454, mode: os.FileMode(420), modTime: time.Unix(1792209427, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
@@ -1438,7 +1447,7 @@ func gou_templateRss1Txt() (*asset, error) {
 	return a, nil
 }
 
-var _gou_templateSearch_formTxt = []byte("\x1f\x8b\x08\x00\x00\x09\x6e\x88\x00\xff\x4c\xce\x4d\x4b\xf3\x40\x10\xc0\xf1\xfb\x7e\x8a\x61\x4e\xcf\x53\x30\x1b\x4b\xbd\x48\x36\x20\x45\x8a\x07\x41\xc1\xbb\xac\xc9\x34\x59\x71\x5f\xdc\x17\x31\x2e\xf3\xdd\x25\xb6\x87\xde\x86\x19\xfe\x3f\xa6\x56\xb9\x11\xb0\xf7\x61\x89\x66\x9a\x33\xfc\x1b\xfe\xc3\xb6\x6d\x6f\xae\xb6\xed\xf5\x0e\xd2\x6c\xdc\xe1\xfe\x25\x15\x78\x8a\xfe\x9d\x86\xdc\x08\xd8\x48\x66\x51\xeb\x48\x47\xe3\x08\x30\x91\x8e\xc3\xfc\x7a\xf4\xd1\x22\xb3\xe8\xd6\x01\x2c\xe5\xd9\x8f\x0a\x27\xca\x08\x7a\xc8\xc6\x3b\x85\xb5\x36\x77\xa3\x35\x6e\x7f\x78\x60\x96\xa7\x0e\xfb\x2e\xf4\xa2\x33\x2e\x94\x0c\x79\x09\xa4\x30\x95\x37\x6b\x32\xc2\x97\xfe\x28\xf4\x57\x3d\x52\x4a\x7a\xa2\xe6\x94\x30\x23\xc8\x5e\x5c\xec\x23\x4d\xf4\x1d\x98\x6f\xcf\x8e\xd3\x96\x14\x7e\x16\x8a\x0b\x42\x32\x3f\xa4\x70\xd7\x5e\x82\xcf\xeb\xe9\xec\x74\x32\xf4\x9d\x5c\xbf\x5e\x4d\x72\x23\xb3\xf8\x0d\x00\x00\xff\xff\x97\xe4\xd5\x6b\x15\x01\x00\x00")
+var _gou_templateSearch_formTxt = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\x4d\x8e\xc1\x6a\xc3\x30\x10\x44\xef\xfa\x8a\x45\xa7\x36\x10\xcb\x0d\xe9\xa5\xc8\x86\x52\x4a\x4f\x85\x16\x7a\x0f\x8a\xbc\xb1\xd5\xd8\x92\x22\xc9\x2d\xae\xd0\xbf\x57\x8e\x73\xf0\x6d\x76\x87\x37\x33\x31\xb2\x0d\x81\x17\x63\x27\xa7\xda\x2e\xc0\x9d\xbc\x87\x5d\x59\x3e\x6e\x77\xe5\xc3\x1e\x7c\xa7\xf4\xdb\xeb\x97\x1f\xe1\xc3\x99\x6f\x94\xa1\x20\xb0\x61\x29\x91\x18\x1b\x3c\x29\x8d\x40\x3d\x0a\x27\xbb\xc3\xc9\xb8\x81\x66\x83\xcf\x02\x06\x0c\x9d\x69\x2a\xda\x62\xa0\x20\x64\x50\x46\x57\x34\xc6\xe2\xf9\x2a\x53\x62\x0b\x45\x6b\x6e\x6b\xc2\x95\xb6\x63\x80\x30\x59\xac\xa8\x1f\x8f\x83\xca\xd0\x8f\xe8\x47\xbc\x32\xef\xe8\xbd\x68\xb1\x58\x90\x94\x28\xb0\x9a\xac\xfe\x67\x9c\x7e\x8d\x6b\x52\x7a\xba\x05\x69\x31\x64\xf2\x32\xa2\x9b\x28\x78\xf5\x97\x8f\x7d\xb9\x4e\xfc\x9c\xad\x5b\x10\x67\xb6\xe6\x6c\x1e\x9d\xb5\x05\xd9\x0b\xef\x2b\xda\x61\x6f\xb7\xc7\xde\xc8\x33\xad\x57\x55\x0d\x7a\x79\xb8\x2c\xf4\x0c\xe6\x19\xa8\x73\x33\xf9\x07\x2a\xf5\x01\x28\x46\x01\x00\x00")
 
 func gou_templateSearch_formTxtBytes() ([]byte, error) {
 	return bindataRead(
@@ -1453,7 +1462,7 @@ func gou_templateSearch_formTxt() (*asset, error) {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "gou_template/search_form.txt", size: 277, mode: os.FileMode(420), modTime: time.Unix(1448763309, 0)}
+	info := bindataFileInfo{name: "gou_template/search_form.txt", size: 326, mode: os.FileMode(420), modTime: time.Unix(1792206030, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
@@ -1538,7 +1547,7 @@ func gou_templateThread_topTxt() (*asset, error) {
 	return a, nil
 }
 
-var _gou_templateTopTxt = []byte("\x1f\x8b\x08\x00\x00\x09\x6e\x88\x00\xff\x8c\x54\x5d\x6f\x9b\x40\x10\x7c\xe7\x57\xac\x50\x22\xd9\x91\x02\x8e\xd5\xbe\xb8\x98\xaa\x72\xd3\x28\x52\x12\x55\x89\xfb\x6c\x5d\x61\x0d\xd7\xc0\x81\xee\x96\x38\xee\x89\xff\x5e\xdd\x01\x8e\xc1\x71\xe3\x37\x7b\x80\x99\x9d\xd9\x0f\xad\xfd\x0b\x07\x16\x45\xb9\x95\x3c\x49\x09\x46\xd1\x18\xa6\x93\xc9\xe7\xcb\xe9\xe4\xea\x13\xa8\x94\x8b\x9b\xeb\xa5\xaa\xe0\xa7\x2c\xfe\x60\x44\x9e\x03\x17\x7e\x5d\x3b\x5a\xc7\xb8\xe6\x02\xc1\xa5\xa2\x74\x2d\x70\x26\x8b\x82\x66\x73\xaf\xae\x9d\x20\xe6\x2f\xa0\x68\x9b\xe1\xdc\xfd\xcd\xa2\xe7\x44\x16\x95\x88\x67\x95\xcc\x46\x3e\x93\xec\x6f\xf5\xcc\x57\x8a\x3d\x57\x5e\x29\x92\x31\x88\xe2\x52\x62\x89\x8c\xe0\x6a\x32\x39\x87\xc9\xf9\x17\x37\x74\x82\x2a\x83\x28\x63\x4a\xcd\x8d\x42\x8e\xa2\x72\x43\x07\x00\x20\xc8\x78\x18\x30\x48\x25\xae\xe7\xae\xd6\xde\x0d\x23\xdc\xb0\xed\xe2\xe6\xb6\xae\xfd\x28\x65\x22\x41\xe5\x02\x71\x32\xe2\x5a\x7b\xdf\x51\x45\x8b\x06\xae\x6b\x37\xd4\xda\xbb\x47\xa5\x58\x82\x5e\xd4\xa1\x81\xcf\x4e\xe0\xe6\x22\xc6\xd7\x21\xf3\xad\x01\x07\xbc\xbc\xc1\x2c\xab\xd6\xc0\xd7\x50\x48\xf0\x6e\xd5\x0f\xc9\x51\xc4\xe6\xd7\xb7\x38\xe7\x02\xea\xfa\x63\x51\x89\x11\x0a\x1a\xaa\x3e\x5a\x74\x20\x2b\x5b\xf0\x34\x37\x02\x37\x43\xd6\x07\xdc\x0c\x28\x85\x41\x3a\x1f\xa6\x76\xdb\x68\xe3\xe8\x23\x13\xf6\x61\xa3\xa4\x90\xc9\x28\x1d\x8a\x3d\x59\x74\xa0\xa7\x5a\xf0\xa8\x85\x7d\x5a\x62\x54\x1d\x74\xfa\xc9\xa2\x43\xda\x16\x1c\x38\xe9\x91\xa7\x44\xe5\xcc\xf7\x37\x9b\x8d\x67\x86\x3e\x41\x52\x95\xc7\xc5\xba\xf0\xfb\x54\x9c\xb0\x21\x0a\xfc\x8c\x87\xce\x7f\x33\xce\x0b\x8a\x7b\x5f\xb3\x44\x22\xe6\xbb\x2e\x35\x14\x6d\xa0\xf7\x51\xfa\xeb\xf1\xae\xc9\x73\xc8\xda\x3c\x1b\xb8\xca\xbb\xa4\x76\x34\xef\xf9\x3a\x18\x28\xa5\xfa\x53\xa3\x54\xcf\x8f\x5f\x65\xa1\xe3\x04\xe9\xf4\x70\xb4\x56\x7b\x0b\x93\x4e\x9b\x15\xe5\xb1\xdd\xcf\x55\xb3\x1c\xb6\x0a\xc2\xbc\xcc\x18\x21\xb8\x19\x57\xb4\xe2\x84\xb9\x0b\xf6\x2a\x58\xee\xc6\x2e\x13\x31\x8c\xbc\x87\xe2\x8e\x2b\x1a\x03\x8c\xde\xdd\x90\xb1\x75\x53\x9a\x4a\xae\xf3\x92\xb6\xe6\x65\x23\x5e\xee\xbb\xed\xf2\x5b\xb2\xc4\xe8\xb5\x01\xf6\xeb\x27\x96\x74\x45\x03\x1c\xb9\x2c\x5a\x83\x34\xf6\xe0\xcc\x1c\xb1\x1e\xdb\x41\x43\xec\xa9\xeb\xe7\xda\x81\x4b\x26\x13\xa4\xba\xfe\x4a\x2c\x99\x6b\x7d\x66\x90\x44\x91\x6c\x9a\xb7\xf7\xf7\x2d\x73\xab\xdd\xba\x01\xd8\xa5\x34\xf0\xd7\x9b\x8f\xbe\xbd\x3c\x4a\x57\x11\x23\x4c\x0a\xc9\xdf\xda\x73\xd4\xe9\x89\xf3\xc5\xb2\xec\xa0\xca\x36\xa1\x56\x6c\x3b\x9b\x9b\x4f\x17\x3b\xe9\x63\x71\x75\xef\x7b\x3b\x95\x37\x68\x89\xaf\x74\x7a\x1a\x5a\xa3\x88\xed\x2c\xc5\xfc\x25\x74\xfe\x05\x00\x00\xff\xff\xd0\xff\xa3\xb0\xbe\x06\x00\x00")
+var _gou_templateTopTxt = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\x8d\x56\xd1\x6e\x9b\x30\x14\x7d\xe7\x2b\x2c\xd4\x4a\x49\xa5\x42\x5a\x6d\x2f\x19\xc9\x34\x65\x5d\x55\xa9\xad\xa6\x36\x7b\x8e\x3c\x70\xc0\x0b\xd8\xcc\x36\x4b\x33\xc4\xbf\xef\xda\x40\x0a\x4e\x59\xc9\x13\x9c\x6b\xce\xb9\xe7\xfa\xfa\x3a\x65\xe9\x5f\x38\x68\xc5\xf3\x83\xa0\x71\xa2\xd0\x24\x9c\xa2\xeb\xd9\xec\xe3\xe5\xf5\xec\xea\x03\x92\x09\x65\xb7\x37\x6b\x59\xa0\xef\x82\xff\x22\xa1\xf2\x1c\x74\xe1\x57\x95\x53\x96\x11\xd9\x52\x46\x90\xab\x78\xee\x1a\xe0\x4c\x70\xae\xe6\x0b\x0f\x5e\x82\x88\xfe\x41\x52\x1d\x52\xb2\x70\x7f\xe2\x70\x17\x0b\x5e\xb0\x68\x5e\x88\x74\xe2\x63\x81\xff\x16\x3b\xba\x91\x78\x57\x78\x39\x8b\xa7\x88\xf1\x4b\x41\x72\x82\x15\xba\x9a\xcd\xce\xd1\xec\xfc\x93\xbb\x74\x82\x22\x45\x61\x8a\xa5\x5c\x68\x85\x8c\xb0\x02\x40\x04\xbf\x20\xa5\xcb\x00\xa3\x44\x90\xed\xc2\x2d\x4b\xef\x16\x2b\xb2\xc7\x87\xd5\xed\x5d\x55\xf9\x61\x82\x59\x4c\xa4\x8b\x14\x55\x5a\x1c\xe2\x5f\x89\x0c\x57\x35\x5c\x55\xee\x12\x90\x07\x22\x25\x8e\x89\x17\xb6\x68\xe0\xe3\x11\xdc\x94\x45\xe4\xc5\x66\xbe\xd3\xa0\xc5\x4b\x6b\x6c\x1c\xab\x24\x58\x84\x89\x4d\xfb\x6c\x50\x8b\x57\x36\xa0\x21\x2e\x4b\x44\xb7\x88\x0b\xe4\xdd\xc9\x6f\x82\x12\x16\xe9\xa7\x2f\x51\x46\x19\x82\x1d\x78\x57\x57\x90\x90\x30\x65\xeb\x3e\x19\xd4\xd2\x15\x0d\x38\xce\x10\x23\x7b\x9b\xf5\x91\xec\x2d\x4a\xa6\x91\xd6\x87\xce\xdd\x74\x90\x76\xf4\x9e\x09\x13\x6c\x4a\xa7\xb0\x2a\x4e\xf6\xfa\xd9\xa0\x76\xe9\x1a\x70\xd0\x42\x87\x36\xe3\x11\x11\x58\x51\xce\xba\xd4\x2d\x53\x04\x12\x9b\xd7\x25\x96\x4e\x37\x30\x46\xab\xc8\x23\xa8\xdd\xef\x82\x0c\x4a\x1d\x57\x58\x4a\x1d\x7c\x8c\x90\x3e\x85\x45\x3e\xa8\x52\x87\x2d\x89\x16\x1c\xc3\xbf\x95\xe1\x6e\x90\x5d\x07\x2d\xee\x1a\x1a\x95\x39\x4c\x8e\x74\xb8\x3e\x75\xd8\xce\xbc\x01\xc7\xf0\x93\x97\x9c\x0b\x35\xc8\x5f\x87\x2d\xfe\x16\xb4\x3a\xb8\xa7\x92\x28\x95\xcf\x7d\x7f\xbf\xdf\x7b\x7a\x8a\xc6\x44\xc9\x02\x46\xc3\x96\xfb\xfd\xce\xa4\xaa\x49\x34\xf0\xe1\x73\xe7\xbf\x67\x2b\xe3\x2a\xea\x7d\x8d\x63\x41\x48\x76\x3c\x9d\x35\x45\x73\x90\x1e\xc2\xe4\xc7\xd3\x7d\x7d\x8e\x6c\xd6\x3a\x66\x37\x6f\x3b\x5c\x8e\x34\x6f\xf9\x3a\x19\x24\x52\xf6\xa7\x85\x94\x3d\x3f\x7e\x91\x2e\x1d\x27\x48\xae\x4f\x47\xca\xa6\x33\x81\x21\x6e\x66\x3e\x8d\xcc\xc0\xdf\xd4\xd3\xd6\x64\xa1\x48\x96\xa7\x20\x89\xdc\x94\x4a\xb5\x81\x82\x65\x2e\x32\xd7\x8c\xe1\xae\xed\x62\x48\x75\xe2\x3d\xf2\x7b\x58\x32\x45\x68\xf2\xe6\x64\x9c\x1a\x37\xb9\xce\xe4\x26\xcb\xd5\x41\x2f\xd6\xe2\x79\xd7\x6d\x5b\xbf\x35\x8e\xb5\x5e\x53\xc0\x7e\xfe\x0a\xc7\x6d\xd2\x10\x7b\xfb\xaa\x02\x1a\xa1\xed\xa1\x33\x7d\x2b\xf6\xd8\x4e\x36\xc4\xdc\x9d\xfd\xba\xb6\xe0\x1a\x0b\xe8\x9d\xaa\xfa\x0c\x9a\x0b\x00\x35\x12\x4b\x25\xea\xcd\xeb\xbc\xbe\xd6\xdc\x68\x37\x6e\x40\xaa\xad\x92\xe5\xaf\xd7\x1f\x7d\x7b\xd0\x09\x9b\x10\x92\x89\x39\xd4\x4f\xbe\xeb\x74\x64\x7f\xe1\x34\x3d\xc9\xb2\xa9\x50\x23\x76\x80\x42\xc1\xa7\xab\xa3\xf4\x50\xb9\xda\xf5\xde\x51\xe5\x15\x5a\x93\x17\x35\xbe\x1a\x65\x09\x0f\xa6\x97\xe0\x3f\xcb\xd2\xf9\x07\x06\xfc\x67\x75\x0f\x09\x00\x00")
 
 func gou_templateTopTxtBytes() ([]byte, error) {
 	return bindataRead(
@@ -1553,7 +1562,187 @@ func gou_templateTopTxt() (*asset, error) {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "gou_template/top.txt", size: 1726, mode: os.FileMode(420), modTime: time.Unix(1502257508, 0)}
+	info := bindataFileInfo{name: "gou_template/top.txt", size: 2319, mode: os.FileMode(420), modTime: time.Unix(1792209129, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _gou_templateModerationTxt = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\xa5\x54\x4d\x8b\xdb\x30\x10\xbd\xe7\x57\x08\xb3\x87\x76\x21\x76\xba\xb4\x97\xe2\x18\x96\xdd\x65\xc9\xa1\xb0\x6c\x72\x5f\x14\x6b\x12\xab\xb5\x24\x23\x8d\x53\x82\xf0\x7f\xaf\x64\x39\xb6\xf3\xd1\x90\xd2\x83\xd1\xd7\x7b\x6f\xac\x37\xa3\xb1\x36\xb9\x9f\x90\x27\x55\xed\x35\xdf\x16\x48\x3e\xe5\x9f\xc9\xc3\x6c\xf6\x6d\xfa\x30\xfb\xf2\x95\x98\x82\xcb\xd7\x97\x95\xa9\xc9\x9b\x56\x3f\x21\xc7\x78\x42\xee\x93\xa6\x99\x58\xcb\x60\xc3\x25\x90\x48\x28\x06\x9a\x22\x57\x32\x6a\xf7\xef\xb4\x52\xf8\x7d\x1e\xbb\x45\x5a\x65\xd6\xc6\x3f\xc0\x18\xba\x85\x98\x81\xc9\x3f\x06\x74\xd3\xa4\x49\x95\x39\x02\xe1\x1b\x12\xbf\x83\x50\x3b\x5a\x1a\xe2\x69\x48\xd7\x25\x10\x53\x0b\x41\xf5\x7e\x1e\x8d\x34\xc6\xf4\x88\xe4\x25\x35\x66\x1e\x19\x55\x72\x16\x65\x13\x42\x52\xd4\x7e\xf0\x93\x62\x1c\x1a\x39\x96\xe0\x03\xba\xed\x4b\xe7\x54\x23\xcf\xaf\x22\xba\xc0\x4a\x5f\xc1\x30\x8a\xd7\x24\x0c\x52\xac\xcd\x00\x70\xa3\x6e\xef\xaf\xa9\xdc\x02\xb9\xd3\xc2\xb9\x36\xf6\xe1\xe8\x3e\x2c\x4b\x29\x29\x34\x6c\xbc\x1f\xad\xc7\xf1\xca\x2d\x29\x7b\x7a\x5d\x34\x4d\x62\xad\x41\xfd\x22\x73\xf7\x9b\x5e\x29\x5e\x85\x1b\x47\x99\x07\xf7\xcb\x34\xa1\x99\x0b\xcb\xfe\x4f\x34\x09\x9a\xcb\x42\x69\x5c\x3c\x0f\x41\xfa\x8d\x0b\x61\x4c\x45\x65\x9f\x2f\xbe\x95\x47\x9c\xb7\x7a\xfd\x0b\xf6\x9e\xe7\x61\x27\x54\x6b\x4b\x95\xd3\x12\xb9\x08\x3f\xb1\x44\x2a\xaa\xd6\xc5\x11\xa8\x9d\x84\x52\xf2\x98\xc7\xaa\x2a\x39\xb0\x60\x62\x38\x0a\xb7\x3b\xe4\x42\x7b\x9b\x81\x75\xe7\x8e\x08\xa5\x81\xbf\xc3\xb9\xfc\xd0\xb0\xe3\xf0\xbb\x47\xa4\x1b\xa5\x05\x11\x80\x85\x62\xf3\xa8\x52\x06\x23\x42\x73\x5f\x98\x83\x95\x8f\x4c\x70\x19\x9c\x1c\x3d\x92\x83\x0b\x5e\x60\xca\x65\xe9\x1e\x51\x94\xa5\x8c\xef\xb2\x4e\xda\x89\x73\x59\xd5\x48\x70\x5f\xc1\x3c\x2a\x38\x63\xe0\x68\x92\x0a\xb7\xda\xf0\x12\x22\xe2\x2a\xa4\x86\x10\xc8\x65\x85\xea\x2d\x60\xfc\x4c\xd1\x1f\xfa\x77\x91\xdc\x24\x85\x2d\xef\xb2\xd8\x82\xb9\xcc\xdf\x2e\x15\xec\xd4\x27\x5a\xef\x61\xf7\x5f\xc5\x8c\x7b\xcc\x63\x21\x6f\xe5\x92\xb3\x53\x81\x75\x8d\xa8\x64\xc7\xc9\xc5\xc0\xa1\x2e\xf9\xfb\xde\xe6\x35\x4a\xe2\xbe\x29\xf3\x8f\x4c\x87\xb2\x3b\xaf\x04\x5f\x4f\x41\xf0\x96\x08\x8c\x1b\xc1\x8d\x19\xc7\x38\x17\xee\x40\xe7\xca\x69\xe2\x93\x9d\x26\xbe\x00\xfa\xc2\x05\xd9\x57\xeb\xa1\xb2\xfb\xfe\xd0\x9d\xb9\xb5\xef\x8b\x61\xab\x2b\xd7\xe3\x0e\x2b\xd5\x87\x0e\xfd\x63\x68\xae\x1d\xd9\x5a\x37\x71\xe3\x1f\x24\x1e\x9b\x46\xef\x05\x00\x00")
+
+func gou_templateModerationTxtBytes() ([]byte, error) {
+	return bindataRead(
+		_gou_templateModerationTxt,
+		"gou_template/moderation.txt",
+	)
+}
+
+func gou_templateModerationTxt() (*asset, error) {
+	bytes, err := gou_templateModerationTxtBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "gou_template/moderation.txt", size: 1519, mode: os.FileMode(420), modTime: time.Unix(1792204863, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _fileModeratorTxt = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\x55\x90\xcb\x6e\xab\x30\x10\x86\xf7\x79\x8a\x91\xb2\x69\x37\x85\x24\xca\x75\xd7\xa4\xe9\xa1\x6d\x22\x37\x37\x1a\xce\xce\xc0\x40\x7c\x00\x9b\xd8\x86\x40\x9f\xbe\x86\xa0\x23\xd5\x9b\x7f\x34\x97\xef\xf7\x4c\xbf\xd7\x87\x0d\x53\x1a\x44\x04\x79\xe1\x27\x58\xab\x26\xd4\xb2\x50\x1a\x43\xc8\x44\x88\x92\x6a\x21\xd5\x53\xaf\x6f\x5a\xbf\x24\xd3\x08\x82\x63\xd7\x0c\x0f\x9d\x0a\xd9\x65\x86\x10\x31\x4c\xc3\x47\xa0\x3c\x04\x91\x6b\x26\x38\x4d\x5b\x0e\xe4\x28\xdb\xd1\x94\x71\x34\x38\xd8\x63\x26\x4a\x53\x94\x18\x08\x19\x2a\x50\x2c\xe6\xc6\xd3\xaf\x41\x5f\xfe\x1b\x50\x89\x40\xf3\x3c\x65\xa6\xa2\x85\xa1\x36\x29\xcd\x82\xb4\x45\x98\x47\x0b\x2d\x16\x46\x65\x43\xc3\x76\xb4\x6b\x00\x6a\x98\x42\xf0\x46\x9b\xb4\xfc\xe5\x07\x4c\x35\x11\xb2\x12\xc3\x0e\x25\xb1\x64\x78\x5b\xc0\xb5\xc0\x02\x7f\x8d\x44\x66\x3f\x1a\x66\x8c\xc3\x43\x2b\x4f\x41\xcc\xac\xee\x38\x66\xc3\xc7\x06\xf0\x82\x11\x2d\x52\x7d\xdf\xd5\xc0\x9b\x8f\xdd\xaf\xb6\xae\x68\x96\xa7\xb8\xb8\xbb\x7c\x1d\x87\x7c\x7c\xf3\x0f\xa7\xd2\xae\x1c\xdb\x27\xb2\xfa\xae\x4f\x2f\xd6\xc5\x21\xb3\x52\x55\x6f\x3e\xff\xab\x3c\xd7\x23\xc3\x44\x8e\xc6\xbb\xd2\x76\x6a\x57\xf3\xc1\x6a\xb5\x9e\x09\xc2\x97\xff\x26\x81\xe5\x1e\xea\xfd\x67\x8d\xe2\xe4\x57\xf5\x95\xa8\x6c\xc4\xb6\xf6\xae\xb5\xbb\x1b\x6c\x1c\x32\x39\xaf\xa6\xa5\xe5\x58\x85\xfd\xbd\x3d\xc4\xef\xde\x7a\x99\xf0\x99\xf0\xc6\xb1\x33\x38\x5f\x96\xc7\x38\xb8\xba\xf3\x8f\xe1\xed\x99\x6c\x0b\xef\xf5\xd5\xfd\x33\x72\xcf\xfc\xc6\xc9\xe4\xf4\x61\x0d\x92\xe7\x29\x26\x51\x7d\x9d\x7a\x13\x42\xdf\x37\x6f\x73\xcd\xf7\xe1\x71\xd7\x5d\xa7\xf7\x03\xfe\xb9\x29\x26\x32\x02\x00\x00")
+
+func fileModeratorTxtBytes() ([]byte, error) {
+	return bindataRead(
+		_fileModeratorTxt,
+		"file/moderator.txt",
+	)
+}
+
+func fileModeratorTxt() (*asset, error) {
+	bytes, err := fileModeratorTxtBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "file/moderator.txt", size: 562, mode: os.FileMode(420), modTime: time.Unix(1792204991, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _gou_templateSearch_resultTxt = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\x9d\x53\x4d\x8b\xdb\x30\x10\xbd\xe7\x57\x0c\x26\x85\xdd\x85\xda\xc9\xd2\xbd\x04\xdb\x87\xa6\x61\x1b\x68\xa0\x34\xb9\x07\xad\x3d\x8e\x55\x14\xc9\x95\xc6\xd0\xad\x56\xff\xbd\x92\xec\xdd\x38\xf4\xb2\xf4\x64\xcd\xc7\x7b\x6f\xe6\x49\xb6\x36\xbb\x9b\xc1\x5a\x75\xcf\x9a\x9f\x5a\x82\x9b\xea\x16\xee\x17\x8b\x87\x8f\xf7\x8b\xe5\x27\x30\x2d\x97\x8f\x9b\x83\xe9\xe1\xbb\x56\x3f\xb1\xa2\x74\x06\x77\x99\x73\x33\x6b\x6b\x6c\xb8\x44\x48\x0c\x32\x5d\xb5\x47\x8d\xa6\x17\x94\xc4\xd2\x5c\x2b\x45\xab\x22\x8d\x01\xf0\x06\xd2\x8d\xd6\x4a\x83\x8f\xf3\xae\xb4\x36\xdd\xa1\x31\xec\x84\xe9\xaf\x1e\xf5\xf3\x11\x43\xd1\xb9\x15\xf8\xca\x66\x38\xe7\x59\x57\x06\x2c\x0a\x83\x91\xe0\x47\xa4\x37\x91\xa2\x17\xc0\xeb\x22\xa1\x56\x23\xab\x8f\x5c\xd6\xf8\x3b\x89\xdd\x9a\xc9\x13\xc2\x5c\x7b\xe9\x69\xbf\xe0\xe5\x0c\x20\x37\x1d\x93\x50\x09\x66\x4c\x91\x18\x62\xe7\x2e\xf1\xa3\x08\x55\x31\x41\xfc\x1c\x60\xe9\x9a\x55\x2d\x7a\x68\x85\x92\xf6\xa1\x23\x0c\x12\x60\x11\xcf\xc0\x0b\x36\x45\x32\xee\x97\x1e\xa2\xfe\xfa\x71\xeb\x5c\x66\xad\x21\xbd\x91\x95\xaa\x23\xd1\x81\x93\x40\xe7\x82\xc0\x25\xca\x33\x16\x78\x6e\x62\x6e\x90\xfa\x86\x12\x96\x11\x4e\x6a\x2b\xe9\x32\xc3\x9e\xff\xc1\x17\x52\xbb\xcf\x2f\x9d\xe6\x92\x1a\x48\x3e\xa4\xcb\xc6\xbb\xfb\x2a\xfe\xea\xe0\xf9\xc9\xb9\x5b\xcf\x3a\xf8\x1c\xc4\xe2\x54\x3b\x46\x9e\xa6\xf6\xeb\x5f\xad\x4d\xec\x34\xcc\x34\xa5\x18\x7d\x3c\x0f\x90\xb7\x95\x83\xfb\x32\x30\x4c\xd9\xbf\xf2\xc1\x53\x6f\x47\x2d\xca\xa1\x32\xba\xde\xae\x8a\xab\x86\xd0\x42\xe5\x7f\xb8\xe6\xd3\xf3\x36\xdd\xb7\x4a\xd3\xf6\xcb\x68\xe2\x24\x1e\x6d\x7c\xc7\x85\x7a\xd0\xd5\x25\xe6\x99\x1f\x68\x9c\xac\x1e\x59\x25\xef\x3a\xa4\xd0\xe2\x53\xc3\x3e\x6f\x5b\xfb\x9c\xb8\xce\xe5\x59\x78\x4b\xd3\xb8\x17\x97\x77\xfa\xcf\xf3\x96\x6a\xfc\x2d\x26\x0f\x7a\x00\x5a\xeb\x0f\xfe\xfb\x17\xd3\x67\xdb\xad\x7e\x03\x00\x00")
+
+func gou_templateSearch_resultTxtBytes() ([]byte, error) {
+	return bindataRead(
+		_gou_templateSearch_resultTxt,
+		"gou_template/search_result.txt",
+	)
+}
+
+func gou_templateSearch_resultTxt() (*asset, error) {
+	bytes, err := gou_templateSearch_resultTxtBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "gou_template/search_result.txt", size: 894, mode: os.FileMode(420), modTime: time.Unix(1792206030, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _gou_templateFsckTxt = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\xb5\x53\x4d\x6b\xe3\x30\x10\xbd\xe7\x57\x0c\x22\x87\xb6\x10\x3b\x2d\xdd\x4b\xb1\x0d\x4b\x5a\x4a\x59\x0a\xa5\xdd\x7b\x51\xa4\x49\xac\xad\x2d\x09\x49\x2e\x1b\x8c\xff\x7b\x25\xe5\x4b\xad\x09\xdb\xcb\x1e\x8c\x2c\xcd\x9b\x99\xf7\xde\x48\x7d\x9f\x5f\x4c\x60\xa1\xf4\xc6\x88\x75\xed\xe0\x8c\x9d\xc3\xd5\x7c\xfe\x63\x76\x35\xbf\xbc\x06\x5b\x0b\x79\x7f\xf7\xdb\x76\xf0\x64\xd4\x1f\x64\x2e\x9b\xc0\x45\x3e\x0c\x93\xbe\xe7\xb8\x12\x12\x81\xac\x2c\x7b\x23\xf1\x64\x6a\x94\x72\x37\x65\xe6\x37\x85\xae\xfa\x3e\x7b\x44\x6b\xe9\x1a\x33\x8e\x96\xbd\x06\xdc\x30\x14\xb9\xae\x3c\x14\xc4\x0a\xb2\x3b\x63\x94\x81\x03\x3a\x6e\x8f\x08\x94\x3c\xc6\x1c\x5d\x36\x08\xb6\x6b\x5b\x6a\x36\x25\x49\xca\x6e\x2b\x12\x60\x0d\xb5\xb6\x24\x56\x35\x82\x93\x6a\x02\x50\x38\x13\x96\xf0\x53\x57\x5f\x12\x5e\x23\x3a\xb4\xf1\xb1\x93\x20\x6d\x94\xef\xda\x26\x38\xbf\x9a\xc8\xcb\x50\xb9\x46\x98\x32\x2f\x74\x11\x4a\xa1\x0d\x34\x3f\x35\xe5\xbe\xde\x94\xc5\x5c\x9e\x9e\x09\xc9\xf1\x2f\x44\x9b\xb2\x85\xea\xa4\x83\x14\x75\xe8\xb0\x57\x9e\x47\xe9\x07\xbb\x9e\x76\x9c\xbe\xe1\x4a\xc2\xff\xbf\xd8\xf3\x86\x9b\x7f\x41\x38\x3a\x2a\x9a\xd3\xfe\x69\xef\x5f\xaa\x68\x6c\xa0\xde\xfa\x3b\xb6\xd1\x47\x7e\xed\x08\x7c\x3d\xbf\x3d\x76\xfd\x96\xa7\xcf\xa8\xa9\x30\xc8\x61\x74\x67\xa3\x08\xb3\x0b\x27\xb7\xb2\xb1\x18\xc1\x2b\x65\x5a\x68\xd1\xd5\x8a\x97\x44\x2b\xeb\x08\x50\xe6\x84\x92\x71\x18\x3f\x79\x2b\xe4\xe2\xfe\x61\x18\xf2\xf8\x3e\xf6\x43\x08\x59\x33\x21\x1b\xff\x72\x48\x55\x70\xf1\x1e\x49\x0a\xa9\x3b\x07\x6e\xa3\xb1\x24\xb5\xe0\x1c\x25\x01\x49\x5b\xbf\xb3\x7e\x64\xf0\x4e\x9b\x0e\x63\xd9\x17\xc1\xc3\x44\xf3\x98\xb5\xec\x9c\x53\x72\x07\x64\xed\x11\xb8\x65\x7d\xe8\xb9\x74\x12\xfc\x37\xe3\xc1\x78\x43\x4e\x88\x0c\x12\xb7\x15\x2b\xef\x52\x60\x56\xe4\x81\x6d\xea\x5e\xaa\x7f\x6c\x96\x54\xfb\x6b\x37\x7a\xc4\x7d\xef\x7f\xfc\xfa\x01\x2d\xfa\xfb\xb5\x6d\x04\x00\x00")
+
+func gou_templateFsckTxtBytes() ([]byte, error) {
+	return bindataRead(
+		_gou_templateFsckTxt,
+		"gou_template/fsck.txt",
+	)
+}
+
+func gou_templateFsckTxt() (*asset, error) {
+	bytes, err := gou_templateFsckTxtBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "gou_template/fsck.txt", size: 1133, mode: os.FileMode(420), modTime: time.Unix(1792206535, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _gou_templateBundleTxt = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\xb5\x55\x4d\x6f\xdb\x30\x0c\xbd\xe7\x57\x10\xc2\x0e\x5b\x01\xdb\x69\xd0\xed\x30\x38\x06\x86\x22\x28\x76\x18\xb0\xb5\xbd\x17\x8a\xc5\xc4\xda\x64\x4b\x93\xe4\x7e\xcc\xc8\x7f\x9f\x64\xd9\x89\xe3\x2e\x6b\x82\xad\x87\x38\x34\x4d\x3e\x3e\x51\x8f\x52\xd3\x24\x67\x13\xb8\x94\xea\x49\xf3\x75\x61\xe1\x6d\xfe\x0e\x66\xd3\xe9\xfb\x68\x36\x3d\xbf\x00\x53\xf0\xea\x6a\x71\x6b\x6a\xf8\xaa\xe5\x77\xcc\x6d\x3c\x81\xb3\x64\xb3\x99\x34\x0d\xc3\x15\xaf\x10\xc8\xb2\xae\x98\x40\xd2\xfa\xde\x68\x29\xed\xc7\x79\xec\x5e\x52\x95\x35\x4d\xfc\x05\x8d\xa1\x6b\x8c\x19\x9a\xfc\x2e\x44\x6e\x36\x69\xa2\x32\x17\x0c\x7c\x05\xf1\x42\x6b\xa9\x61\x1b\xdf\xbe\xee\x22\xb0\x62\xd0\x02\xc3\x03\xb7\x05\xc4\xd7\x68\x6a\x61\xdb\x70\x4b\x97\x02\xc1\xd4\x65\x49\xf5\xd3\x9c\x74\xb5\xb7\x05\x79\xa9\xa4\xb6\xdb\x92\x04\x72\x41\x8d\x99\x13\x23\x05\x67\x24\x9b\x00\xa4\x56\x67\xa9\x2d\xb2\x71\x66\x48\xb9\xb3\x85\x46\xca\x8c\xe7\xe2\x82\x52\xcb\x3c\xbd\xdb\x81\x93\x65\xee\xa1\x8f\x40\xd2\x98\x4b\x3d\x46\xba\x1e\x38\x8f\x46\xc2\x47\x6e\xec\x08\x68\xb1\xf3\x9d\xc0\xa8\x94\xf7\xc8\xc6\x8c\x76\xce\xa3\x91\x8c\xa2\xe5\x3e\xcc\x4d\xef\x39\x81\x8d\x17\xd6\x73\x3a\x03\xef\xd1\x58\x3f\x6b\xac\xc7\x48\xdf\x76\xbe\x1e\xc7\x3d\xbd\x7c\x86\x1a\x4b\x8b\xd9\x50\xb0\xf8\xb8\xa7\x9f\x34\x71\x9f\x27\xe9\x4a\xea\x12\x4a\xb4\x85\x64\x73\xb2\x46\x4b\x80\xe6\x96\xcb\xca\xeb\x2f\xfe\xc4\x4a\x5e\x5d\x5e\x7d\xde\x6c\x92\x6e\x26\x7a\xd1\xf9\xb4\xa8\x90\x9a\xff\x92\x95\xa5\x82\x64\x29\xe3\xf7\xed\x6a\x78\xa5\x6a\x0b\xf6\x49\xe1\x9c\x14\x9c\x31\xac\x08\x54\xb4\x74\x6f\x79\xc9\x08\xdc\x53\x51\x3b\x3b\x90\x21\x90\xb4\x39\x2e\xb7\x07\xce\x1d\x9e\x96\x22\x5a\x6b\x59\xab\x56\xd5\xee\xbb\xa0\x4b\x14\xe3\x88\xd6\x49\xc0\x31\x99\x93\x20\x6d\x32\x5c\xae\xe5\x36\x2c\xb3\x8d\xeb\x80\x9e\x17\x32\x5d\x0d\xbf\x0f\xf8\x68\xa9\xc3\xe9\xe8\x76\x98\xc0\xd9\xce\xd6\xf2\xc1\xa5\x5e\xb8\x36\xb8\x4c\x67\x4c\x89\x6f\x7f\x97\xb7\x05\x1a\x54\x29\x50\xa8\x68\x29\x64\xfe\x83\x1c\x38\x3c\xba\xb1\xf4\x4c\xbb\x16\x3a\x84\xde\xdc\x19\xff\xde\x22\xba\xde\xef\x0f\x5d\x1f\xd5\x9d\x6e\x43\xbb\x9e\x38\x90\xd0\x10\x6f\x24\xd9\x6b\x10\x35\xbc\xca\x71\x8f\x6a\x3f\x99\xfe\xc3\xe9\x9c\x03\x5e\xcb\xba\x33\x95\xa0\x39\x16\x52\x30\x74\xe5\xdc\xcd\xf0\x21\x9a\x9e\x47\xd3\xd9\xcb\x0b\x6a\x55\x1f\xe6\xa3\xd7\xcd\x9e\xde\x4d\xbd\x2c\xb9\xdd\x8a\xfc\xf0\xf0\x6d\xe7\x68\x69\x2b\x70\xbf\x48\x69\xee\x4f\xfd\x7e\x20\x42\xf1\xf0\x97\x26\xbe\x6c\x36\x1e\xe7\xd1\x75\xf0\xa7\x71\x56\xd2\xbc\x38\xcf\x58\xe5\x81\x7b\xe9\xae\x21\xae\xa8\xb6\x6d\xb9\x88\x51\x4b\xff\xdb\xb4\x07\xae\xfd\xe2\x0e\xe7\x18\xce\x86\xcd\xbb\xe1\xcc\xb7\xea\xb8\x33\xe2\x6f\x42\x08\xb5\x56\xdc\x2f\x38\x54\xea\x97\xff\x7a\x3b\x7e\xe8\xba\x3e\x61\xc7\x9b\xc6\x9d\xe4\xee\x20\xff\x0d\xc8\x04\x64\xe5\xd1\x08\x00\x00")
+
+func gou_templateBundleTxtBytes() ([]byte, error) {
+	return bindataRead(
+		_gou_templateBundleTxt,
+		"gou_template/bundle.txt",
+	)
+}
+
+func gou_templateBundleTxt() (*asset, error) {
+	bytes, err := gou_templateBundleTxtBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "gou_template/bundle.txt", size: 2257, mode: os.FileMode(420), modTime: time.Unix(1792206819, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _gou_templateExport_formTxt = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\xad\x53\xc1\x6a\xdc\x30\x10\xbd\xef\x57\x0c\x22\x87\x36\x60\x6b\x13\x92\x4b\xb0\x0d\x25\xa4\x21\x87\x42\x0f\xb9\x2f\x5a\x7b\x6c\xab\x95\x25\x21\xc9\xa1\x1b\xe3\x7f\xaf\x64\xcb\xbb\xde\x84\x42\x0a\x31\x18\xc9\x7e\x4f\x6f\xde\x8c\x66\x86\x81\x5e\x6e\xe0\x5e\xe9\x83\xe1\x4d\xeb\xe0\x4b\xf9\x15\xae\xb7\xdb\xdb\xe4\x7a\x7b\x75\x03\xb6\xe5\xf2\xf1\xe1\xd9\xf6\xf0\xd3\xa8\x5f\x58\xba\x74\x03\x97\x74\x1c\x37\xc3\x50\x61\xcd\x25\x02\xc1\x3f\x5a\x19\xb7\xab\x95\xe9\x88\x07\x32\x5d\x0c\x43\xfa\x03\xad\x65\x0d\xa6\x15\xda\x72\x37\x33\xc6\x31\xa3\xba\xf0\x07\x81\xd7\x90\x3e\x18\xa3\x0c\x1c\xf9\xd3\xe7\x89\x81\xb2\x9a\xb0\x20\x0a\x1d\xba\x56\x55\x39\x69\xd0\x11\x60\xa5\xe3\x4a\xe6\xc4\x9f\xf9\x56\x75\x5c\xde\x3f\x3e\x8d\x23\x9d\x23\x10\x28\x05\xb3\x36\x27\xe1\x58\xd2\x2a\xc3\x5f\x95\x74\x4c\x90\x22\xab\xf8\x4b\xb1\x01\x08\xeb\x42\x2a\x3d\x66\x94\x48\x1a\xa3\x7a\x4d\x02\xea\x71\xc1\xf6\x28\xde\x32\xa6\x9f\x04\xbc\x6a\x4e\x5c\x6b\x90\x55\x64\x9d\xa3\xe3\x4e\x60\xf0\x3e\xf1\xa2\xd0\xfb\x40\xd6\xdb\xe0\x52\xf7\x0e\x24\xeb\xf0\xa8\x04\xbc\x3a\xed\x2d\x7f\xf5\xc8\xcd\x96\x00\x2d\x32\xba\x98\xa6\x9f\xe7\x9e\x35\xe7\xd6\x59\xf3\x21\xe3\x13\xe4\xc1\x33\xff\x5e\x6b\x36\x1f\x36\xf4\xc8\x59\x09\xb4\x28\x74\xb2\x17\xaa\xfc\x4d\xfe\xd1\x14\xbb\xe8\x20\x66\xb8\x4e\xf6\xf3\xb2\x0e\xed\xc0\xdc\x99\x85\x55\xd3\x32\xf7\x5f\x25\xb0\x28\xfc\x18\xc4\x1a\x44\xe5\xa9\x0c\x4b\x94\x48\xf4\x5d\x6c\x98\x6c\x10\x2e\xea\xbb\x3c\xfd\x3e\x81\x36\x34\x35\xc4\x27\x53\x3a\xb4\x32\xbc\x30\xd1\x63\x68\xe8\x8b\x7a\x1c\x83\xc9\xb0\x66\x74\x46\x57\x6a\x71\x26\xe2\x61\x3a\xfb\xf8\x48\xd1\xa6\x69\x98\xe7\x66\xc9\x23\x5e\xa4\x3b\x68\x1f\xd9\xf6\xfb\x8e\xfb\x24\x8e\x46\xde\x54\xc9\xbb\x5a\xa4\xf6\x4e\x82\x7f\x13\x6d\x78\xc7\xcc\x21\x5e\x7b\x8c\x3a\x2f\x19\x0d\xf1\xc2\x14\x7b\xc3\xde\xef\x5f\x1e\x1c\x5a\xac\x60\x04\x00\x00")
+
+func gou_templateExport_formTxtBytes() ([]byte, error) {
+	return bindataRead(
+		_gou_templateExport_formTxt,
+		"gou_template/export_form.txt",
+	)
+}
+
+func gou_templateExport_formTxt() (*asset, error) {
+	bytes, err := gou_templateExport_formTxtBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "gou_template/export_form.txt", size: 1120, mode: os.FileMode(420), modTime: time.Unix(1792206974, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _gou_templateExport_htmlTxt = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\x7d\x54\x51\x6f\xd3\x30\x10\x7e\xef\xaf\x38\xb2\x3d\xc0\x44\x93\x74\x30\x54\x65\x69\x25\xd8\xc6\xc4\x03\x30\x41\x41\x42\x08\x21\x37\x76\x12\xb3\xc4\xae\x6c\x57\xa3\x8a\xf2\xdf\xb9\x73\xd2\xa8\x19\x05\xf5\xc1\xbd\xfb\xbe\x3b\x7f\xfe\x7c\x71\xd3\x44\x67\x13\xb8\xd2\x9b\x9d\x91\x45\xe9\xe0\x69\xf6\x0c\xce\xe3\xf8\x62\x7a\x1e\xcf\x5e\x82\x2d\xa5\xba\xbd\x59\xd9\x2d\xdc\x19\xfd\x4b\x64\x2e\x9c\xc0\x59\xd4\xb6\x93\xa6\xe1\x22\x97\x4a\x40\x20\x7e\x6f\xb4\x71\x3f\x4b\x57\x57\x81\x07\x4e\x8d\xd6\x2e\x59\x84\x18\xa4\x4f\xae\x3f\x5e\xad\xbe\xdd\xdd\x00\xc1\xcb\x49\xba\x5f\x04\xe3\xb8\xd4\xc2\x31\xc8\x4a\x66\xac\x70\x8b\xe0\xcb\xea\xed\x74\x1e\x40\x84\x80\x93\xae\x12\xcb\xa6\x09\x57\xf4\xa7\x6d\xd3\xa8\xcb\x4c\x52\xeb\x76\xb4\xae\x35\xdf\x41\x03\xb9\x56\x6e\x9a\xb3\x5a\x56\xbb\x04\x2c\x53\x76\x6a\x85\x91\xf9\x25\xd4\xcc\x14\x52\x25\x30\x13\x35\x9c\x8b\xfa\x12\xda\x09\x77\x58\xd0\xe5\xa7\x4e\x6f\x3c\xe6\xf3\x7c\xc8\x27\x10\x87\x2f\xb0\x22\xc6\xdf\x2c\xbc\xe8\xf0\x50\xf2\xe7\x10\x5a\xc7\xea\x0d\x12\x33\x5d\x69\x93\xc0\xc9\x7c\x3e\xf7\xa0\x62\xb5\x38\x48\xc7\xf3\xf8\xb2\x53\xf5\x20\xc8\xce\x04\xd6\xba\xe2\x9e\x69\x65\xa1\x0e\x98\xec\x55\x4c\xe9\xc4\xe1\xce\x82\xa4\xad\x59\x76\x5f\x18\xbd\x55\x1c\xd1\x3c\xf7\x45\x69\xd4\x9f\x37\x8d\x7a\xcb\xe8\xe0\x64\xe0\x6c\xe4\x0e\x86\x68\x3c\xc8\x1c\xc2\x15\x2b\x2c\x90\xf5\x1b\xc8\x2a\x66\xed\x22\x70\x98\x09\x90\x0e\x86\xa9\x42\xc0\x29\xc6\x78\x3b\x3d\x2f\xb5\x1b\xa6\x0e\x98\x44\x24\x06\x75\x25\x68\x09\x58\x28\x14\x27\x6a\xb4\xf1\xbb\x74\xd1\x24\xe5\x95\x0f\xfb\xae\x46\x64\xd8\xf5\x93\xc8\xb4\xe1\x9d\x00\x34\x5c\xf2\x45\x60\x68\x22\x44\x16\xbe\xbb\x9e\xb7\x6d\x80\xda\x19\x94\x46\xe4\x8b\xe0\x64\x8c\xec\x45\x48\xee\x35\x0c\x40\x1a\x31\xda\xe7\x94\x9c\x4e\x16\x3e\x7f\x2b\xdc\x1b\xf4\xe1\x2b\xab\xb6\x38\x80\x04\x04\x10\x04\xe0\xa7\x8f\x4c\xf0\x5c\x0a\x01\x46\xe7\xf3\xcc\x65\xdf\x6b\x38\xa1\x3f\x53\x65\xff\x5b\x40\x23\x1d\xbe\x17\xd6\xb2\x42\x84\x4c\x69\xb5\xab\xf5\xd6\x8e\x5b\x74\xb6\x20\xb9\x66\xb2\x3a\xaa\x94\x80\x47\x4a\x29\xd5\x6d\xfc\xbd\xaf\x6c\xdb\x1f\xa3\x76\x9e\x46\xbd\x3e\x97\xf8\x9d\xdd\x6d\xd7\xf7\x62\x77\x44\x2a\x8d\xd7\x60\xdc\x01\xf5\x98\xc6\x71\x21\x4d\x36\x55\x56\x3a\x63\x95\x93\xb5\xe8\xb7\xa3\xfc\xa8\x7a\x2f\xe4\xb5\x73\x2c\x2b\x7b\x0d\xfb\xdb\xec\x77\xee\x30\xba\x4e\xae\x1f\x54\xa5\x19\x7f\x04\x7d\xf0\xd6\x0f\x4a\x0f\x93\xfd\x4d\x0f\x2a\x23\xee\x70\x5c\x38\xdf\x73\xc9\xc9\xb1\x25\xab\x72\x5b\xaf\xd5\x60\x61\xba\x36\xf8\x7c\xfc\x53\xd2\x32\x95\x75\x01\xd6\x64\x03\x34\xd4\x93\x60\x3c\xfc\x71\xad\xd4\xf3\x2f\x69\xfc\x51\x4c\xcf\x5a\xd4\x7f\x9d\x51\xf7\xcc\x35\x0d\xc2\x88\xfe\x01\x19\x7a\xbf\x69\x63\x05\x00\x00")
+
+func gou_templateExport_htmlTxtBytes() ([]byte, error) {
+	return bindataRead(
+		_gou_templateExport_htmlTxt,
+		"gou_template/export_html.txt",
+	)
+}
+
+func gou_templateExport_htmlTxt() (*asset, error) {
+	bytes, err := gou_templateExport_htmlTxtBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "gou_template/export_html.txt", size: 1379, mode: os.FileMode(420), modTime: time.Unix(1792206941, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _gou_templateUpdatequeTxt = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\xa5\x55\xdf\x6b\xdb\x30\x10\x7e\xf7\x5f\x71\x98\x32\xb6\x42\xe3\xae\x6c\x2f\x9b\xe3\x51\xda\xac\x84\xb1\x32\x68\xde\x8b\x22\x5d\x6a\xad\xb6\xe4\x49\xe7\x52\x63\xfc\xbf\x4f\xb2\x52\xdb\xe9\x92\x50\xd8\x43\x62\x9f\xf4\xdd\x77\x3f\xf4\xe9\xdc\xb6\xc9\x69\x04\x57\xba\x6a\x8c\x7c\xc8\x09\xde\xf3\x0f\x70\x71\x7e\xfe\xf9\xec\xe2\xfc\xe3\x27\xb0\xb9\x54\x37\x8b\x95\xad\xe1\x97\xd1\xbf\x91\xd3\x2c\x82\xd3\xa4\xeb\xa2\xb6\x15\xb8\x91\x0a\x21\xae\x2b\xc1\x08\xff\xd4\x18\xf7\xcb\x27\x46\x6b\xfa\x32\x9f\x39\x23\xad\xb2\xb6\x9d\xfd\x44\x6b\xd9\x03\xce\x04\x5a\x7e\x3f\x80\xbb\x2e\x4d\xaa\xcc\xe1\x41\x6e\x60\xb6\x24\x2c\x2d\x78\x97\x8d\x36\x25\x94\x48\xb9\x16\xf3\xb8\xd2\x96\x62\x60\x9c\xa4\x56\xf3\xd8\x71\x5d\x8a\x52\xaa\xab\x9b\x65\xd7\x25\x63\x58\xe0\x05\xb3\x76\x1e\x7b\xd7\x33\xa9\x0a\x97\x55\x9c\xa5\x42\x3e\x65\x51\x2a\x55\x55\x13\x50\x53\xe1\x3c\xce\xa5\x10\xa8\x62\x50\xac\x74\x96\x95\x22\x86\x27\x56\xd4\xd8\x33\xdf\x49\xd1\x75\x31\x24\xce\x87\xd8\xba\x40\xb0\x75\x59\x32\xd3\xf4\x9b\x2f\x25\x4c\xb2\x1f\xa2\x5a\x5d\x38\xa6\x2c\x02\x48\xc9\xf8\x87\x7f\xc9\xb3\x34\x71\x7f\x83\xb5\x8f\xe3\xfe\x51\x2a\xe1\xdb\x70\x00\x48\x92\x0a\x3c\xb2\xcf\x0c\x49\x7e\x14\x31\x86\x52\x5a\x1c\x03\x7a\xd8\x9b\x78\xc8\x48\xb4\x6f\x8b\x88\xcf\xf4\x26\x20\x1a\xa3\xcd\x88\x74\x4f\xd3\xcb\xc2\x30\xf5\x80\x70\x22\xbd\x96\x06\x79\xec\x34\x59\x64\x3b\xc7\xcb\x73\xe4\x8f\x6b\xfd\xfc\x72\xc0\xd2\x39\x4d\x4e\xd8\x31\xcd\x7e\x60\x13\x0e\xd9\x45\x11\x23\x4d\x10\xa1\x07\x2c\xed\x77\x24\x9e\xbb\x50\x5b\x21\xef\x49\x78\xe3\x11\x1e\x00\x58\x58\x74\xd0\x94\x41\x6e\x70\xd3\x07\xe9\x7d\x26\x2a\x15\x58\xc8\x27\x34\xcd\xb7\x8d\x2c\x70\x1e\xb2\xb8\x66\xe4\xad\xae\x7b\xc7\xca\xea\xab\x41\xae\x8d\xd8\x6e\xdd\x91\x5b\xea\xba\xfb\x60\x2d\xaf\x5d\xb6\xd9\xe1\x4c\x08\x8b\xc2\x77\x8e\xf9\x0a\x50\x09\xe8\xdb\x38\x29\xec\x9f\xcc\x56\xce\x64\x22\xa4\xd6\xb6\x96\xcc\x42\x71\x27\x8d\xbe\xf6\x55\xd0\x5b\x1f\x70\x34\x3d\xfb\xff\x93\x26\xdb\xf2\x72\x6d\x68\xa8\x6a\xba\xe0\xc3\x40\xdb\x16\x9a\xb3\x82\x64\x19\x9c\xb7\xdd\x78\x7d\x58\x7e\xeb\xf6\x45\xd0\x3b\x3b\xbb\xee\x97\xee\xba\x8b\xfd\xee\xab\x41\xc7\x7b\x65\xb0\x78\xae\xa4\x41\x71\x54\x06\x18\x30\x53\x21\xbc\x4e\xe0\xb6\xbf\x03\x07\xce\x26\x24\xb2\x30\x66\xdc\x18\xb4\x1f\xf0\x91\xb3\xfd\x28\x72\x23\x69\x5d\x13\x69\xb5\x15\x36\x2f\xc7\xc9\x65\x90\x4c\x33\xcc\xa2\x35\xa9\x78\xff\x2d\xeb\x71\x3e\x52\x60\x3a\x46\xe9\x24\x8b\x84\x53\x4e\x70\xbf\x33\xe1\xef\xa3\x39\x40\x1f\x7c\x76\xf8\x13\x3f\x80\xd3\xc4\x0f\xe5\x50\x53\xe8\xd1\xab\x8f\xc2\xa4\x9f\x65\x45\xcd\xf8\x55\xd8\xb6\xa0\x6d\xd1\x0f\xc9\xe8\x2f\xa7\x6c\xa5\x14\xa6\x06\x00\x00")
+
+func gou_templateUpdatequeTxtBytes() ([]byte, error) {
+	return bindataRead(
+		_gou_templateUpdatequeTxt,
+		"gou_template/updateque.txt",
+	)
+}
+
+func gou_templateUpdatequeTxt() (*asset, error) {
+	bytes, err := gou_templateUpdatequeTxtBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "gou_template/updateque.txt", size: 1702, mode: os.FileMode(420), modTime: time.Unix(1792209432, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _gou_templateDeliveryTxt = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\x95\x53\xdb\x6a\x1b\x31\x10\x7d\xf7\x57\x0c\x8b\x03\x49\xa0\x5a\x37\x34\x2f\xc1\x36\x94\xc4\x98\x50\x9a\x86\xc4\xef\x46\x95\x66\xbd\x6a\xb5\xd2\x22\x69\x4b\x8d\xd0\xbf\x57\x5a\xdf\xdd\xc5\xdd\x3e\x69\x34\x73\xe6\xcc\xdd\xfb\xfc\x76\x00\x8f\xba\x5e\x1b\xb1\x2a\x1d\x5c\xb3\x1b\xb8\x1b\x8d\xee\x3f\xdc\x8d\x3e\x7e\x02\x5b\x0a\x35\x9f\x2d\x6c\x03\xaf\x46\xff\x40\xe6\xc8\x00\x6e\xf3\x10\x06\xde\x73\x2c\x84\x42\xc8\x38\x4a\xf1\x0b\xcd\x3a\x6b\xb5\x43\xa3\xb5\x7b\x98\x90\xf8\x19\xd7\x53\xef\xc9\x57\xb4\x96\xae\x90\x70\xb4\x6c\xb9\xc3\x86\x30\xce\xeb\x69\x42\x8c\x29\x94\x06\x8b\x49\x16\xa1\x8b\x28\x51\xfe\x38\x7f\x0e\x21\xf7\xde\x3a\x33\x53\x4c\x73\x04\xb2\x10\x4e\x62\x08\x59\xe2\xdb\xca\xe3\x9c\x4e\x07\x00\xff\xe3\x1e\x95\xe4\xbd\xd4\xc6\x3d\x3f\x6d\xb9\xf6\xbf\xc4\x06\xde\x4b\xcd\xa8\x74\xa2\x8a\x3e\xef\x8e\x56\xf5\x36\x4d\xef\x41\x14\x40\x9e\x36\xc9\x0b\xb4\xd0\x51\xdd\xa6\xb0\x25\x65\x0c\x6b\x87\x3c\x84\x87\x48\x48\x3e\xef\xbf\x90\xa7\x00\xa8\x8e\x79\x76\x6d\x70\xf4\xbb\x44\xb0\x4d\x55\x51\xb3\x6e\x6b\x39\xe7\x8d\x09\x03\x93\xd4\xda\x49\x66\xb5\x14\x3c\x6b\x8b\x77\x26\x3d\x49\x28\x3b\x93\x51\xb1\xfc\x14\x23\x9a\x2f\xe2\x0c\xda\x46\xba\x4b\x48\xea\x7a\x11\x59\xa6\x4d\x2f\xa0\xfe\xd9\x07\xa5\x56\x7d\x50\x69\x60\xba\x71\xfd\x0a\x2d\x1a\x9b\xa6\xf1\x6f\x28\x1a\xa3\xcd\x01\x18\x5f\xd3\x6e\x82\xa1\x6a\x85\x30\xe4\x71\xc7\x4f\x17\xe2\x64\x1e\x3c\x72\x0e\x39\x79\xd9\x0d\x80\x1f\x1b\x84\xe2\xf8\x1b\xda\x4b\xd9\xc5\x85\xeb\xda\x08\xe5\x8a\xc3\x39\x2d\xaf\x6c\x16\xc3\x90\xb7\x76\x36\x37\x7f\xb3\x1c\x96\x35\xa2\xf6\xeb\x7a\x8a\x89\x96\x57\x34\x0c\x55\xec\xce\x55\x87\xf1\xdb\x97\x4e\x9f\x97\x79\xa7\x7a\x71\xd4\xe8\x73\xdb\xdb\x51\x67\xcf\x6d\xb3\x7d\x2b\xf9\x49\x2b\x51\xf1\xf6\x92\xf2\x76\xff\x37\x2a\x69\xf1\xc2\x75\x61\x55\xbb\xf5\xe1\x2a\xb7\x04\xde\x47\x21\xbe\x7f\x00\x19\xe3\x86\x16\xcb\x04\x00\x00")
+
+func gou_templateDeliveryTxtBytes() ([]byte, error) {
+	return bindataRead(
+		_gou_templateDeliveryTxt,
+		"gou_template/delivery.txt",
+	)
+}
+
+func gou_templateDeliveryTxt() (*asset, error) {
+	bytes, err := gou_templateDeliveryTxtBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "gou_template/delivery.txt", size: 1227, mode: os.FileMode(420), modTime: time.Unix(1792210930, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
@@ -1681,6 +1870,15 @@ var _bindata = map[string]func() (*asset, error){
 	"gou_template/thread_tags.txt": gou_templateThread_tagsTxt,
 	"gou_template/thread_top.txt": gou_templateThread_topTxt,
 	"gou_template/top.txt": gou_templateTopTxt,
+	"gou_template/moderation.txt": gou_templateModerationTxt,
+	"file/moderator.txt": fileModeratorTxt,
+	"gou_template/search_result.txt": gou_templateSearch_resultTxt,
+	"gou_template/fsck.txt": gou_templateFsckTxt,
+	"gou_template/bundle.txt": gou_templateBundleTxt,
+	"gou_template/export_form.txt": gou_templateExport_formTxt,
+	"gou_template/export_html.txt": gou_templateExport_htmlTxt,
+	"gou_template/updateque.txt": gou_templateUpdatequeTxt,
+	"gou_template/delivery.txt": gou_templateDeliveryTxt,
 }
 
 // AssetDir returns the file names below a certain
@@ -1732,6 +1930,7 @@ var _bintree = &bintree{nil, map[string]*bintree{
 		"node_deny.txt": &bintree{fileNode_denyTxt, map[string]*bintree{}},
 		"saku.ini": &bintree{fileSakuIni, map[string]*bintree{}},
 		"spam.txt": &bintree{fileSpamTxt, map[string]*bintree{}},
+		"moderator.txt": &bintree{fileModeratorTxt, map[string]*bintree{}},
 	}},
 	"gou_template": &bintree{nil, map[string]*bintree{
 		"2ch_error.txt": &bintree{gou_template2ch_errorTxt, map[string]*bintree{}},
@@ -1756,6 +1955,14 @@ var _bintree = &bintree{nil, map[string]*bintree{
 		"thread_tags.txt": &bintree{gou_templateThread_tagsTxt, map[string]*bintree{}},
 		"thread_top.txt": &bintree{gou_templateThread_topTxt, map[string]*bintree{}},
 		"top.txt": &bintree{gou_templateTopTxt, map[string]*bintree{}},
+		"moderation.txt": &bintree{gou_templateModerationTxt, map[string]*bintree{}},
+		"search_result.txt": &bintree{gou_templateSearch_resultTxt, map[string]*bintree{}},
+		"fsck.txt": &bintree{gou_templateFsckTxt, map[string]*bintree{}},
+		"bundle.txt": &bintree{gou_templateBundleTxt, map[string]*bintree{}},
+		"export_form.txt": &bintree{gou_templateExport_formTxt, map[string]*bintree{}},
+		"export_html.txt": &bintree{gou_templateExport_htmlTxt, map[string]*bintree{}},
+		"updateque.txt": &bintree{gou_templateUpdatequeTxt, map[string]*bintree{}},
+		"delivery.txt": &bintree{gou_templateDeliveryTxt, map[string]*bintree{}},
 	}},
 	"www": &bintree{nil, map[string]*bintree{
 		"00default.css": &bintree{www00defaultCss, map[string]*bintree{}},
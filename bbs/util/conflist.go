@@ -39,43 +39,58 @@ import (
 	"time"
 )
 
+//checkInterval is the interval of checking if files of ConfList are updated.
+const checkInterval = 10 * time.Second
+
 //ConfList represents regexp list.
 //    One regexp per one line.
 type ConfList struct {
-	mtime *time.Time
-	path  string
-	data  []string
-	mutex sync.RWMutex
+	mtime       *time.Time
+	checked     time.Time
+	path        string
+	data        []string
+	defaultList []string
+	mutex       sync.RWMutex
 }
 
 //NewConfList makes a confList instance from path.
 func NewConfList(path string, defaultList []string) *ConfList {
 	r := &ConfList{
-		path: path,
+		path:        path,
+		defaultList: defaultList,
 	}
 	r.update()
-	if len(r.data) == 0 {
-		r.data = defaultList
-	}
 	return r
 }
 
-//GetData retuns a copy of lines in the file.
+//GetData reads the file if newer, and retuns a copy of lines in the file.
+//if the file has no lines, returns a copy of the default list.
 func (r *ConfList) GetData() []string {
+	r.update()
 	r.mutex.RLock()
 	defer r.mutex.RUnlock()
-	d := make([]string, len(r.data))
-	copy(d, r.data)
+	data := r.data
+	if len(data) == 0 {
+		data = r.defaultList
+	}
+	d := make([]string, len(data))
+	copy(d, data)
 	return d
 }
 
 //update read the file if newer, and stores all lines in the file.
+//the file is checked at most once per checkInterval.
 func (r *ConfList) update() bool {
 	r.mutex.Lock()
 	defer r.mutex.Unlock()
 	if r.path == "" {
 		return false
 	}
+	now := time.Now()
+	if now.Sub(r.checked) < checkInterval {
+		return false
+	}
+	r.checked = now
 	s, err := os.Stat(r.path)
 	if err != nil {
 		r.data = nil
@@ -0,0 +1,81 @@
+/*
+ * Copyright (c) 2015, Shinya Yagyu
+ * All rights reserved.
+ * Redistribution and use in source and binary forms, with or without
+ * modification, are permitted provided that the following conditions are met:
+ *
+ * 1. Redistributions of source code must retain the above copyright notice,
+ *    this list of conditions and the following disclaimer.
+ * 2. Redistributions in binary form must reproduce the above copyright notice,
+ *    this list of conditions and the following disclaimer in the documentation
+ *    and/or other materials provided with the distribution.
+ * 3. Neither the name of the copyright holder nor the names of its
+ *    contributors may be used to endorse or promote products derived from this
+ *    software without specific prior written permission.
+ *
+ * THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+ * AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+ * IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+ * ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+ * LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+ * CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+ * SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+ * INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+ * CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+ * ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+ * POSSIBILITY OF SUCH DAMAGE.
+ */
+package util
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"log"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+//ed25519Salt is the salt of scrypt for deriving ed25519 keys from passwords.
+//it must be same in all nodes so that the same password makes the same key.
+var ed25519Salt = []byte("shinGETsu-gou ed25519 key")
+
+//Ed25519Key is a ed25519 private key, which signs sha256 of messages.
+type Ed25519Key ed25519.PrivateKey
+
+//MakeEd25519Key makes ed25519 private key whose seed is derived from keystr by scrypt,
+//which makes brute force search of passwords from public keys expensive.
+func MakeEd25519Key(keystr string) Ed25519Key {
+	seed, err := scrypt.Key([]byte(keystr), ed25519Salt, 1<<15, 8, 1, ed25519.SeedSize)
+	if err != nil {
+		log.Fatal(err)
+	}
+	return Ed25519Key(ed25519.NewKeyFromSeed(seed))
+}
+
+//PublicKey returns base64 encoded public key.
+func (k Ed25519Key) PublicKey() string {
+	pub := ed25519.PrivateKey(k).Public().(ed25519.PublicKey)
+	return base64.RawStdEncoding.EncodeToString(pub)
+}
+
+//Sign signs sha256 of mesg by k and returns base64 encoded sign.
+func (k Ed25519Key) Sign(mesg string) string {
+	digest := sha256.Sum256([]byte(mesg))
+	sig := ed25519.Sign(ed25519.PrivateKey(k), digest[:])
+	return base64.RawStdEncoding.EncodeToString(sig)
+}
+
+//VerifyEd25519 verifies testsig of sha256 of mesg by base64 encoded publicKey.
+func VerifyEd25519(mesg, testsig, publicKey string) bool {
+	pub, err := base64.RawStdEncoding.DecodeString(publicKey)
+	if err != nil || len(pub) != ed25519.PublicKeySize {
+		return false
+	}
+	sig, err := base64.RawStdEncoding.DecodeString(testsig)
+	if err != nil || len(sig) != ed25519.SignatureSize {
+		return false
+	}
+	digest := sha256.Sum256([]byte(mesg))
+	return ed25519.Verify(pub, digest[:], sig)
+}
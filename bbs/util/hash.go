@@ -0,0 +1,122 @@
+/*
+ * Copyright (c) 2015, Shinya Yagyu
+ * All rights reserved.
+ * Redistribution and use in source and binary forms, with or without
+ * modification, are permitted provided that the following conditions are met:
+ *
+ * 1. Redistributions of source code must retain the above copyright notice,
+ *    this list of conditions and the following disclaimer.
+ * 2. Redistributions in binary form must reproduce the above copyright notice,
+ *    this list of conditions and the following disclaimer in the documentation
+ *    and/or other materials provided with the distribution.
+ * 3. Neither the name of the copyright holder nor the names of its
+ *    contributors may be used to endorse or promote products derived from this
+ *    software without specific prior written permission.
+ *
+ * THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+ * AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+ * IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+ * ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+ * LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+ * CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+ * SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+ * INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+ * CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+ * ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+ * POSSIBILITY OF SUCH DAMAGE.
+ */
+package util
+
+import (
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"errors"
+	"hash"
+	"sync"
+)
+
+//Hasher makes digests of record ids.
+type Hasher interface {
+	//Name returns the name used as cache_hash_method.
+	Name() string
+	//Digest returns hex string of digest of dat.
+	Digest(dat string) string
+}
+
+//stdHasher is a Hasher using hash.Hash.
+type stdHasher struct {
+	name    string
+	newHash func() hash.Hash
+}
+
+//Name returns the name of the hasher.
+func (h *stdHasher) Name() string {
+	return h.name
+}
+
+//Digest returns hex string of digest of dat.
+func (h *stdHasher) Digest(dat string) string {
+	hh := h.newHash()
+	hh.Write([]byte(dat))
+	return hex.EncodeToString(hh.Sum(nil))
+}
+
+var (
+	hasherMutex sync.RWMutex
+	hashers     []Hasher
+)
+
+func init() {
+	RegisterHasher(&stdHasher{"md5", md5.New})
+	RegisterHasher(&stdHasher{"sha1", sha1.New})
+	RegisterHasher(&stdHasher{"sha224", sha256.New224})
+	RegisterHasher(&stdHasher{"sha256", sha256.New})
+	RegisterHasher(&stdHasher{"sha384", sha512.New384})
+	RegisterHasher(&stdHasher{"sha512", sha512.New})
+}
+
+//RegisterHasher registers h as a hasher which can be selected by h.Name().
+//if a hasher with the same name exists, it is replaced.
+func RegisterHasher(h Hasher) {
+	hasherMutex.Lock()
+	defer hasherMutex.Unlock()
+	for i, hh := range hashers {
+		if hh.Name() == h.Name() {
+			hashers[i] = h
+			return
+		}
+	}
+	hashers = append(hashers, h)
+}
+
+//GetHasher returns the hasher whose name is name.
+//"asis" means md5, which is used in shinGETsu protocol.
+func GetHasher(name string) (Hasher, error) {
+	if name == "asis" {
+		name = "md5"
+	}
+	hasherMutex.RLock()
+	defer hasherMutex.RUnlock()
+	for _, h := range hashers {
+		if h.Name() == name {
+			return h, nil
+		}
+	}
+	return nil, errors.New("unknown hash method " + name)
+}
+
+//FindHasher returns the first registered hasher whose digests have the same length as digest.
+//returns nil if not found.
+func FindHasher(digest string) Hasher {
+	hasherMutex.RLock()
+	defer hasherMutex.RUnlock()
+	for _, h := range hashers {
+		if len(h.Digest("")) == len(digest) {
+			return h
+		}
+	}
+	return nil
+}
@@ -0,0 +1,119 @@
+/*
+ * Copyright (c) 2015, Shinya Yagyu
+ * All rights reserved.
+ * Redistribution and use in source and binary forms, with or without
+ * modification, are permitted provided that the following conditions are met:
+ *
+ * 1. Redistributions of source code must retain the above copyright notice,
+ *    this list of conditions and the following disclaimer.
+ * 2. Redistributions in binary form must reproduce the above copyright notice,
+ *    this list of conditions and the following disclaimer in the documentation
+ *    and/or other materials provided with the distribution.
+ * 3. Neither the name of the copyright holder nor the names of its
+ *    contributors may be used to endorse or promote products derived from this
+ *    software without specific prior written permission.
+ *
+ * THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+ * AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+ * IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+ * ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+ * LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+ * CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+ * SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+ * INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+ * CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+ * ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+ * POSSIBILITY OF SUCH DAMAGE.
+ */
+package util
+
+import (
+	"strings"
+	"unicode"
+)
+
+//isCJK returns true if r is a character which is not separated by spaces,
+//i.e. kanji, hiragana, katakana or hangul.
+func isCJK(r rune) bool {
+	return unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul) ||
+		r == 'ー' || r == '々'
+}
+
+//Token is a term in a text and its position by rune offset.
+type Token struct {
+	Term string
+	Pos  int
+}
+
+//IsCJKChar returns true if str is one CJK character.
+func IsCJKChar(str string) bool {
+	rs := []rune(str)
+	return len(rs) == 1 && isCJK(rs[0])
+}
+
+//TokenizeWithPos splits str into lowercased words and bigrams of CJK characters,
+//with their positions.
+//a CJK run of one character is a token by itself.
+func TokenizeWithPos(str string) []Token {
+	return tokenize(str, false)
+}
+
+//IndexTokens is TokenizeWithPos plus each character in CJK runs of two or more characters,
+//so that a query of one CJK character can be looked up in the index.
+func IndexTokens(str string) []Token {
+	return tokenize(str, true)
+}
+
+//tokenize splits str into tokens, adding unigrams of CJK runs if unigrams.
+func tokenize(str string, unigrams bool) []Token {
+	var tokens []Token
+	rs := []rune(strings.ToLower(str))
+	for i := 0; i < len(rs); {
+		switch {
+		case isCJK(rs[i]):
+			j := i
+			for j < len(rs) && isCJK(rs[j]) {
+				j++
+			}
+			if j-i == 1 {
+				tokens = append(tokens, Token{string(rs[i]), i})
+			}
+			for k := i; k+1 < j; k++ {
+				tokens = append(tokens, Token{string(rs[k : k+2]), k})
+			}
+			for k := i; unigrams && j-i > 1 && k < j; k++ {
+				tokens = append(tokens, Token{string(rs[k]), k})
+			}
+			i = j
+		case unicode.IsLetter(rs[i]) || unicode.IsDigit(rs[i]):
+			j := i
+			for j < len(rs) && !isCJK(rs[j]) && (unicode.IsLetter(rs[j]) || unicode.IsDigit(rs[j])) {
+				j++
+			}
+			tokens = append(tokens, Token{string(rs[i:j]), i})
+			i = j
+		default:
+			i++
+		}
+	}
+	return tokens
+}
+
+//Tokenize splits str into lowercased words and bigrams of CJK characters.
+func Tokenize(str string) []string {
+	return terms(TokenizeWithPos(str))
+}
+
+//IndexTerms returns terms of IndexTokens.
+func IndexTerms(str string) []string {
+	return terms(IndexTokens(str))
+}
+
+//terms returns terms of tokens.
+func terms(tokens []Token) []string {
+	ts := make([]string, len(tokens))
+	for i, t := range tokens {
+		ts[i] = t.Term
+	}
+	return ts
+}
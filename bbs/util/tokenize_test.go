@@ -0,0 +1,64 @@
+/*
+ * Copyright (c) 2015, Shinya Yagyu
+ * All rights reserved.
+ * Redistribution and use in source and binary forms, with or without
+ * modification, are permitted provided that the following conditions are met:
+ *
+ * 1. Redistributions of source code must retain the above copyright notice,
+ *    this list of conditions and the following disclaimer.
+ * 2. Redistributions in binary form must reproduce the above copyright notice,
+ *    this list of conditions and the following disclaimer in the documentation
+ *    and/or other materials provided with the distribution.
+ * 3. Neither the name of the copyright holder nor the names of its
+ *    contributors may be used to endorse or promote products derived from this
+ *    software without specific prior written permission.
+ *
+ * THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+ * AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+ * IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+ * ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+ * LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+ * CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+ * SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+ * INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+ * CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+ * ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+ * POSSIBILITY OF SUCH DAMAGE.
+ */
+package util
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTokenize(t *testing.T) {
+	tests := map[string][]string{
+		"Hello, World 2ch": {"hello", "world", "2ch"},
+		"東京都に行く":           {"東京", "京都", "都に", "に行", "行く"},
+		"新月のGouです":         {"新月", "月の", "gou", "です"},
+		"字 test":           {"字", "test"},
+		"  ":               {},
+	}
+	for str, terms := range tests {
+		if r := Tokenize(str); !reflect.DeepEqual(r, terms) {
+			t.Fatal(str, "tokenized to", r)
+		}
+	}
+}
+
+func TestIndexTerms(t *testing.T) {
+	tests := map[string][]string{
+		"東京に":    {"東京", "京に", "東", "京", "に"},
+		"字 test": {"字", "test"},
+		"gou":    {"gou"},
+	}
+	for str, terms := range tests {
+		if r := IndexTerms(str); !reflect.DeepEqual(r, terms) {
+			t.Fatal(str, "tokenized to", r)
+		}
+	}
+	if !IsCJKChar("京") || IsCJKChar("京都") || IsCJKChar("a") {
+		t.Fatal("illegal IsCJKChar")
+	}
+}
@@ -48,7 +48,7 @@ shingetsu.initialize(function () {
         }
         var newid = "";
         var lastStamp = null;
-        $("dt span.stamp[data-stamp]").each(function () {
+        $("#records dt span.stamp[data-stamp]").each(function () {
             var stamp = $(this).attr('data-stamp');
             if (stamp > read) {
                 var dt = $(this).closest("dt").get(0);